@@ -22,6 +22,7 @@ import (
 var (
 	version   = "dev"
 	buildDate = ""
+	gitCommit = ""
 )
 
 func main() {
@@ -30,5 +31,6 @@ func main() {
 	cmd.RunAppService(cmd.AppServiceParams{
 		Version:   version,
 		BuildDate: buildDate,
+		GitCommit: gitCommit,
 	})
 }
@@ -64,9 +64,9 @@ func (svc *AppService) Start(args []string) error {
 				return
 			case err := <-done:
 				if err != nil {
-					// Handle error during execution and exit with a non-zero code.
+					// Handle error during execution and exit with the non-zero code mapped to the error.
 					svc.err = err
-					if err = svc.sd.Shutdown(fx.ExitCode(1)); err != nil {
+					if err = svc.sd.Shutdown(fx.ExitCode(ExitCode(err))); err != nil {
 						svc.err = errors.Join(svc.err, err)
 					}
 					return
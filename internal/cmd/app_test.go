@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -92,3 +93,34 @@ func TestAppService_StartWithError(t *testing.T) {
 
 	mockShutdowner.AssertCalled(t, "Shutdown", []fx.ShutdownOption{fx.ExitCode(1)})
 }
+
+// TestAppService_StartWithTypedError tests that typed errors returned by the CLI command
+// are mapped to their documented exit codes when shutting down.
+func TestAppService_StartWithTypedError(t *testing.T) {
+	tests := map[int]error{
+		ExitCodeConfig: util.NewConfigError(fmt.Errorf("simulated config error")),
+		ExitCodeAccess: util.NewAccessError("test", fmt.Errorf("simulated access error")),
+	}
+
+	for code, e := range tests {
+		mockShutdowner := new(MockShutdowner)
+		mockShutdowner.On("Shutdown", mock.Anything).Return(nil)
+
+		app := &cli.Command{
+			Name: "test-app",
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return e
+			},
+		}
+
+		svc := NewAppService(app, mockShutdowner)
+
+		err := svc.Start(nil)
+		assert.NoError(t, err, "Start should not return an error")
+
+		err = svc.Stop()
+		require.ErrorIs(t, err, e, "Stop should return the command error")
+
+		mockShutdowner.AssertCalled(t, "Shutdown", []fx.ShutdownOption{fx.ExitCode(code)})
+	}
+}
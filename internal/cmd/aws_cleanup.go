@@ -16,9 +16,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"strings"
 	"time"
 
@@ -29,6 +31,23 @@ import (
 // awsNoneValue is the string AWS CLI returns for empty/null query results
 const awsNoneValue = "None"
 
+// awsCli runs an AWS CLI command and returns its stdout, replaced in tests.
+var awsCli = func(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, "aws", args...).Output()
+}
+
+// BlockingAWSResources lists the resources tagged with the cluster that would block
+// Terraform destroy.
+type BlockingAWSResources struct {
+	InstanceIds         []string // EC2 instances not yet terminated
+	NetworkInterfaceIds []string // ENIs still in use
+}
+
+// Count returns the total number of blocking resources.
+func (r BlockingAWSResources) Count() int {
+	return len(r.InstanceIds) + len(r.NetworkInterfaceIds)
+}
+
 // HasBlockingAWSResources performs a quick check to detect resources that would block
 // Terraform destroy (orphaned EC2 instances, in-use ENIs). This is a fast check
 // (~2-3 seconds) that allows us to proactively run cleanup instead of waiting
@@ -42,49 +61,128 @@ const awsNoneValue = "None"
 //   - bool: true if blocking resources were found, false otherwise.
 //   - error: An error if the check fails.
 func HasBlockingAWSResources(ctx context.Context, p *CommandParams) (bool, error) {
+	res, err := FindBlockingAWSResources(ctx, p)
+	if err != nil && res.Count() == 0 {
+		var cfgErr util.ConfigError
+		if errors.As(err, &cfgErr) {
+			return false, err
+		}
+
+		// best effort, the aws cli may be missing or lack permissions
+		log.Debug("Unable to check for blocking AWS resources", "error", err)
+		return false, nil
+	}
+
+	if len(res.InstanceIds) > 0 {
+		log.Info("Found running EC2 instances", "count", len(res.InstanceIds))
+	}
+	if len(res.NetworkInterfaceIds) > 0 {
+		log.Info("Found in-use ENIs", "count", len(res.NetworkInterfaceIds))
+	}
+
+	return res.Count() > 0, nil
+}
+
+// FindBlockingAWSResources lists the EC2 instances and in-use ENIs tagged with the cluster
+// that would block Terraform destroy, without modifying anything.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - BlockingAWSResources: The blocking resources found, including those found before any error.
+//   - error: An error if the cluster isn't configured or any lookup fails.
+func FindBlockingAWSResources(ctx context.Context, p *CommandParams) (BlockingAWSResources, error) {
 	cfg := p.Settings().Config
 	clusterName := cfg.Name
 	region := cfg.Aws.Region
 
 	if clusterName == "" || region == "" {
-		return false, fmt.Errorf("cluster name and region are required")
+		return BlockingAWSResources{}, util.NewConfigErrorf("cluster name and region are required")
 	}
 
-	// Check for running EC2 instances tagged with this cluster
-	cmd := exec.CommandContext(ctx, "aws", "ec2", "describe-instances",
+	var res BlockingAWSResources
+	var errs []error
+
+	// Running EC2 instances tagged with this cluster
+	ids, err := awsCliList(ctx, "ec2", "describe-instances",
 		"--region", region,
 		"--filters",
 		fmt.Sprintf("Name=tag:kubernetes.io/cluster/%s,Values=owned,shared", clusterName),
 		"Name=instance-state-name,Values=running,pending,stopping,stopped",
-		"--query", "length(Reservations[].Instances[])",
+		"--query", "Reservations[].Instances[].InstanceId",
 		"--output", "text")
-	output, err := cmd.Output()
-	if err == nil {
-		count := strings.TrimSpace(string(output))
-		if count != "" && count != "0" && count != awsNoneValue {
-			log.Info("Found running EC2 instances", "count", count)
-			return true, nil
-		}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("describe instances: %w", err))
 	}
+	res.InstanceIds = ids
 
-	// Check for in-use ENIs tagged with this cluster
-	cmd = exec.CommandContext(ctx, "aws", "ec2", "describe-network-interfaces",
+	// In-use ENIs tagged with this cluster
+	ids, err = awsCliList(ctx, "ec2", "describe-network-interfaces",
 		"--region", region,
 		"--filters",
 		fmt.Sprintf("Name=tag:kubernetes.io/cluster/%s,Values=owned,shared", clusterName),
 		"Name=status,Values=in-use",
-		"--query", "length(NetworkInterfaces)",
+		"--query", "NetworkInterfaces[].NetworkInterfaceId",
 		"--output", "text")
-	output, err = cmd.Output()
-	if err == nil {
-		count := strings.TrimSpace(string(output))
-		if count != "" && count != "0" && count != awsNoneValue {
-			log.Info("Found in-use ENIs", "count", count)
-			return true, nil
+	if err != nil {
+		errs = append(errs, fmt.Errorf("describe network interfaces: %w", err))
+	}
+	res.NetworkInterfaceIds = ids
+
+	return res, errors.Join(errs...)
+}
+
+// awsCliList runs an AWS CLI query with text output and splits the result into its values,
+// ignoring the "None" returned for empty results.
+func awsCliList(ctx context.Context, args ...string) ([]string, error) {
+	out, err := awsCli(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, f := range strings.Fields(string(out)) {
+		if f != awsNoneValue {
+			res = append(res, f)
 		}
 	}
 
-	return false, nil
+	return res, nil
+}
+
+// ValidateCleanup prints the AWS resources that would block Terraform destroy and cause
+// clean to run the proactive AWS cleanup, without deleting anything.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the lookup fails, otherwise nil.
+func ValidateCleanup(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "internal:validateCleanup")
+	defer log.Debug("Completed", "command", "internal:validateCleanup")
+
+	res, err := FindBlockingAWSResources(ctx, p)
+
+	util.Hdrf("Blocking AWS resources (%d)", res.Count())
+	var rows [][]string
+	for _, id := range res.InstanceIds {
+		rows = append(rows, []string{"EC2 instance", id})
+	}
+	for _, id := range res.NetworkInterfaceIds {
+		rows = append(rows, []string{"Network interface", id})
+	}
+
+	if len(rows) > 0 {
+		util.PrintTable([]string{"Type", "ID"}, rows)
+	} else if err == nil {
+		util.Msg("No blocking resources found")
+	}
+
+	return err
 }
 
 // ForceAWSCleanup runs AWS CLI commands to forcibly clean up resources that may block Terraform destroy.
@@ -121,7 +219,7 @@ func ForceAWSCleanup(ctx context.Context, p *CommandParams) error {
 	// preventing "no endpoints available" errors during subsequent Helm uninstall operations.
 	util.Msg("Phase 0: Cleaning up Kubernetes blocking resources...")
 	k8sStart := time.Now()
-	cleanupKubernetesBlockers(ctx)
+	cleanupKubernetesBlockers(ctx, p)
 	util.Msgf("  Kubernetes cleanup completed in %v", time.Since(k8sStart))
 
 	// Phase 1: Delete LoadBalancers
@@ -512,13 +610,13 @@ func cleanupSecurityGroups(ctx context.Context, clusterName, region string) erro
 
 // cleanupKubernetesBlockers removes Kubernetes resources that would block Helm uninstall operations.
 // This includes:
-// - Validating and mutating webhooks (especially Kyverno, Istio, cert-manager)
+// - Validating and mutating webhooks matching cleanup.webhooks (Kyverno, Istio, cert-manager), or all with --all-webhooks
 // - Stale API services (metrics-server, custom-metrics) that block namespace finalization
 // - Finalizers on stuck namespaces
 //
 // This function should be called BEFORE terminating EC2 instances to ensure the cluster
 // is still healthy enough to process these deletions.
-func cleanupKubernetesBlockers(ctx context.Context) {
+func cleanupKubernetesBlockers(ctx context.Context, p *CommandParams) {
 	// Use KUBECONFIG from environment if set, otherwise use default path
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
@@ -545,34 +643,15 @@ func cleanupKubernetesBlockers(ctx context.Context) {
 		return
 	}
 
-	util.Msg("  Removing ALL validating webhooks...")
-	// Delete ALL validating webhooks during cleanup to prevent any blocking
-	// This is aggressive but safe during teardown - the cluster is being destroyed anyway
-	cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
-		"delete", "validatingwebhookconfiguration", "--all",
-		"--ignore-not-found=true", "--timeout=30s")
-	if out, err := cmd.CombinedOutput(); err != nil {
-		log.Warn("Failed to delete all validating webhooks", "error", err, "output", string(out))
-	}
+	cfg := p.Settings().Config.Cleanup
 
-	util.Msg("  Removing ALL mutating webhooks...")
-	// Delete ALL mutating webhooks during cleanup
-	cmd = exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
-		"delete", "mutatingwebhookconfiguration", "--all",
-		"--ignore-not-found=true", "--timeout=30s")
-	if out, err := cmd.CombinedOutput(); err != nil {
-		log.Warn("Failed to delete all mutating webhooks", "error", err, "output", string(out))
+	for _, kind := range []string{"validatingwebhookconfiguration", "mutatingwebhookconfiguration"} {
+		deleteWebhookConfigurations(ctx, kubeconfig, kind, p.AllWebhooks(), cfg.Webhooks)
 	}
 
 	util.Msg("  Removing stale API services...")
 	// These API services often block namespace finalization when their backing pods are gone
-	staleAPIServices := []string{
-		"v1beta1.metrics.k8s.io",
-		"v1beta1.external.metrics.k8s.io",
-		"v1beta1.custom.metrics.k8s.io",
-		"v1.external.metrics.k8s.io",
-	}
-	for _, apiSvc := range staleAPIServices {
+	for _, apiSvc := range cfg.AllStaleApiServices() {
 		cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
 			"delete", "apiservice", apiSvc,
 			"--ignore-not-found=true", "--timeout=10s")
@@ -581,14 +660,7 @@ func cleanupKubernetesBlockers(ctx context.Context) {
 
 	util.Msg("  Patching stuck namespaces to remove finalizers...")
 	// Remove finalizers from namespaces that commonly get stuck during cleanup
-	stuckNamespaces := []string{
-		"flux-system",
-		"kyverno",
-		"monitoring",
-		"istio-system",
-		"cert-manager",
-	}
-	for _, ns := range stuckNamespaces {
+	for _, ns := range cfg.AllStuckNamespaces() {
 		// Check if namespace exists and is terminating
 		cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
 			"get", "namespace", ns, "-o", "jsonpath={.status.phase}",
@@ -612,3 +684,56 @@ func cleanupKubernetesBlockers(ctx context.Context) {
 
 	util.Msg("  ✅ Kubernetes blocking resources removed")
 }
+
+// deleteWebhookConfigurations deletes the webhook configurations of the given kind whose names
+// match the patterns, or every configuration of the kind when all is set.
+func deleteWebhookConfigurations(ctx context.Context, kubeconfig string, kind string, all bool, patterns []string) {
+	if all {
+		util.Msgf("  Removing ALL %s...", kind)
+		cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
+			"delete", kind, "--all",
+			"--ignore-not-found=true", "--timeout=30s")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			log.Warn("Failed to delete all webhooks", "kind", kind, "error", err, "output", string(out))
+		}
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig,
+		"get", kind, "-o", "jsonpath={.items[*].metadata.name}",
+		"--request-timeout=10s")
+	out, err := cmd.Output()
+	if err != nil {
+		log.Warn("Failed to list webhooks", "kind", kind, "error", err)
+		return
+	}
+
+	names := matchWebhookNames(strings.Fields(string(out)), patterns)
+	if len(names) == 0 {
+		log.Debug("No matching webhooks to remove", "kind", kind, "patterns", patterns)
+		return
+	}
+
+	util.Msgf("  Removing %s: %s", kind, strings.Join(names, ", "))
+	args := append([]string{"--kubeconfig", kubeconfig, "delete", kind}, names...)
+	cmd = exec.CommandContext(ctx, "kubectl", append(args, "--ignore-not-found=true", "--timeout=30s")...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Warn("Failed to delete webhooks", "kind", kind, "error", err, "output", string(out))
+	}
+}
+
+// matchWebhookNames returns the webhook configuration names matching any of the
+// path.Match patterns, invalid patterns never match.
+func matchWebhookNames(names []string, patterns []string) []string {
+	var res []string
+	for _, n := range names {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, n); ok {
+				res = append(res, n)
+				break
+			}
+		}
+	}
+
+	return res
+}
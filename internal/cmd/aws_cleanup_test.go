@@ -0,0 +1,153 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdMatchWebhookNames(t *testing.T) {
+	names := []string{
+		"kyverno-resource-validating-webhook-cfg",
+		"istio-sidecar-injector",
+		"cert-manager-webhook",
+		"quartz-policy",
+		"customer-admission-webhook",
+		"vpa-webhook-config",
+	}
+
+	assert.Equal(t, []string{
+		"kyverno-resource-validating-webhook-cfg",
+		"istio-sidecar-injector",
+		"cert-manager-webhook",
+		"quartz-policy",
+	}, matchWebhookNames(names, schema.NewCleanupConfig().Webhooks))
+
+	assert.Equal(t, []string{"vpa-webhook-config"}, matchWebhookNames(names, []string{"vpa-*", "[invalid"}))
+	assert.Empty(t, matchWebhookNames(names, nil))
+}
+
+func TestCmdAllWebhooks(t *testing.T) {
+	p := defaultTestConfig(t)
+	assert.False(t, p.AllWebhooks())
+
+	p.SetAllWebhooks(true)
+	assert.True(t, p.AllWebhooks())
+
+	p.SetAllWebhooks(false)
+	p.Settings().Config.Cleanup.AllWebhooks = true
+	assert.True(t, p.AllWebhooks())
+}
+
+// mockAwsCli replaces the aws cli for the test with canned output for the ec2 describe calls.
+func mockAwsCli(t *testing.T, instances string, enis string, err error) {
+	orig := awsCli
+	t.Cleanup(func() { awsCli = orig })
+
+	awsCli = func(ctx context.Context, args ...string) ([]byte, error) {
+		if !slices.Contains(args, "--region") || !slices.Contains(args, "Name=tag:kubernetes.io/cluster/mytest,Values=owned,shared") {
+			t.Errorf("unexpected aws cli args, %v", args)
+		}
+
+		switch args[1] {
+		case "describe-instances":
+			return []byte(instances), err
+		case "describe-network-interfaces":
+			return []byte(enis), err
+		}
+
+		t.Errorf("unexpected aws cli command, %v", args)
+		return nil, errors.New("unexpected command")
+	}
+}
+
+func awsCleanupTestConfig(t *testing.T) *CommandParams {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Name = "mytest"
+	p.Settings().Config.Aws.Region = "us-east-1"
+	return p
+}
+
+func TestCmdFindBlockingAWSResources(t *testing.T) {
+	mockAwsCli(t, "i-0123456789abcdef0\ti-0fedcba9876543210\n", "eni-0123456789abcdef0\n", nil)
+	p := awsCleanupTestConfig(t)
+
+	res, err := FindBlockingAWSResources(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"i-0123456789abcdef0", "i-0fedcba9876543210"}, res.InstanceIds)
+	assert.Equal(t, []string{"eni-0123456789abcdef0"}, res.NetworkInterfaceIds)
+	assert.Equal(t, 3, res.Count())
+
+	blocking, err := HasBlockingAWSResources(context.Background(), p)
+	assert.NoError(t, err)
+	assert.True(t, blocking)
+}
+
+func TestCmdFindBlockingAWSResourcesNone(t *testing.T) {
+	mockAwsCli(t, "None\n", "\n", nil)
+	p := awsCleanupTestConfig(t)
+
+	res, err := FindBlockingAWSResources(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Zero(t, res.Count())
+
+	blocking, err := HasBlockingAWSResources(context.Background(), p)
+	assert.NoError(t, err)
+	assert.False(t, blocking)
+}
+
+func TestCmdFindBlockingAWSResourcesError(t *testing.T) {
+	mockAwsCli(t, "", "", errors.New("aws not found"))
+	p := awsCleanupTestConfig(t)
+
+	_, err := FindBlockingAWSResources(context.Background(), p)
+	assert.ErrorContains(t, err, "aws not found")
+
+	// the proactive check is best effort
+	blocking, err := HasBlockingAWSResources(context.Background(), p)
+	assert.NoError(t, err)
+	assert.False(t, blocking)
+
+	p.Settings().Config.Aws.Region = ""
+	_, err = HasBlockingAWSResources(context.Background(), p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+}
+
+func TestCmdValidateCleanup(t *testing.T) {
+	mockAwsCli(t, "i-0123456789abcdef0\n", "eni-0123456789abcdef0\n", nil)
+	p := awsCleanupTestConfig(t)
+
+	// tables are printed to stdout
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	err := ValidateCleanup(context.Background(), p)
+	w.Close()
+	assert.NoError(t, err)
+
+	out, _ := io.ReadAll(r)
+	assert.Contains(t, string(out), "i-0123456789abcdef0")
+	assert.Contains(t, string(out), "eni-0123456789abcdef0")
+}
@@ -16,6 +16,10 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
 	"slices"
 
 	"github.com/MetroStar/quartzctl/internal/log"
@@ -53,23 +57,56 @@ func NewCliCommand(deps CliDependencies, p AppServiceParams) *cli.Command {
 	slices.SortFunc(deps.Root.Commands, ByCommandName)
 
 	return &cli.Command{
+		// -v is --verbose, so the builtin version flag (-v/--version) is replaced by the
+		// --version flag below rather than changing cli.VersionFlag for the whole process
 		Version:               p.Version,
+		HideVersion:           true,
 		Name:                  "quartz",
 		Description:           "Quartz cloud/kubernetes platform automation tool",
 		Usage:                 "\b\b ",
 		EnableShellCompletion: true,
-		Commands:              deps.Root.Commands,
+		// allows combining short bool flags, e.g. -vv
+		UseShortOptionHandling: true,
+		ConfigureShellCompletionCommand: func(ccmd *cli.Command) {
+			ccmd.Hidden = false
+			ccmd.Usage = "Output a shell completion script for bash, zsh or fish"
+			ccmd.ArgsUsage = "[bash|zsh|fish]"
+		},
+		Commands: deps.Root.Commands,
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "override default config file", Value: "./quartz.yaml"},
 			&cli.StringFlag{Name: "secrets", Usage: "configure secrets with yaml"},
+			&cli.StringSliceFlag{Name: "set", Usage: "override a config value, e.g. --set dns.domain=foo.example.com (repeatable)"},
+			&cli.StringSliceFlag{Name: "var-file", Usage: "merge a yaml file over the config (repeatable)"},
+			&cli.StringFlag{Name: "env", Aliases: []string{"config-env"}, Usage: "merge the quartz.<env>.yaml overlay next to the config file"},
+			&cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}, Usage: "log more to the console, repeatable (-v info, -vv debug)"},
+			&cli.StringFlag{Name: "log-level", Usage: "console log level (debug, info, warn, error), overrides -v and the config", Validator: log.CheckLevel},
+			&cli.BoolFlag{Name: "quiet", Aliases: []string{"q"}, Usage: "suppress decorative output, errors and data are still printed (also QUARTZ_QUIET)"},
+			&cli.BoolFlag{Name: "keep-tmp", Usage: "keep the tmp directory (tfvars, kubeconfig, logs) for debugging"},
+			&cli.BoolFlag{Name: "version", Usage: "print the version", HideDefault: true, Local: true},
+		},
+		// Action runs without a subcommand, printing the version or the help.
+		Action: func(ctx context.Context, ccmd *cli.Command) error {
+			if ccmd.Bool("version") {
+				Version(p.Version, p.BuildDate)
+				return nil
+			}
+			return cli.ShowAppHelp(ccmd)
 		},
 		// Before is executed before the command runs to set up configuration and secrets.
 		Before: func(ctx context.Context, ccmd *cli.Command) (context.Context, error) {
 			configureLogger(ccmd)
 			deps.Params.SetConfig(ccmd.String("config"))
 			deps.Params.SetSecrets(ccmd.String("secrets"))
+			deps.Params.SetOverrides(ccmd.StringSlice("set"), ccmd.StringSlice("var-file"))
+			deps.Params.SetEnvironment(ccmd.String("env"))
+			deps.Params.SetKeepTmp(ccmd.Bool("keep-tmp"))
 			return ctx, nil
 		},
+		// After surfaces any config load error so it is mapped to the config exit code.
+		After: func(ctx context.Context, ccmd *cli.Command) error {
+			return deps.Params.SettingsErr()
+		},
 	}
 }
 
@@ -83,5 +120,62 @@ func NewCliCommand(deps CliDependencies, p AppServiceParams) *cli.Command {
 func configureLogger(ccmd *cli.Command) {
 	w := ccmd.Root().Writer
 	util.SetWriter(w)
-	log.ConfigureDefault(ccmd.String("config"), w)
+	util.SetQuiet(ccmd.Bool("quiet"))
+
+	level := ccmd.String("log-level")
+	if level == "" {
+		level = log.VerbosityLevel(ccmd.Count("verbose"))
+	}
+	log.ConfigureDefaultLevel(ccmd.String("config"), level, w)
+}
+
+// stageShellComplete creates a shell completion function listing the configured stage names
+// when completing the value of a --stage flag, otherwise falling back to the default flag
+// and subcommand completion.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - cli.ShellCompleteFunc: The completion function for commands with a --stage flag.
+func stageShellComplete(p *CommandParams) cli.ShellCompleteFunc {
+	return func(ctx context.Context, ccmd *cli.Command) {
+		if isStageFlagCompletion(os.Args) {
+			printStageCompletions(ccmd.Root().Writer, p)
+			return
+		}
+		cli.DefaultCompleteWithFlags(ctx, ccmd)
+	}
+}
+
+// isStageFlagCompletion reports whether the shell is completing the value of a --stage flag,
+// i.e. the last argument before the completion flag is --stage or -s.
+//
+// Parameters:
+//   - args: The command line arguments.
+//
+// Returns:
+//   - bool: true if a stage name is being completed.
+func isStageFlagCompletion(args []string) bool {
+	if len(args) > 0 && args[len(args)-1] == "--generate-shell-completion" {
+		args = args[:len(args)-1]
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+
+	last := args[len(args)-1]
+	return last == "--stage" || last == "-s"
+}
+
+// printStageCompletions writes the sorted names of the configured stages, one per line.
+//
+// Parameters:
+//   - w: The writer for the completions.
+//   - p: *CommandParams containing configuration and runtime parameters.
+func printStageCompletions(w io.Writer, p *CommandParams) {
+	for _, s := range slices.Sorted(maps.Keys(p.Settings().Config.Stages)) {
+		fmt.Fprintln(w, s)
+	}
 }
@@ -0,0 +1,197 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+// newTestCliCommand creates the root cli command with the given root commands, writing to the buffer.
+func newTestCliCommand(t *testing.T, p *CommandParams, buf *bytes.Buffer, commands ...*cli.Command) *cli.Command {
+	t.Cleanup(func() {
+		util.SetWriter(os.Stderr)
+		util.SetQuiet(false)
+	})
+
+	c := NewCliCommand(CliDependencies{Params: p, Root: RootCommandParams{Commands: commands}}, AppServiceParams{Version: "1.0.0"})
+	c.Writer = buf
+	c.ErrWriter = buf
+	return c
+}
+
+func TestCliCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := newTestCliCommand(t, defaultTestConfig(t), &buf)
+
+			// the completion subcommand writes the script to stdout
+			r, w, _ := os.Pipe()
+			defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+			os.Stdout = w
+
+			err := c.Run(context.Background(), []string{"quartz", "completion", shell})
+			w.Close()
+			assert.NoError(t, err)
+
+			out, _ := io.ReadAll(r)
+			assert.NotEmpty(t, out)
+			assert.Contains(t, string(out), "quartz")
+		})
+	}
+}
+
+func TestCliQuiet(t *testing.T) {
+	testCmd := &cli.Command{
+		Name: "test",
+		Action: func(ctx context.Context, ccmd *cli.Command) error {
+			util.Hdr("test header")
+			util.Msg("test message")
+			util.Error("test error")
+			return nil
+		},
+	}
+
+	var buf bytes.Buffer
+	c := newTestCliCommand(t, defaultTestConfig(t), &buf, testCmd)
+
+	err := c.Run(context.Background(), []string{"quartz", "--quiet", "test"})
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), "test header")
+	assert.NotContains(t, buf.String(), "test message")
+	assert.Contains(t, buf.String(), "test error")
+
+	buf.Reset()
+	err = c.Run(context.Background(), []string{"quartz", "test"})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "test header")
+	assert.Contains(t, buf.String(), "test message")
+}
+
+func TestCliVerbosity(t *testing.T) {
+	t.Setenv("DEBUG", "")
+
+	tests := []struct {
+		name       string
+		args       []string
+		infoShown  bool
+		debugShown bool
+	}{
+		// warnings are shown by default
+		{name: "none", args: nil},
+		{name: "verbose", args: []string{"-v"}, infoShown: true},
+		{name: "very verbose", args: []string{"-vv"}, infoShown: true, debugShown: true},
+		{name: "repeated", args: []string{"-v", "--verbose"}, infoShown: true, debugShown: true},
+		{name: "log level", args: []string{"-vv", "--log-level", "info"}, infoShown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCmd := &cli.Command{
+				Name: "test",
+				Action: func(ctx context.Context, ccmd *cli.Command) error {
+					log.Warn("test warn entry")
+					log.Info("test info entry")
+					log.Debug("test debug entry")
+					return nil
+				},
+			}
+
+			var buf bytes.Buffer
+			c := newTestCliCommand(t, defaultTestConfig(t), &buf, testCmd)
+
+			args := append(append([]string{"quartz", "--config", ""}, tt.args...), "test")
+			err := c.Run(context.Background(), args)
+			assert.NoError(t, err)
+
+			assert.Contains(t, buf.String(), "test warn entry")
+			assert.Equal(t, tt.infoShown, strings.Contains(buf.String(), "test info entry"), "info entry in %s", buf.String())
+			assert.Equal(t, tt.debugShown, strings.Contains(buf.String(), "test debug entry"), "debug entry in %s", buf.String())
+		})
+	}
+}
+
+func TestCliVersionFlag(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestCliCommand(t, defaultTestConfig(t), &buf)
+
+	// the version is written to stdout
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	err := c.Run(context.Background(), []string{"quartz", "--version"})
+	assert.NoError(t, err)
+
+	// -v is --verbose, printing the help rather than the version
+	err = c.Run(context.Background(), []string{"quartz", "-v"})
+	w.Close()
+	assert.NoError(t, err)
+
+	out, _ := io.ReadAll(r)
+	assert.Equal(t, 1, strings.Count(string(out)+buf.String(), "1.0.0"), "version printed once in %s", string(out)+buf.String())
+	assert.Contains(t, buf.String(), "--verbose")
+	assert.Equal(t, []string{"version", "v"}, cli.VersionFlag.Names(), "the global version flag should be untouched")
+}
+
+func TestCliLogLevelInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestCliCommand(t, defaultTestConfig(t), &buf, &cli.Command{Name: "test"})
+
+	err := c.Run(context.Background(), []string{"quartz", "--log-level", "loud", "test"})
+	assert.ErrorContains(t, err, "invalid log level loud")
+}
+
+func TestCliStageCompletion(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	args := []string{"quartz", "init", "--stage", "--generate-shell-completion"}
+	orig := os.Args
+	os.Args = args
+	defer func() { os.Args = orig }()
+
+	var buf bytes.Buffer
+	c := newTestCliCommand(t, p, &buf, NewTfInitCommand(p).Command)
+
+	err := c.Run(context.Background(), args)
+	assert.NoError(t, err)
+
+	stages := strings.Fields(buf.String())
+	assert.NotEmpty(t, stages)
+	assert.True(t, slices.IsSorted(stages))
+	for _, s := range stages {
+		assert.Contains(t, p.Settings().Config.Stages, s)
+	}
+	assert.Len(t, stages, len(p.Settings().Config.Stages))
+}
+
+func TestCliIsStageFlagCompletion(t *testing.T) {
+	assert.True(t, isStageFlagCompletion([]string{"quartz", "terraform", "init", "--stage", "--generate-shell-completion"}))
+	assert.True(t, isStageFlagCompletion([]string{"quartz", "terraform", "init", "-s"}))
+	assert.False(t, isStageFlagCompletion([]string{"quartz", "terraform", "init", "--generate-shell-completion"}))
+	assert.False(t, isStageFlagCompletion([]string{"quartz", "terraform", "init", "--stage", "vpc", "--generate-shell-completion"}))
+	assert.False(t, isStageFlagCompletion(nil))
+}
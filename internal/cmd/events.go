@@ -0,0 +1,112 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// eventTimeFormat is the layout of the event times printed by Events.
+const eventTimeFormat = "2006-01-02 15:04:05"
+
+// NewRootEventsCommand creates the root events CLI command.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - RootCommandResult containing the root events CLI command.
+func NewRootEventsCommand(p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "events",
+			Usage: "Print the Kubernetes events for a namespace, warnings only unless --all is set",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "namespace",
+					Aliases: []string{"n"},
+					Usage:   "Namespace to print the events for, all namespaces if unset",
+				},
+				&cli.BoolFlag{
+					Name:    "follow",
+					Aliases: []string{"f"},
+					Usage:   "Keep printing new events until interrupted",
+				},
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "Include Normal events, not just warnings",
+				},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return Events(ctx, os.Stdout, ccmd.String("namespace"), ccmd.Bool("follow"), ccmd.Bool("all"), p)
+			},
+		},
+	}
+}
+
+// Events prints the Kubernetes events for a namespace as a table, or streams them to w as they
+// occur when following.
+//
+// Parameters:
+//   - ctx: The context for the operation, following stops when it is done.
+//   - w: The writer followed events are written to.
+//   - ns: The namespace to print the events for, all namespaces if empty.
+//   - follow: Whether to keep streaming new events.
+//   - all: Whether to include Normal events, not just warnings.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the events can't be listed or watched, otherwise nil.
+func Events(ctx context.Context, w io.Writer, ns string, follow bool, all bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "events", "namespace", ns, "follow", follow)
+	defer log.Debug("Completed", "command", "events")
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	if follow {
+		return kube.WatchEvents(ctx, ns, all, func(e provider.KubernetesEvent) {
+			fmt.Fprintf(w, "%s  %s  %-7s  %s  %s  %s\n", e.Time.Local().Format(eventTimeFormat), e.Namespace, e.Type, e.Reason, e.Object, e.Message)
+		})
+	}
+
+	events, err := kube.ListEvents(ctx, ns, all)
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		util.Msg("No events found")
+		return nil
+	}
+
+	var rows [][]string
+	for _, e := range events {
+		rows = append(rows, []string{e.Time.Local().Format(eventTimeFormat), e.Namespace, e.Type, e.Reason, e.Object, e.Message})
+	}
+	util.PrintTable([]string{"Time", "Namespace", "Type", "Reason", "Object", "Message"}, rows)
+
+	return nil
+}
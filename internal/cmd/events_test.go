@@ -0,0 +1,132 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+// newTestEvent creates an event for a pod in testns1.
+func newTestEvent(name string, eventType string, reason string) *corev1.Event {
+	e := corev1.Event{
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " message",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "testpod1"},
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	e.Name = name
+	e.Namespace = "testns1"
+	return &e
+}
+
+// withTestEvents replaces the kubernetes client with one holding a warning and a normal event,
+// whose watch emits the same events and then closes, to be resumed until the follow is cancelled.
+func withTestEvents(t *testing.T, p *CommandParams) {
+	warning := newTestEvent("event1", corev1.EventTypeWarning, "BackOff")
+	warning.ResourceVersion = "11"
+	normal := newTestEvent("event2", corev1.EventTypeNormal, "Pulled")
+	normal.ResourceVersion = "12"
+
+	api := provider.NewKubernetesApiMock().
+		WithClientReactor("list", "events", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, &corev1.EventList{ListMeta: metav1.ListMeta{ResourceVersion: "10"}, Items: []corev1.Event{*warning, *normal}}, nil
+		}).
+		WithClientWatchReactor("events", func(action k8sTesting.Action) (bool, watch.Interface, error) {
+			if action.(k8sTesting.WatchActionImpl).WatchRestrictions.ResourceVersion != "10" {
+				// resumed after the first watch closed, nothing more to emit
+				return true, watch.NewFake(), nil
+			}
+
+			fw := watch.NewFake()
+			go func() {
+				fw.Add(warning)
+				fw.Add(normal)
+				fw.Stop()
+			}()
+			return true, fw, nil
+		})
+
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+}
+
+func TestNewRootEventsCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestEvents(t, p)
+	cmd := NewRootEventsCommand(p).Command
+
+	assert.Equal(t, "events", cmd.Name)
+	assert.Len(t, cmd.Flags, 3)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-n", "testns1", "--all"})
+	assert.NoError(t, err)
+}
+
+func TestCmdEventsFollow(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestEvents(t, p)
+
+	// following runs until interrupted
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var buf bytes.Buffer
+	err := Events(ctx, &buf, "testns1", true, false, p)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "Warning  BackOff  pod/testpod1  BackOff message")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	buf.Reset()
+	err = Events(ctx, &buf, "testns1", true, true, p)
+	assert.NoError(t, err)
+
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "Normal   Pulled  pod/testpod1  Pulled message")
+}
+
+func TestCmdEventsList(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestEvents(t, p)
+
+	var buf bytes.Buffer
+	err := Events(context.Background(), &buf, "testns1", false, false, p)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+
+	err = Events(context.Background(), &buf, "emptyns", false, true, p)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// Exit codes returned by the quartz CLI.
+const (
+	ExitCodeSuccess = 0 // The command completed successfully.
+	ExitCodeFailure = 1 // Generic failure.
+	ExitCodeConfig  = 2 // Invalid or missing configuration.
+	ExitCodeAccess  = 3 // A required provider could not be accessed.
+)
+
+// multiError matches the aggregate error type returned by the CLI framework
+// when more than one error is encountered (e.g. from a command and its After hook).
+type multiError interface {
+	Errors() []error
+}
+
+// ExitCode maps an error returned from a command to the process exit code.
+// Configuration errors take precedence over access errors, which take precedence
+// over any other failure.
+//
+// Parameters:
+//   - err: The error returned by the command, may be nil.
+//
+// Returns:
+//   - int: The exit code for the error.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	errs := []error{err}
+	if m, ok := err.(multiError); ok {
+		errs = m.Errors()
+	}
+
+	code := ExitCodeFailure
+	for _, e := range errs {
+		var cfgErr util.ConfigError
+		if errors.As(e, &cfgErr) {
+			return ExitCodeConfig
+		}
+
+		var accessErr util.AccessError
+		if errors.As(e, &accessErr) {
+			code = ExitCodeAccess
+		}
+	}
+
+	return code
+}
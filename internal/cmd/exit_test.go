@@ -0,0 +1,57 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMultiError []error
+
+func (m testMultiError) Error() string {
+	return fmt.Sprintf("%v", []error(m))
+}
+
+func (m testMultiError) Errors() []error {
+	return m
+}
+
+func TestExitCode(t *testing.T) {
+	cfgErr := util.NewConfigError(fmt.Errorf("bad config"))
+	accessErr := util.NewAccessError("test", fmt.Errorf("denied"))
+
+	assert.Equal(t, ExitCodeSuccess, ExitCode(nil))
+	assert.Equal(t, ExitCodeFailure, ExitCode(fmt.Errorf("generic")))
+	assert.Equal(t, ExitCodeConfig, ExitCode(cfgErr))
+	assert.Equal(t, ExitCodeAccess, ExitCode(accessErr))
+
+	// wrapped
+	assert.Equal(t, ExitCodeConfig, ExitCode(fmt.Errorf("wrapped, %w", cfgErr)))
+	assert.Equal(t, ExitCodeAccess, ExitCode(fmt.Errorf("wrapped, %w", accessErr)))
+
+	// joined, config takes precedence over access
+	assert.Equal(t, ExitCodeAccess, ExitCode(errors.Join(fmt.Errorf("generic"), accessErr)))
+	assert.Equal(t, ExitCodeConfig, ExitCode(errors.Join(accessErr, cfgErr)))
+
+	// cli multi error
+	assert.Equal(t, ExitCodeAccess, ExitCode(testMultiError{fmt.Errorf("generic"), accessErr}))
+	assert.Equal(t, ExitCodeConfig, ExitCode(testMultiError{accessErr, cfgErr}))
+	assert.Equal(t, ExitCodeFailure, ExitCode(testMultiError{fmt.Errorf("generic")}))
+}
@@ -29,6 +29,7 @@ import (
 type AppServiceParams struct {
 	Version   string
 	BuildDate string
+	GitCommit string
 }
 
 // RunAppService initializes and runs the application service using Uber's Fx framework.
@@ -94,10 +95,23 @@ var RootCommandsModule = fx.Module("rootCmds",
 		NewRootRestartCommand,
 		NewRootTerraformCommand,
 		NewRootAwsCommand,
+		NewRootStateCommand,
+		NewRootGetCommand,
+		NewRootIngressCommand,
+		NewRootEventsCommand,
+		NewRootTopCommand,
+		NewRootKeycloakCommand,
 		NewRootInternalCommand,
+		NewRootLogsCommand,
+		NewRootInitCommand,
+		NewRootMirrorCommand,
+		NewRootVersionCommand,
 	),
 	tfCommandsModule,
 	awsCommandsModule,
+	stateCommandsModule,
+	getCommandsModule,
+	keycloakCommandsModule,
 )
 
 // TfCommandParams represents the input parameters for Terraform-related commands.
@@ -123,12 +137,18 @@ var tfCommandsModule = fx.Module("tfCmds",
 		NewTfPlanCommand,
 		NewTfDestroyCommand,
 		NewTfOutputCommand,
+		NewTfShowCommand,
+		NewTfWorkspaceCommand,
 		NewTfRefreshCommand,
 		NewTfRefreshAllCommand,
 		NewTfValidateCommand,
+		NewTfValidateAllCommand,
 		NewTfFormatCommand,
 		NewTfFormatAllCommand,
 		NewTfVersionCommand,
+		NewTfForceUnlockCommand,
+		NewTfGraphCommand,
+		NewTfProvidersLockCommand,
 	),
 )
 
@@ -152,3 +172,70 @@ var awsCommandsModule = fx.Module("awsCmds",
 		NewGetEksTokenCommand,
 	),
 )
+
+// StateCommandParams represents the input parameters for state-related commands.
+// It is used to group state commands for dependency injection.
+type StateCommandParams struct {
+	fx.In
+	Commands []*cli.Command `group:"state"`
+}
+
+// StateCommandResult represents the output result for a state command.
+// It is used to group state commands for dependency injection.
+type StateCommandResult struct {
+	fx.Out
+	Command *cli.Command `group:"state"`
+}
+
+// stateCommandsModule defines the state commands module for dependency injection.
+var stateCommandsModule = fx.Module("stateCmds",
+	fx.Provide(
+		NewStateMigrateCommand,
+		NewStateShowCommand,
+		NewStateResetCommand,
+	),
+)
+
+// GetCommandParams represents the input parameters for resource retrieval commands.
+// It is used to group get commands for dependency injection.
+type GetCommandParams struct {
+	fx.In
+	Commands []*cli.Command `group:"get"`
+}
+
+// GetCommandResult represents the output result for a get command.
+// It is used to group get commands for dependency injection.
+type GetCommandResult struct {
+	fx.Out
+	Command *cli.Command `group:"get"`
+}
+
+// getCommandsModule defines the get commands module for dependency injection.
+var getCommandsModule = fx.Module("getCmds",
+	fx.Provide(
+		NewGetSecretCommand,
+		NewGetConfigMapCommand,
+	),
+)
+
+// KeycloakCommandParams represents the input parameters for Keycloak-related commands.
+// It is used to group Keycloak commands for dependency injection.
+type KeycloakCommandParams struct {
+	fx.In
+	Commands []*cli.Command `group:"keycloak"`
+}
+
+// KeycloakCommandResult represents the output result for a Keycloak command.
+// It is used to group Keycloak commands for dependency injection.
+type KeycloakCommandResult struct {
+	fx.Out
+	Command *cli.Command `group:"keycloak"`
+}
+
+// keycloakCommandsModule defines the Keycloak commands module for dependency injection.
+var keycloakCommandsModule = fx.Module("keycloakCmds",
+	fx.Provide(
+		NewKeycloakExportCommand,
+		NewKeycloakOtpCommand,
+	),
+)
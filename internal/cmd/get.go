@@ -0,0 +1,189 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/urfave/cli/v3"
+)
+
+// maskedSecretValue replaces secret values in GetSecret output unless --show-values is given.
+const maskedSecretValue = "********"
+
+// NewRootGetCommand creates the root get CLI command.
+// It organizes and returns all resource retrieval subcommands.
+//
+// Parameters:
+//   - cmds: GetCommandParams containing the list of get subcommands.
+//
+// Returns:
+//   - RootCommandResult containing the root get CLI command.
+func NewRootGetCommand(cmds GetCommandParams) RootCommandResult {
+	slices.SortFunc(cmds.Commands, ByCommandName)
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:     "get",
+			Usage:    "Retrieve cluster resources",
+			Commands: cmds.Commands,
+		},
+	}
+}
+
+// NewGetSecretCommand creates a CLI command for printing the keys or a value of a Kubernetes secret.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - GetCommandResult containing the "secret" CLI command.
+func NewGetSecretCommand(p *CommandParams) GetCommandResult {
+	return GetCommandResult{
+		Command: &cli.Command{
+			Name:  "secret",
+			Usage: "Print the keys of a secret (values masked), or the value of a single key",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "namespace", Aliases: []string{"n"}, Usage: "Secret namespace", Required: true},
+				&cli.StringFlag{Name: "name", Usage: "Secret name", Required: true},
+				&cli.StringFlag{Name: "key", Aliases: []string{"k"}, Usage: "only print the value of this key"},
+				&cli.BoolFlag{Name: "show-values", Usage: "print the values instead of masking them"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return GetSecret(ctx, os.Stdout, ccmd.String("namespace"), ccmd.String("name"), ccmd.String("key"), ccmd.Bool("show-values"), p)
+			},
+		},
+	}
+}
+
+// NewGetConfigMapCommand creates a CLI command for printing the keys and values of a Kubernetes ConfigMap.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - GetCommandResult containing the "configmap" CLI command.
+func NewGetConfigMapCommand(p *CommandParams) GetCommandResult {
+	return GetCommandResult{
+		Command: &cli.Command{
+			Name:  "configmap",
+			Usage: "Print the keys and values of a ConfigMap, or the value of a single key",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "namespace", Aliases: []string{"n"}, Usage: "ConfigMap namespace", Required: true},
+				&cli.StringFlag{Name: "name", Usage: "ConfigMap name", Required: true},
+				&cli.StringFlag{Name: "key", Aliases: []string{"k"}, Usage: "only print the value of this key"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return GetConfigMap(ctx, os.Stdout, ccmd.String("namespace"), ccmd.String("name"), ccmd.String("key"), p)
+			},
+		},
+	}
+}
+
+// GetSecret writes the keys of a Kubernetes secret, one "key: value" per line with the values
+// masked unless showValues is set. When a key is given only its raw value is written.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - w: The writer the secret is printed to.
+//   - ns: The namespace of the secret.
+//   - name: The name of the secret.
+//   - key: Optional key to print the value of.
+//   - showValues: Whether to print the values of all keys instead of masking them.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the secret or key isn't found, otherwise nil.
+func GetSecret(ctx context.Context, w io.Writer, ns string, name string, key string, showValues bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "get:secret", "namespace", ns, "name", name)
+	defer log.Debug("Completed", "command", "get:secret", "namespace", ns, "name", name)
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	data, err := kube.GetSecretValue(ctx, ns, name)
+	if err != nil {
+		return fmt.Errorf("failed to read secret %s/%s: %w", ns, name, err)
+	}
+
+	return writeGetData(w, "secret", ns, name, data, key, !showValues)
+}
+
+// GetConfigMap writes the keys and values of a Kubernetes ConfigMap, one "key: value" per line.
+// When a key is given only its raw value is written.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - w: The writer the ConfigMap is printed to.
+//   - ns: The namespace of the ConfigMap.
+//   - name: The name of the ConfigMap.
+//   - key: Optional key to print the value of.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the ConfigMap or key isn't found, otherwise nil.
+func GetConfigMap(ctx context.Context, w io.Writer, ns string, name string, key string, p *CommandParams) error {
+	log.Debug("Entering", "command", "get:configmap", "namespace", ns, "name", name)
+	defer log.Debug("Completed", "command", "get:configmap", "namespace", ns, "name", name)
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	data, err := kube.GetConfigMapValue(ctx, ns, name)
+	if err != nil {
+		return fmt.Errorf("failed to read configmap %s/%s: %w", ns, name, err)
+	}
+
+	return writeGetData(w, "configmap", ns, name, data, key, false)
+}
+
+// writeGetData writes the raw value of key, or every "key: value" pair sorted by key
+// with the values masked when mask is set.
+func writeGetData(w io.Writer, kind string, ns string, name string, data map[string]string, key string, mask bool) error {
+	keys := slices.Sorted(maps.Keys(data))
+
+	if key != "" {
+		v, ok := data[key]
+		if !ok {
+			return fmt.Errorf("key %s not found in %s %s/%s, available keys: %s", key, kind, ns, name, strings.Join(keys, ", "))
+		}
+
+		_, err := fmt.Fprintln(w, v)
+		return err
+	}
+
+	for _, k := range keys {
+		v := data[k]
+		if mask {
+			v = maskedSecretValue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s\n", k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// withTestSecret replaces the kubernetes client with one holding a single secret testns1/test-secret.
+func withTestSecret(t *testing.T, p *CommandParams) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "testns1"},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("hunter2"),
+		},
+	}
+
+	api := provider.NewKubernetesApiMock().WithClientObjects(secret)
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+}
+
+func TestNewRootGetCommand(t *testing.T) {
+	cmds := GetCommandParams{
+		Commands: []*cli.Command{
+			{Name: "secret"},
+			{Name: "configmap"},
+		},
+	}
+	cmd := NewRootGetCommand(cmds).Command
+
+	assert.Equal(t, "get", cmd.Name)
+	assert.Len(t, cmd.Commands, 2)
+	assert.Equal(t, "configmap", cmd.Commands[0].Name)
+	assert.Equal(t, "secret", cmd.Commands[1].Name)
+}
+
+func TestNewGetSecretCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+	cmd := NewGetSecretCommand(p).Command
+
+	assert.Equal(t, "secret", cmd.Name)
+	assert.Len(t, cmd.Flags, 4)
+
+	nsFlag := cmd.Flags[0].(*cli.StringFlag)
+	assert.Equal(t, "namespace", nsFlag.Name)
+	assert.True(t, nsFlag.Required)
+
+	nameFlag := cmd.Flags[1].(*cli.StringFlag)
+	assert.Equal(t, "name", nameFlag.Name)
+	assert.True(t, nameFlag.Required)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-n", "testns1", "--name", "test-secret"})
+	assert.NoError(t, err)
+}
+
+func TestCmdGetSecretMasked(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+
+	var buf bytes.Buffer
+	err := GetSecret(context.Background(), &buf, "testns1", "test-secret", "", false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "password: ********\nusername: ********\n", buf.String())
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestCmdGetSecretShowValues(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+
+	var buf bytes.Buffer
+	err := GetSecret(context.Background(), &buf, "testns1", "test-secret", "", true, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "password: hunter2\nusername: admin\n", buf.String())
+}
+
+func TestCmdGetSecretKey(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+
+	var buf bytes.Buffer
+	err := GetSecret(context.Background(), &buf, "testns1", "test-secret", "password", false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2\n", buf.String())
+}
+
+func TestCmdGetSecretKeyNotFound(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+
+	var buf bytes.Buffer
+	err := GetSecret(context.Background(), &buf, "testns1", "test-secret", "token", false, p)
+	assert.ErrorContains(t, err, "key token not found in secret testns1/test-secret, available keys: password, username")
+	assert.Empty(t, buf.String())
+}
+
+func TestCmdGetSecretNotFound(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestSecret(t, p)
+
+	var buf bytes.Buffer
+	err := GetSecret(context.Background(), &buf, "testns1", "missing", "", false, p)
+	assert.ErrorContains(t, err, "failed to read secret testns1/missing")
+	assert.Empty(t, buf.String())
+}
+
+func TestNewGetConfigMapCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewGetConfigMapCommand(p).Command
+
+	assert.Equal(t, "configmap", cmd.Name)
+	assert.Len(t, cmd.Flags, 3)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-n", "quartz", "--name", "quartz-install-state"})
+	assert.NoError(t, err)
+}
+
+func TestCmdGetConfigMap(t *testing.T) {
+	p := defaultTestConfig(t)
+	withStateUpdates(t, p, map[string]string{"key2": "false", "key1": "true"})
+
+	var buf bytes.Buffer
+	err := GetConfigMap(context.Background(), &buf, "quartz", "quartz-install-state", "", p)
+	assert.NoError(t, err)
+	assert.Equal(t, "key1: true\nkey2: false\n", buf.String())
+}
+
+func TestCmdGetConfigMapKey(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	var buf bytes.Buffer
+	err := GetConfigMap(context.Background(), &buf, "quartz", "quartz-install-state", "key1", p)
+	assert.NoError(t, err)
+	assert.Equal(t, "true\n", buf.String())
+
+	err = GetConfigMap(context.Background(), &buf, "quartz", "quartz-install-state", "key3", p)
+	assert.ErrorContains(t, err, "key key3 not found in configmap quartz/quartz-install-state, available keys: key1")
+}
+
+func TestCmdGetConfigMapNotFound(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	var buf bytes.Buffer
+	err := GetConfigMap(context.Background(), &buf, "quartz", "missing", "", p)
+	assert.ErrorContains(t, err, "failed to read configmap quartz/missing")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// ingressInfo is the json shape of a VirtualService listed by IngressList.
+type ingressInfo struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Hosts     []string `json:"hosts"`
+	Gateways  []string `json:"gateways"`
+	MeshOnly  bool     `json:"mesh_only"`
+}
+
+// NewRootIngressCommand creates the root ingress CLI command.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - RootCommandResult containing the root ingress CLI command.
+func NewRootIngressCommand(p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "ingress",
+			Usage: "Ingress (Istio VirtualService) subcommands",
+			Commands: []*cli.Command{
+				{
+					Name:  "list",
+					Usage: "List the VirtualServices in the cluster with their hosts and gateways",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{Name: "json", Usage: "print the VirtualServices as json"},
+						&cli.BoolFlag{Name: "external", Usage: "skip mesh-only VirtualServices not exposed through an ingress gateway"},
+					},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return IngressList(ctx, os.Stdout, ccmd.Bool("json"), ccmd.Bool("external"), p)
+					},
+				},
+			},
+		},
+	}
+}
+
+// IngressList lists the VirtualServices in the cluster, sorted by namespace and name, as a table
+// or as json written to w. Mesh-only VirtualServices are marked, or skipped when externalOnly is set.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - w: The writer the json output is written to.
+//   - asJson: Whether to write json instead of printing a table.
+//   - externalOnly: Whether to skip mesh-only VirtualServices.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the VirtualServices can't be listed, otherwise nil.
+func IngressList(ctx context.Context, w io.Writer, asJson bool, externalOnly bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "ingress:list")
+	defer log.Debug("Completed", "command", "ingress:list")
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	vss, err := kube.ListVirtualServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := []ingressInfo{}
+	for _, vs := range vss {
+		if externalOnly && vs.MeshOnly() {
+			continue
+		}
+		res = append(res, newIngressInfo(vs))
+	}
+
+	if asJson {
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	if len(res) == 0 {
+		util.Msg("No VirtualServices found")
+		return nil
+	}
+
+	var rows [][]string
+	for _, i := range res {
+		exposure := "external"
+		if i.MeshOnly {
+			exposure = "mesh"
+		}
+		rows = append(rows, []string{i.Name, i.Namespace, strings.Join(i.Hosts, ", "), strings.Join(i.Gateways, ", "), exposure})
+	}
+	util.PrintTable([]string{"Name", "Namespace", "Hosts", "Gateways", "Exposure"}, rows)
+
+	return nil
+}
+
+// newIngressInfo converts a VirtualService to its json shape, with empty rather than null lists.
+func newIngressInfo(vs provider.VirtualServiceInfo) ingressInfo {
+	i := ingressInfo{
+		Name:      vs.Name,
+		Namespace: vs.Namespace,
+		Hosts:     vs.Hosts,
+		Gateways:  vs.Gateways,
+		MeshOnly:  vs.MeshOnly(),
+	}
+
+	if i.Hosts == nil {
+		i.Hosts = []string{}
+	}
+	if i.Gateways == nil {
+		i.Gateways = []string{}
+	}
+
+	return i
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// withTestVirtualServices replaces the kubernetes client with one holding an externally exposed
+// and two mesh-only VirtualServices, one bound to the mesh by default without gateways.
+func withTestVirtualServices(t *testing.T, p *CommandParams) {
+	newVs := func(ns string, name string, host string, gateways ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "networking.istio.io/v1beta1",
+				"kind":       "VirtualService",
+				"metadata": map[string]interface{}{
+					"namespace": ns,
+					"name":      name,
+				},
+				"spec": map[string]interface{}{
+					"hosts":    []interface{}{host},
+					"gateways": gateways,
+				},
+			},
+		}
+	}
+
+	api := provider.NewKubernetesApiMock().WithDynamicObjects(
+		newVs("app2", "internal-service", "internal.app2.svc.cluster.local", "mesh"),
+		newVs("app1", "my-service", "my-service.example.com", "istio-system/main-gateway", "mesh"),
+		newVs("app3", "default-service", "default.app3.svc.cluster.local"),
+	)
+
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+}
+
+func TestNewRootIngressCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestVirtualServices(t, p)
+	cmd := NewRootIngressCommand(p).Command
+
+	assert.Equal(t, "ingress", cmd.Name)
+	assert.Len(t, cmd.Commands, 1)
+
+	list := cmd.Commands[0]
+	assert.Equal(t, "list", list.Name)
+	assert.Len(t, list.Flags, 2)
+
+	err := list.Run(context.Background(), []string{list.Name, "--external"})
+	assert.NoError(t, err)
+}
+
+func TestCmdIngressListJson(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestVirtualServices(t, p)
+
+	var buf bytes.Buffer
+	err := IngressList(context.Background(), &buf, true, false, p)
+	assert.NoError(t, err)
+
+	var res []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &res))
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"name":      "my-service",
+			"namespace": "app1",
+			"hosts":     []interface{}{"my-service.example.com"},
+			"gateways":  []interface{}{"istio-system/main-gateway", "mesh"},
+			"mesh_only": false,
+		},
+		{
+			"name":      "internal-service",
+			"namespace": "app2",
+			"hosts":     []interface{}{"internal.app2.svc.cluster.local"},
+			"gateways":  []interface{}{"mesh"},
+			"mesh_only": true,
+		},
+		{
+			"name":      "default-service",
+			"namespace": "app3",
+			"hosts":     []interface{}{"default.app3.svc.cluster.local"},
+			"gateways":  []interface{}{},
+			"mesh_only": true,
+		},
+	}, res)
+}
+
+func TestCmdIngressListJsonExternal(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestVirtualServices(t, p)
+
+	var buf bytes.Buffer
+	err := IngressList(context.Background(), &buf, true, true, p)
+	assert.NoError(t, err)
+
+	var res []ingressInfo
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &res))
+	assert.Len(t, res, 1)
+	assert.Equal(t, "my-service", res[0].Name)
+}
+
+func TestCmdIngressListTable(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestVirtualServices(t, p)
+
+	// the table is written to stdout
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	err := IngressList(context.Background(), io.Discard, false, false, p)
+	w.Close()
+	assert.NoError(t, err)
+
+	out, _ := io.ReadAll(r)
+	assert.Contains(t, string(out), "my-service.example.com")
+	assert.Contains(t, string(out), "external")
+	assert.Contains(t, string(out), "internal-service")
+	assert.Contains(t, string(out), "mesh")
+
+	// no gateways binds the VirtualService to the mesh
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.Contains(line, "default-service") {
+			assert.Contains(t, line, "mesh")
+			assert.NotContains(t, line, "external")
+		}
+	}
+}
+
+func TestCmdIngressListEmpty(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	api := provider.NewKubernetesApiMock().WithDynamicObjects(&unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "networking.istio.io/v1beta1",
+			"kind":       "VirtualService",
+			"metadata": map[string]interface{}{
+				"namespace": "app2",
+				"name":      "internal-service",
+			},
+			"spec": map[string]interface{}{
+				"gateways": []interface{}{"mesh"},
+			},
+		},
+	})
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+
+	// only a mesh-only service, nothing external to list
+	var buf bytes.Buffer
+	err = IngressList(context.Background(), &buf, true, true, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", buf.String())
+
+	// listed when not filtered, with an empty rather than null hosts list
+	buf.Reset()
+	err = IngressList(context.Background(), &buf, true, false, p)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"hosts": []`)
+}
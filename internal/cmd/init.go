@@ -0,0 +1,178 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MetroStar/quartzctl/internal/config"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// NewRootInitCommand creates the "init" root command for the CLI.
+// This command scaffolds a new quartz project, with subcommands for scaffolding other quartz files.
+//
+// Returns:
+//   - RootCommandResult containing the "init" CLI command.
+func NewRootInitCommand() RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "init",
+			Usage: "Scaffold a new quartz project (quartz.yaml, terraform/stages, base)",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "dir", Usage: "Directory to scaffold the project in", Value: "."},
+				&cli.StringFlag{Name: "name", Usage: "Cluster name"},
+				&cli.StringFlag{Name: "zone", Usage: "DNS zone, the domain defaults to <name>.<zone>"},
+				&cli.StringFlag{Name: "domain", Usage: "Full DNS domain, instead of --zone"},
+				&cli.StringFlag{Name: "cloud", Usage: "Cloud provider (aws, local), default aws"},
+				&cli.StringFlag{Name: "region", Usage: "AWS region, required for the aws cloud provider"},
+				&cli.StringFlag{Name: "source-control", Usage: "Source control provider (github, gitea), default github"},
+				&cli.StringFlag{Name: "org", Usage: "Source control organization owning the gitops repositories"},
+				&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Don't prompt for missing values"},
+				&cli.BoolFlag{Name: "force", Usage: "Overwrite quartz.yaml if it already exists"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				t := config.ProjectTemplate{
+					Name:          ccmd.String("name"),
+					Zone:          ccmd.String("zone"),
+					Domain:        ccmd.String("domain"),
+					Cloud:         ccmd.String("cloud"),
+					Region:        ccmd.String("region"),
+					SourceControl: ccmd.String("source-control"),
+					Organization:  ccmd.String("org"),
+				}
+				return InitProject(ccmd.String("dir"), t, ccmd.Bool("yes"), ccmd.Bool("force"), util.PromptInput)
+			},
+			Commands: []*cli.Command{
+				{
+					Name:  "secrets",
+					Usage: "Write a commented secrets file template with placeholder values",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "out",
+							Aliases: []string{"o"},
+							Usage:   "Path to write the secrets file to",
+							Value:   "./secrets.yaml",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Overwrite the file if it already exists",
+						},
+					},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return InitSecrets(ccmd.String("out"), ccmd.Bool("force"))
+					},
+				},
+			},
+		},
+	}
+}
+
+// InitProject scaffolds a new quartz project, prompting for any values not already provided
+// unless yes or the SILENT environment variable is set.
+//
+// Parameters:
+//   - dir: The directory to scaffold the project in.
+//   - t: The project values provided by flags, missing values are prompted for.
+//   - yes: true to skip prompting, e.g. from --yes.
+//   - force: true to overwrite an existing quartz.yaml.
+//   - prompt: The input prompt, util.PromptInput outside of tests.
+//
+// Returns:
+//   - error: An error if a prompt fails, the values are invalid or the write fails, otherwise nil.
+func InitProject(dir string, t config.ProjectTemplate, yes bool, force bool, prompt func(msg string) (string, error)) error {
+	log.Debug("Entering", "command", "init", "dir", dir)
+	defer log.Debug("Completed", "command", "init", "dir", dir)
+
+	if !yes && os.Getenv("SILENT") == "" {
+		var err error
+		t, err = promptProjectTemplate(t, prompt)
+		if err != nil {
+			return fmt.Errorf("aborting, %w", err)
+		}
+	}
+
+	if t.Cloud == "" {
+		t.Cloud = "aws"
+	}
+
+	if t.SourceControl == "" {
+		t.SourceControl = "github"
+	}
+
+	err := config.WriteProjectScaffold(dir, t, force)
+	if err != nil {
+		return err
+	}
+
+	util.Msgf("Project scaffolded in %s, add terraform stages under terraform/stages and run `quartz check`", dir)
+	return nil
+}
+
+// promptProjectTemplate prompts for each project value not already set.
+func promptProjectTemplate(t config.ProjectTemplate, prompt func(msg string) (string, error)) (config.ProjectTemplate, error) {
+	fields := []struct {
+		value *string
+		msg   string
+		skip  func() bool
+	}{
+		{&t.Name, "Cluster name:", nil},
+		{&t.Zone, "DNS zone (e.g. example.com):", func() bool { return t.Domain != "" }},
+		{&t.Cloud, "Cloud provider (aws, local) [aws]:", nil},
+		{&t.Region, "AWS region (e.g. us-east-1):", func() bool { return t.Cloud != "" && t.Cloud != "aws" }},
+		{&t.SourceControl, "Source control provider (github, gitea) [github]:", nil},
+		{&t.Organization, "Source control organization:", nil},
+	}
+
+	for _, f := range fields {
+		if *f.value != "" || (f.skip != nil && f.skip()) {
+			continue
+		}
+
+		r, err := prompt(f.msg)
+		if err != nil {
+			return t, err
+		}
+
+		*f.value = r
+	}
+
+	return t, nil
+}
+
+// InitSecrets writes the secrets file template to the specified path.
+//
+// Parameters:
+//   - path: The path to write the secrets file to.
+//   - force: true to overwrite an existing file.
+//
+// Returns:
+//   - error: An error if the file already exists and force is not set, or the write fails, otherwise nil.
+func InitSecrets(path string, force bool) error {
+	log.Debug("Entering", "command", "init:secrets", "path", path, "force", force)
+	defer log.Debug("Completed", "command", "init:secrets", "path", path, "force", force)
+
+	err := config.WriteSecretsTemplate(path, force)
+	if err != nil {
+		return err
+	}
+
+	util.Msgf("Secrets template written to %s, uncomment the values to set and pass it with --secrets", path)
+	return nil
+}
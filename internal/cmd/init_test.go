@@ -0,0 +1,119 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRootInitCommand(t *testing.T) {
+	cmd := NewRootInitCommand().Command
+
+	assert.Equal(t, "init", cmd.Name)
+	assert.Len(t, cmd.Flags, 10)
+	assert.Len(t, cmd.Commands, 1)
+	assert.Equal(t, "secrets", cmd.Commands[0].Name)
+	assert.Len(t, cmd.Commands[0].Flags, 2)
+}
+
+func TestCmdInitSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+
+	err := InitSecrets(path, false)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, config.SecretsTemplate, string(b))
+
+	err = InitSecrets(path, false)
+	assert.ErrorContains(t, err, "use --force to overwrite")
+
+	err = InitSecrets(path, true)
+	assert.NoError(t, err)
+}
+
+func TestCmdInitProjectPrompts(t *testing.T) {
+	t.Setenv("SILENT", "")
+	dir := t.TempDir()
+
+	answers := map[string]string{
+		"Cluster name:":                                     "mytest",
+		"DNS zone (e.g. example.com):":                      "example.com",
+		"Cloud provider (aws, local) [aws]:":                "local",
+		"Source control provider (github, gitea) [github]:": "",
+		"Source control organization:":                      "myorg",
+	}
+	var asked []string
+	prompt := func(msg string) (string, error) {
+		asked = append(asked, msg)
+		a, ok := answers[msg]
+		if !ok {
+			return "", fmt.Errorf("unexpected prompt %s", msg)
+		}
+		return a, nil
+	}
+
+	err := InitProject(dir, config.ProjectTemplate{}, false, false, prompt)
+	assert.NoError(t, err)
+	assert.Len(t, asked, 5)
+
+	s, err := config.Load(context.Background(), filepath.Join(dir, "quartz.yaml"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "mytest", s.Config.Name)
+	assert.Equal(t, "mytest.example.com", s.Config.Dns.Domain)
+	assert.Equal(t, "local", s.Config.Providers.Cloud)
+	assert.Equal(t, "github", s.Config.Providers.SourceControl)
+	assert.Equal(t, "myorg", s.Config.Gitops.Apps.Organization)
+	assert.DirExists(t, filepath.Join(dir, "terraform", "stages"))
+	assert.DirExists(t, filepath.Join(dir, "base"))
+}
+
+func TestCmdInitProjectYes(t *testing.T) {
+	dir := t.TempDir()
+	prompt := func(msg string) (string, error) {
+		return "", fmt.Errorf("unexpected prompt %s", msg)
+	}
+
+	tmpl := config.ProjectTemplate{Name: "mytest", Domain: "mytest.example.com", Cloud: "local"}
+	err := InitProject(dir, tmpl, true, false, prompt)
+	assert.NoError(t, err)
+
+	s, err := config.Load(context.Background(), filepath.Join(dir, "quartz.yaml"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", s.Config.Dns.Zone)
+
+	// missing values fail validation instead of prompting
+	err = InitProject(t.TempDir(), config.ProjectTemplate{}, true, false, prompt)
+	assert.ErrorContains(t, err, "name required")
+}
+
+func TestCmdInitProjectSilent(t *testing.T) {
+	t.Setenv("SILENT", "true")
+	prompt := func(msg string) (string, error) {
+		return "", fmt.Errorf("unexpected prompt %s", msg)
+	}
+
+	tmpl := config.ProjectTemplate{Name: "mytest", Zone: "example.com", Cloud: "local"}
+	err := InitProject(t.TempDir(), tmpl, false, false, prompt)
+	assert.NoError(t, err)
+}
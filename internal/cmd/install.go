@@ -16,10 +16,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/urfave/cli/v3"
 )
@@ -37,8 +42,26 @@ func NewRootInstallCommand(p *CommandParams) RootCommandResult {
 		Command: &cli.Command{
 			Name:  "install",
 			Usage: "Perform a full install/update of the system",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{Name: "timeout", Usage: "cancel the install if it runs longer than this duration, e.g. 90m (0 for no limit)"},
+				&cli.StringSliceFlag{Name: "only", Usage: "only install the given stage, repeatable (prompts for the stages when omitted on a terminal)"},
+				&cli.DurationFlag{Name: "stage-timeout", Usage: "fail a stage if its apply runs longer than this duration, e.g. 45m (default terraform.stage_timeout_seconds, 0 for no limit)"},
+				&cli.StringFlag{Name: "metrics", Usage: "write phase timings to a file, json for a .json path, otherwise Prometheus textfile format"},
+				&cli.BoolFlag{Name: "resume", Usage: "skip the stages applied by the previous install, starting from the first incomplete stage"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
-				err := Install(ctx, p)
+				p.SetOnly(ccmd.StringSlice("only"))
+				p.SetResume(ccmd.Bool("resume"))
+				if ccmd.IsSet("stage-timeout") {
+					// an explicit 0 disables the terraform.stage_timeout_seconds limit
+					p.SetStageTimeout(ccmd.Duration("stage-timeout"))
+				}
+				p.SetMetricsPath(ccmd.String("metrics"))
+
+				err := RunWithTimeout(ctx, "install", ccmd.Duration("timeout"), func(ctx context.Context) error {
+					return Install(ctx, p)
+				})
+				notifyCompletion(ctx, "install", err, p)
 				if err != nil {
 					return err
 				}
@@ -64,11 +87,30 @@ func NewRootCleanCommand(p *CommandParams) RootCommandResult {
 			Usage: "Perform a full cleanup/teardown of the system",
 			Flags: []cli.Flag{
 				&cli.BoolFlag{Name: "refresh", Aliases: []string{"r"}, Usage: "refresh", Value: false},
+				&cli.DurationFlag{Name: "timeout", Usage: "cancel the cleanup if it runs longer than this duration, e.g. 90m (0 for no limit)"},
+				&cli.DurationFlag{Name: "stage-timeout", Usage: "fail a stage if its destroy runs longer than this duration, e.g. 45m (default terraform.stage_timeout_seconds, 0 for no limit)"},
+				&cli.BoolFlag{Name: "all-webhooks", Usage: "remove every validating and mutating webhook configuration, not only those matching cleanup.webhooks"},
+				&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "skip the confirmation prompts, including the destroy plan"},
+				&cli.StringFlag{Name: "metrics", Usage: "write phase timings to a file, json for a .json path, otherwise Prometheus textfile format"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				refresh := ccmd.Bool("refresh")
-
-				err := Clean(ctx, refresh, p)
+				p.SetAllWebhooks(ccmd.Bool("all-webhooks"))
+				if ccmd.IsSet("stage-timeout") {
+					// an explicit 0 disables the terraform.stage_timeout_seconds limit
+					p.SetStageTimeout(ccmd.Duration("stage-timeout"))
+				}
+				p.SetAssumeYes(ccmd.Bool("yes"))
+				p.SetMetricsPath(ccmd.String("metrics"))
+
+				err := RunWithTimeout(ctx, "clean", ccmd.Duration("timeout"), func(ctx context.Context) error {
+					return Clean(ctx, refresh, p)
+				})
+				notifyCompletion(ctx, "clean", err, p)
+				if errors.Is(err, errAborted) {
+					// the user declined, nothing was destroyed
+					return nil
+				}
 				if err != nil {
 					return err
 				}
@@ -79,6 +121,63 @@ func NewRootCleanCommand(p *CommandParams) RootCommandResult {
 	}
 }
 
+// RunWithTimeout runs the command function with a context cancelled after the given timeout.
+// In-flight operations observing the context are cancelled once the deadline passes.
+//
+// Parameters:
+//   - ctx: The parent context for the operation.
+//   - command: The command name used in the timeout error.
+//   - timeout: The maximum duration for the command, 0 or less disables the limit.
+//   - f: The command function to run with the derived context.
+//
+// Returns:
+//   - error: A timeout error wrapping the command error if the deadline was exceeded, otherwise the command error.
+func RunWithTimeout(ctx context.Context, command string, timeout time.Duration, f func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return f(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := f(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %v, %w", command, timeout, err)
+	}
+
+	return err
+}
+
+// RunStageWithTimeout runs a stage operation with a context cancelled after the per stage timeout,
+// so a wedged stage fails on its own instead of hanging the whole install or clean.
+//
+// Parameters:
+//   - ctx: The parent context for the operation.
+//   - stage: The stage ID the operation runs against.
+//   - op: The operation name used in the timeout error, e.g. apply or destroy.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//   - f: The stage operation to run with the derived context.
+//
+// Returns:
+//   - error: A timeout error naming the stage if the stage deadline was exceeded, otherwise the operation error.
+//     An error from the parent context, e.g. the command --timeout, is returned as is.
+func RunStageWithTimeout(ctx context.Context, stage string, op string, p *CommandParams, f func(ctx context.Context) error) error {
+	timeout := p.StageTimeout()
+	if timeout <= 0 {
+		return f(ctx)
+	}
+
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := f(stageCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("stage %s %s timed out after %v, %w", stage, op, timeout, err)
+	}
+
+	return err
+}
+
 // Install sets up the Quartz environment by initializing and applying all stages.
 // This includes preparing the account, creating the Terraform backend, and applying configurations.
 //
@@ -100,43 +199,266 @@ func Install(ctx context.Context, p *CommandParams) error {
 		return err
 	}
 
+	stages, err := InstallStages(p)
+	if err != nil {
+		return err
+	}
+
+	err = PreflightTmp(p)
+	if err != nil {
+		return err
+	}
+
+	installStart := time.Now()
+	stageTiming := make(map[string]time.Duration)
+	defer writePhaseMetrics("install", stageTiming, installStart, p)
+
+	prepareStart := time.Now()
 	err = PrepareAccount(ctx, p)
+	stageTiming["prepare-account"] = time.Since(prepareStart)
 	if err != nil {
 		return err
 	}
 
+	backendStart := time.Now()
 	err = TfCreateBackend(ctx, p)
+	stageTiming["create-backend"] = time.Since(backendStart)
+	if err != nil {
+		return err
+	}
+
+	// the lock lives in the state backend, so it can only be taken once the backend exists
+	release, err := AcquireRunLock(ctx, "install", p)
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	for _, s := range p.Settings().Config.StagesOrdered() {
+	if p.Resume() {
+		stages, err = ResumeStages(ctx, stages, p)
+		if err != nil {
+			return err
+		}
+	} else if len(stages) == len(p.Settings().Config.StagesOrdered()) {
+		// a full install starts over, so a later --resume doesn't skip stages this run didn't apply,
+		// partial runs (--only or the stage selection) only add to the recorded stages
+		recordAppliedStage(ctx, "", p)
+	}
+
+	for _, s := range stages {
+		stageStart := time.Now()
 		err = TfInit(ctx, s.Id, p)
 		if err != nil {
+			stageTiming["apply-"+s.Id] = time.Since(stageStart)
 			return err
 		}
 
-		err = TfApply(ctx, s.Id, p)
+		err = RunStageWithTimeout(ctx, s.Id, "apply", p, func(ctx context.Context) error {
+			return TfApply(ctx, s.Id, p)
+		})
+		stageTiming["apply-"+s.Id] = time.Since(stageStart)
 		if err != nil {
 			return err
 		}
+
+		recordAppliedStage(ctx, s.Id, p)
 	}
 
+	// the cluster is created by the stages, so administrators can only be granted access afterward,
+	// a cluster that doesn't exist yet (e.g. an --only run of earlier stages) is skipped by the provider
+	adminStart := time.Now()
+	err = ReconcileAdministrators(ctx, p)
+	stageTiming["reconcile-administrators"] = time.Since(adminStart)
+	if err != nil {
+		return err
+	}
+
+	refreshStart := time.Now()
 	err = RefreshSecrets(ctx, p)
+	stageTiming["refresh-secrets"] = time.Since(refreshStart)
 	if err != nil {
 		return err
 	}
 
-	err = ClusterInfo(ctx, p)
+	infoStart := time.Now()
+	err = ClusterInfo(ctx, p, provider.ClusterInfoOpts{})
+	stageTiming["cluster-info"] = time.Since(infoStart)
 	if err != nil {
 		return err
 	}
 
+	err = HealthSummary(ctx, p)
+	if err != nil {
+		log.Warn("Failed to retrieve cluster health", "err", err)
+	}
+
 	return nil
 }
 
+// resumeStateKey is the install state key recording the stages applied by install, comma separated.
+const resumeStateKey = "applied_stages"
+
+// ResumeStages drops the stages applied by previous installs, as recorded in the install state
+// ConfigMap. All stages are kept when nothing is recorded or the cluster can't be reached,
+// e.g. install failed before the cluster was created.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - stages: The stages to install, in install order.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - []schema.StageConfig: The stages still to install, in install order.
+//   - error: A ConfigError if the install state is disabled or a recorded stage is no longer configured, otherwise nil.
+func ResumeStages(ctx context.Context, stages []schema.StageConfig, p *CommandParams) ([]schema.StageConfig, error) {
+	if !p.Settings().Config.State.Enabled {
+		return nil, util.NewConfigErrorf("--resume requires the install state, set state.enabled")
+	}
+
+	applied := appliedStages(ctx, p)
+	if len(applied) == 0 {
+		util.Msg("No install progress recorded, installing all stages")
+		return stages, nil
+	}
+
+	for _, id := range applied {
+		if _, found := p.Settings().Config.Stages[id]; !found {
+			return nil, util.NewConfigErrorf("applied stage %s is no longer configured, run install without --resume", id)
+		}
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(stages), func(s schema.StageConfig) bool {
+		return slices.Contains(applied, s.Id)
+	})
+
+	util.Msgf("Resuming install, skipping %d applied stages", len(stages)-len(remaining))
+	return remaining, nil
+}
+
+// appliedStages returns the stages recorded as applied in the install state ConfigMap.
+// Best effort, failures are only logged.
+func appliedStages(ctx context.Context, p *CommandParams) []string {
+	cfg := p.Settings().Config.State
+	if !cfg.Enabled {
+		return nil
+	}
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		log.Warn("Failed to read install progress", "err", err)
+		return nil
+	}
+
+	data, err := kube.GetConfigMapValue(ctx, cfg.ConfigMapNamespace, cfg.ConfigMapName)
+	if err != nil {
+		log.Warn("Failed to read install progress", "err", err)
+		return nil
+	}
+
+	if data[resumeStateKey] == "" {
+		return nil
+	}
+
+	return strings.Split(data[resumeStateKey], ",")
+}
+
+// recordAppliedStage adds the stage to the stages applied by install in the install state
+// ConfigMap, empty to clear them. Best effort, failures are only logged.
+func recordAppliedStage(ctx context.Context, stage string, p *CommandParams) {
+	cfg := p.Settings().Config.State
+	if !cfg.Enabled {
+		return
+	}
+
+	var applied []string
+	if stage != "" {
+		applied = appliedStages(ctx, p)
+		if !slices.Contains(applied, stage) {
+			applied = append(applied, stage)
+		}
+	}
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err == nil {
+		err = kube.SetConfigMapValue(ctx, cfg.ConfigMapNamespace, cfg.ConfigMapName, resumeStateKey, strings.Join(applied, ","))
+	}
+	if err != nil {
+		log.Warn("Failed to record install progress", "stage", stage, "err", err)
+	}
+}
+
+// installSelectStages and installInteractive are the stage selection prompt and terminal check,
+// replaced in tests.
+var (
+	installSelectStages = util.PromptMultiSelect
+	installInteractive  = util.IsInteractive
+)
+
+// InstallStages resolves the ordered stages to install. Stages given with --only are installed,
+// otherwise on a terminal the stages are picked from a multiselect, all selected by default.
+// All stages are installed under SILENT or without a terminal.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - []schema.StageConfig: The stages to install, in install order.
+//   - error: An error if an --only stage is unknown or manual, or nothing is selected, otherwise nil.
+func InstallStages(p *CommandParams) ([]schema.StageConfig, error) {
+	ordered := p.Settings().Config.StagesOrdered()
+
+	only := p.Only()
+	if len(only) == 0 {
+		if !installInteractive() {
+			return ordered, nil
+		}
+
+		ids := make([]string, len(ordered))
+		for i, s := range ordered {
+			ids[i] = s.Id
+		}
+
+		selected, err := installSelectStages("Select the stages to install", ids)
+		if err != nil {
+			return nil, fmt.Errorf("aborting, %w", err)
+		}
+
+		only = selected
+	}
+
+	for _, id := range only {
+		if err := ValidateStage(id, p); err != nil {
+			return nil, err
+		}
+
+		if p.Settings().Config.Stages[id].Manual {
+			return nil, util.NewConfigErrorf("stage %s is manual and not part of install, use `quartz terraform apply --stage %s`", id, id)
+		}
+	}
+
+	stages := filterStages(ordered, only)
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("aborting, no stages selected")
+	}
+
+	return stages, nil
+}
+
+// filterStages returns the stages whose id is in ids, keeping the order of stages.
+func filterStages(stages []schema.StageConfig, ids []string) []schema.StageConfig {
+	var r []schema.StageConfig
+	for _, s := range stages {
+		if slices.Contains(ids, s.Id) {
+			r = append(r, s)
+		}
+	}
+
+	return r
+}
+
 // Clean tears down the Quartz environment, including all managed resources and data.
 // This includes refreshing Terraform states, destroying resources, and cleaning up.
+// The destroy plan is confirmed before any resources are removed, declining either prompt returns errAborted.
 //
 // Parameters:
 //   - ctx: The context for the operation.
@@ -144,7 +466,7 @@ func Install(ctx context.Context, p *CommandParams) error {
 //   - p: *CommandParams containing configuration and runtime parameters.
 //
 // Returns:
-//   - error: An error if the cleanup fails, otherwise nil.
+//   - error: errAborted if the user declined, an error if the cleanup fails, otherwise nil.
 func Clean(ctx context.Context, refresh bool, p *CommandParams) error {
 	log.Debug("Entering", "command", "clean")
 	defer log.Debug("Completed", "command", "clean")
@@ -153,19 +475,61 @@ func Clean(ctx context.Context, refresh bool, p *CommandParams) error {
 
 	err := Confirm(ctx, "Are you sure? This action will destroy the Quartz cluster, including all managed resources and data.", p)
 	if err != nil {
-		// just means the user said no
-		return nil
+		return err
+	}
+
+	release, err := AcquireRunLock(ctx, "clean", p)
+	if err != nil {
+		return err
 	}
+	defer release()
 
 	cleanupStart := time.Now()
 	stageTiming := make(map[string]time.Duration)
+	defer writePhaseMetrics("clean", stageTiming, cleanupStart, p)
+
+	stages := p.Settings().Config.StagesOrdered()
+
+	// refresh each stage in case local state is out of sync
+	initStart := time.Now()
+	for _, s := range stages {
+		err = TfInit(ctx, s.Id, p)
+		if err != nil {
+			return err
+		}
+		if refresh {
+			err = TfRefresh(ctx, s.Id, p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	stageTiming["init-refresh"] = time.Since(initStart)
+
+	// destroy stages in reverse order with retry logic for transient failures
+	slices.Reverse(stages)
+
+	var ids []string
+	for _, s := range stages {
+		ids = append(ids, s.Id)
+	}
+	// resolve and confirm the plan before anything is removed
+	err = ConfirmDestroyPlan(ctx, ids, p)
+	if errors.Is(err, errAborted) {
+		// declined, nothing was destroyed so there's no timing to report
+		return err
+	}
+	if err != nil {
+		printCleanupTimingSummary(stageTiming, time.Since(cleanupStart))
+		return err
+	}
 
 	// Phase 1: Always clean up Kubernetes blocking resources first
 	// This removes webhooks, API services, and finalizers that would block Helm uninstalls.
 	// We do this BEFORE any AWS cleanup to ensure the cluster is still healthy.
 	util.Hdr("Kubernetes Cleanup (preparation)")
 	k8sStart := time.Now()
-	cleanupKubernetesBlockers(ctx)
+	cleanupKubernetesBlockers(ctx, p)
 	stageTiming["k8s-cleanup"] = time.Since(k8sStart)
 
 	// Phase 2: Check for blocking AWS resources and clean up if needed
@@ -186,29 +550,18 @@ func Clean(ctx context.Context, refresh bool, p *CommandParams) error {
 		util.Msg("No blocking resources detected, proceeding with Terraform destroy")
 	}
 
-	stages := p.Settings().Config.StagesOrdered()
-
-	// refresh each stage in case local state is out of sync
-	initStart := time.Now()
 	for _, s := range stages {
-		err = TfInit(ctx, s.Id, p)
+		stageStart := time.Now()
+		err = WaitPreDestroy(ctx, s.Id, p)
 		if err != nil {
+			stageTiming["destroy-"+s.Id] = time.Since(stageStart)
+			printCleanupTimingSummary(stageTiming, time.Since(cleanupStart))
 			return err
 		}
-		if refresh {
-			err = TfRefresh(ctx, s.Id, p)
-			if err != nil {
-				return err
-			}
-		}
-	}
-	stageTiming["init-refresh"] = time.Since(initStart)
 
-	// destroy stages in reverse order with retry logic for transient failures
-	slices.Reverse(stages)
-	for _, s := range stages {
-		stageStart := time.Now()
-		err = TfDestroyWithRetry(ctx, s.Id, p, 3, 60*time.Second)
+		err = RunStageWithTimeout(ctx, s.Id, "destroy", p, func(ctx context.Context) error {
+			return TfDestroyWithRetry(ctx, s.Id, p, 3, 60*time.Second)
+		})
 		stageTiming["destroy-"+s.Id] = time.Since(stageStart)
 		if err != nil {
 			printCleanupTimingSummary(stageTiming, time.Since(cleanupStart))
@@ -232,6 +585,83 @@ func Clean(ctx context.Context, refresh bool, p *CommandParams) error {
 	return err
 }
 
+// runLockReleaseTimeout limits how long releasing the run lock may delay the command exit.
+const runLockReleaseTimeout = 30 * time.Second
+
+// AcquireRunLock acquires the cluster-wide lock preventing concurrent quartz runs against the same cluster.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - operation: The name of the operation taking the lock, recorded for other runs.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - func(): Releases the lock, even once ctx is cancelled, logging any failure.
+//   - error: An error if the lock is held by another run or could not be acquired, otherwise nil.
+func AcquireRunLock(ctx context.Context, operation string, p *CommandParams) (func(), error) {
+	cp, err := p.Provider().Cloud(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cp.AcquireRunLock(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		// the command context may already be cancelled, e.g. by --timeout, and the lock
+		// must still be released for the next run
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runLockReleaseTimeout)
+		defer cancel()
+
+		if err := cp.ReleaseRunLock(ctx); err != nil {
+			log.Warn("Failed to release run lock", "operation", operation, "err", err)
+		}
+	}, nil
+}
+
+// WaitPreDestroy blocks until all Kubernetes resources listed in the stage's
+// pre_destroy_wait config have been fully removed, including pending finalizers.
+// This gives controllers hosted by the stage a chance to finish their cleanup
+// before the stage itself is torn down.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - stage: The stage ID about to be destroyed.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if a resource is still present after its timeout, otherwise nil.
+func WaitPreDestroy(ctx context.Context, stage string, p *CommandParams) error {
+	waits := p.Settings().Config.Stages[stage].PreDestroyWait
+	if len(waits) == 0 {
+		return nil
+	}
+
+	k8s, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range waits {
+		kind, err := k8s.LookupKind(ctx, w.Kind)
+		if err != nil {
+			// the resource type is already gone along with any instances of it
+			log.Info("Resource kind not found, skipping pre-destroy wait", "stage", stage, "kind", w.Kind, "err", err)
+			continue
+		}
+
+		util.Msgf("Waiting for %s %s/%s to be removed before destroying stage %s", w.Kind, w.Namespace, w.Name, stage)
+		err = k8s.WaitResourcesDeleted(ctx, kind, w.Namespace, w.Name, w.Timeout, w.WaitSeconds)
+		if err != nil {
+			return fmt.Errorf("pre-destroy wait failed for stage %s, %w", stage, err)
+		}
+	}
+
+	return nil
+}
+
 // printCleanupTimingSummary outputs timing information for each phase of the cleanup.
 func printCleanupTimingSummary(stageTiming map[string]time.Duration, totalDuration time.Duration) {
 	util.Hdr("Cleanup Timing Summary")
@@ -258,55 +688,51 @@ func printCleanupTimingSummary(stageTiming map[string]time.Duration, totalDurati
 // Returns:
 //   - error: An error if all attempts fail, otherwise nil.
 func TfDestroyWithRetry(ctx context.Context, stage string, p *CommandParams, maxRetries int, retryDelay time.Duration) error {
-	var lastErr error
 	awsCleanupRun := false
 	k8sCleanupRun := false
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			log.Info("Retrying destroy after transient failure", "stage", stage, "attempt", attempt, "maxRetries", maxRetries)
-			util.Msgf("Waiting %v before retry %d/%d for stage %s...", retryDelay, attempt, maxRetries, stage)
-			time.Sleep(retryDelay)
-		}
-
-		lastErr = TfDestroy(ctx, stage, p)
-		if lastErr == nil {
-			return nil
-		}
-
-		// Check if this is a retryable error
-		errStr := lastErr.Error()
-		if !isRetryableDestroyError(errStr) {
-			log.Warn("Non-retryable error during destroy", "stage", stage, "error", lastErr)
-			return lastErr
-		}
-
-		log.Warn("Retryable error during destroy", "stage", stage, "attempt", attempt, "error", lastErr)
-
-		// If it's a Helm release error (cluster unreachable, webhooks, etc.),
-		// try cleaning up K8s blocking resources first
-		if isHelmReleaseError(errStr) && !k8sCleanupRun {
-			util.Hdr("Running Kubernetes Cleanup (retry)")
-			util.Msg("Terraform encountered a Helm/Kubernetes error. Cleaning up blocking resources...")
-			cleanupKubernetesBlockers(ctx)
-			k8sCleanupRun = true
-		}
+	return util.Retry(ctx, util.RetryOpts{
+		MaxAttempts: maxRetries + 1,
+		BaseDelay:   retryDelay,
+		MaxDelay:    retryDelay,
+		Retryable: func(err error) bool {
+			if !isRetryableDestroyError(err.Error()) {
+				log.Warn("Non-retryable error during destroy", "stage", stage, "error", err)
+				return false
+			}
+			return true
+		},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			log.Warn("Retryable error during destroy", "stage", stage, "attempt", attempt, "error", err)
+
+			// If it's a Helm release error (cluster unreachable, webhooks, etc.),
+			// try cleaning up K8s blocking resources first
+			if isHelmReleaseError(err.Error()) && !k8sCleanupRun {
+				util.Hdr("Running Kubernetes Cleanup (retry)")
+				util.Msg("Terraform encountered a Helm/Kubernetes error. Cleaning up blocking resources...")
+				cleanupKubernetesBlockers(ctx, p)
+				k8sCleanupRun = true
+			}
 
-		// Run AWS CLI cleanup as fallback (only once)
-		// This handles orphaned EC2 instances, ENIs, and security groups that may be
-		// blocking Terraform destroy. The primary cleanup runs via Helm pre-delete hooks,
-		// but those may fail if the cluster is unreachable or has other issues.
-		if !awsCleanupRun {
-			util.Hdr("Running AWS Resource Cleanup (fallback)")
-			util.Msg("Terraform encountered a dependency error. Running AWS CLI cleanup to remove orphaned resources...")
-			if cleanupErr := ForceAWSCleanup(ctx, p); cleanupErr != nil {
-				log.Warn("AWS cleanup encountered errors (continuing)", "error", cleanupErr)
+			// Run AWS CLI cleanup as fallback (only once)
+			// This handles orphaned EC2 instances, ENIs, and security groups that may be
+			// blocking Terraform destroy. The primary cleanup runs via Helm pre-delete hooks,
+			// but those may fail if the cluster is unreachable or has other issues.
+			if !awsCleanupRun {
+				util.Hdr("Running AWS Resource Cleanup (fallback)")
+				util.Msg("Terraform encountered a dependency error. Running AWS CLI cleanup to remove orphaned resources...")
+				if cleanupErr := ForceAWSCleanup(ctx, p); cleanupErr != nil {
+					log.Warn("AWS cleanup encountered errors (continuing)", "error", cleanupErr)
+				}
+				awsCleanupRun = true
 			}
-			awsCleanupRun = true
-		}
-	}
 
-	return lastErr
+			log.Info("Retrying destroy after transient failure", "stage", stage, "attempt", attempt, "maxRetries", maxRetries)
+			util.Msgf("Waiting %v before retry %d/%d for stage %s...", delay, attempt, maxRetries, stage)
+		},
+	}, func(ctx context.Context) error {
+		return TfDestroy(ctx, stage, p)
+	})
 }
 
 // isRetryableDestroyError checks if an error is likely transient and worth retrying.
@@ -16,8 +16,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v3"
 )
@@ -28,6 +34,22 @@ func TestNewRootInstallCommand(t *testing.T) {
 
 	assert.Equal(t, "install", cmd.Name)
 	assert.Equal(t, "Perform a full install/update of the system", cmd.Usage)
+	assert.Len(t, cmd.Flags, 5)
+
+	timeoutFlag := cmd.Flags[0].(*cli.DurationFlag)
+	assert.Equal(t, "timeout", timeoutFlag.Name)
+
+	onlyFlag := cmd.Flags[1].(*cli.StringSliceFlag)
+	assert.Equal(t, "only", onlyFlag.Name)
+
+	stageTimeoutFlag := cmd.Flags[2].(*cli.DurationFlag)
+	assert.Equal(t, "stage-timeout", stageTimeoutFlag.Name)
+
+	metricsFlag := cmd.Flags[3].(*cli.StringFlag)
+	assert.Equal(t, "metrics", metricsFlag.Name)
+
+	resumeFlag := cmd.Flags[4].(*cli.BoolFlag)
+	assert.Equal(t, "resume", resumeFlag.Name)
 
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
@@ -39,11 +61,26 @@ func TestNewRootCleanCommand(t *testing.T) {
 
 	assert.Equal(t, "clean", cmd.Name)
 	assert.Equal(t, "Perform a full cleanup/teardown of the system", cmd.Usage)
-	assert.Len(t, cmd.Flags, 1)
+	assert.Len(t, cmd.Flags, 6)
 
 	flag := cmd.Flags[0].(*cli.BoolFlag)
 	assert.Equal(t, "refresh", flag.Name)
 
+	timeoutFlag := cmd.Flags[1].(*cli.DurationFlag)
+	assert.Equal(t, "timeout", timeoutFlag.Name)
+
+	stageTimeoutFlag := cmd.Flags[2].(*cli.DurationFlag)
+	assert.Equal(t, "stage-timeout", stageTimeoutFlag.Name)
+
+	webhooksFlag := cmd.Flags[3].(*cli.BoolFlag)
+	assert.Equal(t, "all-webhooks", webhooksFlag.Name)
+
+	yesFlag := cmd.Flags[4].(*cli.BoolFlag)
+	assert.Equal(t, "yes", yesFlag.Name)
+
+	metricsFlag := cmd.Flags[5].(*cli.StringFlag)
+	assert.Equal(t, "metrics", metricsFlag.Name)
+
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
 }
@@ -57,6 +94,90 @@ func TestCmdInstall(t *testing.T) {
 	}
 }
 
+// mockInstallSelection replaces the install stage prompt, returning a func to restore it.
+func mockInstallSelection(interactive bool, selected []string, err error) func() {
+	origSelect, origInteractive := installSelectStages, installInteractive
+	installInteractive = func() bool { return interactive }
+	installSelectStages = func(msg string, options []string) ([]string, error) {
+		return selected, err
+	}
+
+	return func() {
+		installSelectStages, installInteractive = origSelect, origInteractive
+	}
+}
+
+// addTestInstallStages adds stages around the test config's first stage, one of them manual.
+func addTestInstallStages(p *CommandParams) {
+	stages := p.Settings().Config.Stages
+	stages["second"] = schema.StageConfig{Id: "second", Order: stages[testStage].Order + 1}
+	stages["third"] = schema.StageConfig{Id: "third", Order: stages[testStage].Order + 2}
+	stages["manual"] = schema.StageConfig{Id: "manual", Manual: true}
+}
+
+func stageIds(stages []schema.StageConfig) []string {
+	var r []string
+	for _, s := range stages {
+		r = append(r, s.Id)
+	}
+	return r
+}
+
+func TestCmdFilterStages(t *testing.T) {
+	stages := []schema.StageConfig{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+
+	assert.Equal(t, []string{"a", "c"}, stageIds(filterStages(stages, []string{"c", "a"})))
+	assert.Equal(t, []string{"a", "b", "c"}, stageIds(filterStages(stages, []string{"a", "b", "c"})))
+	assert.Empty(t, filterStages(stages, nil))
+}
+
+func TestCmdInstallStagesNonInteractive(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testStage, "second", "third"}, stageIds(stages))
+}
+
+func TestCmdInstallStagesInteractive(t *testing.T) {
+	defer mockInstallSelection(true, []string{"third", testStage}, nil)()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testStage, "third"}, stageIds(stages))
+}
+
+func TestCmdInstallStagesInteractiveNone(t *testing.T) {
+	defer mockInstallSelection(true, nil, nil)()
+	p := defaultTestConfig(t)
+
+	_, err := InstallStages(p)
+	assert.ErrorContains(t, err, "no stages selected")
+}
+
+func TestCmdInstallStagesOnly(t *testing.T) {
+	defer mockInstallSelection(true, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+
+	p.SetOnly([]string{"second"})
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second"}, stageIds(stages))
+
+	p.SetOnly([]string{"secnd"})
+	_, err = InstallStages(p)
+	assert.ErrorContains(t, err, "did you mean second")
+
+	p.SetOnly([]string{"manual"})
+	_, err = InstallStages(p)
+	assert.ErrorContains(t, err, "stage manual is manual")
+}
+
 func TestCmdClean(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -66,6 +187,310 @@ func TestCmdClean(t *testing.T) {
 	}
 }
 
+func TestCmdRunWithTimeout(t *testing.T) {
+	// fake slow stage which only finishes when its context is cancelled
+	slowStage := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Minute):
+			return nil
+		}
+	}
+
+	start := time.Now()
+	err := RunWithTimeout(context.Background(), "install", 10*time.Millisecond, slowStage)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "install timed out after 10ms")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestCmdRunWithTimeoutDisabled(t *testing.T) {
+	err := RunWithTimeout(context.Background(), "clean", 0, func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// errors unrelated to the deadline are returned unchanged
+	expected := errors.New("stage failed")
+	err = RunWithTimeout(context.Background(), "clean", time.Minute, func(ctx context.Context) error {
+		return expected
+	})
+	assert.Equal(t, expected, err)
+}
+
+func TestCmdResumeStages(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+	withStateUpdates(t, p, map[string]string{"key1": "true", resumeStateKey: testStage + ",second"})
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+
+	remaining, err := ResumeStages(context.Background(), stages, p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"third"}, stageIds(remaining))
+
+	// the install stages are left as is
+	assert.Equal(t, []string{testStage, "second", "third"}, stageIds(stages))
+}
+
+func TestCmdResumeStagesOnly(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+	withStateUpdates(t, p, map[string]string{resumeStateKey: testStage})
+
+	p.SetOnly([]string{testStage, "third"})
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+
+	remaining, err := ResumeStages(context.Background(), stages, p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"third"}, stageIds(remaining))
+}
+
+func TestCmdResumeStagesPartialRun(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+
+	// an earlier --only run applied just the third stage, the ones before it were never applied
+	withStateUpdates(t, p, map[string]string{resumeStateKey: "third"})
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+
+	remaining, err := ResumeStages(context.Background(), stages, p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testStage, "second"}, stageIds(remaining))
+}
+
+func TestCmdResumeStagesNotRecorded(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+	withStateUpdates(t, p, map[string]string{"key1": "true"})
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+
+	remaining, err := ResumeStages(context.Background(), stages, p)
+	assert.NoError(t, err)
+	assert.Equal(t, stageIds(stages), stageIds(remaining))
+}
+
+func TestCmdResumeStagesChanged(t *testing.T) {
+	defer mockInstallSelection(false, nil, fmt.Errorf("unexpected prompt"))()
+	p := defaultTestConfig(t)
+	addTestInstallStages(p)
+	withStateUpdates(t, p, map[string]string{resumeStateKey: "removed"})
+
+	stages, err := InstallStages(p)
+	assert.NoError(t, err)
+
+	_, err = ResumeStages(context.Background(), stages, p)
+	var cfgErr util.ConfigError
+	assert.ErrorAs(t, err, &cfgErr)
+	assert.ErrorContains(t, err, "applied stage removed is no longer configured")
+
+	p.Settings().Config.State.Enabled = false
+	_, err = ResumeStages(context.Background(), stages, p)
+	assert.ErrorContains(t, err, "--resume requires the install state")
+}
+
+func TestCmdRecordAppliedStage(t *testing.T) {
+	p := defaultTestConfig(t)
+	updates := withStateUpdates(t, p, map[string]string{"key1": "true", resumeStateKey: "second"})
+
+	// added to the recorded stages once
+	recordAppliedStage(context.Background(), "third", p)
+	recordAppliedStage(context.Background(), "second", p)
+	if assert.Len(t, *updates, 2) {
+		assert.Equal(t, map[string]string{"key1": "true", resumeStateKey: "second,third"}, (*updates)[0].Data)
+		assert.Equal(t, map[string]string{"key1": "true", resumeStateKey: "second"}, (*updates)[1].Data)
+	}
+
+	// empty clears the recorded stages
+	recordAppliedStage(context.Background(), "", p)
+	if assert.Len(t, *updates, 3) {
+		assert.Equal(t, map[string]string{"key1": "true", resumeStateKey: ""}, (*updates)[2].Data)
+	}
+
+	// disabled install state isn't written
+	p.Settings().Config.State.Enabled = false
+	recordAppliedStage(context.Background(), "third", p)
+	assert.Len(t, *updates, 3)
+}
+
+func TestCmdRunStageWithTimeout(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	// fake slow stage which only finishes when its context is cancelled
+	slowStage := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Minute):
+			return nil
+		}
+	}
+
+	p.SetStageTimeout(10 * time.Millisecond)
+
+	start := time.Now()
+	err := RunStageWithTimeout(context.Background(), "bigbang", "apply", p, slowStage)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stage bigbang apply timed out after 10ms")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	// a stage finishing within the timeout succeeds
+	err = RunStageWithTimeout(context.Background(), "bigbang", "apply", p, func(ctx context.Context) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestCmdStageTimeout(t *testing.T) {
+	p := defaultTestConfig(t)
+	assert.Equal(t, time.Duration(0), p.StageTimeout())
+
+	// config applies when the flag isn't set
+	p.Settings().Config.Terraform.StageTimeoutSeconds = 30
+	assert.Equal(t, 30*time.Second, p.StageTimeout())
+
+	// the flag takes precedence
+	p.SetStageTimeout(time.Minute)
+	assert.Equal(t, time.Minute, p.StageTimeout())
+
+	err := RunStageWithTimeout(context.Background(), "core-infra", "destroy", p, func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// an explicit 0 disables the configured limit
+	p.SetStageTimeout(0)
+	assert.Equal(t, time.Duration(0), p.StageTimeout())
+
+	err = RunStageWithTimeout(context.Background(), "core-infra", "destroy", p, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestCmdRunStageWithTimeoutParentExpired(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.SetStageTimeout(time.Minute)
+
+	// the command --timeout fires before the stage timeout
+	err := RunWithTimeout(context.Background(), "install", 10*time.Millisecond, func(ctx context.Context) error {
+		return RunStageWithTimeout(ctx, "bigbang", "apply", p, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "install timed out after 10ms")
+	assert.NotContains(t, err.Error(), "stage bigbang apply timed out")
+}
+
+func TestCmdAcquireRunLock(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	p := defaultTestConfig(t)
+
+	release, err := AcquireRunLock(context.Background(), "install", p)
+	assert.NoError(t, err)
+
+	// simulate a second quartz process against the same cluster
+	_, err = AcquireRunLock(context.Background(), "clean", p)
+	var lockErr provider.RunLockError
+	assert.ErrorAs(t, err, &lockErr)
+	assert.Equal(t, "install", lockErr.Operation)
+
+	release()
+
+	release, err = AcquireRunLock(context.Background(), "clean", p)
+	assert.NoError(t, err)
+	release()
+}
+
+func TestCmdAcquireRunLockReleaseCancelled(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	p := defaultTestConfig(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release, err := AcquireRunLock(ctx, "install", p)
+	assert.NoError(t, err)
+
+	// e.g. --timeout expired before the run finished
+	cancel()
+	release()
+
+	release, err = AcquireRunLock(context.Background(), "clean", p)
+	assert.NoError(t, err)
+	release()
+}
+
+func TestCmdInstallLocked(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	p := defaultTestConfig(t)
+
+	release, err := AcquireRunLock(context.Background(), "clean", p)
+	assert.NoError(t, err)
+	defer release()
+
+	err = Install(context.Background(), p)
+	assert.ErrorAs(t, err, &provider.RunLockError{})
+}
+
+func TestCmdCleanLocked(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	p := defaultTestConfig(t)
+
+	release, err := AcquireRunLock(context.Background(), "install", p)
+	assert.NoError(t, err)
+	defer release()
+
+	err = Clean(context.Background(), false, p)
+	assert.ErrorAs(t, err, &provider.RunLockError{})
+}
+
+func TestCmdWaitPreDestroy(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	// no waits configured
+	err := WaitPreDestroy(context.Background(), testStage, p)
+	assert.NoError(t, err)
+
+	stg := p.Settings().Config.Stages[testStage]
+	stg.PreDestroyWait = []schema.StageWaitConfig{
+		{Kind: "ExternalSecret", Namespace: "testns1", Name: "notfound", Timeout: 1, WaitSeconds: 1},
+		{Kind: "UnknownKind", Namespace: "testns1", Name: "testobj1", Timeout: 1, WaitSeconds: 1},
+	}
+	p.Settings().Config.Stages[testStage] = stg
+
+	// nothing matching left in the cluster
+	err = WaitPreDestroy(context.Background(), testStage, p)
+	assert.NoError(t, err)
+
+	stg.PreDestroyWait = []schema.StageWaitConfig{
+		{Kind: "ExternalSecret", Namespace: "testns1", Name: "testobj1", Timeout: 1, WaitSeconds: 1},
+	}
+	p.Settings().Config.Stages[testStage] = stg
+
+	// resource never goes away
+	err = WaitPreDestroy(context.Background(), testStage, p)
+	assert.ErrorContains(t, err, "pre-destroy wait failed for stage first")
+}
+
 func TestIsRetryableDestroyError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/urfave/cli/v3"
 )
@@ -41,22 +42,47 @@ func NewRootInternalCommand(p *CommandParams) RootCommandResult {
 				{
 					Name:  "force-cleanup",
 					Usage: "Perform post-delete cleanup actions",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "yes",
+							Aliases: []string{"y"},
+							Usage:   "Skip typing the cluster name to confirm",
+						},
+					},
 					Action: func(ctx context.Context, ccmd *cli.Command) error {
-						return ForceCleanup(ctx, p)
+						return ForceCleanup(ctx, p, ccmd.Bool("yes"))
 					},
 				},
 				{
 					Name:  "cleanup-terminating-pods",
-					Usage: "Force-delete pods stuck in Terminating state",
+					Usage: "Delete pods stuck in Terminating state",
 					Flags: []cli.Flag{
 						&cli.IntFlag{
 							Name:  "timeout",
-							Usage: "Minutes a pod must be stuck before force-deleting",
+							Usage: "Minutes a pod must be stuck before deleting",
 							Value: 5,
 						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Delete stuck pods with a zero grace period",
+						},
+						&cli.BoolFlag{
+							Name:  "remove-finalizers",
+							Usage: "Remove the finalizers of stuck pods before force-deleting, requires --force",
+						},
+					},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return CleanupTerminatingPods(ctx, p, ccmd.Int("timeout"), provider.TerminatingPodCleanupOpts{
+							Force:            ccmd.Bool("force"),
+							RemoveFinalizers: ccmd.Bool("remove-finalizers"),
+						})
 					},
+				},
+				{
+					Name:  "validate-cleanup",
+					Usage: "List the AWS resources that would block destroy, without deleting anything",
 					Action: func(ctx context.Context, ccmd *cli.Command) error {
-						return CleanupTerminatingPods(ctx, p, ccmd.Int("timeout"))
+						return ValidateCleanup(ctx, p)
 					},
 				},
 			},
@@ -65,21 +91,23 @@ func NewRootInternalCommand(p *CommandParams) RootCommandResult {
 }
 
 // ForceCleanup performs post-delete cleanup, including removing temporary files
-// and destroying the Terraform state bucket.
+// and destroying the Terraform state bucket. The operator must type the cluster
+// name to confirm unless yes is set.
 //
 // Parameters:
 //   - ctx: The context for the operation.
 //   - p: *CommandParams containing configuration and runtime parameters.
+//   - yes: true to skip the cluster name confirmation.
 //
 // Returns:
-//   - error: An error if the cleanup fails, otherwise nil.
-func ForceCleanup(ctx context.Context, p *CommandParams) error {
+//   - error: An error if the cleanup is not confirmed or fails, otherwise nil.
+func ForceCleanup(ctx context.Context, p *CommandParams, yes bool) error {
 	log.Debug("Entering", "command", "internal:forceCleanup")
 	defer log.Debug("Completed", "command", "internal:forceCleanup")
 
 	util.Errorf("Manually executing post delete cleanup actions")
-	if r := util.PromptYesNo("This cannot be undone, are you sure?"); !r {
-		return fmt.Errorf("aborting")
+	if err := ConfirmClusterName(p.Settings().Config.Name, yes, util.PromptInput); err != nil {
+		return err
 	}
 
 	// Destroy the Terraform backend
@@ -97,21 +125,58 @@ func ForceCleanup(ctx context.Context, p *CommandParams) error {
 	return nil
 }
 
-// CleanupTerminatingPods force-deletes pods that have been stuck in Terminating
+// ConfirmClusterName requires the operator to type the cluster name before a destructive
+// operation proceeds, guarding against running it against the wrong cluster.
+//
+// Parameters:
+//   - name: The configured cluster name the input must match.
+//   - yes: true to skip the prompt, e.g. from --yes.
+//   - prompt: The input prompt, util.PromptInput outside of tests.
+//
+// Returns:
+//   - error: An error if the input doesn't match the cluster name, otherwise nil.
+func ConfirmClusterName(name string, yes bool, prompt func(msg string) (string, error)) error {
+	if yes {
+		log.Info("Skipping cluster name confirmation", "cluster", name)
+		return nil
+	}
+
+	if name == "" {
+		return fmt.Errorf("aborting, cluster name not configured")
+	}
+
+	r, err := prompt(fmt.Sprintf("This cannot be undone, type the cluster name (%s) to confirm:", name))
+	if err != nil {
+		return fmt.Errorf("aborting, %w", err)
+	}
+
+	if r != name {
+		return fmt.Errorf("aborting, %q does not match cluster name %s", r, name)
+	}
+
+	return nil
+}
+
+// CleanupTerminatingPods deletes pods that have been stuck in Terminating
 // state for longer than the specified timeout. This is useful for cleaning up
 // pods that cannot terminate due to CNI or other infrastructure issues.
 //
 // Parameters:
 //   - ctx: The context for the operation.
 //   - p: *CommandParams containing configuration and runtime parameters.
-//   - timeoutMinutes: Minutes a pod must be stuck before force-deleting.
+//   - timeoutMinutes: Minutes a pod must be stuck before deleting.
+//   - opts: Whether to force the delete and remove the pods' finalizers.
 //
 // Returns:
-//   - error: An error if the cleanup fails, otherwise nil.
-func CleanupTerminatingPods(ctx context.Context, p *CommandParams, timeoutMinutes int) error {
+//   - error: A ConfigError if finalizer removal is requested without force, an error if the cleanup fails, otherwise nil.
+func CleanupTerminatingPods(ctx context.Context, p *CommandParams, timeoutMinutes int, opts provider.TerminatingPodCleanupOpts) error {
 	log.Debug("Entering", "command", "internal:cleanupTerminatingPods")
 	defer log.Debug("Completed", "command", "internal:cleanupTerminatingPods")
 
+	if opts.RemoveFinalizers && !opts.Force {
+		return util.NewConfigErrorf("--remove-finalizers requires --force")
+	}
+
 	kube, err := p.Provider().Kubernetes(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get kubernetes client: %w", err)
@@ -120,18 +185,24 @@ func CleanupTerminatingPods(ctx context.Context, p *CommandParams, timeoutMinute
 	timeout := time.Duration(timeoutMinutes) * time.Minute
 	util.Printf("Cleaning up pods stuck in Terminating state for more than %v", timeout)
 
-	cleaned, err := kube.CleanupStuckTerminatingPods(ctx, timeout)
+	cleaned, err := kube.CleanupStuckTerminatingPods(ctx, timeout, opts)
 	if err != nil {
 		return fmt.Errorf("failed to cleanup terminating pods: %w", err)
 	}
 
 	if len(cleaned) == 0 {
 		util.Printf("No stuck terminating pods found")
+		return nil
+	}
+
+	if opts.Force {
+		util.Printf("Deleted %d stuck pods:", len(cleaned))
 	} else {
-		util.Printf("Force-deleted %d stuck pods:", len(cleaned))
-		for _, pod := range cleaned {
-			util.Printf("  - %s", pod)
-		}
+		// without a zero grace period the pods stay until whatever is blocking them clears
+		util.Printf("Requested deletion of %d stuck pods, --force deletes them immediately:", len(cleaned))
+	}
+	for _, pod := range cleaned {
+		util.Printf("  - %s (node %s, terminating for %v)", pod, pod.Node, pod.TerminatingFor.Round(time.Second))
 	}
 
 	return nil
@@ -17,21 +17,70 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"errors"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCmdForceCleanup(t *testing.T) {
 	p := defaultTestConfig(t)
 
-	err := ForceCleanup(context.Background(), p)
+	err := ForceCleanup(context.Background(), p, true)
 	if err != nil {
 		t.Errorf("unexpected error in cmd ForceCleanup, %v", err)
 	}
 }
 
+func TestCmdConfirmClusterName(t *testing.T) {
+	prompted := false
+	prompt := func(input string) func(string) (string, error) {
+		return func(msg string) (string, error) {
+			prompted = true
+			assert.Contains(t, msg, "testcluster")
+			return input, nil
+		}
+	}
+
+	assert.NoError(t, ConfirmClusterName("testcluster", false, prompt("testcluster")))
+	assert.True(t, prompted)
+
+	err := ConfirmClusterName("testcluster", false, prompt("othercluster"))
+	assert.ErrorContains(t, err, "does not match cluster name testcluster")
+
+	err = ConfirmClusterName("testcluster", false, func(string) (string, error) { return "", errors.New("no tty") })
+	assert.ErrorContains(t, err, "no tty")
+
+	prompted = false
+	assert.NoError(t, ConfirmClusterName("testcluster", true, prompt("")))
+	assert.False(t, prompted)
+
+	assert.Error(t, ConfirmClusterName("", false, prompt("")))
+}
+
+func TestCmdForceCleanupMismatchAborts(t *testing.T) {
+	p := defaultTestConfig(t)
+	tmp := p.Settings().Config.Tmp
+
+	r, w, _ := os.Pipe()
+	w.Write([]byte("wrongcluster\n"))
+	w.Close()
+
+	defer func(v *os.File) { os.Stdin = v }(os.Stdin)
+	os.Stdin = r
+	t.Setenv("ACCESSIBLE", "1")
+
+	err := ForceCleanup(context.Background(), p, false)
+	assert.ErrorContains(t, err, "does not match cluster name")
+	assert.DirExists(t, tmp)
+}
+
 func TestCleanupTerminatingPods(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -41,7 +90,7 @@ func TestCleanupTerminatingPods(t *testing.T) {
 	defer util.SetWriter(&bytes.Buffer{})
 
 	// Test with default timeout (no pods should be found in mock)
-	err := CleanupTerminatingPods(context.Background(), p, 5)
+	err := CleanupTerminatingPods(context.Background(), p, 5, provider.TerminatingPodCleanupOpts{})
 	assert.NoError(t, err)
 
 	// Verify output contains expected message
@@ -58,6 +107,40 @@ func TestCleanupTerminatingPodsZeroTimeout(t *testing.T) {
 	defer util.SetWriter(&bytes.Buffer{})
 
 	// Test with zero timeout
-	err := CleanupTerminatingPods(context.Background(), p, 0)
+	err := CleanupTerminatingPods(context.Background(), p, 0, provider.TerminatingPodCleanupOpts{Force: true})
+	assert.NoError(t, err)
+}
+
+func TestCleanupTerminatingPodsRequested(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	stuck := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:              "stuck",
+		Namespace:         "testns1",
+		DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+		Finalizers:        []string{"example.com/block"},
+	}}
+	k8s, err := provider.NewKubernetesClient(provider.NewKubernetesApiMock().WithClientObjects(stuck), provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	// a graceful delete is only requested, the pod may well stay terminating
+	err = CleanupTerminatingPods(context.Background(), p, 5, provider.TerminatingPodCleanupOpts{})
 	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Requested deletion of 1 stuck pods")
+	assert.NotContains(t, buf.String(), "Deleted")
+}
+
+func TestCleanupTerminatingPodsRemoveFinalizersWithoutForce(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := CleanupTerminatingPods(context.Background(), p, 5, provider.TerminatingPodCleanupOpts{RemoveFinalizers: true})
+	assert.EqualError(t, err, "--remove-finalizers requires --force")
+	assert.ErrorAs(t, err, &util.ConfigError{})
 }
@@ -0,0 +1,273 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// NewRootKeycloakCommand creates the root Keycloak CLI command.
+// It organizes and returns all Keycloak-related subcommands.
+//
+// Parameters:
+//   - cmds: KeycloakCommandParams containing the list of Keycloak subcommands.
+//
+// Returns:
+//   - RootCommandResult containing the root Keycloak CLI command.
+func NewRootKeycloakCommand(cmds KeycloakCommandParams) RootCommandResult {
+	slices.SortFunc(cmds.Commands, ByCommandName)
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:     "keycloak",
+			Usage:    "Keycloak subcommands",
+			Commands: cmds.Commands,
+		},
+	}
+}
+
+// NewKeycloakExportCommand creates a CLI command for exporting Keycloak realm clients and mappers.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - KeycloakCommandResult containing the "export" CLI command.
+func NewKeycloakExportCommand(p *CommandParams) KeycloakCommandResult {
+	return KeycloakCommandResult{
+		Command: &cli.Command{
+			Name:  "export",
+			Usage: "Export Keycloak realm clients and protocol mappers as json",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{Name: "realm", Usage: "realm to export, defaults to the core and enabled environment realms (repeatable)"},
+				&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "output path, defaults to stdout"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				realms := ccmd.StringSlice("realm")
+				out := ccmd.String("out")
+				if out == "" {
+					return KeycloakExport(ctx, realms, os.Stdout, p)
+				}
+
+				f, err := os.OpenFile(out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				return KeycloakExport(ctx, realms, f, p)
+			},
+		},
+	}
+}
+
+// NewKeycloakOtpCommand creates a CLI command for reconciling realm OTP settings with the environment config.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - KeycloakCommandResult containing the "otp" CLI command.
+func NewKeycloakOtpCommand(p *CommandParams) KeycloakCommandResult {
+	return KeycloakCommandResult{
+		Command: &cli.Command{
+			Name:  "otp",
+			Usage: "Apply each environment's otp settings to its Keycloak realm",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "check", Usage: "only verify the realms match the config, without changing them"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return KeycloakOtp(ctx, ccmd.Bool("check"), p)
+			},
+		},
+	}
+}
+
+// KeycloakOtp reconciles the OTP requirement of the core and enabled environment realms with
+// their otp config, or only verifies them when check is set.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - check: true to report realms that don't match the config without changing them.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error listing the realms out of sync when checking, or if reconciling
+//     any realm fails, otherwise nil.
+func KeycloakOtp(ctx context.Context, check bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "keycloak:otp")
+	defer log.Debug("Completed", "command", "keycloak:otp")
+
+	kc, err := keycloakAdminClient(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return reconcileKeycloakOtp(ctx, kc, keycloakRealmOtp(p.Settings().Config), check)
+}
+
+// reconcileKeycloakOtp applies, or verifies when check is set, the desired OTP configuration of each realm.
+func reconcileKeycloakOtp(ctx context.Context, kc provider.KeycloakClient, desired map[string]schema.ApplicationEnvironmentOtpConfig, check bool) error {
+	util.Hdr("Keycloak OTP")
+
+	var errs []error
+	var rows [][]string
+	for _, realm := range slices.Sorted(maps.Keys(desired)) {
+		want := desired[realm]
+		want.Required = want.Enabled && want.Required
+
+		status := "unchanged"
+		if check {
+			actual, err := kc.RealmOtp(ctx, realm)
+			switch {
+			case err != nil:
+				status = "error"
+				errs = append(errs, err)
+			case actual != want:
+				status = fmt.Sprintf("out of sync, enabled=%t required=%t", actual.Enabled, actual.Required)
+				errs = append(errs, fmt.Errorf("keycloak realm %s otp out of sync with config", realm))
+			}
+		} else {
+			changed, err := kc.ReconcileRealmOtp(ctx, realm, want)
+			switch {
+			case err != nil:
+				status = "error"
+				errs = append(errs, err)
+			case changed:
+				status = "updated"
+			}
+		}
+
+		rows = append(rows, []string{realm, strconv.FormatBool(want.Enabled), strconv.FormatBool(want.Required), status})
+	}
+
+	util.PrintTable([]string{"Realm", "Enabled", "Required", "Status"}, rows)
+	return errors.Join(errs...)
+}
+
+// KeycloakExport exports the clients and protocol mappers of the given Keycloak realms as json.
+// The admin credentials and url are resolved from the keycloak application lookup in the cluster.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - realms: The realms to export, the core and enabled environment realms when empty.
+//   - w: The writer the json export is written to.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: A ConfigError if keycloak lookup isn't configured, an AccessError if the admin
+//     credentials can't be resolved, or an error if the export fails, otherwise nil.
+func KeycloakExport(ctx context.Context, realms []string, w io.Writer, p *CommandParams) error {
+	log.Debug("Entering", "command", "keycloak:export")
+	defer log.Debug("Completed", "command", "keycloak:export")
+
+	if len(realms) == 0 {
+		realms = keycloakRealms(p.Settings().Config)
+	}
+
+	kc, err := keycloakAdminClient(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return writeKeycloakExport(ctx, kc, realms, w)
+}
+
+// keycloakAdminClient creates a Keycloak admin client using the url and credentials
+// resolved from the keycloak application lookup in the cluster.
+func keycloakAdminClient(ctx context.Context, p *CommandParams) (provider.KeycloakClient, error) {
+	app, ok := p.Settings().Config.Core.Applications["keycloak"]
+	if !ok || app.Disabled || !app.Lookup.Enabled {
+		return provider.KeycloakClient{}, util.NewConfigErrorf("keycloak application lookup not configured")
+	}
+
+	k8s, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return provider.KeycloakClient{}, err
+	}
+
+	info := k8s.GetAppConnectionInfo(ctx, "keycloak", app.Lookup)
+	if info.Error != nil {
+		return provider.KeycloakClient{}, util.NewAccessError(k8s.ProviderName(), info.Error)
+	}
+
+	kc, err := provider.NewKeycloakClient(util.NewHttpClientFactory(), "Keycloak", "https://"+info.PublicEndpoint, info.AdminUsername, info.AdminPassword)
+	if err != nil {
+		return provider.KeycloakClient{}, util.NewAccessError("Keycloak", err)
+	}
+
+	return kc, nil
+}
+
+// writeKeycloakExport exports the realms with the client and writes them as indented json.
+func writeKeycloakExport(ctx context.Context, kc provider.KeycloakClient, realms []string, w io.Writer) error {
+	export, err := kc.ExportRealms(ctx, realms)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// keycloakRealms returns the realm names quartz configures, the core realm followed by
+// each enabled application environment, sorted by name.
+func keycloakRealms(cfg schema.QuartzConfig) []string {
+	otp := keycloakRealmOtp(cfg)
+	delete(otp, cfg.Core.Name)
+
+	envs := slices.Sorted(maps.Keys(otp))
+	return append([]string{cfg.Core.Name}, envs...)
+}
+
+// keycloakRealmOtp returns the desired OTP configuration of each realm quartz configures,
+// the core realm and each enabled application environment.
+func keycloakRealmOtp(cfg schema.QuartzConfig) map[string]schema.ApplicationEnvironmentOtpConfig {
+	res := map[string]schema.ApplicationEnvironmentOtpConfig{
+		cfg.Core.Name: cfg.Core.Otp,
+	}
+
+	for k, e := range cfg.Environments {
+		if !e.Enabled {
+			continue
+		}
+
+		name := e.Name
+		if name == "" {
+			name = k
+		}
+		res[name] = e.Otp
+	}
+
+	return res
+}
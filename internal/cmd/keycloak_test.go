@@ -0,0 +1,128 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+func TestNewRootKeycloakCommand(t *testing.T) {
+	cmds := KeycloakCommandParams{
+		Commands: []*cli.Command{
+			{Name: "import"},
+			{Name: "export"},
+		},
+	}
+	cmd := NewRootKeycloakCommand(cmds).Command
+
+	assert.Equal(t, "keycloak", cmd.Name)
+	assert.Len(t, cmd.Commands, 2)
+	assert.Equal(t, "export", cmd.Commands[0].Name)
+	assert.Equal(t, "import", cmd.Commands[1].Name)
+}
+
+func TestNewKeycloakExportCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewKeycloakExportCommand(p).Command
+
+	assert.Equal(t, "export", cmd.Name)
+	assert.Len(t, cmd.Flags, 2)
+
+	realmFlag := cmd.Flags[0].(*cli.StringSliceFlag)
+	assert.Equal(t, "realm", realmFlag.Name)
+
+	outFlag := cmd.Flags[1].(*cli.StringFlag)
+	assert.Equal(t, "out", outFlag.Name)
+}
+
+func TestCmdKeycloakRealms(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{Name: "infra"},
+		Environments: map[string]schema.ApplicationEnvironmentConfig{
+			"stage":    {Name: "stage", Enabled: true},
+			"dev":      {Enabled: true},
+			"disabled": {Name: "disabled", Enabled: false},
+		},
+	}
+
+	assert.Equal(t, []string{"infra", "dev", "stage"}, keycloakRealms(cfg))
+}
+
+func TestCmdKeycloakExportNotConfigured(t *testing.T) {
+	p := defaultTestConfig(t)
+	app := p.Settings().Config.Core.Applications["keycloak"]
+	app.Lookup.Enabled = false
+	p.Settings().Config.Core.Applications["keycloak"] = app
+
+	var out bytes.Buffer
+	err := KeycloakExport(context.Background(), nil, &out, p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+	assert.Empty(t, out.String())
+}
+
+func TestCmdKeycloakExportCredentialsNotFound(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	// the mocked cluster has no keycloak admin secret
+	var out bytes.Buffer
+	err := KeycloakExport(context.Background(), []string{"infra"}, &out, p)
+	assert.ErrorAs(t, err, &util.AccessError{})
+	assert.Empty(t, out.String())
+}
+
+func TestNewKeycloakOtpCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewKeycloakOtpCommand(p).Command
+
+	assert.Equal(t, "otp", cmd.Name)
+	assert.Len(t, cmd.Flags, 1)
+
+	checkFlag := cmd.Flags[0].(*cli.BoolFlag)
+	assert.Equal(t, "check", checkFlag.Name)
+}
+
+func TestCmdKeycloakRealmOtp(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{Name: "infra", Otp: schema.ApplicationEnvironmentOtpConfig{Enabled: true}},
+		Environments: map[string]schema.ApplicationEnvironmentConfig{
+			"stage":    {Name: "stage", Enabled: true, Otp: schema.ApplicationEnvironmentOtpConfig{Enabled: true, Required: true}},
+			"dev":      {Enabled: true},
+			"disabled": {Name: "disabled", Enabled: false, Otp: schema.ApplicationEnvironmentOtpConfig{Enabled: true}},
+		},
+	}
+
+	assert.Equal(t, map[string]schema.ApplicationEnvironmentOtpConfig{
+		"infra": {Enabled: true},
+		"stage": {Enabled: true, Required: true},
+		"dev":   {},
+	}, keycloakRealmOtp(cfg))
+}
+
+func TestCmdKeycloakOtpNotConfigured(t *testing.T) {
+	p := defaultTestConfig(t)
+	app := p.Settings().Config.Core.Applications["keycloak"]
+	app.Disabled = true
+	p.Settings().Config.Core.Applications["keycloak"] = app
+
+	err := KeycloakOtp(context.Background(), true, p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/terraform"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// logFollowInterval is how often a followed log file is polled for new content.
+var logFollowInterval = 500 * time.Millisecond
+
+// NewRootLogsCommand creates the "logs" root command for the CLI.
+// This command provides subcommands for viewing the logs written by quartz.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - RootCommandResult containing the "logs" CLI command.
+func NewRootLogsCommand(p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "logs",
+			Usage: "Log viewing subcommands",
+			Commands: []*cli.Command{
+				{
+					Name:          "terraform",
+					Usage:         "Print the terraform log file configured by log.terraform",
+					ShellComplete: stageShellComplete(p),
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "follow",
+							Aliases: []string{"f"},
+							Usage:   "Keep printing new log lines until interrupted",
+						},
+						&cli.StringFlag{
+							Name:    "stage",
+							Aliases: []string{"s"},
+							Usage:   "Stage to print the log for, when log.terraform.path contains a $stage placeholder",
+						},
+					},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return TerraformLogs(ctx, os.Stdout, ccmd.String("stage"), ccmd.Bool("follow"), p)
+					},
+				},
+			},
+		},
+	}
+}
+
+// TerraformLogs prints today's terraform log file, optionally following it for new content.
+//
+// Parameters:
+//   - ctx: The context for the operation, following stops when it is done.
+//   - w: The writer for the log content.
+//   - stage: The stage whose log to print, required when the log path is per stage.
+//   - follow: true to keep printing new content until the context is done.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: A config error if terraform logging is disabled or the log doesn't exist, otherwise nil.
+func TerraformLogs(ctx context.Context, w io.Writer, stage string, follow bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "logs:terraform", "stage", stage, "follow", follow)
+	defer log.Debug("Completed", "command", "logs:terraform", "stage", stage, "follow", follow)
+
+	cfg := p.Settings().Config
+	if !cfg.Log.Terraform.Enabled || cfg.Log.Terraform.Path == "" {
+		return util.NewConfigErrorf("terraform logging is disabled, set log.terraform.enabled: true (and optionally log.terraform.path) in the config")
+	}
+
+	if terraform.IsPerStageLogPath(cfg) {
+		if stage == "" {
+			return util.NewConfigErrorf("log.terraform.path %s is per stage, specify the stage with --stage", cfg.Log.Terraform.Path)
+		}
+
+		if _, err := lookupStage(stage, p); err != nil {
+			return err
+		}
+	}
+
+	path := terraform.LogPath(cfg, stage, time.Now())
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return util.NewConfigErrorf("terraform log %s not found, it is written by terraform commands run with log.terraform.enabled", path)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	if err != nil || !follow {
+		return err
+	}
+
+	t := time.NewTicker(logFollowInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			_, err = io.Copy(w, f)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// enableTestTerraformLog enables terraform logging to a temp dir, returning the resolved log path.
+func enableTestTerraformLog(t *testing.T, p *CommandParams) string {
+	dir := t.TempDir()
+	p.Settings().Config.Log.Terraform.Enabled = true
+	p.Settings().Config.Log.Terraform.Path = filepath.Join(dir, "$name.tf.log")
+	return filepath.Join(dir, "mytest.tf.log")
+}
+
+func TestNewRootLogsCommand(t *testing.T) {
+	cmd := NewRootLogsCommand(defaultTestConfig(t)).Command
+
+	assert.Equal(t, "logs", cmd.Name)
+	assert.Len(t, cmd.Commands, 1)
+	assert.Equal(t, "terraform", cmd.Commands[0].Name)
+	assert.Equal(t, "follow", cmd.Commands[0].Flags[0].Names()[0])
+}
+
+func TestCmdTerraformLogs(t *testing.T) {
+	p := defaultTestConfig(t)
+	path := enableTestTerraformLog(t, p)
+	assert.NoError(t, os.WriteFile(path, []byte("line one\n"), 0644))
+
+	var buf bytes.Buffer
+	err := TerraformLogs(context.Background(), &buf, "", false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "line one\n", buf.String())
+}
+
+func TestCmdTerraformLogsFollow(t *testing.T) {
+	orig := logFollowInterval
+	logFollowInterval = 10 * time.Millisecond
+	defer func() { logFollowInterval = orig }()
+
+	p := defaultTestConfig(t)
+	path := enableTestTerraformLog(t, p)
+	assert.NoError(t, os.WriteFile(path, []byte("line one\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- TerraformLogs(ctx, &buf, "", true, p)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("line two\n")
+	assert.NoError(t, err)
+	f.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, "line one\nline two\n", buf.String())
+}
+
+func TestCmdTerraformLogsDisabled(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Log.Terraform.Enabled = false
+
+	err := TerraformLogs(context.Background(), &bytes.Buffer{}, "", false, p)
+	assert.ErrorContains(t, err, "set log.terraform.enabled: true")
+
+	var cfgErr util.ConfigError
+	assert.ErrorAs(t, err, &cfgErr)
+}
+
+func TestCmdTerraformLogsMissing(t *testing.T) {
+	p := defaultTestConfig(t)
+	path := enableTestTerraformLog(t, p)
+
+	err := TerraformLogs(context.Background(), &bytes.Buffer{}, "", false, p)
+	assert.ErrorContains(t, err, "terraform log "+path+" not found")
+}
+
+func TestCmdTerraformLogsStage(t *testing.T) {
+	p := defaultTestConfig(t)
+	dir := t.TempDir()
+	p.Settings().Config.Log.Terraform.Enabled = true
+	p.Settings().Config.Log.Terraform.Path = filepath.Join(dir, "$stage.tf.log")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, testStage+".tf.log"), []byte("stage line\n"), 0644))
+
+	var buf bytes.Buffer
+	err := TerraformLogs(context.Background(), &buf, testStage, false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "stage line\n", buf.String())
+}
+
+func TestCmdTerraformLogsStageRequired(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Log.Terraform.Enabled = true
+	p.Settings().Config.Log.Terraform.Path = filepath.Join(t.TempDir(), "$stage.tf.log")
+
+	err := TerraformLogs(context.Background(), &bytes.Buffer{}, "", false, p)
+	assert.ErrorContains(t, err, "specify the stage with --stage")
+
+	err = TerraformLogs(context.Background(), &bytes.Buffer{}, "missing", false, p)
+	assert.ErrorContains(t, err, "stage missing not found")
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// phaseMetrics is the json shape of the phase timing metrics.
+type phaseMetrics struct {
+	Command         string             `json:"command"`
+	DurationSeconds float64            `json:"duration_seconds"`
+	Phases          map[string]float64 `json:"phases"`
+}
+
+// WritePhaseMetrics writes the phase timings of a command to a metrics file, as json for a
+// .json path, otherwise in Prometheus textfile format.
+//
+// Parameters:
+//   - path: The metrics file to write.
+//   - command: The command the phases belong to, e.g. install or clean.
+//   - timing: The duration of each phase.
+//   - total: The duration of the command.
+//
+// Returns:
+//   - error: An error if the metrics can't be written, otherwise nil.
+func WritePhaseMetrics(path string, command string, timing map[string]time.Duration, total time.Duration) error {
+	var b []byte
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		m := phaseMetrics{
+			Command:         command,
+			DurationSeconds: total.Seconds(),
+			Phases:          make(map[string]float64, len(timing)),
+		}
+		for phase, d := range timing {
+			m.Phases[phase] = d.Seconds()
+		}
+
+		j, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		b = append(j, '\n')
+	} else {
+		b = []byte(promPhaseMetrics(command, timing, total))
+	}
+
+	if err := util.WriteBytesToFile(b, path); err != nil {
+		return fmt.Errorf("failed to write metrics %s, %w", path, err)
+	}
+
+	return nil
+}
+
+// promPhaseMetrics formats the phase timings as Prometheus textfile metrics, phases sorted by name.
+func promPhaseMetrics(command string, timing map[string]time.Duration, total time.Duration) string {
+	var sb strings.Builder
+
+	sb.WriteString("# HELP quartz_phase_duration_seconds Duration of each phase of a quartz command.\n")
+	sb.WriteString("# TYPE quartz_phase_duration_seconds gauge\n")
+	for _, phase := range slices.Sorted(maps.Keys(timing)) {
+		fmt.Fprintf(&sb, "quartz_phase_duration_seconds{command=%q,phase=%q} %g\n", command, phase, timing[phase].Seconds())
+	}
+
+	sb.WriteString("# HELP quartz_duration_seconds Total duration of a quartz command.\n")
+	sb.WriteString("# TYPE quartz_duration_seconds gauge\n")
+	fmt.Fprintf(&sb, "quartz_duration_seconds{command=%q} %g\n", command, total.Seconds())
+
+	return sb.String()
+}
+
+// writePhaseMetrics writes the phase metrics when --metrics is set, a failure is only logged
+// so it doesn't mask the command result.
+func writePhaseMetrics(command string, timing map[string]time.Duration, start time.Time, p *CommandParams) {
+	path := p.MetricsPath()
+	if path == "" {
+		return
+	}
+
+	if err := WritePhaseMetrics(path, command, timing, time.Since(start)); err != nil {
+		log.Warn("Failed to write phase metrics", "command", command, "err", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdWritePhaseMetricsPrometheus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics", "quartz.prom")
+
+	err := WritePhaseMetrics(path, "clean", map[string]time.Duration{
+		"k8s-cleanup":     90 * time.Second,
+		"destroy-bigbang": 1500 * time.Millisecond,
+	}, 2*time.Minute)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	expected := `# HELP quartz_phase_duration_seconds Duration of each phase of a quartz command.
+# TYPE quartz_phase_duration_seconds gauge
+quartz_phase_duration_seconds{command="clean",phase="destroy-bigbang"} 1.5
+quartz_phase_duration_seconds{command="clean",phase="k8s-cleanup"} 90
+# HELP quartz_duration_seconds Total duration of a quartz command.
+# TYPE quartz_duration_seconds gauge
+quartz_duration_seconds{command="clean"} 120
+`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestCmdWritePhaseMetricsJson(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	err := WritePhaseMetrics(path, "install", map[string]time.Duration{
+		"apply-core-infra": time.Minute,
+		"prepare-account":  250 * time.Millisecond,
+	}, 2*time.Minute)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var actual phaseMetrics
+	assert.NoError(t, json.Unmarshal(b, &actual))
+	assert.Equal(t, phaseMetrics{
+		Command:         "install",
+		DurationSeconds: 120,
+		Phases: map[string]float64{
+			"apply-core-infra": 60,
+			"prepare-account":  0.25,
+		},
+	}, actual)
+}
+
+func TestCmdWritePhaseMetricsParams(t *testing.T) {
+	p := defaultTestConfig(t)
+	timing := map[string]time.Duration{"k8s-cleanup": time.Second}
+
+	// no path set, nothing written
+	writePhaseMetrics("clean", timing, time.Now(), p)
+
+	path := filepath.Join(t.TempDir(), "quartz.prom")
+	p.SetMetricsPath(path)
+	writePhaseMetrics("clean", timing, time.Now(), p)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `quartz_phase_duration_seconds{command="clean",phase="k8s-cleanup"} 1`)
+
+	// an unwritable path is only logged
+	p.SetMetricsPath(t.TempDir())
+	writePhaseMetrics("clean", timing, time.Now(), p)
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// mirrorTransport creates the registry transport used by the mirror command, replaced in tests.
+var mirrorTransport = func() provider.RegistryTransport {
+	return provider.NewOciRegistryClient(util.NewHttpClientFactory())
+}
+
+// NewRootMirrorCommand creates the "mirror" root command for the CLI.
+// This command copies the configured images and charts to the mirror target registry.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - RootCommandResult containing the "mirror" CLI command.
+func NewRootMirrorCommand(p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "mirror",
+			Usage: "Copy the images and charts in mirror.image_repository to the target registry",
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return Mirror(ctx, p)
+			},
+		},
+	}
+}
+
+// Mirror copies each configured image and chart from its source registry to the mirror target,
+// pulling with the ironbank credentials and pushing with the github credentials.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: A config error if mirroring is disabled or nothing is configured, an error listing
+//     the artifacts that failed to mirror, otherwise nil.
+func Mirror(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "mirror")
+	defer log.Debug("Completed", "command", "mirror")
+
+	cfg := p.Settings().Config.Mirror
+	if !cfg.ImageRepository.Enabled {
+		return util.NewConfigErrorf("mirroring is disabled, set mirror.image_repository.enabled: true in the config")
+	}
+
+	if len(cfg.ImageRepository.Images) == 0 && len(cfg.ImageRepository.Charts) == 0 {
+		return util.NewConfigErrorf("nothing to mirror, add references to mirror.image_repository.images or mirror.image_repository.charts in the config")
+	}
+
+	c := provider.NewMirrorClient(mirrorTransport(), cfg, p.Settings().Secrets)
+	res := c.Mirror(ctx)
+
+	provider.PrintResultTable("Mirror "+cfg.ImageRepository.Target, res)
+	return res.Error()
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+// registryTransportStub serves a blobless manifest for every reference, recording pushes,
+// or fails every pull when pullErr is set.
+type registryTransportStub struct {
+	pullErr error
+	pushed  []string
+}
+
+func (s *registryTransportStub) GetManifest(ctx context.Context, ref provider.ImageReference, creds provider.RegistryCredentials) (provider.RegistryManifest, error) {
+	if s.pullErr != nil {
+		return provider.RegistryManifest{}, s.pullErr
+	}
+	return provider.RegistryManifest{MediaType: "application/vnd.oci.image.manifest.v1+json", Body: []byte(`{}`)}, nil
+}
+
+func (s *registryTransportStub) PutManifest(ctx context.Context, ref provider.ImageReference, r provider.RegistryManifest, creds provider.RegistryCredentials) error {
+	s.pushed = append(s.pushed, ref.String())
+	return nil
+}
+
+func (s *registryTransportStub) BlobExists(ctx context.Context, ref provider.ImageReference, digest string, creds provider.RegistryCredentials) (bool, error) {
+	return true, nil
+}
+
+func (s *registryTransportStub) GetBlob(ctx context.Context, ref provider.ImageReference, digest string, creds provider.RegistryCredentials) (io.ReadCloser, int64, error) {
+	return io.NopCloser(strings.NewReader("")), 0, nil
+}
+
+func (s *registryTransportStub) PutBlob(ctx context.Context, ref provider.ImageReference, digest string, size int64, r io.Reader, creds provider.RegistryCredentials) error {
+	return nil
+}
+
+func withMirrorTransport(t *testing.T, s provider.RegistryTransport) {
+	orig := mirrorTransport
+	mirrorTransport = func() provider.RegistryTransport { return s }
+	t.Cleanup(func() { mirrorTransport = orig })
+}
+
+func TestNewRootMirrorCommand(t *testing.T) {
+	cmd := NewRootMirrorCommand(defaultTestConfig(t)).Command
+
+	assert.Equal(t, "mirror", cmd.Name)
+	assert.NotNil(t, cmd.Action)
+}
+
+func TestCmdMirror(t *testing.T) {
+	s := &registryTransportStub{}
+	withMirrorTransport(t, s)
+
+	p := defaultTestConfig(t)
+	repo := &p.Settings().Config.Mirror.ImageRepository
+	repo.Enabled = true
+	repo.Target = "ghcr.io/myorg/mirror"
+	repo.SourceRegistries = []string{"registry1.dso.mil"}
+	repo.Images = []string{"registry1.dso.mil/ironbank/nginx:1.27"}
+	repo.Charts = []string{"registry1.dso.mil/charts/app:0.1.0"}
+
+	err := Mirror(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io/myorg/mirror/ironbank/nginx:1.27", "ghcr.io/myorg/mirror/charts/app:0.1.0"}, s.pushed)
+}
+
+func TestCmdMirrorAuthFailure(t *testing.T) {
+	withMirrorTransport(t, &registryTransportStub{pullErr: fmt.Errorf("authentication failed for registry1.dso.mil, status 401")})
+
+	p := defaultTestConfig(t)
+	repo := &p.Settings().Config.Mirror.ImageRepository
+	repo.Enabled = true
+	repo.SourceRegistries = []string{"registry1.dso.mil"}
+	repo.Images = []string{"registry1.dso.mil/ironbank/nginx:1.27"}
+
+	err := Mirror(context.Background(), p)
+	assert.ErrorContains(t, err, "failed to mirror 1 artifact(s): registry1.dso.mil/ironbank/nginx:1.27")
+}
+
+func TestCmdMirrorNothingConfigured(t *testing.T) {
+	p := defaultTestConfig(t)
+	repo := &p.Settings().Config.Mirror.ImageRepository
+	repo.Enabled = true
+	repo.Images = nil
+	repo.Charts = nil
+
+	err := Mirror(context.Background(), p)
+	assert.ErrorContains(t, err, "nothing to mirror")
+
+	repo.Enabled = false
+	err = Mirror(context.Background(), p)
+	assert.ErrorContains(t, err, "mirroring is disabled")
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// notifyTimeout limits how long a completion notification may delay the command exit.
+const notifyTimeout = 10 * time.Second
+
+// completionNotification is the json payload posted to the notification webhook.
+type completionNotification struct {
+	Command         string  `json:"command"`
+	Cluster         string  `json:"cluster"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// slackNotification is the payload posted to a Slack incoming webhook.
+type slackNotification struct {
+	Text string `json:"text"`
+}
+
+// Notify posts the outcome of a command to the configured notification webhook.
+// Nothing is sent when notifications.webhook.url isn't set.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - httpClient: The HTTP client factory used to post the notification.
+//   - command: The command that completed, e.g. install or clean.
+//   - duration: How long the command ran.
+//   - cmdErr: The error the command failed with, nil on success or errAborted if the user declined.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the notification could not be sent or was rejected, otherwise nil.
+func Notify(ctx context.Context, httpClient util.HttpClientFactory, command string, duration time.Duration, cmdErr error, p *CommandParams) error {
+	cfg := p.Settings().Config.Notifications.Webhook
+	if cfg.Url == "" {
+		return nil
+	}
+
+	n := completionNotification{
+		Command:         command,
+		Cluster:         p.Settings().Config.Name,
+		Status:          "success",
+		DurationSeconds: duration.Seconds(),
+	}
+	switch {
+	case errors.Is(cmdErr, errAborted):
+		n.Status = "aborted"
+	case cmdErr != nil:
+		n.Status = "failure"
+		n.Error = cmdErr.Error()
+	}
+
+	var payload any
+	switch cfg.Format {
+	case "", "json":
+		payload = n
+	case "slack":
+		payload = slackNotification{Text: slackNotificationText(n, duration)}
+	default:
+		return fmt.Errorf("unsupported notification format %s, must be json or slack", cfg.Format)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpClient.NewClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification, %w", command, err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body) //nolint:errcheck
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("notification webhook returned %s", res.Status)
+	}
+
+	return nil
+}
+
+// slackNotificationText formats the outcome as a Slack message.
+func slackNotificationText(n completionNotification, duration time.Duration) string {
+	d := duration.Round(time.Second)
+	if n.Status == "aborted" {
+		return fmt.Sprintf(":warning: quartz %s aborted for cluster %s after %v", n.Command, n.Cluster, d)
+	}
+	if n.Error != "" {
+		return fmt.Sprintf(":x: quartz %s failed for cluster %s after %v: %s", n.Command, n.Cluster, d, n.Error)
+	}
+
+	return fmt.Sprintf(":white_check_mark: quartz %s succeeded for cluster %s in %v", n.Command, n.Cluster, d)
+}
+
+// notifyCompletion sends the completion notification, a failure is only logged so it
+// doesn't change the command result.
+func notifyCompletion(ctx context.Context, command string, cmdErr error, p *CommandParams) {
+	err := Notify(ctx, util.NewHttpClientFactory(), command, time.Since(p.startTime), cmdErr, p)
+	if err != nil {
+		log.Warn("Failed to send completion notification", "command", command, "err", err)
+	}
+}
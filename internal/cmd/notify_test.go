@@ -0,0 +1,156 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// newNotifyTestServer starts a stub webhook capturing the posted payloads, responding with status.
+func newNotifyTestServer(t *testing.T, status int, payloads *[]map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		b, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal(b, &m))
+		*payloads = append(*payloads, m)
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestCmdNotify(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		cmdErr   error
+		expected map[string]any
+	}{
+		{
+			name: "success",
+			expected: map[string]any{
+				"command":          "install",
+				"cluster":          "unittest",
+				"status":           "success",
+				"duration_seconds": 90.0,
+			},
+		},
+		{
+			name:   "failure",
+			format: "json",
+			cmdErr: fmt.Errorf("stage bigbang failed"),
+			expected: map[string]any{
+				"command":          "install",
+				"cluster":          "unittest",
+				"status":           "failure",
+				"duration_seconds": 90.0,
+				"error":            "stage bigbang failed",
+			},
+		},
+		{
+			name:   "aborted",
+			cmdErr: errAborted,
+			expected: map[string]any{
+				"command":          "install",
+				"cluster":          "unittest",
+				"status":           "aborted",
+				"duration_seconds": 90.0,
+			},
+		},
+		{
+			name:   "slack success",
+			format: "slack",
+			expected: map[string]any{
+				"text": ":white_check_mark: quartz install succeeded for cluster unittest in 1m30s",
+			},
+		},
+		{
+			name:   "slack failure",
+			format: "slack",
+			cmdErr: fmt.Errorf("stage bigbang failed"),
+			expected: map[string]any{
+				"text": ":x: quartz install failed for cluster unittest after 1m30s: stage bigbang failed",
+			},
+		},
+		{
+			name:   "slack aborted",
+			format: "slack",
+			cmdErr: errAborted,
+			expected: map[string]any{
+				"text": ":warning: quartz install aborted for cluster unittest after 1m30s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payloads []map[string]any
+			srv := newNotifyTestServer(t, http.StatusOK, &payloads)
+
+			p := defaultTestConfig(t)
+			p.Settings().Config.Name = "unittest"
+			p.Settings().Config.Notifications.Webhook.Url = srv.URL
+			p.Settings().Config.Notifications.Webhook.Format = tt.format
+
+			err := Notify(context.Background(), util.NewHttpClientFactory(), "install", 90*time.Second, tt.cmdErr, p)
+			assert.NoError(t, err)
+
+			assert.Len(t, payloads, 1)
+			assert.Equal(t, tt.expected, payloads[0])
+		})
+	}
+}
+
+func TestCmdNotifyDisabled(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	// no url configured, the client is never used
+	err := Notify(context.Background(), nil, "clean", time.Second, nil, p)
+	assert.NoError(t, err)
+}
+
+func TestCmdNotifyErrors(t *testing.T) {
+	var payloads []map[string]any
+	srv := newNotifyTestServer(t, http.StatusInternalServerError, &payloads)
+
+	p := defaultTestConfig(t)
+	p.Settings().Config.Notifications.Webhook.Url = srv.URL
+
+	err := Notify(context.Background(), util.NewHttpClientFactory(), "clean", time.Second, nil, p)
+	assert.ErrorContains(t, err, "notification webhook returned 500")
+	assert.Len(t, payloads, 1)
+
+	p.Settings().Config.Notifications.Webhook.Format = "teams"
+	err = Notify(context.Background(), util.NewHttpClientFactory(), "clean", time.Second, nil, p)
+	assert.ErrorContains(t, err, "unsupported notification format teams")
+
+	// best effort, the failure is only logged
+	notifyCompletion(context.Background(), "clean", nil, p)
+}
@@ -16,12 +16,14 @@ package cmd
 
 import (
 	"context"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config"
 	"github.com/MetroStar/quartzctl/internal/log"
 	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/terraform"
 	"github.com/urfave/cli/v3"
 )
 
@@ -30,16 +32,43 @@ import (
 // Fields:
 //   - configFile: Path to the configuration file.
 //   - secretsFile: Path to the secrets file.
+//   - overrides: Command line config overrides from --set, --var-file and --env.
+//   - keepTmp: Retain the tmp directory during cleanup, from --keep-tmp.
+//   - allWebhooks: Remove all webhook configurations during cleanup, from --all-webhooks.
+//   - only: Stages to limit install to, from --only.
+//   - resume: Skip the stages applied by a previous install, from --resume.
+//   - metricsPath: File install and clean write phase timing metrics to, from --metrics.
+//   - stageTimeout: Max duration of each stage apply or destroy during install and clean, from --stage-timeout.
+//   - initWorkers: Max stages initialized concurrently by init-all, from --workers or --serial.
+//   - initMode: Whether init reconfigures or migrates the backend state, from --migrate-state.
+//   - destroyInclude: Resource addresses destroy is limited to, from --include.
+//   - destroyExclude: Resource addresses skipped by destroy, from --exclude.
+//   - assumeYes: Skip the destroy confirmation prompts, from --yes.
 //   - startTime: The time when the command execution started.
 //   - settings: Lazy-loaded settings from the configuration file.
+//   - settingsErr: Error encountered while loading settings, if any.
 //   - provider: Lazy-loaded provider factory for managing resources.
 type CommandParams struct {
-	configFile  string
-	secretsFile string
-	startTime   time.Time
+	configFile   string
+	secretsFile  string
+	overrides    config.Overrides
+	keepTmp      bool
+	allWebhooks  bool
+	only         []string
+	resume       bool
+	metricsPath  string
+	stageTimeout *time.Duration
+	initWorkers  int
+	initMode     terraform.TerraformInitMode
+	startTime    time.Time
 
-	settings *config.Settings
-	provider *provider.ProviderFactory
+	destroyInclude []string
+	destroyExclude []string
+	assumeYes      bool
+
+	settings    *config.Settings
+	settingsErr error
+	provider    *provider.ProviderFactory
 }
 
 // NewCommandParams creates a new instance of CommandParams.
@@ -71,6 +100,207 @@ func (p *CommandParams) SetSecrets(secretsFile string) {
 	p.secretsFile = secretsFile
 }
 
+// SetOverrides sets the command line config overrides for the command parameters.
+//
+// Parameters:
+//   - values: key=value pairs with dotted config keys.
+//   - varFiles: yaml files to merge over the configuration.
+func (p *CommandParams) SetOverrides(values []string, varFiles []string) {
+	p.overrides.Values = values
+	p.overrides.VarFiles = varFiles
+}
+
+// SetEnvironment sets the environment overlay merged over the config file.
+//
+// Parameters:
+//   - env: The environment name, empty to skip the overlay.
+func (p *CommandParams) SetEnvironment(env string) {
+	p.overrides.Env = env
+}
+
+// SetKeepTmp sets whether the tmp directory is retained during cleanup.
+//
+// Parameters:
+//   - keepTmp: true to skip removing the tmp directory.
+func (p *CommandParams) SetKeepTmp(keepTmp bool) {
+	p.keepTmp = keepTmp
+}
+
+// KeepTmp reports whether the tmp directory should be retained during cleanup,
+// either from the --keep-tmp flag or the keep_tmp config setting.
+//
+// Returns:
+//   - bool: true if the tmp directory should be retained.
+func (p *CommandParams) KeepTmp() bool {
+	return p.keepTmp || p.Settings().Config.KeepTmp
+}
+
+// SetAllWebhooks sets whether cleanup removes every webhook configuration.
+//
+// Parameters:
+//   - allWebhooks: true to remove all webhooks instead of those matching cleanup.webhooks.
+func (p *CommandParams) SetAllWebhooks(allWebhooks bool) {
+	p.allWebhooks = allWebhooks
+}
+
+// AllWebhooks reports whether cleanup should remove every webhook configuration,
+// either from the --all-webhooks flag or the cleanup.all_webhooks config setting.
+//
+// Returns:
+//   - bool: true if all webhooks should be removed.
+func (p *CommandParams) AllWebhooks() bool {
+	return p.allWebhooks || p.Settings().Config.Cleanup.AllWebhooks
+}
+
+// SetResume sets whether install skips the stages applied by a previous install.
+//
+// Parameters:
+//   - resume: true to resume after the last applied stage recorded in the install state.
+func (p *CommandParams) SetResume(resume bool) {
+	p.resume = resume
+}
+
+// Resume reports whether install skips the stages applied by a previous install.
+//
+// Returns:
+//   - bool: true to resume after the last applied stage.
+func (p *CommandParams) Resume() bool {
+	return p.resume
+}
+
+// SetMetricsPath sets the file install and clean write phase timing metrics to.
+//
+// Parameters:
+//   - path: The metrics file, json for a .json path, otherwise Prometheus textfile format. Empty to skip.
+func (p *CommandParams) SetMetricsPath(path string) {
+	p.metricsPath = path
+}
+
+// MetricsPath returns the file install and clean write phase timing metrics to.
+//
+// Returns:
+//   - string: The metrics file, empty when metrics aren't written.
+func (p *CommandParams) MetricsPath() string {
+	return p.metricsPath
+}
+
+// SetStageTimeout sets the max duration of each stage apply or destroy during install and clean.
+//
+// Parameters:
+//   - timeout: The per stage timeout, 0 for no limit regardless of terraform.stage_timeout_seconds.
+func (p *CommandParams) SetStageTimeout(timeout time.Duration) {
+	p.stageTimeout = &timeout
+}
+
+// StageTimeout returns the max duration of each stage apply or destroy during install and clean,
+// from the --stage-timeout flag or the terraform.stage_timeout_seconds config setting.
+//
+// Returns:
+//   - time.Duration: The per stage timeout, 0 for no limit.
+func (p *CommandParams) StageTimeout() time.Duration {
+	if p.stageTimeout != nil {
+		return *p.stageTimeout
+	}
+
+	return time.Duration(p.Settings().Config.Terraform.StageTimeoutSeconds) * time.Second
+}
+
+// SetOnly sets the stages install is limited to.
+//
+// Parameters:
+//   - only: The stage ids to install, empty for all stages.
+func (p *CommandParams) SetOnly(only []string) {
+	p.only = only
+}
+
+// Only returns the stages install is limited to, from the --only flag.
+//
+// Returns:
+//   - []string: The stage ids to install, empty for all stages.
+func (p *CommandParams) Only() []string {
+	return p.only
+}
+
+// SetInitWorkers sets the max stages initialized concurrently by init-all.
+//
+// Parameters:
+//   - n: The number of workers, 0 to use the terraform.init_workers config setting.
+func (p *CommandParams) SetInitWorkers(n int) {
+	p.initWorkers = n
+}
+
+// InitWorkers returns the max stages initialized concurrently by init-all, from the --workers
+// or --serial flags, otherwise the terraform.init_workers config setting, defaulting to the number of CPUs.
+//
+// Returns:
+//   - int: The number of workers, at least 1.
+func (p *CommandParams) InitWorkers() int {
+	if p.initWorkers > 0 {
+		return p.initWorkers
+	}
+
+	if n := p.Settings().Config.Terraform.InitWorkers; n > 0 {
+		return n
+	}
+
+	return runtime.NumCPU()
+}
+
+// SetInitMode sets whether terraform init reconfigures the backend or migrates existing state.
+//
+// Parameters:
+//   - mode: The init mode, empty for the default reconfigure.
+func (p *CommandParams) SetInitMode(mode terraform.TerraformInitMode) {
+	p.initMode = mode
+}
+
+// InitMode returns whether terraform init reconfigures the backend or migrates existing state.
+//
+// Returns:
+//   - terraform.TerraformInitMode: The init mode, from the --migrate-state flag, otherwise reconfigure.
+func (p *CommandParams) InitMode() terraform.TerraformInitMode {
+	if p.initMode == "" {
+		return terraform.InitReconfigure
+	}
+
+	return p.initMode
+}
+
+// SetDestroyFilters sets the resource address filters passed to destroy on the command line.
+//
+// Parameters:
+//   - include: Resource addresses or regex patterns to limit the destroy to.
+//   - exclude: Resource addresses or regex patterns to skip during the destroy.
+func (p *CommandParams) SetDestroyFilters(include []string, exclude []string) {
+	p.destroyInclude = include
+	p.destroyExclude = exclude
+}
+
+// DestroyFilters returns the resource address filters passed to destroy on the command line.
+//
+// Returns:
+//   - []string: The included resource addresses, from the --include flag.
+//   - []string: The excluded resource addresses, from the --exclude flag.
+func (p *CommandParams) DestroyFilters() ([]string, []string) {
+	return p.destroyInclude, p.destroyExclude
+}
+
+// SetAssumeYes sets whether confirmation prompts are skipped.
+//
+// Parameters:
+//   - yes: true to proceed without prompting.
+func (p *CommandParams) SetAssumeYes(yes bool) {
+	p.assumeYes = yes
+}
+
+// AssumeYes returns whether confirmation prompts are skipped, from the --yes flag.
+//
+// Returns:
+//   - bool: true to proceed without prompting.
+func (p *CommandParams) AssumeYes() bool {
+	return p.assumeYes
+}
+
 // Settings lazy loads the settings from the configuration file.
 //
 // Returns:
@@ -78,16 +308,25 @@ func (p *CommandParams) SetSecrets(secretsFile string) {
 func (p *CommandParams) Settings() *config.Settings {
 	if p.settings == nil {
 		// Load configuration and secrets into a settings struct
-		cfg, err := config.Load(context.Background(), p.configFile, p.secretsFile)
+		cfg, err := config.LoadWithOverrides(context.Background(), p.configFile, p.secretsFile, p.overrides)
 		if err != nil {
 			log.Error("Failed to parse config", "err", err)
 		}
 		p.settings = &cfg
+		p.settingsErr = err
 	}
 
 	return p.settings
 }
 
+// SettingsErr returns the error encountered while loading the settings, if any.
+//
+// Returns:
+//   - error: The config load error, or nil if settings were not loaded or loaded successfully.
+func (p *CommandParams) SettingsErr() error {
+	return p.settingsErr
+}
+
 // Provider lazy loads the provider factory.
 //
 // Returns:
@@ -0,0 +1,266 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/terraform"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// NewRootStateCommand creates the root state CLI command.
+// It organizes and returns all state-related subcommands.
+//
+// Parameters:
+//   - cmds: StateCommandParams containing the list of state subcommands.
+//
+// Returns:
+//   - RootCommandResult containing the root state CLI command.
+func NewRootStateCommand(cmds StateCommandParams) RootCommandResult {
+	slices.SortFunc(cmds.Commands, ByCommandName)
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:     "state",
+			Usage:    "Terraform and install state subcommands",
+			Commands: cmds.Commands,
+		},
+	}
+}
+
+// NewStateMigrateCommand creates a CLI command for migrating Terraform state to the configured backend.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - StateCommandResult containing the "migrate" CLI command.
+func NewStateMigrateCommand(p *CommandParams) StateCommandResult {
+	return StateCommandResult{
+		Command: &cli.Command{
+			Name:          "migrate",
+			Usage:         "Migrate Terraform state for one or all stages to the configured backend",
+			ShellComplete: stageShellComplete(p),
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name, defaults to all stages"},
+				&cli.StringSliceFlag{Name: "backend-config", Usage: "additional backend config key=value overriding the configured backend (repeatable)"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				stage := ccmd.String("stage")
+				bc := ccmd.StringSlice("backend-config")
+				if stage == "" {
+					return TfStateMigrateAll(ctx, bc, p)
+				}
+
+				return TfStateMigrate(ctx, stage, bc, p)
+			},
+		},
+	}
+}
+
+// confirmStateReset prompts before clearing the install state, replaceable in tests.
+var confirmStateReset = util.PromptYesNo
+
+// NewStateShowCommand creates a CLI command for printing the recorded install state.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - StateCommandResult containing the "show" CLI command.
+func NewStateShowCommand(p *CommandParams) StateCommandResult {
+	return StateCommandResult{
+		Command: &cli.Command{
+			Name:  "show",
+			Usage: "Print the install state recorded in the state ConfigMap",
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return StateShow(ctx, p)
+			},
+		},
+	}
+}
+
+// NewStateResetCommand creates a CLI command for clearing the recorded install state.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - StateCommandResult containing the "reset" CLI command.
+func NewStateResetCommand(p *CommandParams) StateCommandResult {
+	return StateCommandResult{
+		Command: &cli.Command{
+			Name:  "reset",
+			Usage: "Clear the install state recorded in the state ConfigMap after confirmation",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Clear the install state without confirming"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				p.SetAssumeYes(ccmd.Bool("yes"))
+				return StateReset(ctx, p)
+			},
+		},
+	}
+}
+
+// StateShow prints the key-value pairs recorded in the install state ConfigMap.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the state ConfigMap can't be read, otherwise nil.
+func StateShow(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "state:show")
+	defer log.Debug("Completed", "command", "state:show")
+
+	cfg := p.Settings().Config.State
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	data, err := kube.GetConfigMapValue(ctx, cfg.ConfigMapNamespace, cfg.ConfigMapName)
+	if err != nil {
+		return fmt.Errorf("failed to read install state configmap %s/%s: %w", cfg.ConfigMapNamespace, cfg.ConfigMapName, err)
+	}
+
+	util.Infof("Install state %s/%s", cfg.ConfigMapNamespace, cfg.ConfigMapName)
+
+	if len(data) == 0 {
+		util.Info("No install state recorded")
+		return nil
+	}
+
+	var rows [][]string
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		rows = append(rows, []string{k, data[k]})
+	}
+	util.PrintTable([]string{"Key", "Value"}, rows)
+
+	return nil
+}
+
+// StateReset clears the key-value pairs recorded in the install state ConfigMap after confirmation.
+// The prompt is skipped with --yes or the SILENT environment variable.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the state ConfigMap can't be cleared, otherwise nil.
+func StateReset(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "state:reset")
+	defer log.Debug("Completed", "command", "state:reset")
+
+	cfg := p.Settings().Config.State
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	if !p.AssumeYes() && !confirmStateReset(fmt.Sprintf("Clear the install state in configmap %s/%s? Stage state checks will fail until it's recorded again", cfg.ConfigMapNamespace, cfg.ConfigMapName)) {
+		util.Msg("Cancelled")
+		return nil
+	}
+
+	err = kube.ClearConfigMap(ctx, cfg.ConfigMapNamespace, cfg.ConfigMapName)
+	if err != nil {
+		return fmt.Errorf("failed to clear install state configmap %s/%s: %w", cfg.ConfigMapNamespace, cfg.ConfigMapName, err)
+	}
+
+	util.Msgf("Install state in %s/%s cleared", cfg.ConfigMapNamespace, cfg.ConfigMapName)
+	return nil
+}
+
+// TfStateMigrate re-initializes a stage against the target backend and migrates its existing state.
+// The target backend is the cloud provider's state backend with any additional backend config appended.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - stage: The name of the stage to migrate.
+//   - backendConfig: Additional backend config key=value pairs overriding the provider defaults.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the migration fails, otherwise nil.
+func TfStateMigrate(ctx context.Context, stage string, backendConfig []string, p *CommandParams) error {
+	log.Debug("Entering", "command", "state:migrate", "stage", stage)
+	defer log.Debug("Completed", "command", "state:migrate", "stage", stage)
+
+	s, ok := p.Settings().Config.Stages[stage]
+	if !ok {
+		return fmt.Errorf("stage %s not found", stage)
+	}
+
+	util.Hdrf("Migrate state %s", stage)
+
+	client := terraform.Instance(ctx, *p.Settings())
+	err := tfStagePrep(ctx, stage, p)
+	if err != nil {
+		return err
+	}
+
+	cp, err := p.Provider().Cloud(ctx)
+	if err != nil {
+		return err
+	}
+
+	b := cp.StateBackendInfo(stage)
+	bc := append(slices.Clone(b.InitBackendConfig), backendConfig...)
+
+	err = client.MigrateState(ctx, s, terraform.TerraformInitOpts{
+		BackendConfig:      bc,
+		BackendConfigFiles: p.Settings().Config.Terraform.BackendConfigFiles,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate state for stage %s, %w", stage, err)
+	}
+
+	util.Msgf("State for %s migrated to %s", stage, b.Name)
+	return nil
+}
+
+// TfStateMigrateAll migrates Terraform state for all stages in order.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - backendConfig: Additional backend config key=value pairs overriding the provider defaults.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if any migration fails, otherwise nil.
+func TfStateMigrateAll(ctx context.Context, backendConfig []string, p *CommandParams) error {
+	log.Debug("Entering", "command", "state:migrateAll")
+	defer log.Debug("Completed", "command", "state:migrateAll")
+
+	for _, s := range p.Settings().Config.StagesOrdered() {
+		err := TfStateMigrate(ctx, s.Id, backendConfig, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
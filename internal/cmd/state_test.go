@@ -0,0 +1,178 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestNewRootStateCommand(t *testing.T) {
+	cmds := StateCommandParams{
+		Commands: []*cli.Command{
+			{Name: "show"},
+			{Name: "migrate"},
+		},
+	}
+	cmd := NewRootStateCommand(cmds).Command
+
+	assert.Equal(t, "state", cmd.Name)
+	assert.Len(t, cmd.Commands, 2)
+	assert.Equal(t, "migrate", cmd.Commands[0].Name)
+	assert.Equal(t, "show", cmd.Commands[1].Name)
+}
+
+func TestNewStateMigrateCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewStateMigrateCommand(p).Command
+
+	assert.Equal(t, "migrate", cmd.Name)
+	assert.Len(t, cmd.Flags, 2)
+
+	stageFlag := cmd.Flags[0].(*cli.StringFlag)
+	assert.Equal(t, "stage", stageFlag.Name)
+	assert.False(t, stageFlag.Required)
+
+	bcFlag := cmd.Flags[1].(*cli.StringSliceFlag)
+	assert.Equal(t, "backend-config", bcFlag.Name)
+}
+
+func TestNewStateShowCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewStateShowCommand(p).Command
+
+	assert.Equal(t, "show", cmd.Name)
+	assert.Equal(t, "Print the install state recorded in the state ConfigMap", cmd.Usage)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name})
+	assert.NoError(t, err)
+}
+
+func TestNewStateResetCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewStateResetCommand(p).Command
+
+	assert.Equal(t, "reset", cmd.Name)
+	assert.Len(t, cmd.Flags, 1)
+
+	yesFlag := cmd.Flags[0].(*cli.BoolFlag)
+	assert.Equal(t, "yes", yesFlag.Name)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "--yes"})
+	assert.NoError(t, err)
+	assert.True(t, p.AssumeYes())
+}
+
+func TestCmdStateShow(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	// the state table is written to stdout
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	err := StateShow(context.Background(), p)
+	w.Close()
+	assert.NoError(t, err)
+
+	out, _ := io.ReadAll(r)
+	assert.Contains(t, buf.String(), "Install state quartz/quartz-install-state")
+	assert.Contains(t, string(out), "key1")
+	assert.Contains(t, string(out), "true")
+}
+
+// withStateUpdates replaces the kubernetes client with one recording updates to the state configmap,
+// the fake clientset doesn't keep changes between calls.
+func withStateUpdates(t *testing.T, p *CommandParams, data map[string]string) *[]*corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "quartz-install-state", Namespace: "quartz"},
+		Data:       data,
+	}
+
+	var updates []*corev1.ConfigMap
+	api := provider.NewKubernetesApiMock().WithClientObjects(cm).WithClientReactor("update", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		updates = append(updates, action.(k8sTesting.UpdateAction).GetObject().(*corev1.ConfigMap))
+		return false, nil, nil
+	})
+
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+
+	return &updates
+}
+
+func TestCmdStateShowEmpty(t *testing.T) {
+	p := defaultTestConfig(t)
+	withStateUpdates(t, p, nil)
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	err := StateShow(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "No install state recorded")
+}
+
+func TestCmdStateReset(t *testing.T) {
+	p := defaultTestConfig(t)
+	updates := withStateUpdates(t, p, map[string]string{"key1": "true", "key2": "false"})
+
+	err := StateReset(context.Background(), p)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *updates, 1) {
+		assert.Empty(t, (*updates)[0].Data)
+		assert.Equal(t, "quartz-install-state", (*updates)[0].Name)
+	}
+}
+
+func TestCmdStateResetDeclined(t *testing.T) {
+	p := defaultTestConfig(t)
+	updates := withStateUpdates(t, p, map[string]string{"key1": "true"})
+
+	orig := confirmStateReset
+	t.Cleanup(func() { confirmStateReset = orig })
+	confirmStateReset = func(msg string) bool { return false }
+
+	err := StateReset(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Empty(t, *updates)
+
+	// --yes skips the prompt
+	p.SetAssumeYes(true)
+	err = StateReset(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Len(t, *updates, 1)
+}
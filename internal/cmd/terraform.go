@@ -16,15 +16,68 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
 	"github.com/MetroStar/quartzctl/internal/stages"
 	"github.com/MetroStar/quartzctl/internal/terraform"
 	"github.com/MetroStar/quartzctl/internal/util"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/urfave/cli/v3"
 )
 
+const (
+	tfValidateWorkers = 4 // max stages validated concurrently by validate-all
+)
+
+// tfInitStage runs `terraform init` for the stage, replaceable in tests.
+var tfInitStage = TfInit
+
+// tfValidateStage runs `terraform validate` for the stage, replaceable in tests.
+var tfValidateStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) (*tfjson.ValidateOutput, error) {
+	return terraform.Instance(ctx, *p.Settings()).Validate(ctx, s)
+}
+
+// tfDestroyTargets resolves the resources `terraform destroy` removes for the stage, replaceable in tests.
+var tfDestroyTargets = func(ctx context.Context, p *CommandParams, s schema.StageConfig) ([]string, bool, error) {
+	return terraform.Instance(ctx, *p.Settings()).DestroyTargets(ctx, s)
+}
+
+// tfDestroyStage runs `terraform destroy` for the stage, replaceable in tests.
+var tfDestroyStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) error {
+	return terraform.Instance(ctx, *p.Settings()).Destroy(ctx, s)
+}
+
+// confirmDestroy prompts before destroying the resources in the destroy plan, replaceable in tests.
+var confirmDestroy = util.PromptYesNo
+
+// stageWorkspaces manages the terraform workspaces of a stage.
+type stageWorkspaces interface {
+	WorkspaceList(ctx context.Context, stage schema.StageConfig) ([]string, string, error)
+	WorkspaceSelect(ctx context.Context, stage schema.StageConfig, name string) error
+	WorkspaceNew(ctx context.Context, stage schema.StageConfig, name string) error
+}
+
+// tfWorkspaces returns the terraform workspace client, replaceable in tests.
+var tfWorkspaces = func(ctx context.Context, p *CommandParams) stageWorkspaces {
+	return terraform.Instance(ctx, *p.Settings())
+}
+
+// tfShowStage runs `terraform show` for the stage, replaceable in tests.
+var tfShowStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) (*tfjson.State, error) {
+	return terraform.Instance(ctx, *p.Settings()).Show(ctx, s)
+}
+
 // NewRootTerraformCommand creates the "terraform" root command for the CLI.
 // This command provides subcommands for managing Terraform stages.
 //
@@ -56,14 +109,23 @@ func NewRootTerraformCommand(cmds TfCommandParams, p *CommandParams) RootCommand
 func NewTfInitCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "init",
-			Usage: "Run `terraform init` for a specific stage",
+			Name:          "init",
+			Usage:         "Run `terraform init` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
+				&cli.BoolFlag{Name: "migrate-state", Usage: "Copy existing state to the configured backend instead of reconfiguring it"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
-				return TfInit(ctx, stage, p)
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				if ccmd.Bool("migrate-state") {
+					p.SetInitMode(terraform.InitMigrateState)
+				}
+				return tfInitStage(ctx, stage, p)
 			},
 		},
 	}
@@ -81,7 +143,15 @@ func NewTfInitAllCommand(p *CommandParams) TfCommandResult {
 		Command: &cli.Command{
 			Name:  "init-all",
 			Usage: "Run `terraform init` for all stages",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "workers", Usage: "Max stages initialized concurrently (default terraform.init_workers, or the number of CPUs)"},
+				&cli.BoolFlag{Name: "serial", Usage: "Initialize one stage at a time, in stage order"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				p.SetInitWorkers(int(ccmd.Int("workers")))
+				if ccmd.Bool("serial") {
+					p.SetInitWorkers(1)
+				}
 				return TfInitAll(ctx, p)
 			},
 		},
@@ -98,14 +168,19 @@ func NewTfInitAllCommand(p *CommandParams) TfCommandResult {
 func NewTfApplyCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "apply",
-			Usage: "Run `terraform apply` for a specific stage",
+			Name:          "apply",
+			Usage:         "Run `terraform apply` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 				&cli.BoolFlag{Name: "init", Aliases: []string{"i"}, Usage: "Run `terraform init` before applying", Required: false},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
 				init := ccmd.Bool("init")
 				if init {
 					err := TfInit(ctx, stage, p)
@@ -129,14 +204,19 @@ func NewTfApplyCommand(p *CommandParams) TfCommandResult {
 func NewTfPlanCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "plan",
-			Usage: "Run `terraform plan` for a specific stage",
+			Name:          "plan",
+			Usage:         "Run `terraform plan` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 				&cli.BoolFlag{Name: "init", Aliases: []string{"i"}, Usage: "Run `terraform init` before planning", Required: false},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
 				init := ccmd.Bool("init")
 				if init {
 					err := TfInit(ctx, stage, p)
@@ -160,14 +240,28 @@ func NewTfPlanCommand(p *CommandParams) TfCommandResult {
 func NewTfDestroyCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "destroy",
-			Usage: "Run `terraform destroy` for a specific stage",
+			Name:          "destroy",
+			Usage:         "Run `terraform destroy` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 				&cli.BoolFlag{Name: "init", Aliases: []string{"i"}, Usage: "Run `terraform init` before destroying", Required: false},
+				&cli.StringSliceFlag{Name: "include", Usage: "Only destroy resources matching the address or regex, added to the stage destroy.include (repeatable)"},
+				&cli.StringSliceFlag{Name: "exclude", Usage: "Skip resources matching the address or regex, added to the stage destroy.exclude (repeatable)"},
+				&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Destroy without confirming the resolved destroy targets"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				err = validateDestroyFilters(ccmd.StringSlice("include"), ccmd.StringSlice("exclude"))
+				if err != nil {
+					return err
+				}
+				p.SetDestroyFilters(ccmd.StringSlice("include"), ccmd.StringSlice("exclude"))
+				p.SetAssumeYes(ccmd.Bool("yes"))
 				init := ccmd.Bool("init")
 				if init {
 					err := TfInit(ctx, stage, p)
@@ -175,6 +269,10 @@ func NewTfDestroyCommand(p *CommandParams) TfCommandResult {
 						return err
 					}
 				}
+				err = ConfirmDestroyPlan(ctx, []string{stage}, p)
+				if err != nil {
+					return err
+				}
 				return TfDestroy(ctx, stage, p)
 			},
 		},
@@ -185,14 +283,19 @@ func NewTfDestroyCommand(p *CommandParams) TfCommandResult {
 func NewTfOutputCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "output",
-			Usage: "Retrieve Terraform output for a specific stage",
+			Name:          "output",
+			Usage:         "Retrieve Terraform output for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 				&cli.BoolFlag{Name: "init", Aliases: []string{"i"}, Usage: "Run `terraform init` before retrieving output", Required: false},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
 				init := ccmd.Bool("init")
 				if init {
 					err := TfInit(ctx, stage, p)
@@ -206,18 +309,119 @@ func NewTfOutputCommand(p *CommandParams) TfCommandResult {
 	}
 }
 
+// NewTfShowCommand creates a CLI command for printing the current Terraform state of a specific stage.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - TfCommandResult containing the "show" CLI command.
+func NewTfShowCommand(p *CommandParams) TfCommandResult {
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:          "show",
+			Usage:         "Print the current Terraform state for a specific stage",
+			ShellComplete: stageShellComplete(p),
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
+				&cli.BoolFlag{Name: "json", Usage: "Print the full state as JSON instead of the resource addresses"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				return TfShow(ctx, os.Stdout, stage, ccmd.Bool("json"), p)
+			},
+		},
+	}
+}
+
+// NewTfWorkspaceCommand creates a CLI command for managing the Terraform workspaces of a specific stage.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - TfCommandResult containing the "workspace" CLI command with list, select and new subcommands.
+func NewTfWorkspaceCommand(p *CommandParams) TfCommandResult {
+	stageFlag := func() cli.Flag {
+		return &cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true}
+	}
+	nameFlag := func() cli.Flag {
+		return &cli.StringFlag{Name: "name", Aliases: []string{"n"}, Usage: "Workspace name", Required: true}
+	}
+
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:  "workspace",
+			Usage: "Manage the Terraform workspaces of a specific stage",
+			Commands: []*cli.Command{
+				{
+					Name:          "list",
+					Usage:         "List the workspaces of a stage, the selected workspace is marked with *",
+					ShellComplete: stageShellComplete(p),
+					Flags:         []cli.Flag{stageFlag()},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						stage := ccmd.String("stage")
+						err := ValidateStage(stage, p)
+						if err != nil {
+							return err
+						}
+						return TfWorkspaceList(ctx, os.Stdout, stage, p)
+					},
+				},
+				{
+					Name:          "select",
+					Usage:         "Select an existing workspace for a stage",
+					ShellComplete: stageShellComplete(p),
+					Flags:         []cli.Flag{stageFlag(), nameFlag()},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						stage := ccmd.String("stage")
+						err := ValidateStage(stage, p)
+						if err != nil {
+							return err
+						}
+						return TfWorkspaceSelect(ctx, stage, ccmd.String("name"), p)
+					},
+				},
+				{
+					Name:          "new",
+					Usage:         "Create and select a new workspace for a stage",
+					ShellComplete: stageShellComplete(p),
+					Flags:         []cli.Flag{stageFlag(), nameFlag()},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						stage := ccmd.String("stage")
+						err := ValidateStage(stage, p)
+						if err != nil {
+							return err
+						}
+						return TfWorkspaceNew(ctx, stage, ccmd.String("name"), p)
+					},
+				},
+			},
+		},
+	}
+}
+
 // NewTfRefreshCommand creates a CLI command for running `terraform refresh` on a specific stage.
 func NewTfRefreshCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "refresh",
-			Usage: "Run `terraform refresh` for a specific stage",
+			Name:          "refresh",
+			Usage:         "Run `terraform refresh` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 				&cli.BoolFlag{Name: "init", Aliases: []string{"i"}, Usage: "Run `terraform init` before refreshing", Required: false},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
 				init := ccmd.Bool("init")
 				if init {
 					err := TfInit(ctx, stage, p)
@@ -258,14 +462,33 @@ func NewTfRefreshAllCommand(p *CommandParams) TfCommandResult {
 func NewTfValidateCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:  "validate",
-			Usage: "Run `terraform validate` for a specific stage",
+			Name:          "validate",
+			Usage:         "Run `terraform validate` for a specific stage",
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
-				_, err := TfValidate(ctx, stage, p)
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				_, err = TfValidate(ctx, stage, p)
+				return err
+			},
+		},
+	}
+}
+
+// NewTfValidateAllCommand creates a CLI command for running `terraform validate` on all stages.
+func NewTfValidateAllCommand(p *CommandParams) TfCommandResult {
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:  "validate-all",
+			Usage: "Run `terraform validate` for all stages, including manual stages",
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				_, err := TfValidateAll(ctx, p)
 				return err
 			},
 		},
@@ -276,15 +499,21 @@ func NewTfValidateCommand(p *CommandParams) TfCommandResult {
 func NewTfFormatCommand(p *CommandParams) TfCommandResult {
 	return TfCommandResult{
 		Command: &cli.Command{
-			Name:    "format",
-			Usage:   "Run `terraform fmt` for a specific stage",
-			Aliases: []string{"fmt"},
+			Name:          "format",
+			Usage:         "Run `terraform fmt` for a specific stage",
+			Aliases:       []string{"fmt"},
+			ShellComplete: stageShellComplete(p),
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
+				&cli.BoolFlag{Name: "check", Usage: "fail listing unformatted files instead of rewriting them"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				stage := ccmd.String("stage")
-				return TfFormat(ctx, stage, p)
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				return TfFormat(ctx, stage, ccmd.Bool("check"), p)
 			},
 		},
 	}
@@ -296,8 +525,11 @@ func NewTfFormatAllCommand(p *CommandParams) TfCommandResult {
 		Command: &cli.Command{
 			Name:  "format-all",
 			Usage: "Run `terraform fmt` for all stages",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "check", Usage: "fail listing unformatted files instead of rewriting them"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
-				return TfFormatAll(ctx, p)
+				return TfFormatAll(ctx, ccmd.Bool("check"), p)
 			},
 		},
 	}
@@ -316,6 +548,95 @@ func NewTfVersionCommand(p *CommandParams) TfCommandResult {
 	}
 }
 
+// NewTfProvidersLockCommand creates a CLI command for running `terraform providers lock` on a specific stage.
+func NewTfProvidersLockCommand(p *CommandParams) TfCommandResult {
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:          "providers-lock",
+			Usage:         "Run `terraform providers lock` for a specific stage to record provider checksums for multiple platforms",
+			ShellComplete: stageShellComplete(p),
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
+				&cli.StringSliceFlag{Name: "platform", Usage: "platform to lock providers for, e.g. linux_amd64 (repeatable, defaults to the current platform)"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				return TfProvidersLock(ctx, stage, ccmd.StringSlice("platform"), p)
+			},
+		},
+	}
+}
+
+// NewTfGraphCommand creates a CLI command for printing the stage dependency graph.
+func NewTfGraphCommand(p *CommandParams) TfCommandResult {
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:  "graph",
+			Usage: "Output the stage dependency graph in Graphviz DOT format",
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				return TfGraph(os.Stdout, p)
+			},
+		},
+	}
+}
+
+// NewTfForceUnlockCommand creates a CLI command for running `terraform force-unlock` on a specific stage.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - TfCommandResult containing the "force-unlock" CLI command.
+func NewTfForceUnlockCommand(p *CommandParams) TfCommandResult {
+	return TfCommandResult{
+		Command: &cli.Command{
+			Name:          "force-unlock",
+			Usage:         "Run `terraform force-unlock` to release a stuck state lock for a specific stage",
+			ShellComplete: stageShellComplete(p),
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "stage", Aliases: []string{"s"}, Usage: "Stage name", Required: true},
+				&cli.StringFlag{Name: "lock-id", Usage: "ID of the state lock to release", Required: true},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				stage := ccmd.String("stage")
+				err := ValidateStage(stage, p)
+				if err != nil {
+					return err
+				}
+				lockId := ccmd.String("lock-id")
+				return TfForceUnlock(ctx, stage, lockId, p)
+			},
+		},
+	}
+}
+
+// ValidateStage checks that the stage is configured, suggesting the closest configured
+// stage id when it looks like a typo.
+//
+// Parameters:
+//   - stage: The stage name from the --stage flag.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: A config error listing the valid stages if the stage is not configured, otherwise nil.
+func ValidateStage(stage string, p *CommandParams) error {
+	stages := p.Settings().Config.Stages
+	if _, ok := stages[stage]; ok {
+		return nil
+	}
+
+	ids := slices.Sorted(maps.Keys(stages))
+	if s, ok := util.ClosestMatch(stage, ids); ok {
+		return util.NewConfigErrorf("stage %s not found, did you mean %s? valid stages: %s", stage, s, strings.Join(ids, ", "))
+	}
+
+	return util.NewConfigErrorf("stage %s not found, valid stages: %s", stage, strings.Join(ids, ", "))
+}
+
 // TfInit runs `terraform init` for a specific stage.
 func TfInit(ctx context.Context, stage string, p *CommandParams) error {
 	return util.RunOnce("tf:init:"+stage, func() error {
@@ -324,42 +645,89 @@ func TfInit(ctx context.Context, stage string, p *CommandParams) error {
 
 		util.Hdrf("Init %s", stage)
 
-		client := terraform.Instance(ctx, *p.Settings())
 		err := tfStagePrep(ctx, stage, p)
 		if err != nil {
 			return err
 		}
 
+		client := terraform.Instance(ctx, *p.Settings())
+
 		cp, _ := p.Provider().Cloud(ctx)
 		b := cp.StateBackendInfo(stage) // TODO, clean this up
 
 		return wrapChecks(ctx, stage, "init", p, func() error {
 			s := p.Settings().Config.Stages[stage]
 			return client.Init(ctx, s, terraform.TerraformInitOpts{
-				BackendConfig: b.InitBackendConfig,
+				BackendConfig:      b.InitBackendConfig,
+				BackendConfigFiles: p.Settings().Config.Terraform.BackendConfigFiles,
+				Mode:               p.InitMode(),
 			})
 		})
 	})
 }
 
-// TfInitAll runs `terraform init` for all stages.
+// TfInitAll runs `terraform init` for all stages, a few at a time. Stages sharing a directory
+// (e.g. matrix stages) are initialized one after another, and a failed stage doesn't stop the others.
 func TfInitAll(ctx context.Context, p *CommandParams) error {
 	log.Debug("Entering", "command", "tf:initAll")
 	defer log.Debug("Completed", "command", "tf:initAll")
 
-	err := tfStagePrep(ctx, "", p)
-	if err != nil {
+	stages := p.Settings().Config.StagesOrdered()
+
+	// prepare the stages and resolve the cloud provider before starting the workers, the provider
+	// factory initializes its clients lazily and isn't safe to initialize concurrently
+	for _, s := range stages {
+		if err := tfStagePrep(ctx, s.Id, p); err != nil {
+			return err
+		}
+	}
+
+	if _, err := p.Provider().Cloud(ctx); err != nil {
 		return err
 	}
 
-	for _, s := range p.Settings().Config.StagesOrdered() {
-		err = TfInit(ctx, s.Id, p)
-		if err != nil {
-			return err
+	// group stages by directory, concurrent inits in the same directory would conflict
+	var groups [][]schema.StageConfig
+	index := map[string]int{}
+	for _, s := range stages {
+		i, ok := index[s.Path]
+		if !ok || s.Path == "" {
+			i = len(groups)
+			index[s.Path] = i
+			groups = append(groups, nil)
 		}
+		groups[i] = append(groups[i], s)
 	}
 
-	return nil
+	workers := p.InitWorkers()
+	log.Debug("Initializing stages", "groups", len(groups), "workers", workers)
+
+	// groups are handed out in stage order, so a single worker initializes stages in order
+	errs := make([][]error, len(groups))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for range min(workers, len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				for _, s := range groups[i] {
+					if err := tfInitStage(ctx, s.Id, p); err != nil {
+						errs[i] = append(errs[i], terraform.NewOperationError(s.Id, terraform.OpInit, err))
+					}
+				}
+			}
+		}()
+	}
+
+	for i := range groups {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(slices.Concat(errs...)...)
 }
 
 // TfPlan runs `terraform plan` for a specific stage.
@@ -369,12 +737,13 @@ func TfPlan(ctx context.Context, stage string, p *CommandParams) error {
 
 	util.Hdrf("Plan %s", stage)
 
-	client := terraform.Instance(ctx, *p.Settings())
 	err := tfStagePrep(ctx, stage, p)
 	if err != nil {
 		return err
 	}
 
+	client := terraform.Instance(ctx, *p.Settings())
+
 	return wrapChecks(ctx, stage, "plan", p, func() error {
 		s := p.Settings().Config.Stages[stage]
 		empty, err := client.Plan(ctx, s)
@@ -392,12 +761,13 @@ func TfApply(ctx context.Context, stage string, p *CommandParams) error {
 
 	util.Hdrf("Apply %s", stage)
 
-	client := terraform.Instance(ctx, *p.Settings())
 	err := tfStagePrep(ctx, stage, p)
 	if err != nil {
 		return err
 	}
 
+	client := terraform.Instance(ctx, *p.Settings())
+
 	return wrapChecks(ctx, stage, "apply", p, func() error {
 		s := p.Settings().Config.Stages[stage]
 		return client.Apply(ctx, s)
@@ -411,7 +781,6 @@ func TfDestroy(ctx context.Context, stage string, p *CommandParams) error {
 
 	util.Hdrf("Destroy %s", stage)
 
-	client := terraform.Instance(ctx, *p.Settings())
 	err := tfStagePrep(ctx, stage, p)
 	if err != nil {
 		return err
@@ -424,8 +793,119 @@ func TfDestroy(ctx context.Context, stage string, p *CommandParams) error {
 		return err
 	}
 
-	s := p.Settings().Config.Stages[stage]
-	return client.Destroy(ctx, s)
+	s := destroyStageConfig(p.Settings().Config.Stages[stage], p)
+	err = tfDestroyStage(ctx, p, s)
+	if err != nil {
+		suggestForceUnlock(stage, err)
+		return err
+	}
+
+	return nil
+}
+
+// ConfirmDestroyPlan prints the resources each stage's destroy removes, the resolved targets for
+// stages with destroy filters or a full destroy otherwise, and prompts before continuing.
+// The prompt is skipped with --yes or the SILENT environment variable.
+func ConfirmDestroyPlan(ctx context.Context, stages []string, p *CommandParams) error {
+	log.Debug("Entering", "internal", "confirmDestroyPlan", "stages", stages)
+	defer log.Debug("Completed", "internal", "confirmDestroyPlan", "stages", stages)
+
+	util.Hdr("Destroy plan")
+
+	for _, id := range stages {
+		s := destroyStageConfig(p.Settings().Config.Stages[id], p)
+		if s.Destroy.Skip {
+			util.Infof("%s: skipped (destroy.skip)", id)
+			continue
+		}
+
+		targets, found, err := tfDestroyTargets(ctx, p, s)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !found:
+			util.Infof("%s: no resources match the destroy filters, nothing to destroy", id)
+		case len(targets) == 0:
+			util.Infof("%s: full destroy", id)
+		default:
+			util.Infof("%s: %d targeted resources", id, len(targets))
+			for _, t := range targets {
+				util.Infof("  - %s", t)
+			}
+		}
+	}
+
+	if p.AssumeYes() {
+		return nil
+	}
+
+	if !confirmDestroy("Destroy the resources listed above?") {
+		return errAborted
+	}
+
+	return nil
+}
+
+// destroyStageConfig adds the --include and --exclude filters from the command line to the
+// stage's configured destroy filters, exclusions still take priority over inclusions.
+func destroyStageConfig(s schema.StageConfig, p *CommandParams) schema.StageConfig {
+	include, exclude := p.DestroyFilters()
+	s.Destroy.Include = slices.Concat(s.Destroy.Include, include)
+	s.Destroy.Exclude = slices.Concat(s.Destroy.Exclude, exclude)
+	return s
+}
+
+// validateDestroyFilters checks the --include and --exclude patterns compile, matching the
+// validation applied to the configured stage destroy filters.
+func validateDestroyFilters(include []string, exclude []string) error {
+	errs := slices.Concat(
+		schema.ValidateDestroyPatterns("--include", include),
+		schema.ValidateDestroyPatterns("--exclude", exclude))
+
+	if err := errors.Join(errs...); err != nil {
+		return util.NewConfigErrorf("%w", err)
+	}
+
+	return nil
+}
+
+// TfForceUnlock runs `terraform force-unlock` for a specific stage after confirmation.
+func TfForceUnlock(ctx context.Context, stage string, lockId string, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:forceUnlock", "stage", stage)
+	defer log.Debug("Completed", "command", "tf:forceUnlock", "stage", stage)
+
+	s, found := p.Settings().Config.Stages[stage]
+	if !found {
+		return fmt.Errorf("stage %s not found", stage)
+	}
+
+	util.Hdrf("Force unlock %s", stage)
+
+	if !util.PromptYesNo(fmt.Sprintf("Release state lock %s for stage %s? Only do this if no other process is running", lockId, stage)) {
+		util.Msg("Cancelled")
+		return nil
+	}
+
+	client := terraform.Instance(ctx, *p.Settings())
+	err := tfStagePrep(ctx, stage, p)
+	if err != nil {
+		return err
+	}
+
+	return client.ForceUnlock(ctx, s, lockId)
+}
+
+// suggestForceUnlock prints the force-unlock command to run when err is a state lock error.
+func suggestForceUnlock(stage string, err error) {
+	lockId, found := terraform.ParseLockId(err)
+	if !found {
+		return
+	}
+
+	util.Msgf("State for stage %s is locked, if no other process holds the lock run:", stage)
+	util.Msgf("  quartz tf force-unlock --stage %s --lock-id %s", stage, lockId)
 }
 
 // TfOutput retrieves the Terraform output for a specific stage.
@@ -436,13 +916,14 @@ func TfOutput(ctx context.Context, stage string, p *CommandParams) error {
 
 		util.Hdrf("Output %s", stage)
 
-		client := terraform.Instance(ctx, *p.Settings())
-		s := p.Settings().Config.Stages[stage]
 		err := tfStagePrep(ctx, stage, p)
 		if err != nil {
 			return err
 		}
 
+		client := terraform.Instance(ctx, *p.Settings())
+		s := p.Settings().Config.Stages[stage]
+
 		o, err := client.Output(ctx, s)
 		if err != nil {
 			return err
@@ -456,6 +937,116 @@ func TfOutput(ctx context.Context, stage string, p *CommandParams) error {
 	})
 }
 
+// TfShow writes the current Terraform state for a specific stage, either as JSON
+// or as a plaintext list of resource addresses.
+func TfShow(ctx context.Context, w io.Writer, stage string, asJson bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:show", "stage", stage)
+	defer log.Debug("Completed", "command", "tf:show", "stage", stage)
+
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return err
+	}
+
+	state, err := tfShowStage(ctx, p, s)
+	if err != nil {
+		return err
+	}
+
+	if asJson {
+		b, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	addrs := terraform.StateAddresses(state)
+	if len(addrs) == 0 {
+		_, err = fmt.Fprintf(w, "No resources in state for stage %s\n", stage)
+		return err
+	}
+
+	for _, a := range addrs {
+		if _, err := fmt.Fprintln(w, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TfWorkspaceList writes the Terraform workspaces of a specific stage, marking the selected one.
+func TfWorkspaceList(ctx context.Context, w io.Writer, stage string, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:workspace:list", "stage", stage)
+	defer log.Debug("Completed", "command", "tf:workspace:list", "stage", stage)
+
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return err
+	}
+
+	ws, current, err := tfWorkspaces(ctx, p).WorkspaceList(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ws {
+		marker := " "
+		if name == current {
+			marker = "*"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", marker, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TfWorkspaceSelect selects an existing Terraform workspace for a specific stage.
+func TfWorkspaceSelect(ctx context.Context, stage string, name string, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:workspace:select", "stage", stage, "workspace", name)
+	defer log.Debug("Completed", "command", "tf:workspace:select", "stage", stage, "workspace", name)
+
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return err
+	}
+
+	if s.Workspace != "" && s.Workspace != name {
+		log.Warn("Stage workspace is configured, it's selected again before the next terraform run", "stage", stage, "workspace", s.Workspace)
+	}
+
+	err = tfWorkspaces(ctx, p).WorkspaceSelect(ctx, s, name)
+	if err != nil {
+		return err
+	}
+
+	util.Msgf("Selected workspace %s for stage %s", name, stage)
+	return nil
+}
+
+// TfWorkspaceNew creates and selects a new Terraform workspace for a specific stage.
+func TfWorkspaceNew(ctx context.Context, stage string, name string, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:workspace:new", "stage", stage, "workspace", name)
+	defer log.Debug("Completed", "command", "tf:workspace:new", "stage", stage, "workspace", name)
+
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return err
+	}
+
+	err = tfWorkspaces(ctx, p).WorkspaceNew(ctx, s, name)
+	if err != nil {
+		return err
+	}
+
+	util.Msgf("Created and selected workspace %s for stage %s", name, stage)
+	return nil
+}
+
 // TfRefresh runs `terraform refresh` for a specific stage.
 func TfRefresh(ctx context.Context, stage string, p *CommandParams) error {
 	return util.RunOnce("tf:refresh:"+stage, func() error {
@@ -464,16 +1055,17 @@ func TfRefresh(ctx context.Context, stage string, p *CommandParams) error {
 
 		util.Hdrf("Refresh %s", stage)
 
-		client := terraform.Instance(ctx, *p.Settings())
 		err := tfStagePrep(ctx, stage, p)
 		if err != nil {
 			return err
 		}
 
+		client := terraform.Instance(ctx, *p.Settings())
 		s := p.Settings().Config.Stages[stage]
 		err = client.Refresh(ctx, s)
 		if err != nil {
 			log.Info("Error refreshing terraform", "stage", s.Id, "err", err)
+			suggestForceUnlock(stage, err)
 			return err
 		}
 
@@ -508,37 +1100,232 @@ func TfValidate(ctx context.Context, stage string, p *CommandParams) (int, error
 
 	util.Hdrf("Validate %s", stage)
 
-	client := terraform.Instance(ctx, *p.Settings())
-	s := p.Settings().Config.Stages[stage]
-	v, err := client.Validate(ctx, s)
-	return v.ErrorCount, err
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := tfValidateStage(ctx, p, s)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.ErrorCount, nil
+}
+
+// TfValidateResult represents the `terraform validate` result of a single stage.
+type TfValidateResult struct {
+	Stage    string // The stage id.
+	Errors   int    // The number of error diagnostics.
+	Warnings int    // The number of warning diagnostics.
+	Message  string // The summary of the first error diagnostic, if any.
+	Err      error  // Any error encountered running validate.
 }
 
-// TfFormat runs `terraform fmt` for a specific stage.
-func TfFormat(ctx context.Context, stage string, p *CommandParams) error {
-	log.Debug("Entering", "command", "tf:format", "stage", stage)
-	defer log.Debug("Completed", "command", "tf:format", "stage", stage)
+// Failed reports whether the stage failed to validate.
+func (r TfValidateResult) Failed() bool {
+	return r.Err != nil || r.Errors > 0
+}
+
+// TfValidateAll runs `terraform validate` concurrently for all stages, including manual stages,
+// and prints an aggregated report.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - []TfValidateResult: The validate result of each stage, in stage order.
+//   - error: An error listing the failed stages if any stage has errors, otherwise nil.
+func TfValidateAll(ctx context.Context, p *CommandParams) ([]TfValidateResult, error) {
+	log.Debug("Entering", "command", "tf:validateAll")
+	defer log.Debug("Completed", "command", "tf:validateAll")
+
+	util.Hdr("Validate all stages")
+
+	all := p.Settings().Config.StagesOrdered()
+	for _, k := range slices.Sorted(maps.Keys(p.Settings().Config.Stages)) {
+		if s := p.Settings().Config.Stages[k]; s.Manual {
+			all = append(all, s)
+		}
+	}
+
+	results := make([]TfValidateResult, len(all))
+	sem := make(chan struct{}, tfValidateWorkers)
+	wg := sync.WaitGroup{}
+
+	for i, s := range all {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			log.Debug("Validating stage", "stage", s.Id)
+			v, err := tfValidateStage(ctx, p, s)
+			results[i] = newTfValidateResult(s.Id, v, err)
+		}()
+	}
+	wg.Wait()
+
+	var failed []string
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		msg := r.Message
+		if r.Err != nil {
+			msg = r.Err.Error()
+		}
+		rows[i] = []string{r.Stage, strconv.Itoa(r.Errors), strconv.Itoa(r.Warnings), msg}
+
+		if r.Failed() {
+			failed = append(failed, r.Stage)
+		}
+	}
+
+	util.PrintRowStatusTable([]string{"Stage", "Errors", "Warnings", "Message"}, rows, func(i int, row []string) util.RowStatus {
+		switch {
+		case results[i].Failed():
+			return util.StatusError
+		case results[i].Warnings > 0:
+			return util.StatusWarning
+		default:
+			return util.StatusOk
+		}
+	})
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf("terraform validate failed for %d stage(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return results, nil
+}
+
+// newTfValidateResult summarizes the validate output of a stage.
+func newTfValidateResult(stage string, v *tfjson.ValidateOutput, err error) TfValidateResult {
+	r := TfValidateResult{Stage: stage, Err: err}
+	if err != nil || v == nil {
+		return r
+	}
+
+	r.Errors = v.ErrorCount
+	r.Warnings = v.WarningCount
+	for _, d := range v.Diagnostics {
+		if d.Severity == tfjson.DiagnosticSeverityError {
+			r.Message = d.Summary
+			break
+		}
+	}
+
+	return r
+}
+
+// TfFormat runs `terraform fmt` for a specific stage. In check mode the files are left
+// untouched and an error listing the unformatted files is returned instead.
+func TfFormat(ctx context.Context, stage string, check bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:format", "stage", stage, "check", check)
+	defer log.Debug("Completed", "command", "tf:format", "stage", stage, "check", check)
 
 	util.Hdrf("Format %s", stage)
 
-	client := terraform.Instance(ctx, *p.Settings())
-	s := p.Settings().Config.Stages[stage]
-	return client.Format(ctx, s)
+	if !check {
+		s, err := lookupStage(stage, p)
+		if err != nil {
+			return err
+		}
+
+		client := terraform.Instance(ctx, *p.Settings())
+		return client.Format(ctx, s)
+	}
+
+	files, err := tfFormatCheck(ctx, stage, p)
+	if err != nil {
+		return err
+	}
+
+	return unformattedFilesError(files)
 }
 
-// TfFormatAll runs `terraform fmt` for all stages.
-func TfFormatAll(ctx context.Context, p *CommandParams) error {
-	log.Debug("Entering", "command", "tf:formatAll")
-	defer log.Debug("Completed", "command", "tf:formatAll")
+// TfFormatAll runs `terraform fmt` for all stages. In check mode every stage is checked
+// before returning an error listing the unformatted files.
+func TfFormatAll(ctx context.Context, check bool, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:formatAll", "check", check)
+	defer log.Debug("Completed", "command", "tf:formatAll", "check", check)
 
+	var unformatted []string
 	for _, s := range p.Settings().Config.StagesOrdered() {
-		err := TfFormat(ctx, s.Id, p)
+		if !check {
+			err := TfFormat(ctx, s.Id, false, p)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		util.Hdrf("Format %s", s.Id)
+		files, err := tfFormatCheck(ctx, s.Id, p)
 		if err != nil {
 			return err
 		}
+		unformatted = append(unformatted, files...)
 	}
 
-	return nil
+	return unformattedFilesError(unformatted)
+}
+
+// tfFormatCheck lists the files of the stage that `terraform fmt` would rewrite.
+func tfFormatCheck(ctx context.Context, stage string, p *CommandParams) ([]string, error) {
+	s, err := lookupStage(stage, p)
+	if err != nil {
+		return nil, err
+	}
+
+	client := terraform.Instance(ctx, *p.Settings())
+	files, err := client.FormatCheck(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		util.Msgf("%s is not formatted", f)
+	}
+
+	return files, nil
+}
+
+// unformattedFilesError returns an error listing the unformatted files, or nil if there are none.
+func unformattedFilesError(files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d file(s) not formatted, run `quartz terraform format-all` to fix: %s", len(files), strings.Join(files, ", "))
+}
+
+// TfProvidersLock runs `terraform providers lock` for a specific stage, updating the stage's
+// .terraform.lock.hcl with provider checksums for each platform.
+func TfProvidersLock(ctx context.Context, stage string, platforms []string, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:providersLock", "stage", stage, "platforms", platforms)
+	defer log.Debug("Completed", "command", "tf:providersLock", "stage", stage, "platforms", platforms)
+
+	util.Hdrf("Providers lock %s", stage)
+
+	err := tfStagePrep(ctx, stage, p)
+	if err != nil {
+		return err
+	}
+
+	s := p.Settings().Config.Stages[stage]
+	client := terraform.Instance(ctx, *p.Settings())
+	return client.ProvidersLock(ctx, s, platforms)
+}
+
+// TfGraph writes the stage dependency graph in Graphviz DOT format, e.g. for `dot -Tsvg`.
+func TfGraph(w io.Writer, p *CommandParams) error {
+	log.Debug("Entering", "command", "tf:graph")
+	defer log.Debug("Completed", "command", "tf:graph")
+
+	_, err := io.WriteString(w, stages.StagesGraph(p.Settings().Config.Stages))
+	return err
 }
 
 // TfVersion checks and displays the Terraform version.
@@ -602,31 +1389,56 @@ func wrapChecks(ctx context.Context, stage string, event string, p *CommandParam
 
 	err = f()
 	if err != nil {
+		suggestForceUnlock(stage, err)
 		return err
 	}
 
 	return postCheck(ctx, stage, event, p)
 }
 
+// lookupStage returns the configured stage, or a ConfigError if the stage id is empty or unknown
+// rather than a zero value stage which would run terraform in the wrong directory.
+func lookupStage(stage string, p *CommandParams) (schema.StageConfig, error) {
+	if stage == "" {
+		return schema.StageConfig{}, util.NewConfigErrorf("stage required")
+	}
+
+	s, ok := p.Settings().Config.Stages[stage]
+	if !ok {
+		return s, ValidateStage(stage, p)
+	}
+
+	return s, nil
+}
+
 // tfStagePrep prepares the Terraform stage for execution.
 func tfStagePrep(ctx context.Context, stage string, p *CommandParams) error {
-	err := util.RunOnce("tf:prep:0", func() error {
-		return p.Settings().WriteJsonConfig(p.Settings().Config.TfVarFilePath(), "settings", true)
-	})
+	s, err := lookupStage(stage, p)
 	if err != nil {
 		return err
 	}
 
-	if stage == "" {
-		return nil
+	err = util.RunOnce("tf:prep:0", func() error {
+		return p.Settings().WriteJsonConfig(p.Settings().Config.TfVarFilePath(), "settings", "", true)
+	})
+	if err != nil {
+		return err
 	}
 
-	s := p.Settings().Config.Stages[stage]
 	if !s.Providers.Kubernetes {
 		return nil
 	}
 
 	return util.RunOnce("tf:prep:1", func() error {
-		return ClusterLogin(ctx, "", p)
+		err := ClusterLogin(ctx, "", p)
+
+		// suppressing access errors here, if the cluster is unavailable it's not always a blocker downstream
+		var accessErr util.AccessError
+		if errors.As(err, &accessErr) {
+			log.Warn("Continuing without kubeconfig", "err", err)
+			return nil
+		}
+
+		return err
 	})
 }
@@ -15,9 +15,21 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/terraform"
+	"github.com/MetroStar/quartzctl/internal/util"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v3"
 )
@@ -32,7 +44,7 @@ func TestNewTfInitCommand(t *testing.T) {
 
 	assert.Equal(t, "init", cmd.Name)
 	assert.Equal(t, "Run `terraform init` for a specific stage", cmd.Usage)
-	assert.Len(t, cmd.Flags, 1)
+	assert.Len(t, cmd.Flags, 2)
 
 	flag := cmd.Flags[0].(*cli.StringFlag)
 	assert.Equal(t, "stage", flag.Name)
@@ -41,12 +53,41 @@ func TestNewTfInitCommand(t *testing.T) {
 	runTestTfCommandWithStage(t, cmd)
 }
 
+func TestNewTfInitCommandMode(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected terraform.TerraformInitMode
+	}{
+		"default":       {nil, terraform.InitReconfigure},
+		"migrate-state": {[]string{"--migrate-state"}, terraform.InitMigrateState},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := defaultTestConfig(t)
+			mockTfInit(t, nil)
+
+			var mode terraform.TerraformInitMode
+			tfInitStage = func(ctx context.Context, stage string, p *CommandParams) error {
+				mode = p.InitMode()
+				return nil
+			}
+
+			cmd := NewTfInitCommand(p).Command
+			err := cmd.Run(context.Background(), append([]string{cmd.Name, "--stage", testStage}, tt.args...))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
 func TestNewTfInitAllCommand(t *testing.T) {
 	p := defaultTestConfig(t)
 	cmd := NewTfInitAllCommand(p).Command
 
 	assert.Equal(t, "init-all", cmd.Name)
 	assert.Equal(t, "Run `terraform init` for all stages", cmd.Usage)
+	assert.Len(t, cmd.Flags, 2)
 
 	runTestTfCommand(t, cmd)
 }
@@ -93,7 +134,7 @@ func TestNewTfDestroyCommand(t *testing.T) {
 
 	assert.Equal(t, "destroy", cmd.Name)
 	assert.Equal(t, "Run `terraform destroy` for a specific stage", cmd.Usage)
-	assert.Len(t, cmd.Flags, 2)
+	assert.Len(t, cmd.Flags, 5)
 
 	stageFlag := cmd.Flags[0].(*cli.StringFlag)
 	assert.Equal(t, "stage", stageFlag.Name)
@@ -102,6 +143,15 @@ func TestNewTfDestroyCommand(t *testing.T) {
 	initFlag := cmd.Flags[1].(*cli.BoolFlag)
 	assert.Equal(t, "init", initFlag.Name)
 
+	includeFlag := cmd.Flags[2].(*cli.StringSliceFlag)
+	assert.Equal(t, "include", includeFlag.Name)
+
+	excludeFlag := cmd.Flags[3].(*cli.StringSliceFlag)
+	assert.Equal(t, "exclude", excludeFlag.Name)
+
+	yesFlag := cmd.Flags[4].(*cli.BoolFlag)
+	assert.Equal(t, "yes", yesFlag.Name)
+
 	runTestTfCommandWithStage(t, cmd)
 }
 
@@ -123,6 +173,124 @@ func TestNewTfOutputCommand(t *testing.T) {
 	runTestTfCommandWithStage(t, cmd)
 }
 
+func TestNewTfShowCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfShow(t, &tfjson.State{})
+	cmd := NewTfShowCommand(p).Command
+
+	assert.Equal(t, "show", cmd.Name)
+	assert.Equal(t, "Print the current Terraform state for a specific stage", cmd.Usage)
+	assert.Len(t, cmd.Flags, 2)
+
+	stageFlag := cmd.Flags[0].(*cli.StringFlag)
+	assert.Equal(t, "stage", stageFlag.Name)
+	assert.True(t, stageFlag.Required)
+
+	jsonFlag := cmd.Flags[1].(*cli.BoolFlag)
+	assert.Equal(t, "json", jsonFlag.Name)
+
+	runTestTfCommandWithStage(t, cmd)
+}
+
+// workspaceStub keeps terraform workspaces in memory by stage id.
+type workspaceStub struct {
+	workspaces map[string][]string
+	current    map[string]string
+}
+
+func (w *workspaceStub) WorkspaceList(ctx context.Context, stage schema.StageConfig) ([]string, string, error) {
+	current := w.current[stage.Id]
+	if current == "" {
+		current = "default"
+	}
+	return append([]string{"default"}, w.workspaces[stage.Id]...), current, nil
+}
+
+func (w *workspaceStub) WorkspaceSelect(ctx context.Context, stage schema.StageConfig, name string) error {
+	if name != "default" && !slices.Contains(w.workspaces[stage.Id], name) {
+		return fmt.Errorf("workspace %q doesn't exist", name)
+	}
+	w.current[stage.Id] = name
+	return nil
+}
+
+func (w *workspaceStub) WorkspaceNew(ctx context.Context, stage schema.StageConfig, name string) error {
+	if slices.Contains(w.workspaces[stage.Id], name) {
+		return fmt.Errorf("workspace %q already exists", name)
+	}
+	w.workspaces[stage.Id] = append(w.workspaces[stage.Id], name)
+	w.current[stage.Id] = name
+	return nil
+}
+
+// mockTfWorkspaces replaces the terraform workspace client with an in-memory stub for the duration of the test.
+func mockTfWorkspaces(t *testing.T) *workspaceStub {
+	orig := tfWorkspaces
+	t.Cleanup(func() { tfWorkspaces = orig })
+
+	stub := &workspaceStub{workspaces: make(map[string][]string), current: make(map[string]string)}
+	tfWorkspaces = func(ctx context.Context, p *CommandParams) stageWorkspaces {
+		return stub
+	}
+
+	return stub
+}
+
+func TestNewTfWorkspaceCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	stub := mockTfWorkspaces(t)
+	cmd := NewTfWorkspaceCommand(p).Command
+
+	assert.Equal(t, "workspace", cmd.Name)
+	assert.Equal(t, "Manage the Terraform workspaces of a specific stage", cmd.Usage)
+	assert.Len(t, cmd.Commands, 3)
+
+	names := []string{}
+	for _, c := range cmd.Commands {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"list", "select", "new"}, names)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "new", "-s", testStage})
+	assert.Error(t, err) // Missing required flag
+
+	err = cmd.Run(context.Background(), []string{cmd.Name, "new", "-s", testStage, "--name", "dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", stub.current[testStage])
+
+	err = cmd.Run(context.Background(), []string{cmd.Name, "select", "-s", testStage, "-n", "default"})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", stub.current[testStage])
+
+	err = cmd.Run(context.Background(), []string{cmd.Name, "list", "-s", testStage})
+	assert.NoError(t, err)
+}
+
+func TestCmdTfWorkspace(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfWorkspaces(t)
+
+	err := TfWorkspaceNew(context.Background(), testStage, "dev", p)
+	assert.NoError(t, err)
+
+	err = TfWorkspaceNew(context.Background(), testStage, "staging", p)
+	assert.NoError(t, err)
+
+	err = TfWorkspaceSelect(context.Background(), testStage, "dev", p)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = TfWorkspaceList(context.Background(), &buf, testStage, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "  default\n* dev\n  staging\n", buf.String())
+
+	err = TfWorkspaceSelect(context.Background(), testStage, "missing", p)
+	assert.ErrorContains(t, err, `workspace "missing" doesn't exist`)
+
+	err = TfWorkspaceNew(context.Background(), "missing", "dev", p)
+	assert.ErrorContains(t, err, "stage missing not found")
+}
+
 func TestNewTfRefreshCommand(t *testing.T) {
 	p := defaultTestConfig(t)
 	cmd := NewTfRefreshCommand(p).Command
@@ -170,18 +338,86 @@ func TestNewTfValidateCommand(t *testing.T) {
 	runTestTfCommandWithStage(t, cmd)
 }
 
+func TestNewTfValidateAllCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewTfValidateAllCommand(p).Command
+
+	assert.Equal(t, "validate-all", cmd.Name)
+	assert.Equal(t, "Run `terraform validate` for all stages, including manual stages", cmd.Usage)
+}
+
+// mockTfValidate replaces terraform validate with canned results by stage id for the duration of the test.
+func mockTfValidate(t *testing.T, outputs map[string]*tfjson.ValidateOutput, errs map[string]error) {
+	orig := tfValidateStage
+	t.Cleanup(func() { tfValidateStage = orig })
+
+	tfValidateStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) (*tfjson.ValidateOutput, error) {
+		if err, ok := errs[s.Id]; ok {
+			return nil, err
+		}
+		if v, ok := outputs[s.Id]; ok {
+			return v, nil
+		}
+		return &tfjson.ValidateOutput{Valid: true}, nil
+	}
+}
+
+func TestCmdTfValidateAll(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Stages["second"] = schema.StageConfig{Id: "second", Order: 2}
+	p.Settings().Config.Stages["third"] = schema.StageConfig{Id: "third", Order: 3}
+	p.Settings().Config.Stages["manual"] = schema.StageConfig{Id: "manual", Manual: true}
+
+	mockTfValidate(t, map[string]*tfjson.ValidateOutput{
+		"second": {WarningCount: 1, Diagnostics: []tfjson.Diagnostic{{Severity: tfjson.DiagnosticSeverityWarning, Summary: "deprecated"}}},
+		"third": {ErrorCount: 2, Diagnostics: []tfjson.Diagnostic{
+			{Severity: tfjson.DiagnosticSeverityWarning, Summary: "deprecated"},
+			{Severity: tfjson.DiagnosticSeverityError, Summary: "Unsupported argument"},
+			{Severity: tfjson.DiagnosticSeverityError, Summary: "Missing required argument"},
+		}},
+	}, map[string]error{
+		"manual": fmt.Errorf("terraform not initialized"),
+	})
+
+	results, err := TfValidateAll(context.Background(), p)
+	assert.EqualError(t, err, "terraform validate failed for 2 stage(s): third, manual")
+
+	assert.Equal(t, []TfValidateResult{
+		{Stage: testStage},
+		{Stage: "second", Warnings: 1},
+		{Stage: "third", Errors: 2, Message: "Unsupported argument"},
+		{Stage: "manual", Err: fmt.Errorf("terraform not initialized")},
+	}, results)
+}
+
+func TestCmdTfValidateAllValid(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Stages["second"] = schema.StageConfig{Id: "second", Order: 2}
+
+	mockTfValidate(t, map[string]*tfjson.ValidateOutput{
+		"second": {WarningCount: 1},
+	}, nil)
+
+	results, err := TfValidateAll(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
 func TestNewTfFormatCommand(t *testing.T) {
 	p := defaultTestConfig(t)
 	cmd := NewTfFormatCommand(p).Command
 
 	assert.Equal(t, "format", cmd.Name)
 	assert.Equal(t, "Run `terraform fmt` for a specific stage", cmd.Usage)
-	assert.Len(t, cmd.Flags, 1)
+	assert.Len(t, cmd.Flags, 2)
 
 	stageFlag := cmd.Flags[0].(*cli.StringFlag)
 	assert.Equal(t, "stage", stageFlag.Name)
 	assert.True(t, stageFlag.Required)
 
+	checkFlag := cmd.Flags[1].(*cli.BoolFlag)
+	assert.Equal(t, "check", checkFlag.Name)
+
 	runTestTfCommandWithStage(t, cmd)
 }
 
@@ -191,6 +427,8 @@ func TestNewTfFormatAllCommand(t *testing.T) {
 
 	assert.Equal(t, "format-all", cmd.Name)
 	assert.Equal(t, "Run `terraform fmt` for all stages", cmd.Usage)
+	assert.Len(t, cmd.Flags, 1)
+	assert.Equal(t, "check", cmd.Flags[0].Names()[0])
 
 	runTestTfCommand(t, cmd)
 }
@@ -205,6 +443,129 @@ func TestNewTfVersionCommand(t *testing.T) {
 	runTestTfCommand(t, cmd)
 }
 
+func TestNewTfProvidersLockCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewTfProvidersLockCommand(p).Command
+
+	assert.Equal(t, "providers-lock", cmd.Name)
+	assert.Len(t, cmd.Flags, 2)
+
+	stageFlag := cmd.Flags[0].(*cli.StringFlag)
+	assert.Equal(t, "stage", stageFlag.Name)
+	assert.True(t, stageFlag.Required)
+
+	platformFlag := cmd.Flags[1].(*cli.StringSliceFlag)
+	assert.Equal(t, "platform", platformFlag.Name)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-s", "frist", "--platform", "linux_amd64"})
+	assert.ErrorContains(t, err, "did you mean first?")
+}
+
+func TestNewTfGraphCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewTfGraphCommand(p).Command
+
+	assert.Equal(t, "graph", cmd.Name)
+	assert.Equal(t, "Output the stage dependency graph in Graphviz DOT format", cmd.Usage)
+}
+
+// mockTfShow replaces terraform show with a canned state for the duration of the test.
+func mockTfShow(t *testing.T, state *tfjson.State) {
+	orig := tfShowStage
+	t.Cleanup(func() { tfShowStage = orig })
+
+	tfShowStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) (*tfjson.State, error) {
+		return state, nil
+	}
+}
+
+func testShowState() *tfjson.State {
+	return &tfjson.State{
+		FormatVersion: "1.0",
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{{Address: "terraform_data.simple", Type: "terraform_data", Name: "simple"}},
+				ChildModules: []*tfjson.StateModule{
+					{
+						Address:   "module.one",
+						Resources: []*tfjson.StateResource{{Address: "module.one.terraform_data.child", Type: "terraform_data", Name: "child"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCmdTfShow(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfShow(t, testShowState())
+
+	var buf bytes.Buffer
+	err := TfShow(context.Background(), &buf, testStage, false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "terraform_data.simple\nmodule.one.terraform_data.child\n", buf.String())
+}
+
+func TestCmdTfShowJson(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfShow(t, testShowState())
+
+	var buf bytes.Buffer
+	err := TfShow(context.Background(), &buf, testStage, true, p)
+	assert.NoError(t, err)
+
+	var state tfjson.State
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &state))
+	assert.Equal(t, []string{"terraform_data.simple", "module.one.terraform_data.child"}, terraform.StateAddresses(&state))
+}
+
+func TestCmdTfShowEmpty(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfShow(t, &tfjson.State{})
+
+	var buf bytes.Buffer
+	err := TfShow(context.Background(), &buf, testStage, false, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "No resources in state for stage first\n", buf.String())
+}
+
+func TestCmdTfShowUnknownStage(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfShow(t, testShowState())
+
+	err := TfShow(context.Background(), io.Discard, "missing", false, p)
+	assert.ErrorContains(t, err, "stage missing not found")
+}
+
+func TestCmdTfGraph(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	var buf bytes.Buffer
+	err := TfGraph(&buf, p)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "digraph stages {")
+	assert.Contains(t, buf.String(), `"first";`)
+}
+
+func TestNewTfForceUnlockCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewTfForceUnlockCommand(p).Command
+
+	assert.Equal(t, "force-unlock", cmd.Name)
+	assert.Len(t, cmd.Flags, 2)
+
+	stageFlag := cmd.Flags[0].(*cli.StringFlag)
+	assert.Equal(t, "stage", stageFlag.Name)
+	assert.True(t, stageFlag.Required)
+
+	lockIdFlag := cmd.Flags[1].(*cli.StringFlag)
+	assert.Equal(t, "lock-id", lockIdFlag.Name)
+	assert.True(t, lockIdFlag.Required)
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-s", "first"})
+	assert.Error(t, err) // Missing required lock-id flag
+}
+
 func TestNewRootTerraformCommand(t *testing.T) {
 	p := defaultTestConfig(t)
 	cmds := TfCommandParams{
@@ -231,6 +592,89 @@ func TestCmdTfInit(t *testing.T) {
 	}
 }
 
+func TestCmdTfStateMigrate(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := TfStateMigrate(context.Background(), testStage, nil, p)
+	if err != nil {
+		t.Errorf("unexpected error in cmd TfStateMigrate, %v", err)
+	}
+}
+
+func TestCmdTfStateMigrateMissingStage(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := TfStateMigrate(context.Background(), "missing", nil, p)
+	if err == nil {
+		t.Error("expected error migrating state for a missing stage")
+	}
+}
+
+func TestCmdTfForceUnlockMissingStage(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := TfForceUnlock(context.Background(), "missing", "lock-id", p)
+	if err == nil {
+		t.Error("expected error force unlocking a missing stage")
+	}
+}
+
+func TestCmdValidateStage(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := ValidateStage(testStage, p)
+	assert.NoError(t, err)
+
+	err = ValidateStage("frist", p)
+	assert.ErrorContains(t, err, "stage frist not found, did you mean first? valid stages: first")
+
+	err = ValidateStage("monitoring", p)
+	assert.EqualError(t, err, "stage monitoring not found, valid stages: first")
+
+	var cfgErr util.ConfigError
+	assert.ErrorAs(t, err, &cfgErr)
+}
+
+func TestCmdTfCommandUnknownStage(t *testing.T) {
+	p := defaultTestConfig(t)
+	cmd := NewTfPlanCommand(p).Command
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-s", "frist"})
+	assert.ErrorContains(t, err, "did you mean first?")
+}
+
+func TestCmdTfUnknownStage(t *testing.T) {
+	p := defaultTestConfig(t)
+	ctx := context.Background()
+
+	err := TfApply(ctx, "bogus", p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	err = TfPlan(ctx, "bogus", p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	err = TfOutput(ctx, "bogus", p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	_, err = TfValidate(ctx, "bogus", p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	err = TfFormat(ctx, "bogus", false, p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	err = TfFormat(ctx, "bogus", true, p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+
+	err = TfProvidersLock(ctx, "bogus", []string{"linux_amd64"}, p)
+	assert.EqualError(t, err, "stage bogus not found, valid stages: first")
+	assert.ErrorAs(t, err, &util.ConfigError{})
+
+	// an empty stage id is rejected rather than running in the wrong directory
+	err = TfPlan(ctx, "", p)
+	assert.EqualError(t, err, "stage required")
+	assert.ErrorAs(t, err, &util.ConfigError{})
+}
+
 func TestCmdTfInitAll(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -240,6 +684,101 @@ func TestCmdTfInitAll(t *testing.T) {
 	}
 }
 
+// mockTfInit replaces terraform init, recording the stages initialized and the most run at once.
+func mockTfInit(t *testing.T, errs map[string]error) (func() []string, *atomic.Int32) {
+	orig := tfInitStage
+	t.Cleanup(func() { tfInitStage = orig })
+
+	var mu sync.Mutex
+	var initialized []string
+	var running, peak atomic.Int32
+
+	tfInitStage = func(ctx context.Context, stage string, p *CommandParams) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := peak.Load()
+			if n <= m || peak.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		initialized = append(initialized, stage)
+		mu.Unlock()
+
+		return errs[stage]
+	}
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(initialized)
+	}, &peak
+}
+
+func addTestInitStages(p *CommandParams) {
+	for i, id := range []string{"second", "third", "fourth", "fifth"} {
+		p.Settings().Config.Stages[id] = schema.StageConfig{Id: id, Order: i + 2, Path: "stages/" + id}
+	}
+}
+
+func TestCmdTfInitAllConcurrent(t *testing.T) {
+	p := defaultTestConfig(t)
+	addTestInitStages(p)
+	p.Settings().Config.Terraform.InitWorkers = 2
+	initialized, peak := mockTfInit(t, nil)
+
+	err := TfInitAll(context.Background(), p)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{testStage, "second", "third", "fourth", "fifth"}, initialized())
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestCmdTfInitAllSharedPath(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Stages = map[string]schema.StageConfig{
+		"a-east": {Id: "a-east", Order: 1, Path: "stages/a"},
+		"a-west": {Id: "a-west", Order: 2, Path: "stages/a"},
+	}
+	p.SetInitWorkers(4)
+	initialized, peak := mockTfInit(t, nil)
+
+	err := TfInitAll(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a-east", "a-west"}, initialized())
+	assert.Equal(t, int32(1), peak.Load(), "stages sharing a directory shouldn't be initialized concurrently")
+}
+
+func TestCmdTfInitAllSerial(t *testing.T) {
+	p := defaultTestConfig(t)
+	addTestInitStages(p)
+	initialized, peak := mockTfInit(t, nil)
+
+	cmd := NewTfInitAllCommand(p).Command
+	err := cmd.Run(context.Background(), []string{cmd.Name, "--serial"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testStage, "second", "third", "fourth", "fifth"}, initialized())
+	assert.Equal(t, int32(1), peak.Load())
+}
+
+func TestCmdTfInitAllError(t *testing.T) {
+	p := defaultTestConfig(t)
+	addTestInitStages(p)
+	p.SetInitWorkers(2)
+	initialized, _ := mockTfInit(t, map[string]error{
+		"second": fmt.Errorf("provider download failed"),
+		"fourth": fmt.Errorf("backend unreachable"),
+	})
+
+	err := TfInitAll(context.Background(), p)
+	assert.ErrorContains(t, err, "init failed for stage second: provider download failed")
+	assert.ErrorContains(t, err, "init failed for stage fourth: backend unreachable")
+	assert.Len(t, initialized(), 5, "a failed stage shouldn't stop the others")
+}
+
 func TestCmdTfPlan(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -270,6 +809,164 @@ func TestCmdTfDestroy(t *testing.T) {
 	}
 }
 
+func TestCmdValidateDestroyFilters(t *testing.T) {
+	assert.NoError(t, validateDestroyFilters([]string{"module.mod.*"}, []string{"random_integer.keep"}))
+
+	err := validateDestroyFilters([]string{"module.mod["}, []string{"random_integer.(keep"})
+	assert.ErrorAs(t, err, &util.ConfigError{})
+	assert.ErrorContains(t, err, `--include pattern "module.mod[" is invalid`)
+	assert.ErrorContains(t, err, `--exclude pattern "random_integer.(keep" is invalid`)
+}
+
+func TestCmdTfDestroyFilters(t *testing.T) {
+	p := defaultTestConfig(t)
+	s := p.Settings().Config.Stages[testStage]
+	s.Destroy.Include = []string{"random_integer.include"}
+	s.Destroy.Exclude = []string{"random_integer.exclude"}
+
+	// no flags, the configured filters are used as is
+	actual := destroyStageConfig(s, p)
+	assert.Equal(t, []string{"random_integer.include"}, actual.Destroy.Include)
+	assert.Equal(t, []string{"random_integer.exclude"}, actual.Destroy.Exclude)
+
+	// flags are added to the configured filters
+	p.SetDestroyFilters([]string{"module.mod.*"}, []string{"module.mod.keep"})
+	actual = destroyStageConfig(s, p)
+	assert.Equal(t, []string{"random_integer.include", "module.mod.*"}, actual.Destroy.Include)
+	assert.Equal(t, []string{"random_integer.exclude", "module.mod.keep"}, actual.Destroy.Exclude)
+
+	// the configured stage is left untouched
+	assert.Equal(t, []string{"random_integer.include"}, s.Destroy.Include)
+}
+
+// mockTfDestroy replaces terraform destroy and its target resolution for the duration of the test,
+// destroy records the console output at the time it was called.
+func mockTfDestroy(t *testing.T, targets map[string][]string, out *bytes.Buffer) *[]string {
+	origTargets, origDestroy := tfDestroyTargets, tfDestroyStage
+	t.Cleanup(func() { tfDestroyTargets, tfDestroyStage = origTargets, origDestroy })
+
+	tfDestroyTargets = func(ctx context.Context, p *CommandParams, s schema.StageConfig) ([]string, bool, error) {
+		if tt, ok := targets[s.Id]; ok {
+			return tt, len(tt) > 0, nil
+		}
+		return nil, true, nil
+	}
+
+	var seen []string
+	tfDestroyStage = func(ctx context.Context, p *CommandParams, s schema.StageConfig) error {
+		seen = append(seen, out.String())
+		return nil
+	}
+
+	return &seen
+}
+
+func TestCmdConfirmDestroyPlan(t *testing.T) {
+	p := defaultTestConfig(t)
+	addTestInitStages(p)
+	skipped := p.Settings().Config.Stages["fourth"]
+	skipped.Destroy.Skip = true
+	p.Settings().Config.Stages["fourth"] = skipped
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	mockTfDestroy(t, map[string][]string{
+		"second": {"random_integer.include", "module.mod.random_integer.this"},
+		"third":  {},
+	}, &buf)
+
+	err := ConfirmDestroyPlan(context.Background(), []string{"fourth", "third", "second", testStage}, p)
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "fourth: skipped (destroy.skip)")
+	assert.Contains(t, output, "third: no resources match the destroy filters, nothing to destroy")
+	assert.Contains(t, output, "second: 2 targeted resources")
+	assert.Contains(t, output, "  - random_integer.include")
+	assert.Contains(t, output, "  - module.mod.random_integer.this")
+	assert.Contains(t, output, "first: full destroy")
+}
+
+func TestCmdConfirmDestroyPlanQuiet(t *testing.T) {
+	p := defaultTestConfig(t)
+	util.SetQuiet(true)
+	defer util.SetQuiet(false)
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	mockTfDestroy(t, map[string][]string{testStage: {"random_integer.include"}}, &buf)
+
+	err := ConfirmDestroyPlan(context.Background(), []string{testStage}, p)
+	assert.NoError(t, err)
+
+	// the header is decorative, the targets being confirmed aren't
+	output := buf.String()
+	assert.NotContains(t, output, "Destroy plan")
+	assert.Contains(t, output, "first: 1 targeted resources")
+	assert.Contains(t, output, "  - random_integer.include")
+}
+
+func TestCmdConfirmDestroyPlanDeclined(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTfDestroy(t, nil, &bytes.Buffer{})
+
+	prompted := 0
+	orig := confirmDestroy
+	t.Cleanup(func() { confirmDestroy = orig })
+	confirmDestroy = func(msg string) bool {
+		prompted++
+		return false
+	}
+
+	err := ConfirmDestroyPlan(context.Background(), []string{testStage}, p)
+	assert.EqualError(t, err, "aborting")
+	assert.Equal(t, 1, prompted)
+
+	// --yes skips the prompt
+	p.SetAssumeYes(true)
+	err = ConfirmDestroyPlan(context.Background(), []string{testStage}, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, prompted)
+}
+
+func TestNewTfDestroyCommandPlan(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	seen := mockTfDestroy(t, map[string][]string{testStage: {"random_integer.include"}}, &buf)
+
+	cmd := NewTfDestroyCommand(p).Command
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-s", testStage, "--include", "random_integer.include"})
+	assert.NoError(t, err)
+
+	// the targets were printed before destroy was called
+	if assert.Len(t, *seen, 1) {
+		assert.Contains(t, (*seen)[0], "first: 1 targeted resources")
+		assert.Contains(t, (*seen)[0], "  - random_integer.include")
+	}
+}
+
+func TestNewTfDestroyCommandPlanDeclined(t *testing.T) {
+	p := defaultTestConfig(t)
+	seen := mockTfDestroy(t, nil, &bytes.Buffer{})
+
+	orig := confirmDestroy
+	t.Cleanup(func() { confirmDestroy = orig })
+	confirmDestroy = func(msg string) bool { return false }
+
+	cmd := NewTfDestroyCommand(p).Command
+	err := cmd.Run(context.Background(), []string{cmd.Name, "-s", testStage})
+	assert.EqualError(t, err, "aborting")
+	assert.Empty(t, *seen)
+}
+
 func TestCmdTfOutput(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -312,16 +1009,23 @@ func TestCmdTfValidate(t *testing.T) {
 func TestCmdTfFormat(t *testing.T) {
 	p := defaultTestConfig(t)
 
-	err := TfFormat(context.Background(), testStage, p)
+	err := TfFormat(context.Background(), testStage, false, p)
 	if err != nil {
 		t.Errorf("unexpected error in cmd TfFormat, %v", err)
 	}
 }
 
+func TestCmdUnformattedFilesError(t *testing.T) {
+	assert.NoError(t, unformattedFilesError(nil))
+
+	err := unformattedFilesError([]string{"stages/01-first/main.tf", "stages/02-second/vars.tf"})
+	assert.EqualError(t, err, "2 file(s) not formatted, run `quartz terraform format-all` to fix: stages/01-first/main.tf, stages/02-second/vars.tf")
+}
+
 func TestCmdTfFormatAll(t *testing.T) {
 	p := defaultTestConfig(t)
 
-	err := TfFormatAll(context.Background(), p)
+	err := TfFormatAll(context.Background(), false, p)
 	if err != nil {
 		t.Errorf("unexpected error in cmd TfFormatAll, %v", err)
 	}
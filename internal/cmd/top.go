@@ -0,0 +1,161 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/urfave/cli/v3"
+)
+
+// NewRootTopCommand creates the root top CLI command.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - RootCommandResult containing the root top CLI command.
+func NewRootTopCommand(p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "top",
+			Usage: "Resource (CPU/memory) usage subcommands, requires metrics-server",
+			Commands: []*cli.Command{
+				{
+					Name:  "nodes",
+					Usage: "Print the CPU and memory usage of each node",
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return TopNodes(ctx, p)
+					},
+				},
+				{
+					Name:  "pods",
+					Usage: "Print the CPU and memory usage of each pod",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "namespace",
+							Aliases: []string{"n"},
+							Usage:   "Namespace to print the pods for, all namespaces if unset",
+						},
+					},
+					Action: func(ctx context.Context, ccmd *cli.Command) error {
+						return TopPods(ctx, ccmd.String("namespace"), p)
+					},
+				},
+			},
+		},
+	}
+}
+
+// TopNodes prints a table of the CPU and memory usage of each node, with the percentage of the
+// node's allocatable resources when known.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the node metrics can't be retrieved, e.g. metrics-server isn't installed, otherwise nil.
+func TopNodes(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "top:nodes")
+	defer log.Debug("Completed", "command", "top:nodes")
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	usage, err := kube.NodeUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(usage) == 0 {
+		util.Msg("No node metrics found")
+		return nil
+	}
+
+	var rows [][]string
+	for _, u := range usage {
+		rows = append(rows, []string{
+			u.Name,
+			formatCPU(u.CPUMillis),
+			formatPercent(u.CPUMillis, u.AllocatableCPUMillis),
+			formatMemory(u.MemoryBytes),
+			formatPercent(u.MemoryBytes, u.AllocatableMemoryBytes),
+		})
+	}
+	util.PrintTable([]string{"Name", "CPU", "CPU%", "Memory", "Memory%"}, rows)
+
+	return nil
+}
+
+// TopPods prints a table of the CPU and memory usage of each pod, summed across its containers.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - ns: The namespace to print the pods for, all namespaces if empty.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the pod metrics can't be retrieved, e.g. metrics-server isn't installed, otherwise nil.
+func TopPods(ctx context.Context, ns string, p *CommandParams) error {
+	log.Debug("Entering", "command", "top:pods", "namespace", ns)
+	defer log.Debug("Completed", "command", "top:pods")
+
+	kube, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	usage, err := kube.PodUsage(ctx, ns)
+	if err != nil {
+		return err
+	}
+
+	if len(usage) == 0 {
+		util.Msg("No pod metrics found")
+		return nil
+	}
+
+	var rows [][]string
+	for _, u := range usage {
+		rows = append(rows, []string{u.Namespace, u.Name, formatCPU(u.CPUMillis), formatMemory(u.MemoryBytes)})
+	}
+	util.PrintTable([]string{"Namespace", "Name", "CPU", "Memory"}, rows)
+
+	return nil
+}
+
+// formatCPU formats millicores the way kubectl top does, e.g. 250m.
+func formatCPU(millis int64) string {
+	return fmt.Sprintf("%dm", millis)
+}
+
+// formatMemory formats bytes as mebibytes the way kubectl top does, e.g. 512Mi.
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// formatPercent formats used as a percentage of total, or "-" if the total is unknown.
+func formatPercent(used int64, total int64) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", used*100/total)
+}
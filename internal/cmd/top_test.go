@@ -0,0 +1,106 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/provider"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+// withTestMetrics replaces the kubernetes client with one serving the metrics API, returning listErr
+// from the metrics lists if set.
+func withTestMetrics(t *testing.T, p *CommandParams, listErr error) {
+	metricsGv := schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	metrics := func(obj map[string]interface{}) k8sTesting.ReactionFunc {
+		return func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			if listErr != nil {
+				return true, nil, listErr
+			}
+			return true, &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: obj}}}, nil
+		}
+	}
+
+	api := provider.NewKubernetesApiMock().
+		AddResources(&metav1.APIResourceList{
+			GroupVersion: metricsGv.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "nodes", Namespaced: false, Kind: "NodeMetrics"},
+				{Name: "pods", Namespaced: true, Kind: "PodMetrics"},
+			},
+		}).
+		WithDynamicListKind(metricsGv.WithResource("nodes"), "NodeMetricsList").
+		WithDynamicListKind(metricsGv.WithResource("pods"), "PodMetricsList").
+		WithDynamicReactor("list", "nodes", metrics(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "node1"},
+			"usage":    map[string]interface{}{"cpu": "250m", "memory": "512Mi"},
+		})).
+		WithDynamicReactor("list", "pods", metrics(map[string]interface{}{
+			"metadata": map[string]interface{}{"namespace": "testns1", "name": "testpod1"},
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "100m", "memory": "64Mi"}},
+			},
+		}))
+
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+}
+
+func TestNewRootTopCommand(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestMetrics(t, p, nil)
+	cmd := NewRootTopCommand(p).Command
+
+	assert.Equal(t, "top", cmd.Name)
+	assert.Len(t, cmd.Commands, 2)
+
+	nodes := cmd.Commands[0]
+	assert.Equal(t, "nodes", nodes.Name)
+	assert.NoError(t, nodes.Run(context.Background(), []string{nodes.Name}))
+
+	pods := cmd.Commands[1]
+	assert.Equal(t, "pods", pods.Name)
+	assert.Len(t, pods.Flags, 1)
+	assert.NoError(t, pods.Run(context.Background(), []string{pods.Name, "-n", "testns1"}))
+}
+
+func TestCmdTopError(t *testing.T) {
+	p := defaultTestConfig(t)
+	withTestMetrics(t, p, errors.New("the server is currently unable to handle the request"))
+
+	err := TopNodes(context.Background(), p)
+	assert.ErrorContains(t, err, "unable to handle the request")
+
+	err = TopPods(context.Background(), "", p)
+	assert.ErrorContains(t, err, "unable to handle the request")
+}
+
+func TestCmdTopFormat(t *testing.T) {
+	assert.Equal(t, "250m", formatCPU(250))
+	assert.Equal(t, "512Mi", formatMemory(512*1024*1024))
+	assert.Equal(t, "12%", formatPercent(250, 2000))
+	assert.Equal(t, "-", formatPercent(250, 0))
+}
@@ -16,10 +16,13 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -28,10 +31,22 @@ import (
 	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/stages"
 	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/urfave/cli/v3"
 )
 
+const (
+	quartzReleaseOwner = "MetroStar" // GitHub owner of the quartzctl releases
+	quartzReleaseRepo  = "quartzctl" // GitHub repository of the quartzctl releases
+)
+
 var (
+	// errAborted is returned when the user declines a confirmation prompt.
+	errAborted = errors.New("aborting")
+
+	// tmpFreeSpace looks up the free space on the tmp directory's filesystem, replaced in tests.
+	tmpFreeSpace = util.FreeDiskSpace
+
 	// checkOpts defines options for health checks, including callbacks for start, completion, and retries.
 	checkOpts = &stages.CheckOpts{
 		OnStart:    onCheckStart,
@@ -47,8 +62,12 @@ func NewRootLoginCommand(p *CommandParams) RootCommandResult {
 			Usage: "Generate a kubeconfig for the current cluster",
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "output path", Value: "./out/kubeconfig"},
+				&cli.BoolFlag{Name: "print", Usage: "write the kubeconfig to stdout instead of a file"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				if ccmd.Bool("print") {
+					return PrintKubeconfig(ctx, os.Stdout, p)
+				}
 				path := ccmd.String("out")
 				return ClusterLogin(ctx, path, p)
 			},
@@ -56,13 +75,48 @@ func NewRootLoginCommand(p *CommandParams) RootCommandResult {
 	}
 }
 
+func NewRootVersionCommand(a AppServiceParams, p *CommandParams) RootCommandResult {
+	return RootCommandResult{
+		Command: &cli.Command{
+			Name:  "version",
+			Usage: "Output the version and build info",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "json", Usage: "output the version, build date, git commit, go version, os and arch as json"},
+				&cli.BoolFlag{Name: "check-update", Usage: "check GitHub releases for a newer version"},
+			},
+			Action: func(ctx context.Context, ccmd *cli.Command) error {
+				if ccmd.Bool("check-update") {
+					return CheckUpdate(ctx, p, util.NewHttpClientFactory(), a.Version)
+				}
+				if ccmd.Bool("json") {
+					return VersionJson(os.Stdout, a.Version, a.BuildDate, a.GitCommit)
+				}
+				Version(a.Version, a.BuildDate)
+				return nil
+			},
+		},
+	}
+}
+
 func NewRootInfoCommand(p *CommandParams) RootCommandResult {
 	return RootCommandResult{
 		Command: &cli.Command{
 			Name:  "info",
 			Usage: "Output configuration info for the current cluster",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{Name: "app", Usage: "only print the named application, by config key or description (repeatable)"},
+				&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "output format, wide adds the namespace, secret and ingress each application was looked up from"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
-				return ClusterInfo(ctx, p)
+				output := ccmd.String("output")
+				if output != "" && output != "wide" {
+					return fmt.Errorf("unsupported output %q, must be wide", output)
+				}
+
+				return ClusterInfo(ctx, p, provider.ClusterInfoOpts{
+					Apps: ccmd.StringSlice("app"),
+					Wide: output == "wide",
+				})
 			},
 		},
 	}
@@ -73,9 +127,13 @@ func NewRootCheckCommand(p *CommandParams) RootCommandResult {
 		Command: &cli.Command{
 			Name:  "check",
 			Usage: "Check environment and configuration for required values",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{Name: "provider", Usage: "only check the named provider, e.g. github (repeatable)"},
+				&cli.BoolFlag{Name: "refresh", Usage: "ignore cached check results, e.g. github repository access"},
+				&cli.BoolFlag{Name: "app-repos", Usage: "also check application repositories have the expected webhook and deploy key"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
-				Check(ctx, p)
-				return nil
+				return Check(ctx, ccmd.StringSlice("provider"), ccmd.Bool("refresh"), ccmd.Bool("app-repos"), p)
 			},
 		},
 	}
@@ -88,10 +146,16 @@ func NewRootRenderCommand(p *CommandParams) RootCommandResult {
 			Usage: "Write fully rendered yaml config",
 			Flags: []cli.Flag{
 				&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "output path", Value: "./out/quartz.generated.yaml"},
+				&cli.StringFlag{Name: "only", Usage: "only write the config subtree at this key path (ex. auth, stages)"},
+				&cli.StringFlag{Name: "diff", Usage: "print a unified diff against a previously rendered config instead of writing"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				path := ccmd.String("out")
-				return Render(ctx, path, p)
+				only := ccmd.String("only")
+				if prev := ccmd.String("diff"); prev != "" {
+					return RenderDiff(ctx, prev, only, p)
+				}
+				return Render(ctx, path, only, p)
 			},
 		},
 	}
@@ -115,8 +179,11 @@ func NewRootExportCommand(p *CommandParams) RootCommandResult {
 		Command: &cli.Command{
 			Name:  "export",
 			Usage: "Export configured Kubernetes resources to yaml",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "strict", Usage: "fail if any object can't be exported"},
+			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
-				return Export(ctx, p)
+				return Export(ctx, ccmd.Bool("strict"), p)
 			},
 		},
 	}
@@ -131,6 +198,8 @@ func NewRootRestartCommand(p *CommandParams) RootCommandResult {
 				&cli.StringSliceFlag{Name: "kind", Aliases: []string{"k"}, Usage: "Resource kind", Required: false},
 				&cli.StringFlag{Name: "namespace", Aliases: []string{"n"}, Usage: "Namespace", Required: false},
 				&cli.StringFlag{Name: "name", Usage: "Name", Required: false},
+				&cli.BoolFlag{Name: "wait", Usage: "wait for the rollout of each restarted resource to complete"},
+				&cli.DurationFlag{Name: "timeout", Usage: "how long to wait for each rollout with --wait, e.g. 5m (defaults to 10m)"},
 			},
 			Action: func(ctx context.Context, ccmd *cli.Command) error {
 				kinds := ccmd.StringSlice("kind")
@@ -141,14 +210,20 @@ func NewRootRestartCommand(p *CommandParams) RootCommandResult {
 					kinds = []string{"deployment", "daemonset", "statefulset"}
 				}
 
+				var restarted []provider.KubernetesResource
 				for _, k := range kinds {
-					err := Restart(ctx, k, ns, name, p)
+					res, err := Restart(ctx, k, ns, name, p)
 					if err != nil {
 						return err
 					}
+					restarted = append(restarted, res...)
 				}
 
-				return nil
+				if !ccmd.Bool("wait") {
+					return nil
+				}
+
+				return WaitForRestart(ctx, restarted, ccmd.Duration("timeout"), p)
 			},
 		},
 	}
@@ -176,19 +251,111 @@ func Version(version string, buildDate string) {
 		}
 		d = time.Unix(c, 0).Format(format)
 	}
-	util.Msgf("Quartz %s\nBuild Date: %s\n", version, d)
+	util.Infof("Quartz %s\nBuild Date: %s\n", version, d)
+}
+
+// VersionInfo is the machine readable version and build info of the Quartz installer.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"buildDate"` // RFC 3339, empty for local builds
+	GitCommit string `json:"gitCommit"`
+	GoVersion string `json:"goVersion"`
+	Os        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// VersionJson writes the version and build info of the Quartz installer as json.
+//
+// Parameters:
+//   - w: The writer for the json output.
+//   - version: The version of the Quartz installer.
+//   - buildDate: The build date of the Quartz installer, as a unix timestamp.
+//   - gitCommit: The git commit the Quartz installer was built from.
+//
+// Returns:
+//   - error: An error if the build date is invalid or writing fails, otherwise nil.
+func VersionJson(w io.Writer, version string, buildDate string, gitCommit string) error {
+	log.Debug("Entering", "command", "version")
+	defer log.Debug("Completed", "command", "version")
+
+	info := VersionInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		GoVersion: runtime.Version(),
+		Os:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+
+	if buildDate != "" {
+		d, _, _ := strings.Cut(buildDate, ".") // in case a float was passed in
+		c, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid build date %s, %w", buildDate, err)
+		}
+		info.BuildDate = time.Unix(c, 0).UTC().Format(time.RFC3339)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(info)
+}
+
+// CheckUpdate compares the current version with the latest quartzctl GitHub release
+// and reports whether a newer version is available.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: The command parameters containing configuration.
+//   - httpClient: The HTTP client factory used to query GitHub.
+//   - current: The version of the Quartz installer.
+//
+// Returns:
+//   - error: An error if the release lookup or version comparison fails, otherwise nil. Development
+//     builds without a semver version skip the check.
+func CheckUpdate(ctx context.Context, p *CommandParams, httpClient util.HttpClientFactory, current string) error {
+	log.Debug("Entering", "command", "version", "checkUpdate", true)
+	defer log.Debug("Completed", "command", "version", "checkUpdate", true)
+
+	if p.Settings().Config.DisableUpdateCheck {
+		util.Msg("Update check disabled by disable_update_check")
+		return nil
+	}
+
+	if !provider.IsReleaseVersion(current) {
+		util.Infof("Quartz %s is a development build, update check skipped\n", current)
+		return nil
+	}
+
+	r, err := provider.GithubLatestRelease(ctx, httpClient, quartzReleaseOwner, quartzReleaseRepo)
+	if err != nil {
+		return fmt.Errorf("failed to look up the latest release, %w", err)
+	}
+
+	c, err := provider.CompareReleaseVersions(current, r.Tag)
+	if err != nil {
+		return err
+	}
+
+	if c < 0 {
+		util.Infof("A newer version of Quartz is available: %s (current %s)\n%s\n", r.Tag, current, r.Url)
+		return nil
+	}
+
+	util.Infof("Quartz %s is up to date (latest release %s)\n", current, r.Tag)
+	return nil
 }
 
-// Render writes the full configuration to the specified file path.
+// Render writes the full configuration, or a subtree of it, to the specified file path.
 //
 // Parameters:
 //   - ctx: The context for the operation.
 //   - path: The file path where the configuration will be written.
+//   - only: An optional key path limiting the output to that section of the configuration.
 //   - p: *CommandParams containing configuration and runtime parameters.
 //
 // Returns:
 //   - error: An error if the rendering fails, otherwise nil.
-func Render(ctx context.Context, path string, p *CommandParams) error {
+func Render(ctx context.Context, path string, only string, p *CommandParams) error {
 	log.Debug("Entering", "command", "render")
 	defer log.Debug("Completed", "command", "render")
 
@@ -198,7 +365,82 @@ func Render(ctx context.Context, path string, p *CommandParams) error {
 	}
 
 	util.Msgf("Writing generated Quartz YAML to %s", f)
-	return p.Settings().WriteYamlConfig(f)
+	return p.Settings().WriteYamlConfig(f, only)
+}
+
+// RenderDiff renders the current configuration and prints a unified diff against
+// a previously rendered configuration file.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - previous: The file path of the previously rendered configuration.
+//   - only: An optional key path limiting the comparison to that section of the configuration.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the rendering fails or the configurations differ, otherwise nil.
+func RenderDiff(ctx context.Context, previous string, only string, p *CommandParams) error {
+	log.Debug("Entering", "command", "render-diff")
+	defer log.Debug("Completed", "command", "render-diff")
+
+	current, err := p.Settings().MarshalYamlConfig(only)
+	if err != nil {
+		return err
+	}
+
+	prev, err := os.ReadFile(previous) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	diff, err := unifiedDiff(previous, prev, "rendered", current)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		util.Msgf("No differences found against %s", previous)
+		return nil
+	}
+
+	fmt.Print(diff)
+	return fmt.Errorf("rendered config differs from %s", previous)
+}
+
+// unifiedDiff returns a unified diff between two named documents, or an empty string if they match.
+func unifiedDiff(fromName string, from []byte, toName string, to []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
+
+// HealthSummary prints the cluster health (node readiness, critical DaemonSets and failing pods)
+// as a status table. Problems are reported in the table, they don't fail the command.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if the cluster health can't be retrieved, otherwise nil.
+func HealthSummary(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "command", "healthSummary")
+	defer log.Debug("Completed", "command", "healthSummary")
+
+	if !p.Settings().Config.Internal.Installer.Summary.Enabled {
+		return nil
+	}
+
+	k8s, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return err
+	}
+
+	return k8s.PrintHealthSummary(ctx)
 }
 
 // ClusterInfo retrieves and displays information about the Quartz cluster.
@@ -206,10 +448,11 @@ func Render(ctx context.Context, path string, p *CommandParams) error {
 // Parameters:
 //   - ctx: The context for the operation.
 //   - p: *CommandParams containing configuration and runtime parameters.
+//   - opts: Application names (config keys or descriptions) to limit the summary to, and whether to print the wide view.
 //
 // Returns:
 //   - error: An error if retrieving cluster information fails, otherwise nil.
-func ClusterInfo(ctx context.Context, p *CommandParams) error {
+func ClusterInfo(ctx context.Context, p *CommandParams, opts provider.ClusterInfoOpts) error {
 	log.Debug("Entering", "command", "clusterInfo")
 	defer log.Debug("Completed", "command", "clusterInfo")
 
@@ -229,9 +472,12 @@ func ClusterInfo(ctx context.Context, p *CommandParams) error {
 	if err != nil {
 		return err
 	}
-	k8s.PrintClusterInfo(ctx)
+	discovered := k8s.PrintClusterInfo(ctx, opts)
+	if len(discovered) > 0 {
+		util.Infof("%d additional service(s) exposed outside the configured applications, `quartz ingress list` shows every VirtualService", len(discovered))
+	}
 
-	util.Msgf("export KUBECONFIG=%s", p.Settings().Config.KubeconfigPath())
+	util.Infof("export KUBECONFIG=%s", p.Settings().Config.KubeconfigPath())
 	util.Msg("CI/CD builds may take up to 15 minutes to complete following initial setup, progress may be tracked at the Jenkins and ArgoCD URL's above")
 
 	return err
@@ -245,7 +491,7 @@ func ClusterInfo(ctx context.Context, p *CommandParams) error {
 //   - p: *CommandParams containing configuration and runtime parameters.
 //
 // Returns:
-//   - error: An error if generating the kubeconfig fails, otherwise nil.
+//   - error: An AccessError if writing the kubeconfig fails, otherwise nil.
 func ClusterLogin(ctx context.Context, path string, p *CommandParams) error {
 	log.Debug("Entering", "command", "clusterLogin")
 	defer log.Debug("Completed", "command", "clusterLogin")
@@ -264,26 +510,127 @@ func ClusterLogin(ctx context.Context, path string, p *CommandParams) error {
 	err = k8sClient.WriteKubeconfigFile(path)
 	if err != nil {
 		util.Msgf("Failed to write kubeconfig %v", err)
-		return nil // suppressing error here, if the cluster is unavailable it's not always a blocker downstream
+		return util.NewAccessError(k8sClient.ProviderName(), err)
 	}
 
 	util.Msgf("Kubeconfig written to %s", path)
 	return nil
 }
 
+// PrintKubeconfig writes the kubeconfig for the current cluster to the given writer.
+// No decorative output is written so the result can be piped or redirected.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - w: The writer receiving the kubeconfig yaml, typically os.Stdout.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An AccessError if the kubeconfig could not be generated, otherwise nil.
+func PrintKubeconfig(ctx context.Context, w io.Writer, p *CommandParams) error {
+	log.Debug("Entering", "command", "printKubeconfig")
+	defer log.Debug("Completed", "command", "printKubeconfig")
+
+	k8sClient, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = k8sClient.WriteKubeconfig(w)
+	if err != nil {
+		return util.NewAccessError(k8sClient.ProviderName(), err)
+	}
+
+	return nil
+}
+
 // Check verifies the dependencies required for Quartz installation.
 //
 // Parameters:
 //   - ctx: The context for the operation.
+//   - providers: Provider names to limit the checks to, all providers when empty.
+//   - refresh: true to ignore cached check results.
+//   - appRepos: true to also check application repositories have the expected webhook and deploy key.
 //   - p: *CommandParams containing configuration and runtime parameters.
-func Check(ctx context.Context, p *CommandParams) {
+//
+// Returns:
+//   - error: An AccessError for each provider that failed its check, a ConfigError for
+//     an unknown provider name or a tmp directory unfit for install, otherwise nil.
+func Check(ctx context.Context, providers []string, refresh bool, appRepos bool, p *CommandParams) error {
 	log.Debug("Entering", "command", "check")
 	defer log.Debug("Completed", "command", "check")
 
 	util.Hdr("Check")
 
 	opts := provider.NewProviderCheckOpts(ctx, *p.Provider())
-	provider.Check(ctx, &opts)
+	if err := opts.Filter(providers); err != nil {
+		return err
+	}
+
+	// the tmp directory is only checked along with every provider, not when checking a few
+	var tmpErr error
+	if len(providers) == 0 {
+		tmpErr = PreflightTmp(p)
+	}
+
+	if refresh {
+		if err := opts.ClearCache(); err != nil {
+			return err
+		}
+	}
+
+	err := errors.Join(tmpErr, provider.Check(ctx, &opts))
+	if !appRepos {
+		return err
+	}
+
+	sc, scErr := p.Provider().SourceControl(ctx)
+	if scErr != nil {
+		return errors.Join(err, util.NewAccessError("source control", scErr))
+	}
+
+	return errors.Join(err, provider.CheckAppRepos(ctx, sc))
+}
+
+// PreflightTmp verifies the tmp directory is writable and has at least tmp_min_free_mb free
+// for terraform providers and state, so install doesn't fail part way through.
+//
+// Parameters:
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: A ConfigError if the tmp directory isn't writable or is short on space, otherwise nil.
+//     A free space lookup failure only logs a warning.
+func PreflightTmp(p *CommandParams) error {
+	cfg := p.Settings().Config
+	tmp := cfg.Tmp
+
+	f, err := os.CreateTemp(tmp, ".quartz-write-check-*")
+	if err == nil {
+		_, err = f.WriteString("quartz")
+		err = errors.Join(err, f.Close(), os.Remove(f.Name()))
+	}
+	if err != nil {
+		return util.NewConfigErrorf("tmp directory %s is not writable, set tmp to a writable directory, %v", tmp, err)
+	}
+
+	if cfg.TmpMinFreeMb <= 0 {
+		return nil
+	}
+
+	free, err := tmpFreeSpace(tmp)
+	if err != nil {
+		log.Warn("Unable to check free space in the tmp directory", "dir", tmp, "err", err)
+		return nil
+	}
+
+	required := uint64(cfg.TmpMinFreeMb) * 1024 * 1024 // #nosec G115
+	if free < required {
+		return util.NewConfigErrorf("tmp directory %s has %d MB free, at least %d MB is needed for terraform providers and state, free up space or point tmp elsewhere (tmp_min_free_mb sets the minimum)", tmp, free/1024/1024, cfg.TmpMinFreeMb)
+	}
+
+	log.Debug("Tmp directory preflight passed", "dir", tmp, "freeMb", free/1024/1024)
+	return nil
 }
 
 // RefreshSecrets triggers an immediate refresh of all external secrets.
@@ -323,7 +670,14 @@ func Cleanup(ctx context.Context, p *CommandParams) error {
 	log.Debug("Entering", "internal", "cleanup")
 	defer log.Debug("Completed", "internal", "cleanup")
 
-	err := os.RemoveAll(p.Settings().Config.Tmp)
+	tmp := p.Settings().Config.Tmp
+	if p.KeepTmp() {
+		log.Info("Keeping tmp directory", "dir", tmp)
+		util.Msgf("Keeping tmp directory %s", tmp)
+		return nil
+	}
+
+	err := os.RemoveAll(tmp)
 	if err != nil {
 		log.Warn("Error during cleanup", "err", err)
 	}
@@ -338,7 +692,7 @@ func Banner() {
 	util.PrintBanner()
 }
 
-// Confirm prompts the user for confirmation before proceeding with an operation.
+// Confirm prompts the user for confirmation before proceeding with an operation, unless --yes was given.
 //
 // Parameters:
 //   - ctx: The context for the operation.
@@ -354,24 +708,31 @@ func Confirm(ctx context.Context, msg string, p *CommandParams) error {
 	cp, _ := p.Provider().Cloud(ctx)
 	cp.PrintConfig()
 
-	util.Msgf("Domain: %s\n", p.Settings().Config.Dns.Domain)
+	util.Infof("Domain: %s\n", p.Settings().Config.Dns.Domain)
+
+	if p.AssumeYes() {
+		return nil
+	}
 
 	if r := util.PromptYesNo(msg); !r {
-		return fmt.Errorf("aborting")
+		return errAborted
 	}
 
 	return nil
 }
 
 // Export saves the configured Kubernetes resources to YAML files.
+// Objects which export successfully are always written, failures are summarized afterward.
 //
 // Parameters:
 //   - ctx: The context for the operation.
+//   - strict: true to return an error if any object failed to export.
 //   - p: *CommandParams containing configuration and runtime parameters.
 //
 // Returns:
-//   - error: An error if exporting resources fails, otherwise nil.
-func Export(ctx context.Context, p *CommandParams) error {
+//   - error: An error if writing an exported object fails, or under strict if any
+//     object failed to export, otherwise nil.
+func Export(ctx context.Context, strict bool, p *CommandParams) error {
 	log.Debug("Entering", "command", "export")
 	defer log.Debug("Completed", "command", "export")
 
@@ -380,19 +741,33 @@ func Export(ctx context.Context, p *CommandParams) error {
 		return err
 	}
 
-	res, err := k8s.Export(ctx, p.Settings().Config.Export)
-	if err != nil {
-		return err
-	}
+	cfg := p.Settings().Config.Export
+	res, exportErr := k8s.Export(ctx, cfg)
 
-	out := p.Settings().Config.Export.Path
-	for k, v := range res {
-		err := util.WriteBytesToFile(v, path.Join(out, p.Settings().Config.Dns.Domain, k))
+	domain := p.Settings().Config.Dns.Domain
+	for _, o := range cfg.Objects {
+		v, ok := res[o.Key()]
+		if !ok {
+			continue
+		}
+
+		err := util.WriteBytesToFile(v, cfg.ObjectPath(domain, o))
 		if err != nil {
-			return err
+			return errors.Join(err, exportErr)
 		}
 	}
 
+	util.Msgf("Exported %d of %d objects to %s", len(res), len(cfg.Objects), cfg.Path)
+
+	if exportErr == nil {
+		return nil
+	}
+
+	if strict {
+		return exportErr
+	}
+
+	util.Errorf("Some objects failed to export:\n%v", exportErr)
 	return nil
 }
 
@@ -412,6 +787,32 @@ func PrepareAccount(ctx context.Context, p *CommandParams) error {
 	return cp.PrepareAccount(ctx)
 }
 
+// ReconcileAdministrators grants the configured administrators which are cloud principals (e.g. IAM role arns)
+// admin access to the cluster, e.g. through EKS access entries.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: An error if granting any administrator access fails, otherwise nil.
+func ReconcileAdministrators(ctx context.Context, p *CommandParams) error {
+	log.Debug("Entering", "internal", "reconcileAdministrators")
+	defer log.Debug("Completed", "internal", "reconcileAdministrators")
+
+	cp, err := p.Provider().Cloud(ctx)
+	if err != nil {
+		return err
+	}
+
+	created, err := cp.ReconcileAdministrators(ctx, p.Settings().Config.Administrators)
+	for _, c := range created {
+		util.Msgf("Granted cluster admin access to %s", c)
+	}
+
+	return err
+}
+
 // Restart restarts a Kubernetes resource in the specified namespace.
 //
 // Parameters:
@@ -422,21 +823,55 @@ func PrepareAccount(ctx context.Context, p *CommandParams) error {
 //   - p: *CommandParams containing configuration and runtime parameters.
 //
 // Returns:
+//   - []provider.KubernetesResource: The resources a rollout was triggered for.
 //   - error: An error if restarting the resource fails, otherwise nil.
-func Restart(ctx context.Context, res string, ns string, name string, p *CommandParams) error {
+func Restart(ctx context.Context, res string, ns string, name string, p *CommandParams) ([]provider.KubernetesResource, error) {
 	k8s, err := p.Provider().Kubernetes(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	kind, err := k8s.LookupKind(ctx, res)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return k8s.Restart(ctx, kind, ns, name)
 }
 
+// WaitForRestart waits for the rollout of each restarted resource to complete,
+// reporting the outcome for each one.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - resources: The resources returned by Restart.
+//   - timeout: How long to wait for each rollout, 0 for the provider default.
+//   - p: *CommandParams containing configuration and runtime parameters.
+//
+// Returns:
+//   - error: The combined rollout failures or timeouts, otherwise nil.
+func WaitForRestart(ctx context.Context, resources []provider.KubernetesResource, timeout time.Duration, p *CommandParams) error {
+	k8s, err := p.Provider().Kubernetes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, r := range resources {
+		util.Printf("Waiting for rollout of %s %s/%s", r.Kind.Resource, r.Namespace, r.Name)
+		err := k8s.WaitForRollout(ctx, r.Kind, r.Namespace, r.Name, int(timeout.Seconds()), 0)
+		if err != nil {
+			util.Errorf("Rollout of %s %s/%s did not complete, %v", r.Kind.Resource, r.Namespace, r.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		util.Printf("Rollout of %s %s/%s complete", r.Kind.Resource, r.Namespace, r.Name)
+	}
+
+	return errors.Join(errs...)
+}
+
 // onCheckStart logs the start of a health check for a stage.
 //
 // Parameters:
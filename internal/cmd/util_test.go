@@ -17,13 +17,20 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config"
+	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/stages"
 	"github.com/MetroStar/quartzctl/internal/terraform"
@@ -36,6 +43,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sTesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func TestNewRootLoginCommand(t *testing.T) {
@@ -44,11 +53,14 @@ func TestNewRootLoginCommand(t *testing.T) {
 
 	assert.Equal(t, "login", cmd.Name)
 	assert.Equal(t, "Generate a kubeconfig for the current cluster", cmd.Usage)
-	assert.Len(t, cmd.Flags, 1)
+	assert.Len(t, cmd.Flags, 2)
 
 	flag := cmd.Flags[0].(*cli.StringFlag)
 	assert.Equal(t, "out", flag.Name)
 
+	printFlag := cmd.Flags[1].(*cli.BoolFlag)
+	assert.Equal(t, "print", printFlag.Name)
+
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
 }
@@ -59,9 +71,19 @@ func TestNewRootInfoCommand(t *testing.T) {
 
 	assert.Equal(t, "info", cmd.Name)
 	assert.Equal(t, "Output configuration info for the current cluster", cmd.Usage)
+	assert.Len(t, cmd.Flags, 2)
+
+	flag := cmd.Flags[0].(*cli.StringSliceFlag)
+	assert.Equal(t, "app", flag.Name)
 
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
+
+	err = cmd.Run(context.Background(), []string{cmd.Name, "--output", "wide"})
+	assert.NoError(t, err)
+
+	err = cmd.Run(context.Background(), []string{cmd.Name, "-o", "json"})
+	assert.ErrorContains(t, err, `unsupported output "json"`)
 }
 
 func TestNewRootCheckCommand(t *testing.T) {
@@ -70,6 +92,16 @@ func TestNewRootCheckCommand(t *testing.T) {
 
 	assert.Equal(t, "check", cmd.Name)
 	assert.Equal(t, "Check environment and configuration for required values", cmd.Usage)
+	assert.Len(t, cmd.Flags, 3)
+
+	flag := cmd.Flags[0].(*cli.StringSliceFlag)
+	assert.Equal(t, "provider", flag.Name)
+
+	refreshFlag := cmd.Flags[1].(*cli.BoolFlag)
+	assert.Equal(t, "refresh", refreshFlag.Name)
+
+	appReposFlag := cmd.Flags[2].(*cli.BoolFlag)
+	assert.Equal(t, "app-repos", appReposFlag.Name)
 
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
@@ -81,11 +113,17 @@ func TestNewRootRenderCommand(t *testing.T) {
 
 	assert.Equal(t, "render", cmd.Name)
 	assert.Equal(t, "Write fully rendered yaml config", cmd.Usage)
-	assert.Len(t, cmd.Flags, 1)
+	assert.Len(t, cmd.Flags, 3)
 
 	flag := cmd.Flags[0].(*cli.StringFlag)
 	assert.Equal(t, "out", flag.Name)
 
+	onlyFlag := cmd.Flags[1].(*cli.StringFlag)
+	assert.Equal(t, "only", onlyFlag.Name)
+
+	diffFlag := cmd.Flags[2].(*cli.StringFlag)
+	assert.Equal(t, "diff", diffFlag.Name)
+
 	err := cmd.Run(context.Background(), []string{cmd.Name, "--out", filepath.Join(t.TempDir(), "render_test.yaml")})
 	assert.NoError(t, err)
 }
@@ -107,6 +145,7 @@ func TestNewRootExportCommand(t *testing.T) {
 
 	assert.Equal(t, "export", cmd.Name)
 	assert.Equal(t, "Export configured Kubernetes resources to yaml", cmd.Usage)
+	assert.Len(t, cmd.Flags, 1)
 
 	err := cmd.Action(context.Background(), &cli.Command{})
 	assert.NoError(t, err)
@@ -118,23 +157,102 @@ func TestNewRootRestartCommand(t *testing.T) {
 
 	assert.Equal(t, "restart", cmd.Name)
 	assert.Equal(t, "Restart target resource(s)", cmd.Usage)
-	assert.Len(t, cmd.Flags, 3)
+	assert.Len(t, cmd.Flags, 5)
 
 	err := cmd.Run(context.Background(), []string{cmd.Name, "--kind", "deployment"})
 	assert.NoError(t, err)
 }
 
+// withRolloutDeployments replaces the kubernetes client with one serving the given deployments,
+// returning the names of the deployments polled for rollout status.
+func withRolloutDeployments(t *testing.T, p *CommandParams, deployments ...*appsv1.Deployment) *[]string {
+	var objs []runtime.Object
+	for _, d := range deployments {
+		d.TypeMeta = metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		if err != nil {
+			t.Fatalf("unexpected error converting deployment, %v", err)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: u})
+	}
+
+	var gets []string
+	api := provider.NewKubernetesApiMock().WithDynamicObjects(objs...).WithDynamicReactor("get", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		gets = append(gets, action.(k8sTesting.GetAction).GetName())
+		return false, nil, nil
+	})
+
+	k8s, err := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+
+	return &gets
+}
+
+func newRolloutDeployment(name string, replicas int32, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testns1"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          replicas,
+			UpdatedReplicas:   replicas,
+			AvailableReplicas: available,
+		},
+	}
+}
+
+func TestNewRootRestartCommandWait(t *testing.T) {
+	p := defaultTestConfig(t)
+	gets := withRolloutDeployments(t, p, newRolloutDeployment("testdeploy1", 1, 1), newRolloutDeployment("testdeploy2", 2, 2))
+	cmd := NewRootRestartCommand(p).Command
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "--kind", "deployment", "--wait", "--timeout", "5s"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"testdeploy1", "testdeploy2"}, *gets)
+	assert.Contains(t, buf.String(), "Rollout of deployments testns1/testdeploy1 complete")
+	assert.Contains(t, buf.String(), "Rollout of deployments testns1/testdeploy2 complete")
+}
+
+func TestNewRootRestartCommandNoWait(t *testing.T) {
+	p := defaultTestConfig(t)
+	gets := withRolloutDeployments(t, p, newRolloutDeployment("testdeploy1", 1, 0))
+	cmd := NewRootRestartCommand(p).Command
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "--kind", "deployment"})
+	assert.NoError(t, err)
+	assert.Empty(t, *gets)
+}
+
+func TestNewRootRestartCommandWaitTimeout(t *testing.T) {
+	p := defaultTestConfig(t)
+	gets := withRolloutDeployments(t, p, newRolloutDeployment("testdeploy1", 1, 1), newRolloutDeployment("testdeploy2", 2, 1))
+	cmd := NewRootRestartCommand(p).Command
+
+	err := cmd.Run(context.Background(), []string{cmd.Name, "--kind", "deployment", "--wait", "--timeout", "1s"})
+	assert.ErrorContains(t, err, "timed out waiting for rollout of deployments testns1/testdeploy2")
+	assert.NotContains(t, err.Error(), "testdeploy1")
+	assert.Contains(t, *gets, "testdeploy1")
+	assert.Contains(t, *gets, "testdeploy2")
+}
+
 func TestNewRootInternalCommand(t *testing.T) {
 	p := defaultTestConfig(t)
 	cmd := NewRootInternalCommand(p).Command
 
 	assert.Equal(t, "internal", cmd.Name)
 	assert.True(t, cmd.Hidden)
-	assert.Len(t, cmd.Commands, 2)
+	assert.Len(t, cmd.Commands, 3)
 	assert.Equal(t, "force-cleanup", cmd.Commands[0].Name)
 	assert.Equal(t, "cleanup-terminating-pods", cmd.Commands[1].Name)
+	assert.Equal(t, "validate-cleanup", cmd.Commands[2].Name)
 
-	err := cmd.Commands[0].Action(context.Background(), &cli.Command{})
+	err := cmd.Commands[0].Run(context.Background(), []string{"force-cleanup", "--yes"})
 	assert.NoError(t, err)
 }
 
@@ -155,17 +273,182 @@ func TestVersion(t *testing.T) {
 	assert.Contains(t, output, "Build Date: 2023-01-01")
 }
 
+func TestNewRootVersionCommand(t *testing.T) {
+	cmd := NewRootVersionCommand(AppServiceParams{Version: "1.0.0"}, defaultTestConfig(t)).Command
+
+	assert.Equal(t, "version", cmd.Name)
+	assert.Len(t, cmd.Flags, 2)
+
+	flag := cmd.Flags[0].(*cli.BoolFlag)
+	assert.Equal(t, "json", flag.Name)
+
+	flag = cmd.Flags[1].(*cli.BoolFlag)
+	assert.Equal(t, "check-update", flag.Name)
+}
+
+// newReleaseHttpClientMock returns an http client factory answering the GitHub latest release api with the tag.
+func newReleaseHttpClientMock(t *testing.T, tag string, calls *int) util.HttpClientFactoryMock {
+	return util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			*calls++
+			assert.Equal(t, "/repos/MetroStar/quartzctl/releases/latest", req.URL.Path)
+
+			body, _ := json.Marshal(map[string]string{
+				"tag_name": tag,
+				"html_url": "https://github.com/MetroStar/quartzctl/releases/tag/" + tag,
+			})
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(body)),
+				Header:     http.Header{},
+			}
+		},
+	}
+}
+
+func TestCmdCheckUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		expected string
+	}{
+		{"newer", "v1.1.0", "A newer version of Quartz is available: v1.2.0 (current v1.1.0)"},
+		{"same", "1.2.0", "Quartz 1.2.0 is up to date"},
+		{"older", "v1.3.0-rc.1", "Quartz v1.3.0-rc.1 is up to date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := defaultTestConfig(t)
+			calls := 0
+
+			var buf bytes.Buffer
+			util.SetWriter(&buf)
+			defer util.SetWriter(os.Stderr)
+
+			err := CheckUpdate(context.Background(), p, newReleaseHttpClientMock(t, "v1.2.0", &calls), tt.current)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, calls)
+			assert.Contains(t, buf.String(), tt.expected)
+		})
+	}
+}
+
+func TestCmdCheckUpdateDevBuild(t *testing.T) {
+	for _, current := range []string{"dev", "0123abc"} {
+		t.Run(current, func(t *testing.T) {
+			p := defaultTestConfig(t)
+			calls := 0
+
+			var buf bytes.Buffer
+			util.SetWriter(&buf)
+			defer util.SetWriter(os.Stderr)
+
+			err := CheckUpdate(context.Background(), p, newReleaseHttpClientMock(t, "v1.2.0", &calls), current)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, calls)
+			assert.Contains(t, buf.String(), "development build, update check skipped")
+		})
+	}
+}
+
+func TestCmdCheckUpdateDisabled(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.DisableUpdateCheck = true
+	calls := 0
+
+	err := CheckUpdate(context.Background(), p, newReleaseHttpClientMock(t, "v1.2.0", &calls), "v1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestCmdVersionJson(t *testing.T) {
+	var buf bytes.Buffer
+	err := VersionJson(&buf, "1.0.0", "1672531200", "0123456789abcdef")
+	assert.NoError(t, err)
+
+	var info map[string]string
+	err = json.Unmarshal(buf.Bytes(), &info)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"version":   "1.0.0",
+		"buildDate": "2023-01-01T00:00:00Z",
+		"gitCommit": "0123456789abcdef",
+		"goVersion": goruntime.Version(),
+		"os":        goruntime.GOOS,
+		"arch":      goruntime.GOARCH,
+	}, info)
+
+	err = VersionJson(&buf, "1.0.0", "yesterday", "")
+	assert.ErrorContains(t, err, "invalid build date")
+}
+
 func TestCmdRender(t *testing.T) {
 	p := defaultTestConfig(t)
 
 	tmp := t.TempDir()
 	out := filepath.Join(tmp, "render_test.yaml")
-	err := Render(context.Background(), out, p)
+	err := Render(context.Background(), out, "", p)
 	if err != nil {
 		t.Errorf("unexpected error in cmd Render, %v", err)
 	}
 }
 
+func TestCmdRenderOnly(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	out := filepath.Join(t.TempDir(), "render_test.yaml")
+	err := Render(context.Background(), out, "dns", p)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "example.com")
+	assert.NotContains(t, string(b), "stages")
+
+	err = Render(context.Background(), out, "notakey", p)
+	assert.Error(t, err)
+}
+
+func TestCmdRenderDiff(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	prev := filepath.Join(t.TempDir(), "previous.yaml")
+	err := Render(context.Background(), prev, "", p)
+	assert.NoError(t, err)
+
+	// identical
+	err = RenderDiff(context.Background(), prev, "", p)
+	assert.NoError(t, err)
+
+	// changed
+	b, err := os.ReadFile(prev)
+	assert.NoError(t, err)
+	err = os.WriteFile(prev, []byte(strings.Replace(string(b), "example.com", "changed.example.com", 1)), 0600)
+	assert.NoError(t, err)
+
+	err = RenderDiff(context.Background(), prev, "", p)
+	assert.ErrorContains(t, err, "rendered config differs")
+
+	// missing previous file
+	err = RenderDiff(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"), "", p)
+	assert.Error(t, err)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	d, err := unifiedDiff("a", []byte("one: 1\ntwo: 2\n"), "b", []byte("one: 1\ntwo: 2\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, d)
+
+	d, err = unifiedDiff("a", []byte("one: 1\ntwo: 2\n"), "b", []byte("one: 1\ntwo: 3\n"))
+	assert.NoError(t, err)
+	assert.Contains(t, d, "--- a")
+	assert.Contains(t, d, "+++ b")
+	assert.Contains(t, d, "-two: 2")
+	assert.Contains(t, d, "+two: 3")
+}
+
 func TestRender(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputPath := filepath.Join(tmpDir, "output.yaml")
@@ -173,7 +456,7 @@ func TestRender(t *testing.T) {
 	mockSettings, _ := config.NewSettings(koanf.New("."), koanf.New("."))
 	mockParams := &CommandParams{settings: &mockSettings}
 
-	err := Render(context.Background(), outputPath, mockParams)
+	err := Render(context.Background(), outputPath, "", mockParams)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(outputPath)
@@ -186,19 +469,31 @@ func TestCmdClusterInfo(t *testing.T) {
 	// defaults to true, just specifying here to be explicit for the second case
 	p.Settings().Config.Internal.Installer.Summary.Enabled = true
 
-	err := ClusterInfo(context.Background(), p)
+	err := ClusterInfo(context.Background(), p, provider.ClusterInfoOpts{})
 	if err != nil {
 		t.Errorf("unexpected error in cmd ClusterInfo, %v", err)
 	}
 
 	// silently disables the summary output for dev environments where it's prone to failure
 	p.Settings().Config.Internal.Installer.Summary.Enabled = false
-	err = ClusterInfo(context.Background(), p)
+	err = ClusterInfo(context.Background(), p, provider.ClusterInfoOpts{})
 	if err != nil {
 		t.Errorf("unexpected error in cmd ClusterInfo, %v", err)
 	}
 }
 
+func TestCmdHealthSummary(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.Internal.Installer.Summary.Enabled = true
+
+	err := HealthSummary(context.Background(), p)
+	assert.NoError(t, err)
+
+	p.Settings().Config.Internal.Installer.Summary.Enabled = false
+	err = HealthSummary(context.Background(), p)
+	assert.NoError(t, err)
+}
+
 func TestCmdClusterLogin(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -210,9 +505,49 @@ func TestCmdClusterLogin(t *testing.T) {
 	}
 }
 
+func TestCmdLoginPrint(t *testing.T) {
+	p := defaultTestConfig(t)
+	k8s, err := provider.NewKubernetesClient(provider.NewKubernetesApiMock(), provider.KubeconfigInfo{
+		Cluster:  "mytest",
+		Context:  "mytest",
+		User:     "mytest",
+		Endpoint: "https://mytest.example.com",
+		Token:    "token",
+	}, p.Settings().Config)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets, provider.WithKubernetesProvider(k8s))
+
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	cmd := NewRootLoginCommand(p).Command
+	err = cmd.Run(context.Background(), []string{cmd.Name, "--print"})
+	w.Close()
+	assert.NoError(t, err)
+
+	out, _ := io.ReadAll(r)
+	kubeconfig, err := clientcmd.Load(out)
+	if assert.NoError(t, err, "expected valid kubeconfig yaml on stdout") {
+		assert.Contains(t, kubeconfig.Clusters, "mytest")
+		assert.Equal(t, "https://mytest.example.com", kubeconfig.Clusters["mytest"].Server)
+		assert.Equal(t, "mytest", kubeconfig.CurrentContext)
+	}
+}
+
 func TestCmdCheck(t *testing.T) {
 	p := defaultTestConfig(t)
-	Check(context.Background(), p)
+	Check(context.Background(), nil, false, false, p)
+}
+
+func TestCmdCheckUnknownProvider(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	err := Check(context.Background(), []string{"nope"}, false, false, p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+	assert.Equal(t, ExitCodeConfig, ExitCode(err))
 }
 
 func TestCmdRefreshSecrets(t *testing.T) {
@@ -233,6 +568,35 @@ func TestCmdCleanup(t *testing.T) {
 	}
 }
 
+func TestCmdCleanupRemovesTmp(t *testing.T) {
+	p := defaultTestConfig(t)
+	tmp := p.Settings().Config.Tmp
+
+	err := Cleanup(context.Background(), p)
+	assert.NoError(t, err)
+	assert.NoDirExists(t, tmp)
+}
+
+func TestCmdCleanupKeepTmpFlag(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.SetKeepTmp(true)
+	tmp := p.Settings().Config.Tmp
+
+	err := Cleanup(context.Background(), p)
+	assert.NoError(t, err)
+	assert.DirExists(t, tmp)
+}
+
+func TestCmdCleanupKeepTmpConfig(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.KeepTmp = true
+	tmp := p.Settings().Config.Tmp
+
+	err := Cleanup(context.Background(), p)
+	assert.NoError(t, err)
+	assert.DirExists(t, tmp)
+}
+
 func TestCmdBanner(t *testing.T) {
 	Banner()
 }
@@ -251,12 +615,69 @@ func TestCmdExport(t *testing.T) {
 
 	p.Settings().Config.Export.Path = t.TempDir()
 
-	err := Export(context.Background(), p)
+	err := Export(context.Background(), false, p)
 	if err != nil {
 		t.Errorf("unexpected error in cmd Export, %v", err)
 	}
 }
 
+func TestCmdExportPartial(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	out := t.TempDir()
+	p.Settings().Config.Export.Path = out
+	p.Settings().Config.Export.Objects = []schema.ExportObjectConfig{
+		{Kind: "ExternalSecret", Namespace: "testns1", Name: "testobj1"},
+		{Kind: "ExternalSecret", Namespace: "testns1", Name: "missing"},
+	}
+	exported := filepath.Join(out, p.Settings().Config.Dns.Domain, "testns1.testobj1.yaml")
+
+	err := Export(context.Background(), false, p)
+	assert.NoError(t, err)
+	assert.FileExists(t, exported)
+
+	assert.NoError(t, os.Remove(exported))
+
+	err = Export(context.Background(), true, p)
+	assert.ErrorContains(t, err, "failed to export ExternalSecret testns1/missing")
+	assert.FileExists(t, exported, "expected present objects to be written under strict")
+	assert.NoFileExists(t, filepath.Join(out, p.Settings().Config.Dns.Domain, "testns1.missing.yaml"))
+}
+
+func TestCmdExportPathTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "default", template: "", expected: "%s/testns1.testobj1.yaml"},
+		{name: "custom", template: "{namespace}/{kind}/{name}.yaml", expected: "testns1/externalsecret/testobj1.yaml"},
+		{name: "domain", template: "{domain}/{kind}-{name}.yaml", expected: "%s/externalsecret-testobj1.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := defaultTestConfig(t)
+
+			out := t.TempDir()
+			p.Settings().Config.Export.Path = out
+			p.Settings().Config.Export.PathTemplate = tt.template
+			p.Settings().Config.Export.Objects = []schema.ExportObjectConfig{
+				{Kind: "ExternalSecret", Namespace: "testns1", Name: "testobj1"},
+			}
+
+			err := Export(context.Background(), true, p)
+			assert.NoError(t, err)
+
+			expected := tt.expected
+			if strings.Contains(expected, "%s") {
+				expected = fmt.Sprintf(expected, p.Settings().Config.Dns.Domain)
+			}
+			assert.FileExists(t, filepath.Join(out, expected))
+		})
+	}
+}
+
 func TestCmdPrepareAccount(t *testing.T) {
 	p := defaultTestConfig(t)
 
@@ -266,10 +687,54 @@ func TestCmdPrepareAccount(t *testing.T) {
 	}
 }
 
+// testAdminCloudProvider is a local cloud provider recording the administrators it's asked to reconcile.
+type testAdminCloudProvider struct {
+	provider.LocalClient
+	administrators *[]string
+	err            error
+}
+
+// ReconcileAdministrators records the administrators, reporting the arns as granted access.
+func (c testAdminCloudProvider) ReconcileAdministrators(ctx context.Context, administrators []string) ([]string, error) {
+	*c.administrators = administrators
+
+	var granted []string
+	for _, a := range administrators {
+		if strings.HasPrefix(a, "arn:") {
+			granted = append(granted, a)
+		}
+	}
+	return granted, c.err
+}
+
+func TestCmdReconcileAdministrators(t *testing.T) {
+	p := defaultTestConfig(t)
+
+	// local clusters have no cloud principals to grant access to
+	err := ReconcileAdministrators(context.Background(), p)
+	assert.NoError(t, err)
+
+	var administrators []string
+	p.Settings().Config.Administrators = []string{"admins", "arn:aws:iam::123456789:role/admin"}
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets,
+		provider.WithCloudProvider(testAdminCloudProvider{administrators: &administrators}))
+
+	err = ReconcileAdministrators(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admins", "arn:aws:iam::123456789:role/admin"}, administrators)
+
+	p.provider = provider.NewProviderFactory(p.Settings().Config, p.Settings().Secrets,
+		provider.WithCloudProvider(testAdminCloudProvider{administrators: &administrators, err: errors.New("access denied")}))
+
+	err = ReconcileAdministrators(context.Background(), p)
+	assert.ErrorContains(t, err, "access denied")
+}
+
 func defaultTestConfig(t *testing.T) *CommandParams {
 	t.Setenv("SILENT", "1")
 
 	terraform.ResetInstance()
+	util.ResetRunOnce()
 
 	c := filepath.Join("testdata", "config.happy.yaml")
 	s := filepath.Join("testdata", "secrets.happy.yaml")
@@ -450,3 +915,64 @@ func TestOnCheckRetry(t *testing.T) {
 		})
 	}
 }
+
+// mockTmpFreeSpace replaces the tmp free space lookup for the duration of the test.
+func mockTmpFreeSpace(t *testing.T, free uint64, err error) {
+	orig := tmpFreeSpace
+	t.Cleanup(func() { tmpFreeSpace = orig })
+
+	tmpFreeSpace = func(path string) (uint64, error) {
+		return free, err
+	}
+}
+
+func TestCmdPreflightTmp(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.TmpMinFreeMb = 1024
+	mockTmpFreeSpace(t, 2048*1024*1024, nil)
+
+	err := PreflightTmp(p)
+	assert.NoError(t, err)
+
+	entries, _ := os.ReadDir(p.Settings().Config.Tmp)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), "write-check", "expected the write check file removed")
+	}
+}
+
+func TestCmdPreflightTmpLowSpace(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.TmpMinFreeMb = 1024
+	mockTmpFreeSpace(t, 100*1024*1024, nil)
+
+	err := PreflightTmp(p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+	assert.ErrorContains(t, err, "has 100 MB free, at least 1024 MB is needed")
+
+	// a minimum of 0 skips the space check
+	p.Settings().Config.TmpMinFreeMb = 0
+	assert.NoError(t, PreflightTmp(p))
+}
+
+func TestCmdPreflightTmpSpaceUnknown(t *testing.T) {
+	p := defaultTestConfig(t)
+	p.Settings().Config.TmpMinFreeMb = 1024
+	mockTmpFreeSpace(t, 0, fmt.Errorf("statfs not supported"))
+
+	err := PreflightTmp(p)
+	assert.NoError(t, err, "expected only a warning when free space can't be determined")
+}
+
+func TestCmdPreflightTmpNotWritable(t *testing.T) {
+	p := defaultTestConfig(t)
+	mockTmpFreeSpace(t, 2048*1024*1024, nil)
+
+	// a file in place of the tmp directory can't be written to, even as root
+	tmp := filepath.Join(t.TempDir(), "tmp")
+	assert.NoError(t, os.WriteFile(tmp, []byte("not a directory"), 0600))
+	p.Settings().Config.Tmp = tmp
+
+	err := PreflightTmp(p)
+	assert.ErrorAs(t, err, &util.ConfigError{})
+	assert.ErrorContains(t, err, "is not writable")
+}
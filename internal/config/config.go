@@ -19,9 +19,13 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -35,26 +39,47 @@ import (
 	"github.com/MetroStar/quartzctl/internal/log"
 	"github.com/MetroStar/quartzctl/internal/provider"
 	"github.com/MetroStar/quartzctl/internal/stages"
+	"github.com/MetroStar/quartzctl/internal/util"
 )
 
+// Overrides holds command line overrides applied on top of the loaded configuration.
+type Overrides struct {
+	Values   []string // key=value pairs with dotted keys, e.g. dns.domain=foo.example.com
+	VarFiles []string // yaml files merged over the config in order
+	Env      string   // environment overlay, quartz.<env>.yaml next to the config file
+}
+
 // Load reads the configuration and secrets files and parses them into a Settings instance.
 func Load(ctx context.Context, configFile string, secretsFile string) (Settings, error) {
-	k, err := LoadRawConfig(ctx, configFile)
+	return LoadWithOverrides(ctx, configFile, secretsFile, Overrides{})
+}
+
+// LoadWithOverrides reads the configuration and secrets files, applies the provided
+// overrides with the highest precedence, and parses them into a Settings instance.
+func LoadWithOverrides(ctx context.Context, configFile string, secretsFile string, o Overrides) (Settings, error) {
+	k, err := LoadRawConfigWithOverrides(ctx, configFile, o)
 	if err != nil {
-		return Settings{}, err
+		return Settings{}, util.NewConfigError(err)
 	}
 
 	s, err := LoadRawSecrets(ctx, secretsFile)
 	if err != nil {
-		return Settings{}, err
+		return Settings{}, util.NewConfigError(err)
 	}
 
-	return NewSettings(k, s)
+	cfg, err := NewSettings(k, s)
+	return cfg, util.NewConfigError(err)
 }
 
 // LoadRawConfig reads the specified configuration file and processes it into a Koanf map.
 // It applies defaults, environment variables, and additional settings.
 func LoadRawConfig(ctx context.Context, configFile string) (*koanf.Koanf, error) {
+	return LoadRawConfigWithOverrides(ctx, configFile, Overrides{})
+}
+
+// LoadRawConfigWithOverrides reads the specified configuration file and processes it into a Koanf map
+// like LoadRawConfig, merging the provided overrides after the file and environment are loaded.
+func LoadRawConfigWithOverrides(ctx context.Context, configFile string, o Overrides) (*koanf.Koanf, error) {
 	k := koanf.New(".")
 
 	// set initial defaults
@@ -69,6 +94,19 @@ func LoadRawConfig(ctx context.Context, configFile string) (*koanf.Koanf, error)
 		log.Warn("No config file found", "path", configFile, "err", err)
 	}
 
+	// merge the selected environment overlay over the base config
+	if err := loadEnvironmentOverlay(k, configFile, o.Env); err != nil {
+		return nil, err
+	}
+
+	// expand ${VAR} references in config values before anything is derived from them
+	expandEnvironment(k)
+
+	// First pass of overrides in case needed as inputs elsewhere
+	if err := loadOverrides(k, o); err != nil {
+		return nil, err
+	}
+
 	if err := checkCloudConfig(ctx, k); err != nil {
 		return nil, err
 	}
@@ -90,6 +128,23 @@ func LoadRawConfig(ctx context.Context, configFile string) (*koanf.Koanf, error)
 	// Second pass of environment variables to ensure precedence
 	loadDefaultEnvironment(k)
 
+	// Second pass of overrides, these take precedence over everything else
+	if err := loadOverrides(k, o); err != nil {
+		return nil, err
+	}
+
+	if err := checkAdministrators(k); err != nil {
+		return nil, err
+	}
+
+	if err := checkDestroyFilters(k); err != nil {
+		return nil, err
+	}
+
+	if err := setStateEncryption(k); err != nil {
+		return nil, err
+	}
+
 	tmp, err := initTmpDir(k)
 	if err != nil {
 		log.Warn("Failed to create tmp directory", "dir", tmp, "err", err)
@@ -124,11 +179,118 @@ func loadDefaultEnvironment(k *koanf.Koanf) {
 	}
 }
 
+// environmentOverlayPath returns the overlay file for name next to the config file, e.g. quartz.dev.yaml.
+func environmentOverlayPath(configFile string, name string) string {
+	ext := filepath.Ext(configFile)
+	return strings.TrimSuffix(configFile, ext) + "." + name + ext
+}
+
+// loadEnvironmentOverlay validates name against the configured environments and merges its overlay file.
+func loadEnvironmentOverlay(k *koanf.Koanf, configFile string, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	envs := k.MapKeys("environments")
+	if !slices.Contains(envs, name) {
+		return fmt.Errorf("unknown environment %q, must be one of %s", name, strings.Join(envs, ", "))
+	}
+
+	overlay := environmentOverlayPath(configFile, name)
+	if err := k.Load(file.Provider(overlay), yaml.Parser()); err != nil {
+		return fmt.Errorf("failed to load environment overlay %s, %w", overlay, err)
+	}
+
+	log.Debug("Loaded environment overlay", "env", name, "path", overlay)
+	return k.Set("active_environment", name)
+}
+
+// loadOverrides merges any var files followed by key=value overrides into the Koanf map.
+func loadOverrides(k *koanf.Koanf, o Overrides) error {
+	for _, f := range o.VarFiles {
+		if err := k.Load(file.Provider(f), yaml.Parser()); err != nil {
+			return fmt.Errorf("failed to load var file %s, %w", f, err)
+		}
+	}
+
+	for _, v := range o.Values {
+		key, val, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid override %s, expected key=value", v)
+		}
+
+		if err := k.Set(key, val); err != nil {
+			return fmt.Errorf("failed to set override %s, %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// envVarPattern matches ${VAR} and ${VAR:-default} references in config values.
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnvironment replaces ${VAR} and ${VAR:-default} references in string config values
+// with the value of the named environment variable. Only the raw config is expanded, secrets
+// are loaded separately and left untouched since they may legitimately contain `$`.
+func expandEnvironment(k *koanf.Koanf) {
+	for key, val := range k.All() {
+		switch v := val.(type) {
+		case string:
+			if e := expandEnvString(v); e != v {
+				k.Set(key, e)
+			}
+		case []interface{}:
+			changed := false
+			r := make([]interface{}, len(v))
+			for i, item := range v {
+				r[i] = item
+				if str, ok := item.(string); ok {
+					if e := expandEnvString(str); e != str {
+						r[i] = e
+						changed = true
+					}
+				}
+			}
+
+			if changed {
+				k.Set(key, r)
+			}
+		}
+	}
+}
+
+// expandEnvString expands ${VAR} and ${VAR:-default} references in a single string value.
+// A missing variable without a default expands to an empty string.
+func expandEnvString(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	return envVarPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sm := envVarPattern.FindStringSubmatch(m)
+		name, hasDefault, def := sm[1], sm[2] != "", sm[3]
+
+		if val := os.Getenv(name); val != "" {
+			return val
+		}
+
+		if hasDefault {
+			return def
+		}
+
+		log.Warn("Environment variable referenced in config is not set", "name", name)
+		return ""
+	})
+}
+
 // setDnsDefaults sets default values for DNS configuration.
-// It ensures that at least one of `dns.zone` or `dns.domain` is specified.
+// It ensures that at least one of `dns.zone` or `dns.domain` is specified and that both
+// are valid DNS names, trailing dots are dropped.
 func setDnsDefaults(k *koanf.Koanf) error {
-	zone := k.String("dns.zone")
-	domain := k.String("dns.domain")
+	zone := strings.TrimSuffix(k.String("dns.zone"), ".")
+	domain := strings.TrimSuffix(k.String("dns.domain"), ".")
 
 	if zone == "" && domain == "" {
 		return fmt.Errorf("at least one of dns.zone or dns.domain must be specified")
@@ -136,13 +298,64 @@ func setDnsDefaults(k *koanf.Koanf) error {
 
 	// assume zone is the remainder of the domain
 	if zone == "" {
+		if err := checkDnsName(domain); err != nil {
+			return fmt.Errorf("dns.domain %q is invalid, %w", domain, err)
+		}
+
 		s := strings.SplitN(domain, ".", 2)
-		return k.Set("dns.zone", s[1])
+		if len(s) < 2 {
+			return fmt.Errorf("dns.domain %q is invalid, expected <name>.<zone> (e.g. mycluster.example.com)", domain)
+		}
+
+		return errors.Join(k.Set("dns.domain", domain), k.Set("dns.zone", s[1]))
+	}
+
+	if err := checkDnsName(zone); err != nil {
+		return fmt.Errorf("dns.zone %q is invalid, %w", zone, err)
 	}
 
 	// otherwise prepend the cluster name to the zone for the full domain
 	name := k.String("name")
-	return k.Set("dns.domain", name+"."+zone)
+	domain = name + "." + zone
+	if err := checkDnsName(domain); err != nil {
+		return fmt.Errorf("dns.domain %q (name and dns.zone) is invalid, %w", domain, err)
+	}
+
+	return errors.Join(k.Set("dns.zone", zone), k.Set("dns.domain", domain))
+}
+
+// checkDnsName validates a DNS name: not an IP address, at most 253 characters, and
+// dot separated labels of 1-63 letters, digits and hyphens not starting or ending with a hyphen.
+func checkDnsName(s string) error {
+	if net.ParseIP(s) != nil {
+		return fmt.Errorf("an IP address is not a DNS name")
+	}
+
+	if len(s) > 253 {
+		return fmt.Errorf("longer than 253 characters")
+	}
+
+	for _, l := range strings.Split(s, ".") {
+		if l == "" {
+			return fmt.Errorf("empty label")
+		}
+
+		if len(l) > 63 {
+			return fmt.Errorf("label %q longer than 63 characters", l)
+		}
+
+		if strings.HasPrefix(l, "-") || strings.HasSuffix(l, "-") {
+			return fmt.Errorf("label %q starts or ends with a hyphen", l)
+		}
+
+		for _, r := range l {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Errorf("label %q contains invalid character %q", l, r)
+			}
+		}
+	}
+
+	return nil
 }
 
 // loadCredentialsEnvironment loads credentials from environment variables into the Koanf map.
@@ -151,8 +364,11 @@ func loadCredentialsEnvironment(k *koanf.Koanf) {
 		"ironbank.username":     {"IRONBANK_USERNAME", "REGISTRY_USERNAME"},
 		"ironbank.password":     {"IRONBANK_PASSWORD", "REGISTRY_PASSWORD"},
 		"ironbank.email":        {"IRONBANK_EMAIL", "REGISTRY_EMAIL"},
+		"ironbank.registry":     {"IRONBANK_REGISTRY", "REGISTRY_HOST"},
 		"github.username":       {"GITHUB_USERNAME"},
 		"github.token":          {"GITHUB_TOKEN"},
+		"gitea.username":        {"GITEA_USERNAME"},
+		"gitea.token":           {"GITEA_TOKEN"},
 		"cloudflare.account_id": {"CLOUDFLARE_ACCOUNT_ID"},
 		"cloudflare.api_token":  {"CLOUDFLARE_API_TOKEN", "CLOUDFLARE_TOKEN"},
 		"cloudflare.email":      {"CLOUDFLARE_EMAIL"},
@@ -189,11 +405,13 @@ func setDefaults(k *koanf.Koanf) {
 
 	err := k.Load(structs.Provider(schema.QuartzConfig{
 		Project:      "quartz",
+		TmpMinFreeMb: 1024,
 		Chart:        schema.ChartConfig{Path: filepath.Join(pwd, "base")},
 		Providers:    providers,
+		Aws:          schema.NewAwsConfig(),
 		Terraform:    schema.NewTerraformConfig(),
 		Auth:         schema.DefaultAuthConfig(),
-		Gitops:       schema.DefaultGitopsConfig(providers.SourceControl),
+		Gitops:       schema.DefaultGitopsConfig(""), // provider follows providers.source_control, see setGitopsDefaults
 		Github:       schema.NewGithubConfig(),
 		Environments: schema.DefaultApplicationEnvironments(),
 		Core:         schema.NewInfrastructureEnvironmentConfig("infra", "Quartz"),
@@ -201,6 +419,7 @@ func setDefaults(k *koanf.Koanf) {
 		StagePaths:   []string{filepath.Join(pwd, "terraform", "stages")},
 		Export:       schema.NewExportConfig(),
 		State:        schema.NewStateConfig(),
+		Cleanup:      schema.NewCleanupConfig(),
 		Log:          log.DefaultLogConfig.Log,
 		Internal:     schema.NewInternalConfig(),
 	}, "koanf"), nil)
@@ -215,7 +434,9 @@ func checkCloudConfig(ctx context.Context, k *koanf.Koanf) error {
 	p := k.String("providers.cloud")
 
 	pc, err := provider.NewCloudProviderClientWithOpts(ctx, provider.CloudProviderClientOpts{
-		Provider: p,
+		Provider:             p,
+		Region:               k.String("aws.region"),
+		SkipRegionValidation: k.Bool("aws.skip_region_validation"),
 	})
 	if err != nil {
 		return err
@@ -259,7 +480,7 @@ func setGitopsDefaults(k *koanf.Koanf) {
 		}
 
 		if r.RepoUrl == "" {
-			r.RepoUrl = githubRepoUrl(r.Organization, r.Name)
+			r.RepoUrl = repoUrl(k, r.Provider, r.Organization, r.Name)
 		}
 	}
 
@@ -339,7 +560,7 @@ func setAppDefaults(k *koanf.Koanf) {
 		}
 
 		if a.RepoUrl == "" && !strings.EqualFold(a.Type, "external") {
-			a.RepoUrl = githubRepoUrl(a.Organization, a.Name)
+			a.RepoUrl = repoUrl(k, a.Provider, a.Organization, a.Name)
 		}
 
 		if a.Settings == nil {
@@ -432,6 +653,77 @@ func setAuthDefaults(k *koanf.Koanf) {
 	k.MergeAt(k2, "auth")
 }
 
+// checkAdministrators validates that each administrator references a configured auth user or group,
+// or an IAM principal arn. A typo would otherwise silently leave the cluster without administrators.
+func checkAdministrators(k *koanf.Koanf) error {
+	var auth schema.AuthConfig
+	k.Unmarshal("auth", &auth)
+
+	var unknown []string
+	for _, a := range k.Strings("administrators") {
+		if strings.HasPrefix(a, "arn:") {
+			continue
+		}
+
+		if _, ok := auth.Users[a]; ok {
+			continue
+		}
+
+		if g, ok := auth.Groups[a]; ok && !g.Disabled {
+			continue
+		}
+
+		unknown = append(unknown, a)
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("administrators %s not found in auth.users or auth.groups", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// setStateEncryption resolves the state bucket encryption, aws:kms when state.kms_key_arn is set
+// and AES256 otherwise, and validates an explicitly configured one.
+func setStateEncryption(k *koanf.Koanf) error {
+	enc := k.String("state.encryption")
+	key := k.String("state.kms_key_arn")
+
+	switch {
+	case enc == "" && key != "":
+		enc = "aws:kms"
+	case enc == "":
+		enc = "AES256"
+	case strings.EqualFold(enc, "aws:kms"):
+		enc = "aws:kms"
+	case strings.EqualFold(enc, "AES256"):
+		if key != "" {
+			return fmt.Errorf("state.kms_key_arn is only supported with aws:kms encryption, found state.encryption %s", enc)
+		}
+		enc = "AES256"
+	default:
+		return fmt.Errorf("unsupported state.encryption %s, expected AES256 or aws:kms", enc)
+	}
+
+	return k.Set("state.encryption", enc)
+}
+
+// checkDestroyFilters validates that each stage's destroy include and exclude patterns compile,
+// an invalid regex would otherwise only surface as a missed match during destroy.
+func checkDestroyFilters(k *koanf.Koanf) error {
+	var stg map[string]schema.StageConfig
+	k.Unmarshal("stages", &stg)
+
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(stg)) {
+		d := stg[id].Destroy
+		errs = append(errs, schema.ValidateDestroyPatterns(fmt.Sprintf("stage %s destroy.include", id), d.Include)...)
+		errs = append(errs, schema.ValidateDestroyPatterns(fmt.Sprintf("stage %s destroy.exclude", id), d.Exclude)...)
+	}
+
+	return errors.Join(errs...)
+}
+
 // loadStages parses stage configurations from directories and `stage.yaml` files.
 // It merges the parsed stages with the existing configuration.
 func loadStages(k *koanf.Koanf) {
@@ -443,6 +735,13 @@ func loadStages(k *koanf.Koanf) {
 
 	// parse stages from convention directories and discovered stage.yaml configs
 	ss := stages.LoadStages(stg, k.Strings("stage_paths")...)
+
+	// matrix stages are replaced by their expanded copies
+	for key := range stg {
+		if _, found := ss[key]; !found {
+			k.Delete("stages." + key)
+		}
+	}
 	for key, s := range ss {
 		if s.Disabled {
 			// TODO: this won't work if a dependent stage is disabled, need something more robust
@@ -487,6 +786,21 @@ func initTmpDir(k *koanf.Koanf) (string, error) {
 	return tmp, nil
 }
 
+// repoUrl constructs the repository URL for the source control provider, organization and repository name.
+func repoUrl(k *koanf.Koanf, provider string, org string, name string) string {
+	switch strings.ToLower(provider) {
+	case "gitea":
+		return giteaRepoUrl(k.String("gitea.url"), org, name)
+	}
+
+	return githubRepoUrl(org, name)
+}
+
+// giteaRepoUrl constructs the Gitea repository URL on the given host for the organization and repository name.
+func giteaRepoUrl(baseUrl string, org string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(baseUrl, "/"), org, name)
+}
+
 // githubRepoUrl constructs the GitHub repository URL for the specified organization and repository name.
 func githubRepoUrl(org string, name string) string {
 	return fmt.Sprintf("https://github.com/%s/%s", org, name)
@@ -16,6 +16,8 @@ package config
 
 import (
 	jsonenc "encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
@@ -66,10 +68,47 @@ func (r Settings) SecretString(key string) string {
 	return r.rawSecrets.String(key)
 }
 
+// ConfigInt retrieves a raw configuration value by its key as an int.
+// Returns def if the key is not set.
+func (r Settings) ConfigInt(key string, def int) int {
+	if !r.rawConfig.Exists(key) {
+		return def
+	}
+
+	return r.rawConfig.Int(key)
+}
+
+// ConfigBool retrieves a raw configuration value by its key as a bool.
+// Returns def if the key is not set.
+func (r Settings) ConfigBool(key string, def bool) bool {
+	if !r.rawConfig.Exists(key) {
+		return def
+	}
+
+	return r.rawConfig.Bool(key)
+}
+
+// ConfigDuration retrieves a raw configuration value by its key as a duration, parsing
+// strings such as "90s" or "5m" and treating numbers as nanoseconds.
+// Returns def if the key is not set.
+func (r Settings) ConfigDuration(key string, def time.Duration) time.Duration {
+	if !r.rawConfig.Exists(key) {
+		return def
+	}
+
+	return r.rawConfig.Duration(key)
+}
+
 // WriteJsonConfig writes the application configuration to a JSON file.
-// Supports an optional root key and indentation for pretty printing.
-func (r Settings) WriteJsonConfig(path string, root string, indent bool) error {
-	b, err := marshalJsonRoot(r.rawConfig, root, indent)
+// Supports an optional root key, an optional key path to write only a subtree
+// of the configuration, and indentation for pretty printing.
+func (r Settings) WriteJsonConfig(path string, root string, key string, indent bool) error {
+	k, err := subtree(r.rawConfig, key)
+	if err != nil {
+		return err
+	}
+
+	b, err := marshalJsonRoot(k, root, indent)
 	if err != nil {
 		return err
 	}
@@ -79,10 +118,9 @@ func (r Settings) WriteJsonConfig(path string, root string, indent bool) error {
 }
 
 // WriteYamlConfig writes the application configuration to a YAML file.
-func (r Settings) WriteYamlConfig(path string) error {
-	parser := yaml.Parser()
-
-	b, err := r.rawConfig.Marshal(parser)
+// Supports an optional key path to write only a subtree of the configuration.
+func (r Settings) WriteYamlConfig(path string, key string) error {
+	b, err := r.MarshalYamlConfig(key)
 	if err != nil {
 		return err
 	}
@@ -91,6 +129,36 @@ func (r Settings) WriteYamlConfig(path string) error {
 	return util.WriteBytesToFile(b, path)
 }
 
+// MarshalYamlConfig serializes the application configuration to YAML.
+// Supports an optional key path to serialize only a subtree of the configuration.
+func (r Settings) MarshalYamlConfig(key string) ([]byte, error) {
+	k, err := subtree(r.rawConfig, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.Marshal(yaml.Parser())
+}
+
+// subtree returns a copy of the configuration containing only the specified key path,
+// preserving the path itself so the output remains valid config. Secrets are never part
+// of the raw configuration, so they remain excluded regardless of the key requested.
+// Returns the full configuration if the key is empty.
+func subtree(k *koanf.Koanf, key string) (*koanf.Koanf, error) {
+	if key == "" {
+		return k, nil
+	}
+
+	if !k.Exists(key) {
+		return nil, fmt.Errorf("config key not found, %s", key)
+	}
+
+	copy := koanf.New(".")
+	copy.Set(key, k.Get(key))
+
+	return copy, nil
+}
+
 // marshalJsonRoot serializes the configuration to JSON, optionally nesting it under a root key.
 // Supports indentation for pretty printing.
 func marshalJsonRoot(k *koanf.Koanf, root string, indent bool) ([]byte, error) {
@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/knadh/koanf/v2"
 )
@@ -45,13 +46,75 @@ func TestConfigResultSecretString(t *testing.T) {
 	}
 }
 
+func TestConfigResultConfigInt(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("test.number", 42)
+	k.Set("test.string", "7")
+	sut := Settings{rawConfig: k}
+
+	tests := map[string]int{
+		"test.number":  42,
+		"test.string":  7,
+		"test.missing": 3,
+	}
+
+	for key, expected := range tests {
+		actual := sut.ConfigInt(key, 3)
+		if actual != expected {
+			t.Errorf("incorrect response to raw config int lookup of %s, expected %d, found %d", key, expected, actual)
+		}
+	}
+}
+
+func TestConfigResultConfigBool(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("test.bool", true)
+	k.Set("test.string", "true")
+	k.Set("test.false", false)
+	sut := Settings{rawConfig: k}
+
+	tests := map[string]bool{
+		"test.bool":    true,
+		"test.string":  true,
+		"test.false":   false,
+		"test.missing": true,
+	}
+
+	for key, expected := range tests {
+		actual := sut.ConfigBool(key, true)
+		if actual != expected {
+			t.Errorf("incorrect response to raw config bool lookup of %s, expected %v, found %v", key, expected, actual)
+		}
+	}
+}
+
+func TestConfigResultConfigDuration(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("test.string", "90s")
+	k.Set("test.number", int64(time.Second))
+	sut := Settings{rawConfig: k}
+
+	tests := map[string]time.Duration{
+		"test.string":  90 * time.Second,
+		"test.number":  time.Second,
+		"test.missing": time.Minute,
+	}
+
+	for key, expected := range tests {
+		actual := sut.ConfigDuration(key, time.Minute)
+		if actual != expected {
+			t.Errorf("incorrect response to raw config duration lookup of %s, expected %v, found %v", key, expected, actual)
+		}
+	}
+}
+
 func TestConfigResultWriteJsonConfig(t *testing.T) {
 	k := koanf.New(".")
 	k.Set("test.property.one", "value")
 	sut := Settings{rawConfig: k}
 
 	path := filepath.Join(t.TempDir(), "test.json")
-	err := sut.WriteJsonConfig(path, "", false)
+	err := sut.WriteJsonConfig(path, "", "", false)
 	if err != nil {
 		t.Errorf("error writing config to %s, %v", path, err)
 		return
@@ -76,7 +139,7 @@ func TestConfigResultWriteYamlConfig(t *testing.T) {
 	sut := Settings{rawConfig: k}
 
 	path := filepath.Join(t.TempDir(), "test.yaml")
-	err := sut.WriteYamlConfig(path)
+	err := sut.WriteYamlConfig(path, "")
 	if err != nil {
 		t.Errorf("error writing config to %s, %v", path, err)
 		return
@@ -99,6 +162,118 @@ test:
 	}
 }
 
+func TestConfigResultWriteYamlConfigSubtree(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("auth.users", []string{"user1"})
+	k.Set("auth.service_account.name", "quartz")
+	k.Set("stages.first.id", "first")
+	s := koanf.New(".")
+	s.Set("github.token", "supersecrettoken")
+	sut := Settings{rawConfig: k, rawSecrets: s}
+
+	path := filepath.Join(t.TempDir(), "test.yaml")
+	err := sut.WriteYamlConfig(path, "auth")
+	if err != nil {
+		t.Errorf("error writing config to %s, %v", path, err)
+		return
+	}
+
+	actual, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("error reading config from %s, %v", path, err)
+		return
+	}
+
+	expected := strings.TrimSpace(`
+auth:
+    service_account:
+        name: quartz
+    users:
+        - user1
+`)
+
+	if expected != strings.TrimSpace(string(actual)) {
+		t.Errorf("incorrect yaml response, expected %s, found %s", expected, actual)
+	}
+
+	if strings.Contains(string(actual), "stages") ||
+		strings.Contains(string(actual), "supersecrettoken") {
+		t.Errorf("unexpected section in yaml subtree response, %s", actual)
+	}
+}
+
+func TestConfigResultWriteJsonConfigSubtree(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("auth.service_account.name", "quartz")
+	k.Set("stages.first.id", "first")
+	sut := Settings{rawConfig: k}
+
+	path := filepath.Join(t.TempDir(), "test.json")
+	err := sut.WriteJsonConfig(path, "settings", "stages.first", false)
+	if err != nil {
+		t.Errorf("error writing config to %s, %v", path, err)
+		return
+	}
+
+	actual, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("error reading config from %s, %v", path, err)
+		return
+	}
+
+	expected := `{"settings":{"stages":{"first":{"id":"first"}}}}`
+
+	if expected != strings.TrimSpace(string(actual)) {
+		t.Errorf("incorrect json response, expected %s, found %s", expected, actual)
+	}
+}
+
+func TestConfigResultWriteConfigSubtreeNotFound(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("auth.service_account.name", "quartz")
+	sut := Settings{rawConfig: k}
+
+	path := filepath.Join(t.TempDir(), "test.yaml")
+	err := sut.WriteYamlConfig(path, "nope")
+	if err == nil {
+		t.Error("expected error writing missing config subtree")
+	}
+
+	err = sut.WriteJsonConfig(path, "", "auth.nope", false)
+	if err == nil {
+		t.Error("expected error writing missing config subtree")
+	}
+}
+
+func TestConfigResultMarshalYamlConfig(t *testing.T) {
+	k := koanf.New(".")
+	k.Set("auth.service_account.name", "quartz")
+	sut := Settings{rawConfig: k}
+
+	path := filepath.Join(t.TempDir(), "test.yaml")
+	err := sut.WriteYamlConfig(path, "")
+	if err != nil {
+		t.Errorf("error writing config to %s, %v", path, err)
+		return
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("error reading config from %s, %v", path, err)
+		return
+	}
+
+	actual, err := sut.MarshalYamlConfig("")
+	if err != nil {
+		t.Errorf("error marshalling config, %v", err)
+		return
+	}
+
+	if string(actual) != string(written) {
+		t.Errorf("marshalled config does not match written config, expected %s, found %s", written, actual)
+	}
+}
+
 func TestConfigResultMarshalJsonRoot(t *testing.T) {
 	k := koanf.New(".")
 	k.Set("test.property.one", "value")
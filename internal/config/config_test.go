@@ -20,7 +20,12 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/knadh/koanf/v2"
 )
 
 func TestConfigLoadRawConfig(t *testing.T) {
@@ -69,6 +74,550 @@ tmp: %s
 	}
 }
 
+func TestConfigLoadRawConfigExpandEnv(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: ${QUARTZ_TEST_NAME}
+dns:
+  zone: ${QUARTZ_TEST_ZONE:-example.com}
+providers:
+  cloud: local
+description: "prefix-${QUARTZ_TEST_MISSING}-suffix"
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	t.Setenv("QUARTZ_TEST_NAME", "expanded")
+
+	actual, err := LoadRawConfig(context.Background(), cfgFile)
+	if err != nil {
+		t.Errorf("failed loading raw config, %v", err)
+		return
+	}
+
+	expected := map[string]interface{}{
+		// plain expansion
+		"name": "expanded",
+
+		// default fallback
+		"dns.zone": "example.com",
+
+		// missing var
+		"description": "prefix--suffix",
+
+		// derived from expanded values
+		"dns.domain": "expanded.example.com",
+	}
+	for k, v := range expected {
+		a := actual.Get(k)
+		if v != a {
+			t.Errorf("mismatched value found for %s, expected %v, found %v", k, v, a)
+		}
+	}
+}
+
+func TestConfigLoadRawConfigOverrides(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+project: fileproject
+description: filedescription
+dns:
+  zone: example.com
+providers:
+  cloud: local
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	varContent := []byte(`
+description: vardescription
+chart:
+  repository: varrepo
+`)
+	varFile := filepath.Join(tmp, "test-vars.yaml")
+	os.WriteFile(varFile, varContent, 0664)
+
+	t.Setenv("QUARTZ_project", "envproject")
+
+	actual, err := LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{
+		Values:   []string{"project=setproject", "dns.domain=foo.example.com", "chart.repository=setrepo"},
+		VarFiles: []string{varFile},
+	})
+	if err != nil {
+		t.Errorf("failed loading raw config, %v", err)
+		return
+	}
+
+	expected := map[string]interface{}{
+		// --set beats file and env
+		"project": "setproject",
+
+		// --set beats derived values
+		"dns.domain": "foo.example.com",
+
+		// --var-file beats file
+		"description": "vardescription",
+
+		// --set beats --var-file
+		"chart.repository": "setrepo",
+	}
+	for k, v := range expected {
+		a := actual.Get(k)
+		if v != a {
+			t.Errorf("mismatched value found for %s, expected %v, found %v", k, v, a)
+		}
+	}
+}
+
+func TestConfigLoadRawConfigGiteaRepoUrl(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+  source_control: gitea
+gitea:
+  url: https://gitea.example.com/
+  organization: myorg
+gitops:
+  core:
+    repo: infra
+applications:
+  myapp-api: {}
+  othergh-api:
+    provider: github
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	actual, err := LoadRawConfig(context.Background(), cfgFile)
+	if err != nil {
+		t.Errorf("failed loading raw config, %v", err)
+		return
+	}
+
+	expected := map[string]interface{}{
+		"gitops.core.provider":              "gitea",
+		"gitops.core.organization":          "myorg",
+		"gitops.core.repo_url":              "https://gitea.example.com/myorg/infra",
+		"applications.myapp-api.repo_url":   "https://gitea.example.com/myorg/myapp-api",
+		"applications.othergh-api.repo_url": "https://github.com/myorg/othergh-api",
+	}
+	for k, v := range expected {
+		a := actual.Get(k)
+		if v != a {
+			t.Errorf("mismatched value found for %s, expected %v, found %v", k, v, a)
+		}
+	}
+}
+
+func TestConfigLoadStateEncryption(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    string
+		expected string
+		err      string
+	}{
+		{name: "default", state: "enabled: true", expected: "AES256"},
+		{name: "kms key", state: "kms_key_arn: arn:aws:kms:us-east-1:123456789:key/test", expected: "aws:kms"},
+		{name: "explicit kms", state: "encryption: AWS:KMS", expected: "aws:kms"},
+		{name: "aes with kms key", state: "encryption: AES256\n  kms_key_arn: arn:aws:kms:us-east-1:123456789:key/test", err: "state.kms_key_arn is only supported with aws:kms"},
+		{name: "unsupported", state: "encryption: des", err: "unsupported state.encryption des"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+state:
+  %s
+tmp: %s
+`, tt.state, tmp))
+			cfgFile := filepath.Join(tmp, "test-config.yaml")
+			os.WriteFile(cfgFile, cfgContent, 0664)
+
+			actual, err := Load(context.Background(), cfgFile, "")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expected error %q, found %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error loading config, %v", err)
+			}
+
+			if actual.Config.State.Encryption != tt.expected {
+				t.Errorf("incorrect state encryption, expected %s, found %s", tt.expected, actual.Config.State.Encryption)
+			}
+		})
+	}
+}
+
+func TestConfigLoadLookupCredentialsConfigMap(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+core:
+  applications:
+    myapp:
+      lookup:
+        admin_credentials:
+          secret:
+            name: myapp-admin
+          configmap:
+            name: myapp-config
+            username_key: admin-user
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	actual, err := Load(context.Background(), cfgFile, "")
+	if err != nil {
+		t.Fatalf("unexpected error loading config with lookup credentials, %v", err)
+	}
+
+	cm := actual.Config.Core.Applications["myapp"].Lookup.AdminCredentials.ConfigMap
+	if cm.Name != "myapp-config" || cm.UsernameKey != "admin-user" {
+		t.Errorf("incorrect lookup credentials configmap loaded, found %+v", cm)
+	}
+}
+
+func TestConfigLoadRawConfigCleanup(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+cleanup:
+  stuck_namespaces:
+  - longhorn-system
+  - kyverno
+  stale_api_services:
+  - v1beta1.custom.metrics.example.com
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	actual, err := Load(context.Background(), cfgFile, "")
+	if err != nil {
+		t.Fatalf("unexpected error loading config with cleanup settings, %v", err)
+	}
+
+	cfg := actual.Config
+	ns := cfg.Cleanup.AllStuckNamespaces()
+	expectedNs := append(slices.Clone(schema.DefaultStuckNamespaces), "longhorn-system")
+	if !slices.Equal(ns, expectedNs) {
+		t.Errorf("unexpected cleanup stuck namespaces, expected %v, found %v", expectedNs, ns)
+	}
+
+	svcs := cfg.Cleanup.AllStaleApiServices()
+	expectedSvcs := append(slices.Clone(schema.DefaultStaleApiServices), "v1beta1.custom.metrics.example.com")
+	if !slices.Equal(svcs, expectedSvcs) {
+		t.Errorf("unexpected cleanup stale api services, expected %v, found %v", expectedSvcs, svcs)
+	}
+
+	if len(cfg.Cleanup.Webhooks) == 0 {
+		t.Error("expected default cleanup webhooks")
+	}
+}
+
+func TestConfigLoadRawConfigAdministrators(t *testing.T) {
+	tests := []struct {
+		name    string
+		admins  string
+		wantErr bool
+	}{
+		{"user", "[quartzadmin]", false},
+		{"group", "[administrators, ops]", false},
+		{"iam principal", "[\"arn:aws:iam::123456789012:role/admin\"]", false},
+		{"unknown", "[quartzadmin, quartzadmn]", true},
+		{"disabled group", "[legacy]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+auth:
+  groups:
+    ops: {}
+    legacy:
+      disabled: true
+administrators: %s
+tmp: %s
+`, tt.admins, tmp))
+			cfgFile := filepath.Join(tmp, "test-config.yaml")
+			os.WriteFile(cfgFile, cfgContent, 0664)
+
+			_, err := LoadRawConfig(context.Background(), cfgFile)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "administrators") {
+					t.Errorf("expected unknown administrators error, found %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error loading config with administrators %s, %v", tt.admins, err)
+			}
+		})
+	}
+}
+
+func TestConfigLoadRawConfigDestroyFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		include string
+		exclude string
+		wantErr string
+	}{
+		{"valid", `["random_integer.include", "module.mod.*"]`, `["^aws_s3_bucket\\..*$"]`, ""},
+		{"invalid include", `["module.mod[0"]`, "[]", `stage custom destroy.include pattern "module.mod[0" is invalid`},
+		{"invalid exclude", "[]", `["(aws_instance"]`, `stage custom destroy.exclude pattern "(aws_instance" is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+stages:
+  custom:
+    path: %s
+    destroy:
+      include: %s
+      exclude: %s
+tmp: %s
+`, tmp, tt.include, tt.exclude, tmp))
+			cfgFile := filepath.Join(tmp, "test-config.yaml")
+			os.WriteFile(cfgFile, cfgContent, 0664)
+
+			_, err := LoadRawConfig(context.Background(), cfgFile)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error loading config with destroy filters, %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected %s, found %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigSetDnsDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		cluster    string
+		zone       string
+		domain     string
+		wantZone   string
+		wantDomain string
+		wantErr    string
+	}{
+		{name: "zone", cluster: "mytest", zone: "example.com", wantZone: "example.com", wantDomain: "mytest.example.com"},
+		{name: "zone trailing dot", cluster: "mytest", zone: "example.com.", wantZone: "example.com", wantDomain: "mytest.example.com"},
+		{name: "domain", domain: "mytest.example.com", wantZone: "example.com", wantDomain: "mytest.example.com"},
+		{name: "domain trailing dot", domain: "MyTest.Example.com.", wantZone: "Example.com", wantDomain: "MyTest.Example.com"},
+		{name: "neither", wantErr: "at least one of dns.zone or dns.domain must be specified"},
+		{name: "ipv4 domain", domain: "10.0.0.1", wantErr: `dns.domain "10.0.0.1" is invalid, an IP address is not a DNS name`},
+		{name: "ipv6 zone", cluster: "mytest", zone: "fd00::1", wantErr: `dns.zone "fd00::1" is invalid, an IP address is not a DNS name`},
+		{name: "single label domain", domain: "localhost", wantErr: "expected <name>.<zone>"},
+		{name: "empty label", domain: "mytest..example.com", wantErr: "empty label"},
+		{name: "invalid character", cluster: "mytest", zone: "exa_mple.com", wantErr: `label "exa_mple" contains invalid character '_'`},
+		{name: "leading hyphen", domain: "-mytest.example.com", wantErr: "starts or ends with a hyphen"},
+		{name: "long label", cluster: "mytest", zone: strings.Repeat("a", 64) + ".com", wantErr: "longer than 63 characters"},
+		{name: "long name", domain: strings.Repeat(strings.Repeat("a", 60)+".", 5) + "com", wantErr: "longer than 253 characters"},
+		{name: "invalid cluster name", cluster: "my test", zone: "example.com", wantErr: `dns.domain "my test.example.com" (name and dns.zone) is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := koanf.New(".")
+			k.Set("name", tt.cluster)
+			k.Set("dns.zone", tt.zone)
+			k.Set("dns.domain", tt.domain)
+
+			err := setDnsDefaults(k)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected %s, found %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error setting dns defaults, %v", err)
+			}
+
+			if z := k.String("dns.zone"); z != tt.wantZone {
+				t.Errorf("mismatched dns.zone, expected %s, found %s", tt.wantZone, z)
+			}
+
+			if d := k.String("dns.domain"); d != tt.wantDomain {
+				t.Errorf("mismatched dns.domain, expected %s, found %s", tt.wantDomain, d)
+			}
+		})
+	}
+}
+
+func TestConfigLoadRawConfigOverridesInvalid(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	_, err := LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{Values: []string{"project"}})
+	if err == nil {
+		t.Error("expected error for override without a value")
+	}
+
+	_, err = LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{VarFiles: []string{filepath.Join(tmp, "missing.yaml")}})
+	if err == nil {
+		t.Error("expected error for missing var file")
+	}
+}
+
+func TestConfigLoadRawConfigEnvironmentOverlay(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+description: filedescription
+dns:
+  zone: example.com
+providers:
+  cloud: local
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "quartz.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	overlayContent := []byte(`
+description: devdescription
+chart:
+  repository: devrepo
+`)
+	os.WriteFile(filepath.Join(tmp, "quartz.dev.yaml"), overlayContent, 0664)
+
+	actual, err := LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{
+		Values: []string{"chart.repository=setrepo"},
+		Env:    "dev",
+	})
+	if err != nil {
+		t.Fatalf("failed loading raw config, %v", err)
+	}
+
+	expected := map[string]interface{}{
+		// overlay beats file
+		"description": "devdescription",
+
+		// --set beats overlay
+		"chart.repository": "setrepo",
+
+		"active_environment": "dev",
+		"name":               "mytest",
+	}
+	for k, v := range expected {
+		a := actual.Get(k)
+		if v != a {
+			t.Errorf("mismatched value found for %s, expected %v, found %v", k, v, a)
+		}
+	}
+}
+
+func TestConfigLoadRawConfigEnvironmentOverlayInvalid(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+tmp: %s
+`, tmp))
+	cfgFile := filepath.Join(tmp, "quartz.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	_, err := LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{Env: "qa"})
+	if err == nil || !strings.Contains(err.Error(), `unknown environment "qa"`) {
+		t.Errorf("expected unknown environment error, found %v", err)
+	}
+
+	_, err = LoadRawConfigWithOverrides(context.Background(), cfgFile, Overrides{Env: "stage"})
+	if err == nil || !strings.Contains(err.Error(), "quartz.stage.yaml") {
+		t.Errorf("expected missing overlay error, found %v", err)
+	}
+}
+
+func TestConfigEnvironmentOverlayPath(t *testing.T) {
+	actual := environmentOverlayPath(filepath.Join("conf", "quartz.yaml"), "dev")
+	expected := filepath.Join("conf", "quartz.dev.yaml")
+	if actual != expected {
+		t.Errorf("mismatched overlay path, expected %s, found %s", expected, actual)
+	}
+}
+
+func TestConfigExpandEnvString(t *testing.T) {
+	t.Setenv("QUARTZ_TEST_VAR", "value")
+	t.Setenv("QUARTZ_TEST_EMPTY", "")
+
+	tests := map[string]string{
+		"${QUARTZ_TEST_VAR}":                    "value",
+		"a-${QUARTZ_TEST_VAR}-b":                "a-value-b",
+		"${QUARTZ_TEST_VAR:-other}":             "value",
+		"${QUARTZ_TEST_MISSING:-fallback}":      "fallback",
+		"${QUARTZ_TEST_EMPTY:-fallback}":        "fallback",
+		"${QUARTZ_TEST_MISSING}":                "",
+		"$QUARTZ_TEST_VAR":                      "$QUARTZ_TEST_VAR",
+		"no references":                         "no references",
+		"${QUARTZ_TEST_VAR}/${QUARTZ_TEST_VAR}": "value/value",
+	}
+	for in, expected := range tests {
+		actual := expandEnvString(in)
+		if actual != expected {
+			t.Errorf("mismatched expansion for %s, expected %s, found %s", in, expected, actual)
+		}
+	}
+}
+
 func TestConfigLoadRawSecrets(t *testing.T) {
 	tmp := t.TempDir()
 	cfgContent := []byte(`
@@ -106,6 +655,29 @@ github:
 	}
 }
 
+func TestConfigLoadRawSecretsNotExpanded(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(`
+github:
+  token: pa$${QUARTZ_TEST_VAR}word
+`)
+	cfgFile := filepath.Join(tmp, "test-secrets.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	t.Setenv("QUARTZ_TEST_VAR", "value")
+
+	actual, err := LoadRawSecrets(context.Background(), cfgFile)
+	if err != nil {
+		t.Errorf("failed loading raw secrets, %v", err)
+		return
+	}
+
+	expected := "pa$${QUARTZ_TEST_VAR}word"
+	if a := actual.String("github.token"); a != expected {
+		t.Errorf("mismatched value found for github.token, expected %v, found %v", expected, a)
+	}
+}
+
 func TestConfigLoad(t *testing.T) {
 	tmp := t.TempDir()
 	cfgContent := []byte(fmt.Sprintf(`
@@ -144,7 +716,9 @@ auth:
 		actual.Config.Providers.Cloud != "local" ||
 		actual.Config.Tmp != tmp ||
 		actual.Config.Project != "testproject" ||
-		actual.Config.Terraform.Version != "1.5.7" {
+		actual.Config.Terraform.Version != "1.5.7" ||
+		!actual.Config.Terraform.PluginCache.Enabled ||
+		actual.Config.TfPluginCachePath() != filepath.Join(tmp, "terraform-plugin-cache") {
 		t.Errorf("mismatched config value found, expected %s, found %v", cfgContent, actual.Config)
 	}
 
@@ -245,6 +819,63 @@ stages:
 	}
 }
 
+func TestConfigStagesOrderedMatrix(t *testing.T) {
+	tmp := t.TempDir()
+	cfgContent := []byte(fmt.Sprintf(`
+name: mytest
+dns:
+  zone: example.com
+providers:
+  cloud: local
+tmp: %s
+stages:
+  base:
+    id: base
+    order: 1
+  regional:
+    id: regional
+    order: 10
+    matrix_var: region
+    matrix:
+      west: us-west-2
+      east: us-east-1
+  last:
+    id: last
+    order: 20
+    dependencies:
+    - regional
+`, tmp))
+	cfgFile := filepath.Join(tmp, "test-config.yaml")
+	os.WriteFile(cfgFile, cfgContent, 0664)
+
+	conf, err := Load(context.Background(), cfgFile, "")
+	if err != nil {
+		t.Fatalf("failed loading config, %v", err)
+	}
+
+	if _, found := conf.Config.Stages["regional"]; found {
+		t.Errorf("expected matrix stage to be replaced by its expansions")
+	}
+
+	actual := conf.Config.StagesOrdered()
+	if len(actual) != 4 ||
+		actual[0].Id != "base" ||
+		actual[1].Id != "regional-east" ||
+		actual[2].Id != "regional-west" ||
+		actual[3].Id != "last" {
+		t.Fatalf("incorrect stage ordering, found %v", actual)
+	}
+
+	if actual[1].Vars["region"].Value != "us-east-1" ||
+		actual[2].Vars["region"].Value != "us-west-2" {
+		t.Errorf("incorrect matrix vars, found %v and %v", actual[1].Vars, actual[2].Vars)
+	}
+
+	if !slices.Equal(actual[3].Dependencies, []string{"regional-east", "regional-west"}) {
+		t.Errorf("incorrect dependencies on matrix stage, found %v", actual[3].Dependencies)
+	}
+}
+
 func TestConfigKubeconfigPathDefault(t *testing.T) {
 	tmp := t.TempDir()
 	cfgContent := []byte(fmt.Sprintf(`
@@ -0,0 +1,152 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+)
+
+// ProjectTemplate holds the inputs for scaffolding a new quartz project.
+type ProjectTemplate struct {
+	Name          string // The cluster name.
+	Zone          string // The dns zone, the domain defaults to name.zone.
+	Domain        string // The full dns domain, optional when the zone is set.
+	Cloud         string // The cloud provider, aws or local.
+	Region        string // The aws region, required for the aws cloud provider.
+	SourceControl string // The source control provider, github or gitea.
+	Organization  string // The source control organization owning the gitops repositories.
+}
+
+// projectCloudProviders and projectSourceControlProviders are the providers the scaffold supports.
+var (
+	projectCloudProviders         = []string{"aws", "local"}
+	projectSourceControlProviders = []string{"github", "gitea"}
+)
+
+// projectDirs are the convention directories created alongside quartz.yaml.
+var projectDirs = []string{
+	filepath.Join("terraform", "stages"),
+	"base",
+}
+
+// Validate checks the template has the values required for a loadable config.
+func (t ProjectTemplate) Validate() error {
+	var errs []error
+
+	if t.Name == "" {
+		errs = append(errs, fmt.Errorf("name required"))
+	}
+
+	if t.Zone == "" && t.Domain == "" {
+		errs = append(errs, fmt.Errorf("at least one of dns.zone or dns.domain must be specified"))
+	}
+
+	if t.Domain != "" && !strings.Contains(t.Domain, ".") {
+		errs = append(errs, fmt.Errorf("invalid dns.domain %s", t.Domain))
+	}
+
+	if !slices.Contains(projectCloudProviders, t.Cloud) {
+		errs = append(errs, fmt.Errorf("unsupported cloud provider %s, expected one of %s", t.Cloud, strings.Join(projectCloudProviders, ", ")))
+	}
+
+	if t.Cloud == "aws" && t.Region == "" {
+		errs = append(errs, fmt.Errorf("aws.region required"))
+	}
+
+	if !slices.Contains(projectSourceControlProviders, t.SourceControl) {
+		errs = append(errs, fmt.Errorf("unsupported source control provider %s, expected one of %s", t.SourceControl, strings.Join(projectSourceControlProviders, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Render returns a minimal, commented quartz.yaml for the template, values are double quoted
+// so input like a "#" or ": " can't change the document structure.
+func (t ProjectTemplate) Render() []byte {
+	var b strings.Builder
+
+	b.WriteString("# Quartz configuration, see the README for the full set of options.\n")
+	fmt.Fprintf(&b, "name: %q\n\n", t.Name)
+
+	b.WriteString("providers:\n")
+	fmt.Fprintf(&b, "  cloud: %q\n", t.Cloud)
+	fmt.Fprintf(&b, "  source_control: %q\n\n", t.SourceControl)
+
+	if t.Cloud == "aws" {
+		b.WriteString("aws:\n")
+		fmt.Fprintf(&b, "  region: %q\n\n", t.Region)
+	}
+
+	b.WriteString("dns:\n")
+	if t.Zone != "" {
+		fmt.Fprintf(&b, "  zone: %q\n", t.Zone)
+	}
+	if t.Domain != "" {
+		fmt.Fprintf(&b, "  domain: %q\n", t.Domain)
+	}
+	b.WriteString("\n")
+
+	if t.Organization != "" {
+		b.WriteString("# organization owning the gitops repositories\n")
+		fmt.Fprintf(&b, "%s:\n", t.SourceControl)
+		fmt.Fprintf(&b, "  organization: %q\n\n", t.Organization)
+	}
+
+	b.WriteString("# terraform stages are discovered from terraform/stages and the helm chart is read\n")
+	b.WriteString("# from base, relative to the directory quartz runs in\n")
+
+	return []byte(b.String())
+}
+
+// WriteProjectScaffold writes quartz.yaml for the template into dir, along with the
+// terraform/stages and base convention directories.
+// Returns an error if quartz.yaml already exists, unless force is set.
+func WriteProjectScaffold(dir string, t ProjectTemplate, force bool) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "quartz.yaml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists, use --force to overwrite", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for _, d := range projectDirs {
+		p := filepath.Join(dir, d)
+		if err := os.MkdirAll(p, 0750); err != nil {
+			return err
+		}
+
+		// keep the empty directory in source control
+		keep := filepath.Join(p, ".gitkeep")
+		if _, err := os.Stat(keep); errors.Is(err, os.ErrNotExist) {
+			if err := os.WriteFile(keep, nil, 0640); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Info("Writing project config", "path", path)
+	return os.WriteFile(path, t.Render(), 0640)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteProjectScaffold(t *testing.T) {
+	tests := map[string]ProjectTemplate{
+		"local": {
+			Name:          "mytest",
+			Zone:          "example.com",
+			Cloud:         "local",
+			SourceControl: "github",
+			Organization:  "myorg",
+		},
+		"aws": {
+			Name:          "mytest",
+			Domain:        "mytest.example.com",
+			Cloud:         "aws",
+			Region:        "us-east-1",
+			SourceControl: "gitea",
+			Organization:  "myorg",
+		},
+	}
+
+	for name, tmpl := range tests {
+		t.Run(name, func(t *testing.T) {
+			// the aws provider checks the sdk's region, resolved from the environment
+			t.Setenv("AWS_REGION", "us-east-1")
+			dir := t.TempDir()
+
+			err := WriteProjectScaffold(dir, tmpl, false)
+			if err != nil {
+				t.Fatalf("unexpected error writing project scaffold, %v", err)
+			}
+
+			for _, d := range projectDirs {
+				if _, err := os.Stat(filepath.Join(dir, d)); err != nil {
+					t.Errorf("expected directory %s, %v", d, err)
+				}
+			}
+
+			s, err := Load(context.Background(), filepath.Join(dir, "quartz.yaml"), "")
+			if err != nil {
+				t.Fatalf("unexpected error loading generated config, %v", err)
+			}
+
+			if s.Config.Name != "mytest" || s.Config.Dns.Domain != "mytest.example.com" || s.Config.Dns.Zone != "example.com" {
+				t.Errorf("incorrect config loaded, found name %s, dns %+v", s.Config.Name, s.Config.Dns)
+			}
+
+			if s.Config.Providers.Cloud != tmpl.Cloud || s.Config.Providers.SourceControl != tmpl.SourceControl {
+				t.Errorf("incorrect providers loaded, found %+v", s.Config.Providers)
+			}
+
+			if s.Config.Gitops.Core.Organization != "myorg" {
+				t.Errorf("incorrect gitops organization, expected myorg, found %s", s.Config.Gitops.Core.Organization)
+			}
+		})
+	}
+}
+
+func TestProjectTemplateRenderQuoted(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	dir := t.TempDir()
+
+	tmpl := ProjectTemplate{Name: "mytest", Zone: "example.com", Cloud: "local", SourceControl: "github", Organization: "my: org\" #2"}
+	if err := WriteProjectScaffold(dir, tmpl, false); err != nil {
+		t.Fatalf("unexpected error writing project scaffold, %v", err)
+	}
+
+	s, err := Load(context.Background(), filepath.Join(dir, "quartz.yaml"), "")
+	if err != nil {
+		t.Fatalf("unexpected error loading generated config, %v", err)
+	}
+
+	if !strings.Contains(string(tmpl.Render()), `name: "mytest"`) {
+		t.Errorf("expected quoted name in rendered config, found %s", tmpl.Render())
+	}
+
+	if s.Config.Gitops.Core.Organization != tmpl.Organization {
+		t.Errorf("incorrect gitops organization, expected %s, found %s", tmpl.Organization, s.Config.Gitops.Core.Organization)
+	}
+}
+
+func TestWriteProjectScaffoldExists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quartz.yaml")
+	if err := os.WriteFile(path, []byte("existing"), 0600); err != nil {
+		t.Fatalf("unexpected error writing existing file, %v", err)
+	}
+
+	tmpl := ProjectTemplate{Name: "mytest", Zone: "example.com", Cloud: "local", SourceControl: "github"}
+	err := WriteProjectScaffold(dir, tmpl, false)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, found %v", err)
+	}
+
+	err = WriteProjectScaffold(dir, tmpl, true)
+	if err != nil {
+		t.Errorf("unexpected error overwriting project scaffold, %v", err)
+	}
+}
+
+func TestProjectTemplateValidate(t *testing.T) {
+	err := ProjectTemplate{Cloud: "aws", SourceControl: "bitbucket"}.Validate()
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	for _, expected := range []string{"name required", "dns.zone or dns.domain", "aws.region required", "unsupported source control provider bitbucket"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in validation error, found %v", expected, err)
+		}
+	}
+}
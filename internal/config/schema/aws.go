@@ -16,5 +16,18 @@ package schema
 
 // AwsConfig represents the configuration for AWS in Quartz.
 type AwsConfig struct {
-	Region string `koanf:"region"` // The AWS region to use.
+	Region               string            `koanf:"region"`                 // The AWS region to use.
+	SkipRegionValidation bool              `koanf:"skip_region_validation"` // Skip warning about regions unknown to the SDK, e.g. for custom endpoints.
+	Tags                 map[string]string `koanf:"tags"`                   // Tags applied to the AWS resources quartz creates, e.g. the state bucket and lock table.
+	EndpointUrl          string            `koanf:"endpoint_url"`           // Custom endpoint for all AWS SDK clients, e.g. LocalStack. Unset uses the real AWS endpoints.
+	S3UsePathStyle       bool              `koanf:"s3_use_path_style"`      // Address S3 buckets by path rather than virtual host, typically required with endpoint_url.
+
+	EksReadyTimeoutSeconds int `koanf:"eks_ready_timeout_seconds"` // How long to wait for the EKS cluster to be ACTIVE or UPDATING with its endpoint and CA, 0 doesn't wait.
+}
+
+// NewAwsConfig returns a new AwsConfig instance with default values.
+func NewAwsConfig() AwsConfig {
+	return AwsConfig{
+		EksReadyTimeoutSeconds: 300,
+	}
 }
@@ -0,0 +1,83 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import "slices"
+
+// DefaultStuckNamespaces are the namespaces whose finalizers are removed when stuck terminating
+// during cleanup, in addition to cleanup.stuck_namespaces.
+var DefaultStuckNamespaces = []string{
+	"flux-system",
+	"kyverno",
+	"monitoring",
+	"istio-system",
+	"cert-manager",
+}
+
+// DefaultStaleApiServices are the API services removed during cleanup, in addition to
+// cleanup.stale_api_services. These often block namespace finalization once their backing pods are gone.
+var DefaultStaleApiServices = []string{
+	"v1beta1.metrics.k8s.io",
+	"v1beta1.external.metrics.k8s.io",
+	"v1beta1.custom.metrics.k8s.io",
+	"v1.external.metrics.k8s.io",
+}
+
+// CleanupConfig controls which cluster resources the clean command removes ahead of the
+// terraform destroy to unblock Helm uninstalls.
+type CleanupConfig struct {
+	Webhooks    []string `koanf:"webhooks"`     // Name patterns (path.Match syntax) of the webhook configurations to remove.
+	AllWebhooks bool     `koanf:"all_webhooks"` // Remove every validating and mutating webhook configuration, ignoring webhooks.
+
+	StuckNamespaces  []string `koanf:"stuck_namespaces"`   // Additional namespaces to remove finalizers from when stuck terminating.
+	StaleApiServices []string `koanf:"stale_api_services"` // Additional API services to remove.
+}
+
+// NewCleanupConfig returns a new CleanupConfig instance with default values,
+// matching the webhooks of the quartz-managed charts known to block uninstalls.
+func NewCleanupConfig() CleanupConfig {
+	return CleanupConfig{
+		Webhooks: []string{
+			"quartz-*",
+			"kyverno-*",
+			"istio-*",
+			"istiod-*",
+			"cert-manager-*",
+			"aws-load-balancer-*",
+		},
+	}
+}
+
+// AllStuckNamespaces returns the default stuck namespaces followed by any configured ones.
+func (c CleanupConfig) AllStuckNamespaces() []string {
+	return appendMissing(DefaultStuckNamespaces, c.StuckNamespaces)
+}
+
+// AllStaleApiServices returns the default stale API services followed by any configured ones.
+func (c CleanupConfig) AllStaleApiServices() []string {
+	return appendMissing(DefaultStaleApiServices, c.StaleApiServices)
+}
+
+// appendMissing returns a copy of base with each entry of extra not already present appended.
+func appendMissing(base []string, extra []string) []string {
+	res := slices.Clone(base)
+	for _, e := range extra {
+		if e != "" && !slices.Contains(res, e) {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
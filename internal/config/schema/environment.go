@@ -72,15 +72,32 @@ type ApplicationLookupConfig struct {
 }
 
 type ApplicationLookupCredentialsConfig struct {
-	Username string                                   `koanf:"username"`
-	Secret   ApplicationLookupCredentialsSecretConfig `koanf:"secret"`
+	Username  string                                      `koanf:"username"`
+	Secret    ApplicationLookupCredentialsSecretConfig    `koanf:"secret"`
+	ConfigMap ApplicationLookupCredentialsConfigMapConfig `koanf:"configmap"` // optional username source, the password stays in the secret
 }
 
 type ApplicationLookupCredentialsSecretConfig struct {
+	Name           string                        `koanf:"name"`
+	Namespace      string                        `koanf:"namespace"`
+	UsernameKey    string                        `koanf:"username_key"`
+	PasswordKey    string                        `koanf:"password_key"`
+	UsernameDecode ApplicationLookupDecodeConfig `koanf:"username_decode"`
+	PasswordDecode ApplicationLookupDecodeConfig `koanf:"password_decode"`
+}
+
+// ApplicationLookupDecodeConfig describes how a credential is extracted from the raw secret value.
+type ApplicationLookupDecodeConfig struct {
+	Type string `koanf:"type"` // none (default), base64 or jsonpath
+	Path string `koanf:"path"` // jsonpath expression for the jsonpath type, e.g. .data.admin.password
+}
+
+// ApplicationLookupCredentialsConfigMapConfig reads the admin username from a ConfigMap,
+// the namespace defaults to the secret's and the key to "username".
+type ApplicationLookupCredentialsConfigMapConfig struct {
 	Name        string `koanf:"name"`
 	Namespace   string `koanf:"namespace"`
 	UsernameKey string `koanf:"username_key"`
-	PasswordKey string `koanf:"password_key"`
 }
 
 type ApplicationLookupIngressConfig struct {
@@ -14,11 +14,21 @@
 
 package schema
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultExportPathTemplate is the default layout of exported files under the export path.
+const DefaultExportPathTemplate = "{domain}/{namespace}.{name}.yaml"
+
 // ExportConfig represents the configuration for exporting resources in Quartz.
 type ExportConfig struct {
-	Path        string               `koanf:"path"`
-	Annotations map[string]string    `koanf:"annotations"`
-	Objects     []ExportObjectConfig `koanf:"objects"`
+	Path         string               `koanf:"path"`
+	PathTemplate string               `koanf:"path_template"` // file layout under path, tokens {domain}, {namespace}, {name}, {kind} (lowercased)
+	Annotations  map[string]string    `koanf:"annotations"`
+	Objects      []ExportObjectConfig `koanf:"objects"`
 }
 
 // ExportObjectConfig represents the configuration for an individual object to export.
@@ -31,8 +41,33 @@ type ExportObjectConfig struct {
 // NewExportConfig returns a new ExportConfig instance with default values.
 func NewExportConfig() ExportConfig {
 	return ExportConfig{
-		Path:        "./backup",
-		Annotations: map[string]string{},
-		Objects:     []ExportObjectConfig{},
+		Path:         "./backup",
+		PathTemplate: DefaultExportPathTemplate,
+		Annotations:  map[string]string{},
+		Objects:      []ExportObjectConfig{},
+	}
+}
+
+// ObjectPath returns the file an exported object is written to, the path template
+// rendered for the object and domain under the export path.
+func (c ExportConfig) ObjectPath(domain string, o ExportObjectConfig) string {
+	tmpl := c.PathTemplate
+	if tmpl == "" {
+		tmpl = DefaultExportPathTemplate
 	}
+
+	r := strings.NewReplacer(
+		"{domain}", domain,
+		"{namespace}", o.Namespace,
+		"{name}", o.Name,
+		"{kind}", strings.ToLower(o.Kind),
+	)
+
+	return filepath.Join(c.Path, r.Replace(tmpl))
+}
+
+// Key returns the key identifying the exported object, <kind>/<namespace>/<name>, objects of
+// different kinds may share a name.
+func (o ExportObjectConfig) Key() string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(o.Kind), o.Namespace, o.Name)
 }
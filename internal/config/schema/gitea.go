@@ -0,0 +1,27 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+// GiteaConfig represents the configuration for Gitea integration.
+type GiteaConfig struct {
+	Url          string `koanf:"url"`          // The base url of the Gitea instance, e.g. https://gitea.example.com.
+	Organization string `koanf:"organization"` // The default organization owning the repositories.
+}
+
+// GiteaCredentials represents the credentials for accessing Gitea.
+type GiteaCredentials struct {
+	Username string `koanf:"username"`
+	Token    string `koanf:"token"`
+}
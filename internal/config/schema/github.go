@@ -19,6 +19,9 @@ type GithubConfig struct {
 	TagReleaseEnabled bool           `koanf:"tag_release"`
 	Webhooks          GithubWebhooks `koanf:"webhooks"`
 	Organization      string         `koanf:"organization"`
+	CacheTtlSeconds   int            `koanf:"cache_ttl_seconds"` // seconds to reuse repository access check results, 0 disables the cache
+	RequiredScopes    []string       `koanf:"required_scopes"`   // additional token scopes to require, e.g. workflow or admin:org
+	DeployKey         string         `koanf:"deploy_key"`        // title of the deploy key expected on each application repository, empty skips the check
 }
 
 // GithubCredentials represents the credentials for accessing GitHub.
@@ -29,8 +32,9 @@ type GithubCredentials struct {
 
 // GithubWebhooks represents the configuration for GitHub webhooks.
 type GithubWebhooks struct {
-	Build   bool `koanf:"build"`
-	Release bool `koanf:"release"`
+	Build   bool   `koanf:"build"`
+	Release bool   `koanf:"release"`
+	Url     string `koanf:"url"` // payload url expected on application repositories, defaults to the cluster's jenkins github-webhook endpoint
 }
 
 // NewGithubConfig returns a new GithubConfig instance with default values.
@@ -42,5 +46,6 @@ func NewGithubConfig() GithubConfig {
 			Release: false,
 		},
 		TagReleaseEnabled: false,
+		CacheTtlSeconds:   60 * 5, // 5 mins
 	}
 }
@@ -19,4 +19,5 @@ type IronbankCredentials struct {
 	Username string `koanf:"username"`
 	Password string `koanf:"password"`
 	Email    string `koanf:"email"`
+	Registry string `koanf:"registry"` // registry host the credentials are checked against, default registry1.dso.mil
 }
@@ -20,6 +20,7 @@ import (
 	"cmp"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/MetroStar/quartzctl/internal/log"
 )
@@ -29,8 +30,12 @@ type QuartzConfig struct {
 	Name    string `koanf:"name"`
 	Project string `koanf:"project"`
 
-	Tmp   string      `koanf:"tmp"`
-	Chart ChartConfig `koanf:"chart"`
+	Tmp          string      `koanf:"tmp"`
+	KeepTmp      bool        `koanf:"keep_tmp"`
+	TmpMinFreeMb int         `koanf:"tmp_min_free_mb"` // free space required in tmp before install, 0 skips the check
+	Chart        ChartConfig `koanf:"chart"`
+
+	DisableUpdateCheck bool `koanf:"disable_update_check"` // skip querying GitHub releases in version --check-update
 
 	Providers ProvidersConfig `koanf:"providers"`
 	Auth      AuthConfig      `koanf:"auth"`
@@ -38,10 +43,13 @@ type QuartzConfig struct {
 
 	Aws    AwsConfig    `koanf:"aws"`
 	Github GithubConfig `koanf:"github"`
+	Gitea  GiteaConfig  `koanf:"gitea"`
 
-	Core         InfrastructureEnvironmentConfig         `koanf:"core"`
-	Environments map[string]ApplicationEnvironmentConfig `koanf:"environments"`
-	Alerts       AlertsConfig                            `koanf:"alerts"`
+	Core              InfrastructureEnvironmentConfig         `koanf:"core"`
+	Environments      map[string]ApplicationEnvironmentConfig `koanf:"environments"`
+	ActiveEnvironment string                                  `koanf:"active_environment"` // set from --env when an environment overlay is loaded
+	Alerts            AlertsConfig                            `koanf:"alerts"`
+	Notifications     NotificationsConfig                     `koanf:"notifications"`
 
 	Kubernetes KubernetesConfig `koanf:"kubernetes"`
 	Terraform  TerraformConfig  `koanf:"terraform"`
@@ -54,8 +62,9 @@ type QuartzConfig struct {
 	Applications   map[string]ApplicationRepositoryConfig `koanf:"applications"`
 	Mirror         MirrorConfig                           `koanf:"mirror"`
 
-	Export ExportConfig `koanf:"export"`
-	State  StateConfig  `koanf:"state"`
+	Export  ExportConfig  `koanf:"export"`
+	State   StateConfig   `koanf:"state"`
+	Cleanup CleanupConfig `koanf:"cleanup"`
 
 	Log log.LogOptionsConfig `koanf:"log"`
 
@@ -67,7 +76,9 @@ type QuartzConfig struct {
 type QuartzSecrets struct {
 	Ironbank   IronbankCredentials   `koanf:"ironbank"`
 	Github     GithubCredentials     `koanf:"github"`
+	Gitea      GiteaCredentials      `koanf:"gitea"`
 	Cloudflare CloudflareCredentials `koanf:"cloudflare"`
+	Mirror     MirrorCredentials     `koanf:"mirror"` // target registry credentials for `quartz mirror`, ghcr.io targets fall back to the github credentials
 }
 
 // StagesOrdered sorts the configured stages map and returns an ordered slice.
@@ -84,7 +95,8 @@ func (c *QuartzConfig) StagesOrdered() []StageConfig {
 	}
 
 	slices.SortStableFunc(r, func(x, y StageConfig) int {
-		return cmp.Compare(x.Order, y.Order)
+		// ties (e.g. expanded matrix stages) are ordered by id to keep runs deterministic
+		return cmp.Or(cmp.Compare(x.Order, y.Order), strings.Compare(x.Id, y.Id))
 	})
 
 	return r
@@ -101,6 +113,23 @@ func (c QuartzConfig) KubeconfigPath() string {
 	return p
 }
 
+// GithubCachePath derives the path of the cached GitHub repository access check results.
+func (c QuartzConfig) GithubCachePath() string {
+	p, _ := filepath.Abs(filepath.Join(c.Tmp, "github-access.json"))
+	return p
+}
+
+// TfPluginCachePath derives the terraform provider plugin cache directory based on optional overrides in QuartzConfig.
+func (c QuartzConfig) TfPluginCachePath() string {
+	if len(c.Terraform.PluginCache.Path) > 0 {
+		p, _ := filepath.Abs(c.Terraform.PluginCache.Path)
+		return p
+	}
+
+	p, _ := filepath.Abs(filepath.Join(c.Tmp, "terraform-plugin-cache"))
+	return p
+}
+
 // TfVarFilePath derives the expected Terraform tfvars path based on optional overrides in QuartzConfig.
 func (c QuartzConfig) TfVarFilePath() string {
 	p, _ := filepath.Abs(filepath.Join(c.Tmp, "quartz.tfvars.json"))
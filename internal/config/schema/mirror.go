@@ -25,6 +25,14 @@ type MirrorImageRepositoryConfig struct {
 	Enabled          bool     `koanf:"enabled"`
 	Target           string   `koanf:"target"`
 	SourceRegistries []string `koanf:"source_registries"`
+	Images           []string `koanf:"images"` // image references copied to the target by `quartz mirror`, e.g. registry1.dso.mil/ironbank/opensource/nginx/nginx:1.27.0
+	Charts           []string `koanf:"charts"` // oci helm chart references copied to the target by `quartz mirror`
+}
+
+// MirrorCredentials represents the credentials for pushing to the mirror target registry.
+type MirrorCredentials struct {
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
 }
 
 // NewMirrorConfig returns a new MirrorConfig instance with default values.
@@ -0,0 +1,26 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+// NotificationsConfig represents the configuration for install and clean completion notifications.
+type NotificationsConfig struct {
+	Webhook NotificationsWebhookConfig `koanf:"webhook"`
+}
+
+// NotificationsWebhookConfig represents a webhook posted the outcome of install and clean.
+type NotificationsWebhookConfig struct {
+	Url    string `koanf:"url"`    // The webhook url, notifications are disabled when empty.
+	Format string `koanf:"format"` // The payload format, json (default) or slack.
+}
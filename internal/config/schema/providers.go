@@ -23,6 +23,8 @@ type ProvidersConfig struct {
 	Secrets       string `koanf:"secrets"`
 	Oidc          string `koanf:"oidc"`
 	CiCd          string `koanf:"cicd"`
+
+	CheckTimeoutSeconds int `koanf:"check_timeout_seconds"` // per provider access check timeout, 0 disables it
 }
 
 // NewProvidersConfig returns a new ProvidersConfig instance with default values.
@@ -35,5 +37,7 @@ func NewProvidersConfig() ProvidersConfig {
 		Secrets:       "aws-ssm-parameter",
 		Oidc:          "keycloak",
 		CiCd:          "jenkins",
+
+		CheckTimeoutSeconds: 60,
 	}
 }
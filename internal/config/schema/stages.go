@@ -14,23 +14,33 @@
 
 package schema
 
+import (
+	"fmt"
+	"regexp"
+)
+
 // StageConfig represents the configuration for a single stage in the Quartz pipeline.
 // It includes details such as dependencies, variables, and checks.
 type StageConfig struct {
-	Id           string                       `koanf:"id"`
-	Description  string                       `koanf:"description"`
-	Path         string                       `koanf:"path"`
-	Type         string                       `koanf:"type"`         // terraform, other
-	Dependencies []string                     `koanf:"dependencies"` // slice of stages that have to run before
-	Disabled     bool                         `koanf:"disabled"`
-	Manual       bool                         `koanf:"manual"`
-	Order        int                          `koanf:"order"`
-	Providers    StageProvidersConfig         `koanf:"providers"`
-	OverrideVars bool                         `koanf:"override_vars"`
-	Vars         map[string]StageVarsConfig   `koanf:"vars"`
-	Checks       map[string]StageChecksConfig `koanf:"checks"`
-	Destroy      StageDestroyConfig           `koanf:"destroy"`
-	Debug        StageDebugConfig             `koanf:"debug"`
+	Id             string                       `koanf:"id"`
+	Description    string                       `koanf:"description"`
+	Path           string                       `koanf:"path"`
+	Type           string                       `koanf:"type"`         // terraform, other
+	Dependencies   []string                     `koanf:"dependencies"` // slice of stages that have to run before
+	Disabled       bool                         `koanf:"disabled"`
+	Manual         bool                         `koanf:"manual"`
+	Order          int                          `koanf:"order"`
+	Providers      StageProvidersConfig         `koanf:"providers"`
+	OverrideVars   bool                         `koanf:"override_vars"`
+	Vars           map[string]StageVarsConfig   `koanf:"vars"`
+	Checks         map[string]StageChecksConfig `koanf:"checks"`
+	Destroy        StageDestroyConfig           `koanf:"destroy"`
+	PreDestroyWait []StageWaitConfig            `koanf:"pre_destroy_wait"` // resources that must be fully removed before destroy
+	Debug          StageDebugConfig             `koanf:"debug"`
+	Matrix         map[string]string            `koanf:"matrix"`      // expands into one stage per entry, <id>-<key>
+	MatrixVar      string                       `koanf:"matrix_var"`  // input variable receiving the matrix value, default "matrix"
+	WorkingDir     bool                         `koanf:"working_dir"` // copy the source to <tmp>/stages/<id> each run and run terraform there
+	Workspace      string                       `koanf:"workspace"`   // terraform workspace selected (and created if missing) before running, default workspace when empty
 }
 
 // StageChecksConfig represents the configuration for checks associated with a stage.
@@ -41,6 +51,7 @@ type StageChecksConfig struct {
 	Kubernetes []StageChecksKubernetesConfig `koanf:"kubernetes"`
 	DaemonSet  []StageChecksDaemonSetConfig  `koanf:"daemonset"`
 	State      []StageChecksStateConfig      `koanf:"state"`
+	ConfigMap  []StageChecksConfigMapConfig  `koanf:"configmap"`
 	Order      int                           `koanf:"order"`
 }
 
@@ -99,10 +110,24 @@ type StageChecksKubernetesConfig struct {
 }
 
 // StageChecksStateConfig represents the configuration for state-based checks in a stage.
+// The stored value must match Value, an empty Value requiring an empty stored value, unless
+// Present is set, which only requires the key to be present.
 type StageChecksStateConfig struct {
-	Key   string                 `koanf:"key"`
-	Value string                 `koanf:"value"`
-	Retry StageChecksRetryConfig `koanf:"retry"`
+	Key     string                 `koanf:"key"`
+	Value   string                 `koanf:"value"`
+	Present bool                   `koanf:"present"`
+	Retry   StageChecksRetryConfig `koanf:"retry"`
+}
+
+// StageChecksConfigMapConfig represents the configuration for checks which watch a ConfigMap until a key
+// reaches the expected value, e.g. a bootstrap job writing ready=true. An empty Value only requires the key
+// to be present, an empty Name watches the state ConfigMap. Timeout is in seconds, defaulting to 10 minutes.
+type StageChecksConfigMapConfig struct {
+	Name      string `koanf:"name"`
+	Namespace string `koanf:"namespace"`
+	Key       string `koanf:"key"`
+	Value     string `koanf:"value"`
+	Timeout   int    `koanf:"timeout"`
 }
 
 // StageChecksDaemonSetConfig represents the configuration for DaemonSet readiness checks.
@@ -127,6 +152,28 @@ type StageDestroyConfig struct {
 	Exclude []string `koanf:"exclude"`
 }
 
+// ValidateDestroyPatterns checks each destroy include or exclude pattern compiles as a regex,
+// returning an error per invalid pattern named by the given setting, e.g. "--include".
+func ValidateDestroyPatterns(name string, patterns []string) []error {
+	var errs []error
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s pattern %q is invalid, %w", name, p, err))
+		}
+	}
+	return errs
+}
+
+// StageWaitConfig represents a Kubernetes resource that must be fully removed
+// (including any pending finalizers) before a stage is destroyed.
+type StageWaitConfig struct {
+	Name        string `koanf:"name"`      // optional, matches all resources of the kind if empty
+	Namespace   string `koanf:"namespace"` // optional, cluster-wide if empty
+	Kind        string `koanf:"kind"`
+	Timeout     int    `koanf:"timeout"`      // seconds, defaults to 600
+	WaitSeconds int    `koanf:"wait_seconds"` // seconds between polls, defaults to 5
+}
+
 // StageDebugConfig represents the debug configuration for a stage.
 type StageDebugConfig struct {
 	Break bool `koanf:"break"`
@@ -19,6 +19,9 @@ type StateConfig struct {
 	Enabled            bool   `koanf:"enabled"`            // Indicates if state management is enabled.
 	ConfigMapName      string `koanf:"configMapName"`      // The name of the ConfigMap used for state management.
 	ConfigMapNamespace string `koanf:"configMapNamespace"` // The namespace of the ConfigMap used for state management.
+	Encryption         string `koanf:"encryption"`         // Default encryption for the terraform state bucket, AES256 or aws:kms, resolved at load.
+	KmsKeyArn          string `koanf:"kms_key_arn"`        // The KMS key used when encryption is aws:kms, defaults to the AWS managed key.
+	DynamodbPitr       bool   `koanf:"dynamodb_pitr"`      // Enable point-in-time recovery on the terraform state lock table.
 }
 
 // NewStateConfig returns a new StateConfig instance with default values.
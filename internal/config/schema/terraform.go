@@ -17,11 +17,29 @@ package schema
 // TerraformConfig represents the configuration for Terraform.
 type TerraformConfig struct {
 	Version string `koanf:"version"` // The version of Terraform to use.
+	Quiet   bool   `koanf:"quiet"`   // Discard terraform output instead of streaming it to the console.
+
+	InitWorkers int `koanf:"init_workers"` // Max stages initialized concurrently by init-all, 0 uses the number of CPUs.
+
+	StageTimeoutSeconds int `koanf:"stage_timeout_seconds"` // Max duration of each stage apply or destroy during install and clean, 0 for no limit.
+
+	PluginCache TerraformPluginCacheConfig `koanf:"plugin_cache"` // Provider plugin cache shared by all stages.
+
+	BackendConfigFiles []string `koanf:"backend_config_files"` // Backend config files passed to init ahead of the inline backend settings.
+}
+
+// TerraformPluginCacheConfig represents the configuration for the terraform provider plugin cache.
+type TerraformPluginCacheConfig struct {
+	Enabled bool   `koanf:"enabled"` // Set TF_PLUGIN_CACHE_DIR so providers are downloaded once for all stages.
+	Path    string `koanf:"path"`    // The cache directory, defaults to terraform-plugin-cache under tmp.
 }
 
 // NewTerraformConfig returns a new TerraformConfig instance with default values.
 func NewTerraformConfig() TerraformConfig {
 	return TerraformConfig{
 		Version: "1.5.7",
+		PluginCache: TerraformPluginCacheConfig{
+			Enabled: true,
+		},
 	}
 }
@@ -0,0 +1,78 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+)
+
+// SecretsTemplate is a commented secrets file scaffold matching schema.QuartzSecrets. The keys are
+// commented out, the secrets file loads after the environment so placeholder values would override
+// the credentials supplied by the environment variables noted beside them.
+const SecretsTemplate = `# Quartz secrets file, pass to quartz with --secrets.
+# Keep this file out of source control. Uncomment and fill in the values you
+# need, a value set here takes precedence over the environment variable noted
+# beside it, so leave it commented out to use the environment instead.
+
+# Iron Bank registry credentials, used to pull hardened images.
+# ironbank:
+#   username: "changeme"          # IRONBANK_USERNAME or REGISTRY_USERNAME
+#   password: "changeme"          # IRONBANK_PASSWORD or REGISTRY_PASSWORD
+#   email: "changeme@example.com" # IRONBANK_EMAIL or REGISTRY_EMAIL
+#   registry: "registry1.dso.mil" # IRONBANK_REGISTRY or REGISTRY_HOST, checked by quartz check
+
+# GitHub credentials, used when github is the git provider.
+# github:
+#   username: "changeme" # GITHUB_USERNAME
+#   token: "changeme"    # GITHUB_TOKEN, a personal access token
+
+# Gitea credentials, used when gitea is the git provider.
+# gitea:
+#   username: "changeme" # GITEA_USERNAME
+#   token: "changeme"    # GITEA_TOKEN
+
+# Cloudflare credentials, used when cloudflare manages dns.
+# cloudflare:
+#   account_id: "changeme"        # CLOUDFLARE_ACCOUNT_ID
+#   api_token: "changeme"         # CLOUDFLARE_API_TOKEN or CLOUDFLARE_TOKEN
+#   email: "changeme@example.com" # CLOUDFLARE_EMAIL
+
+# Target registry credentials for quartz mirror, ghcr.io targets fall back to the github credentials.
+# mirror:
+#   username: "changeme"
+#   password: "changeme"
+`
+
+// WriteSecretsTemplate writes the secrets file scaffold to path, readable only by the owner.
+// Returns an error if the file already exists, unless force is set.
+func WriteSecretsTemplate(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists, use --force to overwrite", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0740); err != nil {
+		return err
+	}
+
+	log.Info("Writing secrets template", "path", path)
+	return os.WriteFile(path, []byte(SecretsTemplate), 0600)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSecretsTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "secrets.yaml")
+
+	err := WriteSecretsTemplate(path, false)
+	if err != nil {
+		t.Fatalf("unexpected error writing secrets template, %v", err)
+	}
+
+	for _, key := range []string{
+		"ironbank:", "username:", "password:", "email:", "registry:",
+		"github:", "token:", "gitea:",
+		"cloudflare:", "account_id:", "api_token:", "mirror:",
+	} {
+		if !strings.Contains(SecretsTemplate, "# "+key) && !strings.Contains(SecretsTemplate, "#   "+key) {
+			t.Errorf("expected commented %s in secrets template", key)
+		}
+	}
+
+	// the placeholders are commented out so they don't override the environment
+	t.Setenv("IRONBANK_USERNAME", "envuser")
+	t.Setenv("GITHUB_TOKEN", "envtoken")
+
+	raw, err := LoadRawSecrets(context.Background(), path)
+	if err != nil {
+		t.Fatalf("unexpected error loading secrets template, %v", err)
+	}
+
+	s, err := NewSettings(raw, raw)
+	if err != nil {
+		t.Fatalf("unexpected error parsing secrets template, %v", err)
+	}
+
+	if s.Secrets.Ironbank.Username != "envuser" || s.Secrets.Github.Token != "envtoken" {
+		t.Errorf("expected the environment credentials, found %+v", s.Secrets)
+	}
+
+	if s.Secrets.Cloudflare.Email != "" {
+		t.Errorf("expected no placeholder values, found %+v", s.Secrets)
+	}
+}
+
+func TestWriteSecretsTemplateExists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	if err := os.WriteFile(path, []byte("existing"), 0600); err != nil {
+		t.Fatalf("unexpected error writing existing file, %v", err)
+	}
+
+	err := WriteSecretsTemplate(path, false)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, found %v", err)
+	}
+
+	b, _ := os.ReadFile(path)
+	if string(b) != "existing" {
+		t.Errorf("expected existing file untouched, found %s", b)
+	}
+
+	err = WriteSecretsTemplate(path, true)
+	if err != nil {
+		t.Fatalf("unexpected error overwriting secrets template, %v", err)
+	}
+
+	b, _ = os.ReadFile(path)
+	if string(b) != SecretsTemplate {
+		t.Errorf("expected secrets template written with force")
+	}
+}
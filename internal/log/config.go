@@ -25,6 +25,7 @@ import (
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
+	"go.uber.org/zap/zapcore"
 )
 
 // LogConfig represents the configuration for logging, including log name and options.
@@ -63,7 +64,7 @@ type TerraformLogConfig struct {
 var DefaultLogConfig = LogConfig{
 	Log: LogOptionsConfig{
 		Console: ConsoleLogConfig{
-			Level: "error",
+			Level: "warn",
 		},
 		File: FileLogConfig{
 			Enabled: false,
@@ -91,15 +92,48 @@ func DebugEnvOverride() bool {
 // ConfigureDefault configures the default logger using the provided configuration file and writer.
 // If an error occurs during configuration, it falls back to the default logger.
 func ConfigureDefault(configFile string, w io.Writer) {
+	ConfigureDefaultLevel(configFile, "", w)
+}
+
+// ConfigureDefaultLevel configures the default logger as ConfigureDefault does, with a
+// non-empty level overriding the configured console log level.
+func ConfigureDefaultLevel(configFile string, level string, w io.Writer) {
 	cfg, err := NewLogConfig(configFile)
 	if err != nil {
 		// Log the error and fall back to the default configuration.
 		Debug("Failed to load log configuration", "error", err)
 		cfg = DefaultLogConfig
 	}
+
+	if level != "" {
+		cfg.Log.Console.Level = level
+	}
+
 	SetDefault(NewZapLogger(cfg, w))
 }
 
+// VerbosityLevel returns the console log level for the number of -v flags, info for one and
+// debug for two or more. Empty for none, leaving the configured level.
+func VerbosityLevel(v int) string {
+	switch {
+	case v <= 0:
+		return ""
+	case v == 1:
+		return "info"
+	}
+
+	return "debug"
+}
+
+// CheckLevel returns an error if l isn't a known log level.
+func CheckLevel(l string) error {
+	if _, err := zapcore.ParseLevel(strings.ToLower(l)); err != nil {
+		return fmt.Errorf("invalid log level %s, expected one of debug, info, warn, error", l)
+	}
+
+	return nil
+}
+
 // NewLogConfig loads the logging configuration from the specified file path.
 // If the path is empty or an error occurs, it returns the default configuration.
 func NewLogConfig(path string) (LogConfig, error) {
@@ -38,7 +38,7 @@ log: {}
 		return
 	}
 
-	if conf.Log.Console.Level != "error" ||
+	if conf.Log.Console.Level != "warn" ||
 		conf.Log.File.Level != "info" {
 		t.Errorf("incorrect log config, found %v", conf)
 	}
@@ -74,7 +74,7 @@ log:
 }
 
 func TestDefaultLogConfig_ConsoleLogConfig(t *testing.T) {
-	assert.Equal(t, "error", DefaultLogConfig.Log.Console.Level, "Default console log level should be 'error'")
+	assert.Equal(t, "warn", DefaultLogConfig.Log.Console.Level, "Default console log level should be 'warn'")
 }
 
 func TestDefaultLogConfig_FileLogConfig(t *testing.T) {
@@ -140,3 +140,25 @@ func TestNewLogConfig_ReturnsDefaultOnInvalidFile(t *testing.T) {
 	assert.Error(t, err, "NewLogConfig should return an error for an invalid file")
 	assert.Equal(t, DefaultLogConfig, config, "NewLogConfig should return the default configuration for an invalid file")
 }
+
+func TestLogVerbosityLevel(t *testing.T) {
+	tests := map[int]string{
+		-1: "",
+		0:  "",
+		1:  "info",
+		2:  "debug",
+		3:  "debug",
+	}
+
+	for v, expected := range tests {
+		assert.Equal(t, expected, VerbosityLevel(v), "verbosity %d", v)
+	}
+}
+
+func TestLogCheckLevel(t *testing.T) {
+	for _, l := range []string{"debug", "info", "WARN", "error"} {
+		assert.NoError(t, CheckLevel(l))
+	}
+
+	assert.ErrorContains(t, CheckLevel("loud"), "invalid log level loud")
+}
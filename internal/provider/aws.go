@@ -17,14 +17,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
 	"github.com/MetroStar/quartzctl/internal/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 )
 
 const (
@@ -39,6 +44,23 @@ type AwsClient struct {
 
 	id     string
 	region string
+
+	skipRegionValidation bool   // skip validating the region against the SDK's known regions
+	stateEncryption      string // default encryption for the state bucket, AES256 or aws:kms
+	stateKmsKeyArn       string // kms key for the state bucket when using aws:kms
+	stateDynamodbPitr    bool   // enable point-in-time recovery on the state lock table
+
+	tags map[string]string // tags applied to created resources, in addition to the cluster tag
+
+	eksReadyTimeout time.Duration // how long to wait for the eks cluster to be ready, 0 doesn't wait
+
+	identity *awsIdentityCache // memoized caller identity, shared across copies of the client
+}
+
+// awsIdentityCache holds the caller identity for the lifetime of the process.
+type awsIdentityCache struct {
+	mu       sync.Mutex
+	identity *CloudProviderIdentity
 }
 
 type AwsProviderCheckResult struct {
@@ -47,23 +69,35 @@ type AwsProviderCheckResult struct {
 }
 
 func NewLazyAwsClient(ctx context.Context, id string, region string) (AwsClient, error) {
+	return NewLazyAwsClientWithEndpoint(ctx, id, region, "", false)
+}
+
+// NewLazyAwsClientWithEndpoint creates an AwsClient whose SDK clients use the custom endpoint,
+// e.g. LocalStack, when endpointUrl is set, optionally addressing S3 buckets by path.
+func NewLazyAwsClientWithEndpoint(ctx context.Context, id string, region string, endpointUrl string, s3UsePathStyle bool) (AwsClient, error) {
 	c, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		return AwsClient{}, err
 	}
 
+	if endpointUrl != "" {
+		c.BaseEndpoint = aws.String(endpointUrl)
+	}
+
 	return NewAwsClient(id, region, c, &LazyAwsSdkClient{
-		cfg:    c,
-		region: region,
+		cfg:            c,
+		region:         region,
+		s3UsePathStyle: s3UsePathStyle,
 	}), nil
 }
 
 func NewAwsClient(id string, region string, cfg aws.Config, sdk AwsSdkClientFactory) AwsClient {
 	return AwsClient{
-		cfg:    cfg,
-		id:     id,
-		region: region,
-		sdk:    sdk,
+		cfg:      cfg,
+		id:       id,
+		region:   region,
+		sdk:      sdk,
+		identity: &awsIdentityCache{},
 	}
 }
 
@@ -75,6 +109,20 @@ func (c AwsClient) stateBackendTableName() string {
 	return c.stateBackendBucketName() + "-lock"
 }
 
+// resourceTags returns the tags applied to AWS resources created for the cluster,
+// the configured aws.tags along with the quartz:cluster tag identifying the cluster.
+func (c AwsClient) resourceTags() map[string]string {
+	tags := maps.Clone(c.tags)
+	if c.id != "" {
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags["quartz:cluster"] = c.id
+	}
+
+	return tags
+}
+
 // ------------- implement interface ICloudProviderClient -------------
 
 // ProviderName returns the name of the cloud provider ("AWS").
@@ -82,14 +130,38 @@ func (c AwsClient) ProviderName() string {
 	return AWS_PROVIDER
 }
 
+// CheckConfig validates the AWS configuration, ensuring a region is set. A region unknown to the
+// SDK's partition metadata is only warned about, the metadata lags newly launched regions.
 func (c AwsClient) CheckConfig() error {
 	if c.cfg.Region == "" {
 		return fmt.Errorf("aws.region required")
 	}
 
+	if c.skipRegionValidation {
+		return nil
+	}
+
+	for _, r := range []string{c.cfg.Region, c.region} {
+		if r != "" && !isKnownAwsRegion(r) {
+			log.Warn("Unknown aws region, check for typos or set aws.skip_region_validation if the region is new", "region", r)
+		}
+	}
+
 	return nil
 }
 
+// isKnownAwsRegion checks whether the region exists in any partition (aws, aws-cn, aws-us-gov, etc)
+// known to the SDK's endpoint metadata.
+func isKnownAwsRegion(region string) bool {
+	for _, p := range endpoints.DefaultPartitions() {
+		if _, ok := p.Regions()[region]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c AwsClient) CheckAccess(ctx context.Context) ProviderCheckResult {
 	id, err := c.CurrentIdentity(ctx)
 
@@ -101,7 +173,42 @@ func (c AwsClient) CheckAccess(ctx context.Context) ProviderCheckResult {
 	return res
 }
 
+// CurrentIdentity returns the identity of the current AWS caller. The identity is looked up
+// once and cached for the lifetime of the client, use RefreshIdentity to force a new lookup.
 func (c AwsClient) CurrentIdentity(ctx context.Context) (CloudProviderIdentity, error) {
+	if c.identity == nil {
+		return c.lookupIdentity(ctx)
+	}
+
+	c.identity.mu.Lock()
+	defer c.identity.mu.Unlock()
+
+	if c.identity.identity != nil {
+		return *c.identity.identity, nil
+	}
+
+	id, err := c.lookupIdentity(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	c.identity.identity = &id
+	return id, nil
+}
+
+// RefreshIdentity discards any cached caller identity and looks it up again.
+func (c AwsClient) RefreshIdentity(ctx context.Context) (CloudProviderIdentity, error) {
+	if c.identity != nil {
+		c.identity.mu.Lock()
+		c.identity.identity = nil
+		c.identity.mu.Unlock()
+	}
+
+	return c.CurrentIdentity(ctx)
+}
+
+// lookupIdentity queries STS and IAM for the identity of the current AWS caller.
+func (c AwsClient) lookupIdentity(ctx context.Context) (CloudProviderIdentity, error) {
 	callerId, err := c.sdk.Sts().GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		return CloudProviderIdentity{}, err
@@ -133,16 +240,47 @@ func (c AwsClient) StateBackendInfo(stage string) CloudProviderStateBackend {
 }
 
 func (c AwsClient) CreateStateBackend(ctx context.Context) error {
-	err := c.CreateBucket(ctx, c.stateBackendBucketName(), false)
+	bucket := c.stateBackendBucketName()
+	err := c.CreateBucket(ctx, bucket, false)
+	if err != nil {
+		return err
+	}
+
+	// enforce versioning and encryption even if the bucket already existed
+	err = c.EnableBucketVersioning(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	err = c.EnableBucketEncryption(ctx, bucket, c.stateEncryption, c.stateKmsKeyArn)
 	if err != nil {
 		return err
 	}
 
-	err = c.CreateDynamodbTable(ctx, c.stateBackendTableName(), false)
+	err = c.TagBucket(ctx, bucket)
 	if err != nil {
 		return err
 	}
 
+	table := c.stateBackendTableName()
+	err = c.CreateDynamodbTable(ctx, table, false)
+	if err != nil {
+		return err
+	}
+
+	// tag even if the table already existed, like the bucket
+	err = c.TagDynamodbTable(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	if c.stateDynamodbPitr {
+		err = c.EnableDynamodbPitr(ctx, table)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -160,11 +298,28 @@ func (c AwsClient) DestroyStateBackend(ctx context.Context) error {
 	return nil
 }
 
+func (c AwsClient) AcquireRunLock(ctx context.Context, operation string) error {
+	return c.PutDynamodbLock(ctx, c.stateBackendTableName(), c.runLockId(), newRunLockInfo(operation))
+}
+
+func (c AwsClient) ReleaseRunLock(ctx context.Context) error {
+	return c.DeleteDynamodbLock(ctx, c.stateBackendTableName(), c.runLockId())
+}
+
+// runLockId returns the lock table key used for the cluster-wide quartz run lock.
+func (c AwsClient) runLockId() string {
+	return c.stateBackendBucketName() + "/quartz.lock"
+}
+
 func (c AwsClient) KubeconfigInfo(ctx context.Context) (KubeconfigInfo, error) {
 	kc, _, err := c.EksKubeconfigInfo(ctx)
 	return kc, err
 }
 
+func (c AwsClient) KubeconfigUserInfo(cfg schema.QuartzConfig, kc KubeconfigInfo) *schema.KubeconfigUserInfo {
+	return awsKubeconfigUserInfo(cfg, kc)
+}
+
 func (c AwsClient) PrintConfig() {
 	headers := []string{"Cluster", "Region"}
 	rows := [][]string{
@@ -195,6 +350,24 @@ func (c AwsClient) PrintClusterInfo(ctx context.Context) error {
 	return nil
 }
 
+// ReconcileAdministrators creates EKS access entries with cluster admin access for the IAM principal arns
+// among the administrators, see ReconcileEksAccessEntries. Auth user and group administrators are skipped.
+func (c AwsClient) ReconcileAdministrators(ctx context.Context, administrators []string) ([]string, error) {
+	var principals []string
+	for _, a := range administrators {
+		if strings.HasPrefix(a, "arn:") {
+			principals = append(principals, a)
+		}
+	}
+
+	if len(principals) == 0 {
+		log.Debug("No IAM principal administrators, skipping EKS access entries", "cluster", c.id)
+		return nil, nil
+	}
+
+	return c.ReconcileEksAccessEntries(ctx, principals)
+}
+
 func (c AwsClient) PrepareAccount(ctx context.Context) error {
 	services := []string{
 		"autoscaling.amazonaws.com",
@@ -16,7 +16,10 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/MetroStar/quartzctl/internal/log"
@@ -54,6 +57,7 @@ func (c *AwsClient) CreateDynamodbTable(ctx context.Context, name string, force
 			ReadCapacityUnits:  aws.Int64(5),
 			WriteCapacityUnits: aws.Int64(5),
 		},
+		Tags: c.dynamodbTags(),
 	})
 
 	if err != nil {
@@ -69,6 +73,136 @@ func (c *AwsClient) CreateDynamodbTable(ctx context.Context, name string, force
 	return nil
 }
 
+// EnableDynamodbPitr enables point-in-time recovery on the DynamoDB table with the specified name.
+func (c *AwsClient) EnableDynamodbPitr(ctx context.Context, name string) error {
+	_, err := c.sdk.Dynamodb().UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName: aws.String(name),
+		PointInTimeRecoverySpecification: &types.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+	})
+
+	if err != nil {
+		log.Info("Failed to enable point-in-time recovery", "name", name, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// TagDynamodbTable applies the cluster resource tags to the DynamoDB table with the specified
+// name, so a table created before the tags were configured picks them up. TagResource adds
+// to the existing tags rather than replacing them.
+func (c *AwsClient) TagDynamodbTable(ctx context.Context, name string) error {
+	tags := c.dynamodbTags()
+	if len(tags) == 0 {
+		return nil
+	}
+
+	resp, err := c.sdk.Dynamodb().DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(name),
+	})
+
+	if err != nil {
+		log.Info("Failed to describe table", "name", name, "err", err)
+		return err
+	}
+
+	_, err = c.sdk.Dynamodb().TagResource(ctx, &dynamodb.TagResourceInput{
+		ResourceArn: resp.Table.TableArn,
+		Tags:        tags,
+	})
+
+	if err != nil {
+		log.Info("Failed to tag table", "name", name, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// PutDynamodbLock writes a lock item to the DynamoDB table with the specified name.
+// Returns a RunLockError if an item with the same lock ID already exists.
+func (c *AwsClient) PutDynamodbLock(ctx context.Context, name string, lockId string, info runLockInfo) error {
+	_, err := c.sdk.Dynamodb().PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(name),
+		Item: map[string]types.AttributeValue{
+			"LockID":    &types.AttributeValueMemberS{Value: lockId},
+			"Operation": &types.AttributeValueMemberS{Value: info.Operation},
+			"Who":       &types.AttributeValueMemberS{Value: info.Who},
+			"Created":   &types.AttributeValueMemberS{Value: info.Created},
+		},
+		ConditionExpression:                 aws.String("attribute_not_exists(LockID)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		held := runLockInfo{
+			Operation: dynamodbStringAttr(ccf.Item, "Operation"),
+			Who:       dynamodbStringAttr(ccf.Item, "Who"),
+			Created:   dynamodbStringAttr(ccf.Item, "Created"),
+		}
+		return held.lockError(fmt.Sprintf("%s (table %s)", lockId, name))
+	}
+
+	var rnf *types.ResourceNotFoundException
+	if errors.As(err, &rnf) {
+		// no state backend yet, so there is nothing another run could be modifying
+		log.Info("Lock table not found, skipping lock", "name", name, "lockId", lockId)
+		return nil
+	}
+
+	if err != nil {
+		log.Info("Failed to acquire lock", "name", name, "lockId", lockId, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDynamodbLock removes a lock item from the DynamoDB table with the specified name.
+func (c *AwsClient) DeleteDynamodbLock(ctx context.Context, name string, lockId string) error {
+	_, err := c.sdk.Dynamodb().DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(name),
+		Key: map[string]types.AttributeValue{
+			"LockID": &types.AttributeValueMemberS{Value: lockId},
+		},
+	})
+
+	var rnf *types.ResourceNotFoundException
+	if errors.As(err, &rnf) {
+		// the table was removed along with the state backend, taking the lock with it
+		return nil
+	}
+
+	return err
+}
+
+// dynamodbStringAttr returns the string value of the named attribute, or an empty string.
+func dynamodbStringAttr(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+
+	return ""
+}
+
+// dynamodbTags returns the tags applied to DynamoDB tables created for the cluster.
+func (c *AwsClient) dynamodbTags() []types.Tag {
+	tags := c.resourceTags()
+
+	var res []types.Tag
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		res = append(res, types.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+
+	return res
+}
+
 // DestroyDynamodbTable deletes a DynamoDB table with the specified name.
 func (c *AwsClient) DestroyDynamodbTable(ctx context.Context, name string) error {
 	_, err := c.sdk.Dynamodb().DeleteTable(ctx, &dynamodb.DeleteTableInput{
@@ -16,11 +16,24 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksTypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
 )
 
+// eksReadyPollInterval is how often the EKS cluster is described while waiting for it to be ready.
+var eksReadyPollInterval = 10 * time.Second
+
 // EksToken represents an EKS authentication token and its JSON representation.
 type EksToken struct {
 	Token      token.Token // The EKS authentication token.
@@ -28,9 +41,10 @@ type EksToken struct {
 }
 
 // EksKubeconfigInfo retrieves the kubeconfig information for an EKS cluster.
-// It returns the kubeconfig details, an EKS token, and an error if any occurs.
+// It waits for the cluster to be ready, see WaitEksClusterReady, and returns the kubeconfig details,
+// an EKS token, and an error if any occurs.
 func (c *AwsClient) EksKubeconfigInfo(ctx context.Context) (KubeconfigInfo, EksToken, error) {
-	cluster, err := c.DescribeEksCluster(ctx)
+	cluster, err := c.WaitEksClusterReady(ctx)
 	if err != nil {
 		return KubeconfigInfo{}, EksToken{}, err
 	}
@@ -41,16 +55,40 @@ func (c *AwsClient) EksKubeconfigInfo(ctx context.Context) (KubeconfigInfo, EksT
 	}
 
 	return KubeconfigInfo{
-		Context:              *cluster.Cluster.Arn,
-		User:                 *cluster.Cluster.Arn,
-		Cluster:              *cluster.Cluster.Arn,
-		Endpoint:             *cluster.Cluster.Endpoint,
-		CertificateAuthority: *cluster.Cluster.CertificateAuthority.Data,
+		Context:              *cluster.Arn,
+		User:                 *cluster.Arn,
+		Cluster:              *cluster.Arn,
+		Endpoint:             *cluster.Endpoint,
+		CertificateAuthority: *cluster.CertificateAuthority.Data,
 		Token:                t.Token.Token,
 		Expiration:           t.Token.Expiration,
 	}, t, nil
 }
 
+// awsKubeconfigUserInfo returns an exec block calling `quartz aws get-eks-token` for the cluster.
+// Returns nil when service account auth is enabled so the exchanged static token is used instead.
+func awsKubeconfigUserInfo(cfg schema.QuartzConfig, _ KubeconfigInfo) *schema.KubeconfigUserInfo {
+	if cfg.Auth.ServiceAccount.Enabled {
+		return nil
+	}
+
+	bin, _ := os.Executable()
+	return &schema.KubeconfigUserInfo{
+		Exec: &schema.KubeconfigUserExec{
+			ApiVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    bin,
+			Args: []string{
+				"aws",
+				"get-eks-token",
+				"--cluster",
+				cfg.Name,
+				"--region",
+				cfg.Aws.Region,
+			},
+		},
+	}
+}
+
 // DescribeEksCluster describes the EKS cluster associated with the client.
 // It returns the cluster details or an error if the operation fails.
 func (c *AwsClient) DescribeEksCluster(ctx context.Context) (*eks.DescribeClusterOutput, error) {
@@ -59,6 +97,201 @@ func (c *AwsClient) DescribeEksCluster(ctx context.Context) (*eks.DescribeCluste
 	})
 }
 
+// WaitEksClusterReady describes the EKS cluster until it is ACTIVE or UPDATING with its endpoint and certificate
+// authority populated, which they may not be while the cluster is being created. It polls until the
+// aws.eks_ready_timeout_seconds timeout, returning what the cluster is still missing if it never becomes ready.
+// A DELETING or FAILED cluster fails immediately.
+func (c *AwsClient) WaitEksClusterReady(ctx context.Context) (*eksTypes.Cluster, error) {
+	deadline := time.Now().Add(c.eksReadyTimeout)
+
+	for {
+		o, err := c.DescribeEksCluster(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var cluster *eksTypes.Cluster
+		if o != nil {
+			cluster = o.Cluster
+		}
+
+		if cluster != nil && eksClusterFailed(cluster.Status) {
+			return nil, fmt.Errorf("eks cluster %s is %s and won't become ready", c.id, cluster.Status)
+		}
+
+		pending := eksClusterPending(cluster)
+		if len(pending) == 0 {
+			return cluster, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			if c.eksReadyTimeout <= 0 {
+				return nil, fmt.Errorf("eks cluster %s is not ready, %s", c.id, strings.Join(pending, ", "))
+			}
+			return nil, fmt.Errorf("eks cluster %s not ready after %s, %s", c.id, c.eksReadyTimeout, strings.Join(pending, ", "))
+		}
+
+		log.Info("Waiting for EKS cluster to be ready", "cluster", c.id, "pending", strings.Join(pending, ", "))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(eksReadyPollInterval):
+		}
+	}
+}
+
+// eksClusterFailed returns true for the cluster statuses that never become ready.
+func eksClusterFailed(status eksTypes.ClusterStatus) bool {
+	return status == eksTypes.ClusterStatusDeleting || status == eksTypes.ClusterStatusFailed
+}
+
+// eksClusterPending lists what keeps a described cluster from being usable, empty when it's ready.
+// An UPDATING cluster (e.g. a version or config update) still serves its endpoint, so it's ready once
+// the endpoint and certificate authority are populated.
+func eksClusterPending(cluster *eksTypes.Cluster) []string {
+	if cluster == nil {
+		return []string{"no cluster in describe response"}
+	}
+
+	var r []string
+	if cluster.Status != eksTypes.ClusterStatusActive && cluster.Status != eksTypes.ClusterStatusUpdating {
+		r = append(r, fmt.Sprintf("status %s", cluster.Status))
+	}
+	if aws.ToString(cluster.Arn) == "" {
+		r = append(r, "arn missing")
+	}
+	if aws.ToString(cluster.Endpoint) == "" {
+		r = append(r, "endpoint missing")
+	}
+	if cluster.CertificateAuthority == nil || aws.ToString(cluster.CertificateAuthority.Data) == "" {
+		r = append(r, "certificate authority missing")
+	}
+
+	return r
+}
+
+// eksClusterAdminPolicy is the EKS access policy associated with administrator access entries.
+const eksClusterAdminPolicy = "cluster-access-policy/AmazonEKSClusterAdminPolicy"
+
+// ReconcileEksAccessEntries ensures each IAM principal arn has an EKS access entry with the cluster admin
+// access policy associated cluster-wide, creating the missing entries. Existing entries quartz didn't create,
+// e.g. terraform managed access entries, are left alone so the two don't fight over them. A cluster in
+// CONFIG_MAP authentication mode doesn't support access entries and is skipped with a warning, as is
+// a cluster that doesn't exist yet, e.g. an --only install of the stages before the one creating it.
+// Returns the principals whose entries were created.
+func (c *AwsClient) ReconcileEksAccessEntries(ctx context.Context, principals []string) ([]string, error) {
+	o, err := c.DescribeEksCluster(ctx)
+	var rnf *eksTypes.ResourceNotFoundException
+	if errors.As(err, &rnf) {
+		log.Warn("EKS cluster not found, skipping administrator access entries until the stage creating it is installed",
+			"cluster", c.id, "principals", principals)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if o != nil && o.Cluster != nil && o.Cluster.AccessConfig != nil &&
+		o.Cluster.AccessConfig.AuthenticationMode == eksTypes.AuthenticationModeConfigMap {
+		log.Warn("EKS cluster uses CONFIG_MAP authentication, skipping administrator access entries, set the authentication mode to API_AND_CONFIG_MAP or API, or map them in aws-auth",
+			"cluster", c.id, "principals", principals)
+		return nil, nil
+	}
+
+	existing, err := c.listEksAccessEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+	var errs []error
+	for _, principal := range principals {
+		if slices.Contains(existing, principal) {
+			managed, err := c.eksAccessEntryManaged(ctx, principal)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !managed {
+				log.Info("EKS access entry not created by quartz, leaving it unchanged", "cluster", c.id, "principal", principal)
+				continue
+			}
+		} else {
+			_, err := c.sdk.Eks().CreateAccessEntry(ctx, &eks.CreateAccessEntryInput{
+				ClusterName:  &c.id,
+				PrincipalArn: aws.String(principal),
+				Type:         aws.String("STANDARD"),
+				Tags:         c.resourceTags(),
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to create eks access entry for %s, %w", principal, err))
+				continue
+			}
+
+			log.Info("Created EKS access entry", "cluster", c.id, "principal", principal)
+			created = append(created, principal)
+		}
+
+		// associating is idempotent, so entries quartz created are repaired if the policy was removed
+		_, err := c.sdk.Eks().AssociateAccessPolicy(ctx, &eks.AssociateAccessPolicyInput{
+			ClusterName:  &c.id,
+			PrincipalArn: aws.String(principal),
+			PolicyArn:    aws.String(eksAccessPolicyArn(principal, eksClusterAdminPolicy)),
+			AccessScope:  &eksTypes.AccessScope{Type: eksTypes.AccessScopeTypeCluster},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to associate eks cluster admin policy with %s, %w", principal, err))
+		}
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// eksAccessEntryManaged returns true if the principal's access entry was created by quartz for this cluster.
+func (c *AwsClient) eksAccessEntryManaged(ctx context.Context, principal string) (bool, error) {
+	o, err := c.sdk.Eks().DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
+		ClusterName:  &c.id,
+		PrincipalArn: aws.String(principal),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to describe eks access entry for %s, %w", principal, err)
+	}
+
+	return o.AccessEntry != nil && o.AccessEntry.Tags["quartz:cluster"] == c.id, nil
+}
+
+// listEksAccessEntries returns the principal arns of all access entries in the cluster.
+func (c *AwsClient) listEksAccessEntries(ctx context.Context) ([]string, error) {
+	var entries []string
+	var next *string
+	for {
+		o, err := c.sdk.Eks().ListAccessEntries(ctx, &eks.ListAccessEntriesInput{
+			ClusterName: &c.id,
+			NextToken:   next,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list eks access entries, %w", err)
+		}
+
+		entries = append(entries, o.AccessEntries...)
+		if aws.ToString(o.NextToken) == "" {
+			return entries, nil
+		}
+		next = o.NextToken
+	}
+}
+
+// eksAccessPolicyArn returns the arn of an EKS access policy in the partition of the principal,
+// e.g. aws-us-gov for GovCloud principals.
+func eksAccessPolicyArn(principal string, policy string) string {
+	partition := "aws"
+	if parts := strings.Split(principal, ":"); len(parts) > 1 && parts[1] != "" {
+		partition = parts[1]
+	}
+
+	return fmt.Sprintf("arn:%s:eks::aws:%s", partition, policy)
+}
+
 // generateEksUserToken generates an authentication token for the EKS cluster.
 // It returns the token, its JSON representation, and an error if the operation fails.
 func (c *AwsClient) generateEksUserToken() (EksToken, error) {
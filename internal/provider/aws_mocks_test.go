@@ -17,6 +17,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -52,6 +53,7 @@ type StsClientMock struct {
 	account string
 	userid  string
 	arn     string
+	calls   *atomic.Int32 // optional counter of GetCallerIdentity calls
 }
 
 // IamClientMock provides a mock implementation of the IAM client.
@@ -67,11 +69,15 @@ type S3ClientMock struct {
 	region  string
 	objects []string
 	exists  bool
+	tags    map[string]string // optional existing bucket tags, NoSuchTagSet when empty
+	calls   *[]any            // optional record of put requests made against the client
 }
 
 // DynamodbClientMock provides a mock implementation of the DynamoDB client.
 type DynamodbClientMock struct {
-	err error
+	err   error
+	calls *[]any                                             // optional record of create/update requests made against the client
+	items map[string]map[string]dynamodbTypes.AttributeValue // optional items keyed by LockID for conditional puts
 }
 
 // EksClientMock provides a mock implementation of the EKS client.
@@ -81,6 +87,16 @@ type EksClientMock struct {
 	clusterArn             string
 	clusterEndpoint        string
 	clusterCertificateData string
+	clusterStatus          eksTypes.ClusterStatus // defaults to ACTIVE
+
+	describes []*eksTypes.Cluster // optional responses returned in turn, the last one repeated
+	calls     *atomic.Int32       // optional counter of DescribeCluster calls
+
+	accessEntryPages [][]string                         // existing access entry principals, one ListAccessEntries page each
+	accessEntryTags  map[string]map[string]string       // existing access entry tags by principal
+	createErrs       map[string]error                   // CreateAccessEntry errors by principal
+	created          *[]*eks.CreateAccessEntryInput     // optional record of CreateAccessEntry calls
+	associated       *[]*eks.AssociateAccessPolicyInput // optional record of AssociateAccessPolicy calls
 }
 
 // EksTokenGeneratorMock provides a mock implementation of the EKS token generator.
@@ -128,6 +144,10 @@ func (c *AwsSdkClientMock) EksTokenGenerator() (token.Generator, error) {
 
 // GetCallerIdentity returns a mock response for the GetCallerIdentity API call.
 func (c StsClientMock) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	if c.calls != nil {
+		c.calls.Add(1)
+	}
+
 	return &sts.GetCallerIdentityOutput{
 		Account: aws.String(c.account),
 		UserId:  aws.String(c.userid),
@@ -192,20 +212,110 @@ func (c S3ClientMock) DeleteObjects(ctx context.Context, params *s3.DeleteObject
 	return &s3.DeleteObjectsOutput{}, c.err
 }
 
+// PutBucketVersioning records the request and returns a mock response for the PutBucketVersioning API call.
+func (c S3ClientMock) PutBucketVersioning(ctx context.Context, params *s3.PutBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
+	return &s3.PutBucketVersioningOutput{}, c.err
+}
+
+// PutBucketEncryption records the request and returns a mock response for the PutBucketEncryption API call.
+func (c S3ClientMock) PutBucketEncryption(ctx context.Context, params *s3.PutBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
+	return &s3.PutBucketEncryptionOutput{}, c.err
+}
+
+// GetBucketTagging returns the existing tags, or a NoSuchTagSet error when there are none.
+func (c S3ClientMock) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if len(c.tags) == 0 {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchTagSet"}
+	}
+
+	var tagSet []s3Types.Tag
+	for k, v := range c.tags {
+		tagSet = append(tagSet, s3Types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	return &s3.GetBucketTaggingOutput{TagSet: tagSet}, c.err
+}
+
+// PutBucketTagging records the request and returns a mock response for the PutBucketTagging API call.
+func (c S3ClientMock) PutBucketTagging(ctx context.Context, params *s3.PutBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
+	return &s3.PutBucketTaggingOutput{}, c.err
+}
+
 // CreateTable returns a mock response for the CreateTable API call.
 func (c DynamodbClientMock) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
 	return &dynamodb.CreateTableOutput{}, c.err
 }
 
+// UpdateContinuousBackups records the request and returns a mock response for the UpdateContinuousBackups API call.
+func (c DynamodbClientMock) UpdateContinuousBackups(ctx context.Context, params *dynamodb.UpdateContinuousBackupsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
+	return &dynamodb.UpdateContinuousBackupsOutput{}, c.err
+}
+
+// TagResource records the request and returns a mock response for the TagResource API call.
+func (c DynamodbClientMock) TagResource(ctx context.Context, params *dynamodb.TagResourceInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error) {
+	if c.calls != nil {
+		*c.calls = append(*c.calls, params)
+	}
+
+	return &dynamodb.TagResourceOutput{}, c.err
+}
+
+// PutItem stores the item and returns a ConditionalCheckFailedException if an item with the same LockID exists.
+func (c DynamodbClientMock) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	if c.items != nil {
+		key := params.Item["LockID"].(*dynamodbTypes.AttributeValueMemberS).Value
+		if existing, found := c.items[key]; found {
+			return nil, &dynamodbTypes.ConditionalCheckFailedException{Message: aws.String("The conditional request failed"), Item: existing}
+		}
+		c.items[key] = params.Item
+	}
+
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// DeleteItem removes the item and returns a mock response for the DeleteItem API call.
+func (c DynamodbClientMock) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if c.items != nil {
+		delete(c.items, params.Key["LockID"].(*dynamodbTypes.AttributeValueMemberS).Value)
+	}
+
+	return &dynamodb.DeleteItemOutput{}, c.err
+}
+
 // DeleteTable returns a mock response for the DeleteTable API call.
 func (c DynamodbClientMock) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
 	return &dynamodb.DeleteTableOutput{}, c.err
 }
 
 // DescribeTable returns a mock response for the DescribeTable API call.
-func (c DynamodbClientMock) DescribeTable(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+func (c DynamodbClientMock) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
 	return &dynamodb.DescribeTableOutput{
 		Table: &dynamodbTypes.TableDescription{
+			TableArn:    aws.String("arn:aws:dynamodb:us-west-1:123456789012:table/" + aws.ToString(params.TableName)),
 			TableStatus: "ACTIVE",
 		},
 	}, c.err
@@ -213,12 +323,27 @@ func (c DynamodbClientMock) DescribeTable(context.Context, *dynamodb.DescribeTab
 
 // DescribeCluster returns a mock response for the DescribeCluster API call.
 func (c EksClientMock) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	n := 0
+	if c.calls != nil {
+		n = int(c.calls.Add(1)) - 1
+	}
+
+	if len(c.describes) > 0 {
+		return &eks.DescribeClusterOutput{Cluster: c.describes[min(n, len(c.describes)-1)]}, c.err
+	}
+
+	status := c.clusterStatus
+	if status == "" {
+		status = eksTypes.ClusterStatusActive
+	}
+
 	return &eks.DescribeClusterOutput{
 		Cluster: &eksTypes.Cluster{
 			Name:      aws.String(c.clusterArn),
 			Version:   aws.String("0.1"),
 			CreatedAt: aws.Time(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
 			Arn:       aws.String(c.clusterArn),
+			Status:    status,
 			Endpoint:  aws.String(c.clusterEndpoint),
 			CertificateAuthority: &eksTypes.Certificate{
 				Data: aws.String(c.clusterCertificateData),
@@ -227,6 +352,57 @@ func (c EksClientMock) DescribeCluster(ctx context.Context, params *eks.Describe
 	}, c.err
 }
 
+// ListAccessEntries returns the mock access entries, paging through accessEntryPages with the page index as the token.
+func (c EksClientMock) ListAccessEntries(ctx context.Context, params *eks.ListAccessEntriesInput, optFns ...func(*eks.Options)) (*eks.ListAccessEntriesOutput, error) {
+	if len(c.accessEntryPages) == 0 {
+		return &eks.ListAccessEntriesOutput{}, c.err
+	}
+
+	page := 0
+	if params.NextToken != nil {
+		fmt.Sscanf(*params.NextToken, "%d", &page)
+	}
+
+	o := &eks.ListAccessEntriesOutput{AccessEntries: c.accessEntryPages[page]}
+	if page+1 < len(c.accessEntryPages) {
+		o.NextToken = aws.String(fmt.Sprintf("%d", page+1))
+	}
+
+	return o, c.err
+}
+
+// DescribeAccessEntry returns the mock access entry with its configured tags.
+func (c EksClientMock) DescribeAccessEntry(ctx context.Context, params *eks.DescribeAccessEntryInput, optFns ...func(*eks.Options)) (*eks.DescribeAccessEntryOutput, error) {
+	return &eks.DescribeAccessEntryOutput{
+		AccessEntry: &eksTypes.AccessEntry{
+			PrincipalArn: params.PrincipalArn,
+			Tags:         c.accessEntryTags[aws.ToString(params.PrincipalArn)],
+		},
+	}, c.err
+}
+
+// CreateAccessEntry records the mock access entry creation, returning the configured error for the principal.
+func (c EksClientMock) CreateAccessEntry(ctx context.Context, params *eks.CreateAccessEntryInput, optFns ...func(*eks.Options)) (*eks.CreateAccessEntryOutput, error) {
+	if err := c.createErrs[aws.ToString(params.PrincipalArn)]; err != nil {
+		return nil, err
+	}
+
+	if c.created != nil {
+		*c.created = append(*c.created, params)
+	}
+
+	return &eks.CreateAccessEntryOutput{}, c.err
+}
+
+// AssociateAccessPolicy records the mock access policy association.
+func (c EksClientMock) AssociateAccessPolicy(ctx context.Context, params *eks.AssociateAccessPolicyInput, optFns ...func(*eks.Options)) (*eks.AssociateAccessPolicyOutput, error) {
+	if c.associated != nil {
+		*c.associated = append(*c.associated, params)
+	}
+
+	return &eks.AssociateAccessPolicyOutput{}, c.err
+}
+
 // GetWithOptions returns a mock token for the GetWithOptions API call.
 func (c EksTokenGeneratorMock) GetWithOptions(options *token.GetTokenOptions) (token.Token, error) {
 	return token.Token{
@@ -17,6 +17,10 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/MetroStar/quartzctl/internal/log"
 
@@ -72,6 +76,139 @@ func (c AwsClient) CreateBucket(ctx context.Context, name string, force bool) er
 	return nil
 }
 
+// EnableBucketVersioning enables object versioning on the S3 bucket with the specified name.
+func (c AwsClient) EnableBucketVersioning(ctx context.Context, name string) error {
+	_, err := c.sdk.S3().PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(name),
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: types.BucketVersioningStatusEnabled,
+		},
+	})
+
+	if err != nil {
+		log.Info("Failed to enable bucket versioning", "name", name, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// EnableBucketEncryption enables default server-side encryption on the S3 bucket with the specified name.
+// The algorithm is AES256 or aws:kms, defaulting to aws:kms when only a key ARN is supplied and AES256 otherwise.
+// When using aws:kms without a key ARN the AWS managed key is used.
+func (c AwsClient) EnableBucketEncryption(ctx context.Context, name string, algorithm string, kmsKeyArn string) error {
+	if algorithm == "" {
+		algorithm = string(types.ServerSideEncryptionAes256)
+		if kmsKeyArn != "" {
+			algorithm = string(types.ServerSideEncryptionAwsKms)
+		}
+	}
+
+	sse := types.ServerSideEncryptionByDefault{}
+	var bucketKey *bool
+
+	switch {
+	case strings.EqualFold(algorithm, string(types.ServerSideEncryptionAes256)):
+		if kmsKeyArn != "" {
+			return fmt.Errorf("state.kms_key_arn is only supported with aws:kms encryption")
+		}
+
+		sse.SSEAlgorithm = types.ServerSideEncryptionAes256
+	case strings.EqualFold(algorithm, string(types.ServerSideEncryptionAwsKms)):
+		sse.SSEAlgorithm = types.ServerSideEncryptionAwsKms
+		if kmsKeyArn != "" {
+			sse.KMSMasterKeyID = aws.String(kmsKeyArn)
+		}
+		bucketKey = aws.Bool(true)
+	default:
+		return fmt.Errorf("unsupported state bucket encryption %s, expected AES256 or aws:kms", algorithm)
+	}
+
+	_, err := c.sdk.S3().PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: aws.String(name),
+		ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+			Rules: []types.ServerSideEncryptionRule{
+				{
+					ApplyServerSideEncryptionByDefault: &sse,
+					BucketKeyEnabled:                   bucketKey,
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		log.Info("Failed to enable bucket encryption", "name", name, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// TagBucket applies the cluster resource tags to the S3 bucket with the specified name,
+// merged with any existing tags since PutBucketTagging replaces the whole tag set.
+func (c AwsClient) TagBucket(ctx context.Context, name string) error {
+	tags := c.resourceTags()
+	if len(tags) == 0 {
+		return nil
+	}
+
+	existing, err := c.bucketTags(ctx, name)
+	if err != nil {
+		log.Info("Failed to read bucket tags", "name", name, "err", err)
+		return err
+	}
+
+	merged := maps.Clone(existing)
+	maps.Copy(merged, tags)
+	if maps.Equal(merged, existing) {
+		log.Debug("Bucket already tagged, skipping", "name", name)
+		return nil
+	}
+
+	var tagSet []types.Tag
+	for _, k := range slices.Sorted(maps.Keys(merged)) {
+		tagSet = append(tagSet, types.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(merged[k]),
+		})
+	}
+
+	_, err = c.sdk.S3().PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(name),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+
+	if err != nil {
+		log.Info("Failed to tag bucket", "name", name, "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// bucketTags returns the current tags of the S3 bucket with the specified name, empty if it has none.
+func (c AwsClient) bucketTags(ctx context.Context, name string) (map[string]string, error) {
+	res := map[string]string{}
+
+	resp, err := c.sdk.S3().GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(name),
+	})
+
+	if err != nil {
+		var apiError smithy.APIError
+		if errors.As(err, &apiError) && apiError.ErrorCode() == "NoSuchTagSet" {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	for _, t := range resp.TagSet {
+		res[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+
+	return res, nil
+}
+
 // DestroyBucket deletes an S3 bucket with the specified name.
 // The bucket must be empty before it can be deleted.
 func (c *AwsClient) DestroyBucket(ctx context.Context, name string) error {
@@ -40,8 +40,9 @@ type AwsSdkClientFactory interface {
 // LazyAwsSdkClient is a lazy-loading implementation of AWS SDK clients.
 // It initializes clients only when they are accessed.
 type LazyAwsSdkClient struct {
-	cfg    aws.Config
-	region string
+	cfg            aws.Config
+	region         string
+	s3UsePathStyle bool // address buckets by path, e.g. for custom endpoints
 
 	stsClient      StsClient      // *sts.Client
 	iamClient      IamClient      // *iam.Client
@@ -68,6 +69,10 @@ type S3Client interface {
 	DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
 	ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
 	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	PutBucketVersioning(ctx context.Context, params *s3.PutBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error)
+	PutBucketEncryption(ctx context.Context, params *s3.PutBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	PutBucketTagging(ctx context.Context, params *s3.PutBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
 }
 
 // DynamodbClient defines the interface for interacting with AWS DynamoDB.
@@ -75,11 +80,19 @@ type DynamodbClient interface {
 	dynamodb.DescribeTableAPIClient
 	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
 	DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error)
+	UpdateContinuousBackups(ctx context.Context, params *dynamodb.UpdateContinuousBackupsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error)
+	TagResource(ctx context.Context, params *dynamodb.TagResourceInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
 }
 
 // EksClient defines the interface for interacting with AWS EKS.
 type EksClient interface {
 	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+	ListAccessEntries(ctx context.Context, params *eks.ListAccessEntriesInput, optFns ...func(*eks.Options)) (*eks.ListAccessEntriesOutput, error)
+	DescribeAccessEntry(ctx context.Context, params *eks.DescribeAccessEntryInput, optFns ...func(*eks.Options)) (*eks.DescribeAccessEntryOutput, error)
+	CreateAccessEntry(ctx context.Context, params *eks.CreateAccessEntryInput, optFns ...func(*eks.Options)) (*eks.CreateAccessEntryOutput, error)
+	AssociateAccessPolicy(ctx context.Context, params *eks.AssociateAccessPolicyInput, optFns ...func(*eks.Options)) (*eks.AssociateAccessPolicyOutput, error)
 }
 
 // Sts returns a lazily initialized STS client.
@@ -109,6 +122,7 @@ func (c *LazyAwsSdkClient) S3() S3Client {
 	if c.s3Client == nil {
 		c.s3Client = s3.NewFromConfig(c.cfg, func(o *s3.Options) {
 			o.Region = c.region
+			o.UsePathStyle = c.s3UsePathStyle
 		})
 	}
 
@@ -119,6 +133,7 @@ func (c *LazyAwsSdkClient) S3() S3Client {
 func (c *LazyAwsSdkClient) S3Region(region string) S3Client {
 	return s3.NewFromConfig(c.cfg, func(o *s3.Options) {
 		o.Region = region
+		o.UsePathStyle = c.s3UsePathStyle
 	})
 }
 
@@ -17,9 +17,21 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksTypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -93,6 +105,65 @@ func TestProviderAwsClientCurrentIdentity(t *testing.T) {
 	}
 }
 
+func TestProviderAwsClientCurrentIdentityCached(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := NewAwsClient("", "", aws.Config{},
+		&AwsSdkClientMock{
+			stsClient: StsClientMock{account: "123456789", userid: "testuserid", arn: "arn:aws:iam::123456789:user/testusername", calls: calls},
+			iamClient: IamClientMock{accountAliases: []string{"testaccount"}},
+		})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := c.CurrentIdentity(context.Background())
+			if err != nil || id.AccountId != "123456789" {
+				t.Errorf("unexpected aws client identity, %v, %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// copies of the client share the cached identity
+	c2 := c
+	c2.CheckAccess(context.Background())
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single sts call, found %d", n)
+	}
+
+	_, err := c.RefreshIdentity(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error from aws client identity refresh, %v", err)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected refresh to call sts again, found %d calls", n)
+	}
+}
+
+func TestProviderAwsClientCurrentIdentityErrorNotCached(t *testing.T) {
+	sdk := &AwsSdkClientMock{
+		stsClient: StsClientMock{err: fmt.Errorf("sts unavailable")},
+		iamClient: IamClientMock{},
+	}
+	c := NewAwsClient("", "", aws.Config{}, sdk)
+
+	_, err := c.CurrentIdentity(context.Background())
+	if err == nil {
+		t.Error("expected error from aws client identity lookup")
+	}
+
+	sdk.stsClient = StsClientMock{account: "123456789", userid: "testuserid", arn: "arn:aws:iam::123456789:user/testusername"}
+
+	id, err := c.CurrentIdentity(context.Background())
+	if err != nil || id.AccountId != "123456789" {
+		t.Errorf("unexpected aws client identity after failed lookup, %v, %v", id, err)
+	}
+}
+
 func TestProviderAwsClientCheckAccess(t *testing.T) {
 	c := NewAwsClient("", "", aws.Config{},
 		&AwsSdkClientMock{
@@ -143,6 +214,231 @@ func TestProviderAwsClientCreateStateBackend(t *testing.T) {
 	}
 }
 
+func TestProviderNewLazyAwsClientWithEndpoint(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "foo")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "bar")
+
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Host+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(200)
+	}))
+	defer srv.Close()
+
+	c, err := NewLazyAwsClientWithEndpoint(context.Background(), "test-cluster", "us-east-1", srv.URL, true)
+	if err != nil {
+		t.Fatalf("unexpected error from aws lazy client ctor, %v", err)
+	}
+
+	assert.Equal(t, srv.URL, *c.cfg.BaseEndpoint)
+
+	exists, err := c.BucketExists(context.Background(), "testbucket")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// path style addressing keeps the bucket out of the host name
+	host := strings.TrimPrefix(srv.URL, "http://")
+	assert.Equal(t, []string{host + "/testbucket"}, paths)
+}
+
+func TestProviderNewLazyAwsClientDefaultEndpoint(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "foo")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "bar")
+
+	c, err := NewLazyAwsClient(context.Background(), "test-cluster", "us-east-1")
+	if err != nil {
+		t.Fatalf("unexpected error from aws lazy client ctor, %v", err)
+	}
+
+	assert.Nil(t, c.cfg.BaseEndpoint)
+	assert.False(t, c.sdk.S3().(*s3.Client).Options().UsePathStyle)
+}
+
+func TestProviderAwsClientCreateStateBackendVersioningEncryption(t *testing.T) {
+	calls := []any{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{
+		Region: "us-west-1",
+	}, &AwsSdkClientMock{
+		s3Client:       S3ClientMock{exists: true, calls: &calls},
+		dynamodbClient: DynamodbClientMock{},
+	})
+
+	err := c.CreateStateBackend(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, calls, 3)
+
+	v, ok := calls[0].(*s3.PutBucketVersioningInput)
+	assert.True(t, ok, "expected versioning to be enabled first")
+	assert.Equal(t, "testcluster-state-us-west-1", *v.Bucket)
+	assert.Equal(t, s3Types.BucketVersioningStatusEnabled, v.VersioningConfiguration.Status)
+
+	e, ok := calls[1].(*s3.PutBucketEncryptionInput)
+	assert.True(t, ok, "expected encryption to be enabled")
+	assert.Equal(t, "testcluster-state-us-west-1", *e.Bucket)
+	sse := e.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault
+	assert.Equal(t, s3Types.ServerSideEncryptionAes256, sse.SSEAlgorithm)
+	assert.Nil(t, sse.KMSMasterKeyID)
+
+	_, ok = calls[2].(*s3.PutBucketTaggingInput)
+	assert.True(t, ok, "expected bucket to be tagged")
+}
+
+func TestProviderAwsClientCreateStateBackendTags(t *testing.T) {
+	s3Calls := []any{}
+	dynamodbCalls := []any{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{
+		Region: "us-west-1",
+	}, &AwsSdkClientMock{
+		s3Client:       S3ClientMock{exists: true, calls: &s3Calls, tags: map[string]string{"owner": "someone", "backup": "daily"}},
+		dynamodbClient: DynamodbClientMock{calls: &dynamodbCalls},
+	})
+	c.tags = map[string]string{
+		"cost-center":    "1234",
+		"owner":          "platform",
+		"quartz:cluster": "ignored",
+	}
+
+	err := c.CreateStateBackend(context.Background())
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		"cost-center":    "1234",
+		"owner":          "platform",
+		"quartz:cluster": "testcluster",
+	}
+
+	var bucketTags *s3.PutBucketTaggingInput
+	for _, call := range s3Calls {
+		if in, ok := call.(*s3.PutBucketTaggingInput); ok {
+			bucketTags = in
+		}
+	}
+	if assert.NotNil(t, bucketTags, "expected bucket to be tagged") {
+		assert.Equal(t, "testcluster-state-us-west-1", *bucketTags.Bucket)
+		actual := map[string]string{}
+		for _, tag := range bucketTags.Tagging.TagSet {
+			actual[*tag.Key] = *tag.Value
+		}
+		// existing tags are kept, the configured ones win
+		assert.Equal(t, map[string]string{
+			"backup":         "daily",
+			"cost-center":    "1234",
+			"owner":          "platform",
+			"quartz:cluster": "testcluster",
+		}, actual)
+	}
+
+	// the lock table already existed, so it's tagged rather than created
+	if assert.Len(t, dynamodbCalls, 1) {
+		in, ok := dynamodbCalls[0].(*dynamodb.TagResourceInput)
+		if assert.True(t, ok, "expected existing lock table to be tagged") {
+			assert.Equal(t, "arn:aws:dynamodb:us-west-1:123456789012:table/testcluster-state-us-west-1-lock", *in.ResourceArn)
+			actual := map[string]string{}
+			for _, tag := range in.Tags {
+				actual[*tag.Key] = *tag.Value
+			}
+			assert.Equal(t, expected, actual)
+		}
+	}
+
+	dynamodbCalls = dynamodbCalls[:0]
+	err = c.CreateDynamodbTable(context.Background(), "testtable", true)
+	assert.NoError(t, err)
+	assert.Len(t, dynamodbCalls, 1)
+	table := dynamodbCalls[0].(*dynamodb.CreateTableInput)
+	actual := map[string]string{}
+	for _, tag := range table.Tags {
+		actual[*tag.Key] = *tag.Value
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestProviderAwsClientTagBucketUnchanged(t *testing.T) {
+	calls := []any{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{}, &AwsSdkClientMock{
+		s3Client: S3ClientMock{calls: &calls, tags: map[string]string{"quartz:cluster": "testcluster", "backup": "daily"}},
+	})
+
+	err := c.TagBucket(context.Background(), "testbucket")
+	assert.NoError(t, err)
+	assert.Empty(t, calls, "bucket already has the tags, nothing to put")
+}
+
+func TestProviderAwsClientEnableBucketEncryption(t *testing.T) {
+	calls := []any{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{}, &AwsSdkClientMock{
+		s3Client: S3ClientMock{calls: &calls},
+	})
+
+	keyArn := "arn:aws:kms:us-west-1:123456789:key/test"
+
+	// kms with an explicit key
+	err := c.EnableBucketEncryption(context.Background(), "testbucket", "aws:kms", keyArn)
+	assert.NoError(t, err)
+
+	// key arn alone implies kms
+	err = c.EnableBucketEncryption(context.Background(), "testbucket", "", keyArn)
+	assert.NoError(t, err)
+
+	assert.Len(t, calls, 2)
+	for _, call := range calls {
+		rule := call.(*s3.PutBucketEncryptionInput).ServerSideEncryptionConfiguration.Rules[0]
+		assert.Equal(t, s3Types.ServerSideEncryptionAwsKms, rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		assert.Equal(t, keyArn, *rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)
+		assert.True(t, *rule.BucketKeyEnabled)
+	}
+
+	err = c.EnableBucketEncryption(context.Background(), "testbucket", "AES256", keyArn)
+	assert.ErrorContains(t, err, "only supported with aws:kms")
+
+	err = c.EnableBucketEncryption(context.Background(), "testbucket", "rot13", "")
+	assert.ErrorContains(t, err, "unsupported state bucket encryption")
+
+	assert.Len(t, calls, 2, "no requests expected for invalid encryption settings")
+}
+
+func TestProviderAwsClientRunLock(t *testing.T) {
+	items := map[string]map[string]dynamodbTypes.AttributeValue{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{}, &AwsSdkClientMock{
+		dynamodbClient: DynamodbClientMock{items: items},
+	})
+
+	err := c.AcquireRunLock(context.Background(), "install")
+	assert.NoError(t, err)
+	assert.Contains(t, items, "testcluster-state-us-west-1/quartz.lock")
+
+	// simulate a second process attempting to run against the same cluster
+	err = c.AcquireRunLock(context.Background(), "clean")
+	var lockErr RunLockError
+	if assert.ErrorAs(t, err, &lockErr) {
+		assert.Equal(t, "install", lockErr.Operation)
+		assert.NotEmpty(t, lockErr.Who)
+		assert.Contains(t, lockErr.Lock, "testcluster-state-us-west-1-lock")
+	}
+
+	err = c.ReleaseRunLock(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	err = c.AcquireRunLock(context.Background(), "clean")
+	assert.NoError(t, err)
+}
+
+func TestProviderAwsClientRunLockNoTable(t *testing.T) {
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{}, &AwsSdkClientMock{
+		dynamodbClient: DynamodbClientMock{err: &dynamodbTypes.ResourceNotFoundException{}},
+	})
+
+	assert.NoError(t, c.AcquireRunLock(context.Background(), "clean"))
+	assert.NoError(t, c.ReleaseRunLock(context.Background()))
+}
+
 func TestProviderAwsClientDestroyStateBackend(t *testing.T) {
 	c := NewAwsClient("testcluster", "us-east-1", aws.Config{
 		Region: "us-east-1",
@@ -162,7 +458,9 @@ func TestProviderAwsClientKubeconfigInfo(t *testing.T) {
 		Region: "us-east-1",
 	}, &AwsSdkClientMock{
 		eksClient: EksClientMock{
-			clusterArn: "arn:aws:iam::123456789:cluster/testcluster",
+			clusterArn:             "arn:aws:iam::123456789:cluster/testcluster",
+			clusterEndpoint:        "https://testcluster.eks.amazonaws.com",
+			clusterCertificateData: "Y2VydA==",
 		},
 		eksTokenGenerator: EksTokenGeneratorMock{
 			token: "mysecureapitoken",
@@ -180,6 +478,296 @@ func TestProviderAwsClientKubeconfigInfo(t *testing.T) {
 	}
 }
 
+// withEksReadyPollInterval shortens the interval between EKS describes for the duration of the test.
+func withEksReadyPollInterval(t *testing.T, d time.Duration) {
+	orig := eksReadyPollInterval
+	eksReadyPollInterval = d
+	t.Cleanup(func() { eksReadyPollInterval = orig })
+}
+
+// newTestEksCluster creates a described EKS cluster with the given status, endpoint and certificate data.
+func newTestEksCluster(status eksTypes.ClusterStatus, endpoint string, ca string) *eksTypes.Cluster {
+	cluster := &eksTypes.Cluster{
+		Arn:    aws.String("arn:aws:eks:us-east-1:123456789:cluster/testcluster"),
+		Status: status,
+	}
+	if endpoint != "" {
+		cluster.Endpoint = aws.String(endpoint)
+	}
+	if ca != "" {
+		cluster.CertificateAuthority = &eksTypes.Certificate{Data: aws.String(ca)}
+	}
+	return cluster
+}
+
+func TestProviderAwsClientKubeconfigInfoWaitsForCluster(t *testing.T) {
+	withEksReadyPollInterval(t, time.Millisecond)
+
+	calls := &atomic.Int32{}
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			calls: calls,
+			describes: []*eksTypes.Cluster{
+				newTestEksCluster(eksTypes.ClusterStatusCreating, "", ""),
+				newTestEksCluster(eksTypes.ClusterStatusCreating, "https://testcluster.eks.amazonaws.com", ""),
+				newTestEksCluster(eksTypes.ClusterStatusActive, "https://testcluster.eks.amazonaws.com", "Y2VydA=="),
+			},
+		},
+		eksTokenGenerator: EksTokenGeneratorMock{
+			token: "mysecureapitoken",
+		},
+	})
+	c.eksReadyTimeout = time.Minute
+
+	kc, err := c.KubeconfigInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from aws client kubeconfig generator, %v", err)
+	}
+
+	if kc.Endpoint != "https://testcluster.eks.amazonaws.com" || kc.CertificateAuthority != "Y2VydA==" || kc.Token != "mysecureapitoken" {
+		t.Errorf("unexpected response from aws client kubeconfig generator, %v", kc)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected the cluster to be described until complete, found %d calls", calls.Load())
+	}
+}
+
+func TestProviderAwsClientKubeconfigInfoClusterNeverReady(t *testing.T) {
+	withEksReadyPollInterval(t, 10*time.Millisecond)
+
+	calls := &atomic.Int32{}
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			calls:     calls,
+			describes: []*eksTypes.Cluster{newTestEksCluster(eksTypes.ClusterStatusCreating, "", "")},
+		},
+	})
+	c.eksReadyTimeout = 50 * time.Millisecond
+
+	_, err := c.KubeconfigInfo(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error from aws client kubeconfig generator for a cluster that never becomes ready")
+	}
+
+	for _, s := range []string{"testcluster not ready after 50ms", "status CREATING", "endpoint missing", "certificate authority missing"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, found %v", s, err)
+		}
+	}
+
+	if calls.Load() < 2 {
+		t.Errorf("expected the cluster to be described until the timeout, found %d calls", calls.Load())
+	}
+}
+
+func TestProviderAwsClientKubeconfigInfoNoWait(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			calls:     calls,
+			describes: []*eksTypes.Cluster{newTestEksCluster(eksTypes.ClusterStatusActive, "https://testcluster.eks.amazonaws.com", "")},
+		},
+	})
+
+	_, err := c.KubeconfigInfo(context.Background())
+	if err == nil || err.Error() != "eks cluster testcluster is not ready, certificate authority missing" {
+		t.Errorf("expected a not ready error without waiting, found %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected the cluster to be described once, found %d calls", calls.Load())
+	}
+}
+
+func TestProviderAwsClientKubeconfigInfoClusterUpdating(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			calls:     calls,
+			describes: []*eksTypes.Cluster{newTestEksCluster(eksTypes.ClusterStatusUpdating, "https://testcluster.eks.amazonaws.com", "Y2VydA==")},
+		},
+		eksTokenGenerator: EksTokenGeneratorMock{
+			token: "mysecureapitoken",
+		},
+	})
+	c.eksReadyTimeout = time.Minute
+
+	kc, err := c.KubeconfigInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from aws client kubeconfig generator for an updating cluster, %v", err)
+	}
+
+	if kc.Endpoint != "https://testcluster.eks.amazonaws.com" || calls.Load() != 1 {
+		t.Errorf("expected an updating cluster to be ready without waiting, found %v after %d calls", kc, calls.Load())
+	}
+}
+
+func TestProviderAwsClientKubeconfigInfoClusterFailed(t *testing.T) {
+	withEksReadyPollInterval(t, time.Millisecond)
+
+	for _, status := range []eksTypes.ClusterStatus{eksTypes.ClusterStatusDeleting, eksTypes.ClusterStatusFailed} {
+		calls := &atomic.Int32{}
+		c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+			eksClient: EksClientMock{
+				calls:     calls,
+				describes: []*eksTypes.Cluster{newTestEksCluster(status, "https://testcluster.eks.amazonaws.com", "Y2VydA==")},
+			},
+		})
+		c.eksReadyTimeout = time.Minute
+
+		_, err := c.KubeconfigInfo(context.Background())
+		expected := fmt.Sprintf("eks cluster testcluster is %s and won't become ready", status)
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, found %v", expected, err)
+		}
+
+		if calls.Load() != 1 {
+			t.Errorf("expected a %s cluster to fail without polling, found %d calls", status, calls.Load())
+		}
+	}
+}
+
+func TestProviderAwsClientReconcileAdministrators(t *testing.T) {
+	var created []*eks.CreateAccessEntryInput
+	var associated []*eks.AssociateAccessPolicyInput
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			accessEntryPages: [][]string{
+				{"arn:aws:iam::123456789:role/node", "arn:aws:iam::123456789:role/terraform-admin"},
+				{"arn:aws:iam::123456789:role/existing-admin"},
+			},
+			accessEntryTags: map[string]map[string]string{
+				"arn:aws:iam::123456789:role/existing-admin": {"quartz:cluster": "testcluster"},
+			},
+			created:    &created,
+			associated: &associated,
+		},
+	})
+	c.tags = map[string]string{"team": "platform"}
+
+	res, err := c.ReconcileAdministrators(context.Background(), []string{
+		"admins", // auth group, not an IAM principal
+		"arn:aws:iam::123456789:role/existing-admin",
+		"arn:aws:iam::123456789:role/terraform-admin", // managed outside quartz
+		"arn:aws:iam::123456789:user/new-admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from aws client reconcile administrators, %v", err)
+	}
+
+	if len(res) != 1 || res[0] != "arn:aws:iam::123456789:user/new-admin" {
+		t.Errorf("expected only the missing administrator to be created, found %v", res)
+	}
+
+	if len(created) != 1 ||
+		aws.ToString(created[0].ClusterName) != "testcluster" ||
+		aws.ToString(created[0].PrincipalArn) != "arn:aws:iam::123456789:user/new-admin" ||
+		aws.ToString(created[0].Type) != "STANDARD" ||
+		created[0].Tags["quartz:cluster"] != "testcluster" || created[0].Tags["team"] != "platform" {
+		t.Errorf("unexpected access entries created, %v", created)
+	}
+
+	if len(associated) != 2 {
+		t.Fatalf("expected the admin policy to be associated with the administrators quartz manages, found %v", associated)
+	}
+	for _, a := range associated {
+		if aws.ToString(a.PrincipalArn) == "arn:aws:iam::123456789:role/terraform-admin" {
+			t.Errorf("expected the access entry managed outside quartz to be left unchanged, found %v", a)
+		}
+		if aws.ToString(a.PolicyArn) != "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy" ||
+			a.AccessScope == nil || a.AccessScope.Type != eksTypes.AccessScopeTypeCluster {
+			t.Errorf("unexpected access policy association, %v", a)
+		}
+	}
+}
+
+func TestProviderAwsClientReconcileAdministratorsNone(t *testing.T) {
+	describeCalls := &atomic.Int32{}
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{calls: describeCalls},
+	})
+
+	res, err := c.ReconcileAdministrators(context.Background(), []string{"admins", "jdoe"})
+	if err != nil || len(res) != 0 {
+		t.Errorf("unexpected result reconciling administrators without IAM principals, %v, %v", res, err)
+	}
+
+	if describeCalls.Load() != 0 {
+		t.Errorf("expected no EKS calls without IAM principal administrators, found %d", describeCalls.Load())
+	}
+}
+
+func TestProviderAwsClientReconcileAdministratorsConfigMapMode(t *testing.T) {
+	var created []*eks.CreateAccessEntryInput
+	cluster := newTestEksCluster(eksTypes.ClusterStatusActive, "https://testcluster.eks.amazonaws.com", "Y2VydA==")
+	cluster.AccessConfig = &eksTypes.AccessConfigResponse{AuthenticationMode: eksTypes.AuthenticationModeConfigMap}
+
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			describes: []*eksTypes.Cluster{cluster},
+			created:   &created,
+		},
+	})
+
+	res, err := c.ReconcileAdministrators(context.Background(), []string{"arn:aws:iam::123456789:role/admin"})
+	if err != nil || len(res) != 0 {
+		t.Errorf("expected CONFIG_MAP authentication to be skipped, found %v, %v", res, err)
+	}
+
+	if len(created) != 0 {
+		t.Errorf("expected no access entries to be created, found %v", created)
+	}
+}
+
+func TestProviderAwsClientReconcileAdministratorsClusterNotFound(t *testing.T) {
+	var created []*eks.CreateAccessEntryInput
+	c := NewAwsClient("testcluster", "us-east-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			err:     &eksTypes.ResourceNotFoundException{Message: aws.String("No cluster found for name: testcluster.")},
+			created: &created,
+		},
+	})
+
+	// e.g. an --only install of the stages before the cluster is created
+	res, err := c.ReconcileAdministrators(context.Background(), []string{"arn:aws:iam::123456789:role/admin"})
+	if err != nil || len(res) != 0 {
+		t.Errorf("expected a missing cluster to be skipped, found %v, %v", res, err)
+	}
+
+	if len(created) != 0 {
+		t.Errorf("expected no access entries to be created, found %v", created)
+	}
+}
+
+func TestProviderAwsClientReconcileAdministratorsErrors(t *testing.T) {
+	var created []*eks.CreateAccessEntryInput
+	var associated []*eks.AssociateAccessPolicyInput
+	c := NewAwsClient("testcluster", "us-gov-west-1", aws.Config{}, &AwsSdkClientMock{
+		eksClient: EksClientMock{
+			createErrs: map[string]error{"arn:aws-us-gov:iam::123456789:role/bad": fmt.Errorf("simulating error in CreateAccessEntry")},
+			created:    &created,
+			associated: &associated,
+		},
+	})
+
+	res, err := c.ReconcileAdministrators(context.Background(), []string{
+		"arn:aws-us-gov:iam::123456789:role/bad",
+		"arn:aws-us-gov:iam::123456789:role/good",
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to create eks access entry for arn:aws-us-gov:iam::123456789:role/bad") {
+		t.Errorf("expected an access entry creation error, found %v", err)
+	}
+
+	if len(res) != 1 || res[0] != "arn:aws-us-gov:iam::123456789:role/good" {
+		t.Errorf("expected the other administrator to still be created, found %v", res)
+	}
+
+	if len(associated) != 1 || aws.ToString(associated[0].PolicyArn) != "arn:aws-us-gov:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy" {
+		t.Errorf("expected the GovCloud admin policy to be associated with the created administrator, found %v", associated)
+	}
+}
+
 func TestProviderAwsClientPrepareAccount(t *testing.T) {
 	c1 := NewAwsClient("testcluster", "us-test-1", aws.Config{}, &AwsSdkClientMock{
 		iamClient: IamClientMock{},
@@ -243,6 +831,56 @@ func TestProviderAwsClientCreateDynamodbTable(t *testing.T) {
 	}
 }
 
+func TestProviderAwsClientCreateDynamodbTableTags(t *testing.T) {
+	calls := []any{}
+	c := NewAwsClient("testcluster", "us-west-1", aws.Config{
+		Region: "us-west-1",
+	}, &AwsSdkClientMock{
+		dynamodbClient: DynamodbClientMock{calls: &calls},
+	})
+
+	err := c.CreateDynamodbTable(context.Background(), "testtable", true)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+
+	in := calls[0].(*dynamodb.CreateTableInput)
+	assert.Len(t, in.Tags, 1)
+	assert.Equal(t, "quartz:cluster", *in.Tags[0].Key)
+	assert.Equal(t, "testcluster", *in.Tags[0].Value)
+}
+
+func TestProviderAwsClientCreateStateBackendPitr(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		calls := []any{}
+		c := NewAwsClient("testcluster", "us-west-1", aws.Config{
+			Region: "us-west-1",
+		}, &AwsSdkClientMock{
+			s3Client:       S3ClientMock{exists: true},
+			dynamodbClient: DynamodbClientMock{calls: &calls},
+		})
+		c.stateDynamodbPitr = enabled
+
+		err := c.CreateStateBackend(context.Background())
+		assert.NoError(t, err)
+
+		var pitr []*dynamodb.UpdateContinuousBackupsInput
+		for _, call := range calls {
+			if in, ok := call.(*dynamodb.UpdateContinuousBackupsInput); ok {
+				pitr = append(pitr, in)
+			}
+		}
+
+		if !enabled {
+			assert.Empty(t, pitr, "point-in-time recovery should be skipped when disabled")
+			continue
+		}
+
+		assert.Len(t, pitr, 1, "point-in-time recovery should be enabled")
+		assert.Equal(t, "testcluster-state-us-west-1-lock", *pitr[0].TableName)
+		assert.True(t, *pitr[0].PointInTimeRecoverySpecification.PointInTimeRecoveryEnabled)
+	}
+}
+
 func TestAwsClient_CheckConfig(t *testing.T) {
 	client := AwsClient{
 		cfg:    aws.Config{Region: "us-west-2"},
@@ -257,6 +895,33 @@ func TestAwsClient_CheckConfig(t *testing.T) {
 	assert.Error(t, err, "CheckConfig should return an error if aws.Config.Region is not set")
 }
 
+func TestAwsClient_CheckConfigRegion(t *testing.T) {
+	client := AwsClient{
+		cfg:    aws.Config{Region: "us-east-1"},
+		region: "us-east-1",
+	}
+	assert.NoError(t, client.CheckConfig(), "CheckConfig should accept a known region")
+
+	client.cfg.Region = "us-east-11"
+	client.region = "us-east-11"
+	assert.NoError(t, client.CheckConfig(), "CheckConfig should only warn about a region unknown to the sdk")
+
+	client.skipRegionValidation = true
+	assert.NoError(t, client.CheckConfig(), "CheckConfig should allow an unknown region when validation is skipped")
+
+	client = AwsClient{
+		cfg:    aws.Config{Region: "us-gov-west-1"},
+		region: "us-gov-west-1",
+	}
+	assert.NoError(t, client.CheckConfig(), "CheckConfig should accept a gov-cloud region")
+
+	client = AwsClient{
+		cfg:    aws.Config{Region: "us-east-1"},
+		region: "eu-nowhere-1",
+	}
+	assert.NoError(t, client.CheckConfig(), "CheckConfig should only warn about a region unknown to the sdk")
+}
+
 func TestAwsClient_StateBackendInfo(t *testing.T) {
 	client := AwsClient{
 		id:     "test-cluster",
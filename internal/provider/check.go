@@ -16,9 +16,10 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"reflect"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +29,10 @@ import (
 
 var (
 	lock = &sync.Mutex{}
+
+	// KnownProviderNames lists the provider names accepted when filtering checks, the providers
+	// yielded by ProviderFactory.Providers. Kubernetes isn't checked before install so isn't included.
+	KnownProviderNames = []string{"aws", "cloudflare", "gitea", "github", "ironbank", "local"}
 )
 
 // IProviderCheckResult defines the interface for provider check results.
@@ -36,74 +41,210 @@ type ProviderCheckResult interface {
 	ToTable() ([]string, []ProviderCheckResultRow)
 }
 
+// CheckSeverity is the severity of a provider check result row.
+type CheckSeverity string
+
+const (
+	SeverityOk    CheckSeverity = "ok"    // the check passed
+	SeverityWarn  CheckSeverity = "warn"  // the check passed with an issue worth reporting, doesn't fail the check
+	SeverityError CheckSeverity = "error" // the check failed
+)
+
 // ProviderCheckResultRow represents a single row in the provider check result table.
 type ProviderCheckResultRow struct {
-	Status bool     // Status indicates whether the check was successful.
-	Data   []string // Data contains the row's data fields.
-	Error  error    // Error contains any error associated with the row.
+	Status   bool          // Status indicates whether the check was successful.
+	Data     []string      // Data contains the row's data fields.
+	Error    error         // Error contains any error associated with the row.
+	Severity CheckSeverity // Severity overrides the severity derived from Status and Error when set.
+}
+
+// Level returns the severity of the row, the explicit Severity if set, otherwise an error
+// for a failed Status, a warning for a successful Status with an Error, or ok.
+func (r ProviderCheckResultRow) Level() CheckSeverity {
+	switch {
+	case r.Severity != "":
+		return r.Severity
+	case !r.Status:
+		return SeverityError
+	case r.Error != nil:
+		return SeverityWarn
+	}
+
+	return SeverityOk
 }
 
 // ProviderCheckOpts contains options for performing provider checks.
 type ProviderCheckOpts struct {
-	checks []Provider // checks is the list of providers to check.
+	checks  []Provider    // checks is the list of providers to check.
+	timeout time.Duration // timeout is the per provider check timeout, 0 waits indefinitely.
 }
 
 // NewProviderCheckOpts creates a new ProviderCheckOpts instance.
-// It initializes the list of providers to check by iterating over the provided factory.
+// It initializes the list of providers to check from the factory's configured providers.
 func NewProviderCheckOpts(ctx context.Context, f ProviderFactory) ProviderCheckOpts {
 	var checks []Provider
 
-	// NOTE: the Kubernetes provider is not included in the checks
-	// as this function is primarily used to check provider configurations
-	// before the platform is installed, making the Kubernetes check irrelevant
-	m := map[string]func(ctx context.Context) (Provider, error){
-		"Cloud":         func(ctx context.Context) (Provider, error) { return f.Cloud(ctx) },
-		"Dns":           func(ctx context.Context) (Provider, error) { return f.Dns(ctx) },
-		"SourceControl": f.SourceControl,
-		"ImageRegistry": f.ImageRegistry,
+	for _, p := range f.Providers(ctx) {
+		checks = append(checks, p)
 	}
 
-	for name, fn := range m {
-		p, err := fn(ctx)
+	return ProviderCheckOpts{
+		checks:  checks,
+		timeout: time.Duration(f.cfg.Providers.CheckTimeoutSeconds) * time.Second,
+	}
+}
 
-		// don't add the same provider twice
-		if slices.ContainsFunc(checks, func(ip Provider) bool {
-			return p != nil && reflect.TypeOf(ip) == reflect.TypeOf(p)
-		}) {
-			continue
-		}
+// Filter limits the checks to providers matching the given names, case-insensitive.
+// No names leaves the checks unchanged. Returns a ConfigError for an unknown provider name.
+func (o *ProviderCheckOpts) Filter(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
 
-		if p != nil {
-			checks = append(checks, p)
-		} else if err != nil {
-			checks = append(checks, NewEmptyProvider(name, err))
+	for _, n := range names {
+		if !slices.Contains(KnownProviderNames, strings.ToLower(n)) {
+			return util.NewConfigErrorf("unknown provider %s, expected one of %s", n, strings.Join(KnownProviderNames, ", "))
 		}
 	}
 
-	return ProviderCheckOpts{
-		checks: checks,
+	o.checks = slices.DeleteFunc(o.checks, func(p Provider) bool {
+		return !slices.ContainsFunc(names, func(n string) bool {
+			return strings.EqualFold(n, p.ProviderName())
+		})
+	})
+
+	return nil
+}
+
+// cachedProvider is implemented by providers which cache access check results between runs.
+type cachedProvider interface {
+	ClearCache() error
+}
+
+// ClearCache discards any cached check results for the selected providers so the next
+// check queries them again.
+func (o *ProviderCheckOpts) ClearCache() error {
+	var errs []error
+	for _, c := range o.checks {
+		if cp, ok := c.(cachedProvider); ok {
+			errs = append(errs, cp.ClearCache())
+		}
 	}
+
+	return errors.Join(errs...)
 }
 
 // Check performs access checks for all providers in the given options.
-// It logs the results and execution statistics.
-func Check(ctx context.Context, opts *ProviderCheckOpts) {
+// It logs the results and execution statistics, returning an AccessError
+// for each provider with a failed check.
+func Check(ctx context.Context, opts *ProviderCheckOpts) error {
 	start := time.Now()
 
 	wg := sync.WaitGroup{}
 	wg.Add(len(opts.checks))
 
-	for _, c := range opts.checks {
-		go func(ic Provider) {
+	errs := make([]error, len(opts.checks))
+
+	for i, c := range opts.checks {
+		go func(i int, ic Provider) {
 			defer wg.Done()
-			res := ic.CheckAccess(ctx)
+			res := checkAccessWithTimeout(ctx, ic, opts.timeout)
 			printTable(ic.ProviderName(), res)
-		}(c)
+			errs[i] = checkResultError(ic.ProviderName(), res)
+		}(i, c)
 	}
 
 	wg.Wait()
 
 	log.Debug("Check stats", "start", start, "duration", time.Since(start))
+
+	return errors.Join(errs...)
+}
+
+// checkAccessWithTimeout runs the provider's access check, reporting a timeout error row
+// if it doesn't complete within the timeout. A zero timeout waits for the check to complete.
+func checkAccessWithTimeout(ctx context.Context, p Provider, timeout time.Duration) ProviderCheckResult {
+	if timeout <= 0 {
+		return p.CheckAccess(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// buffered so an abandoned check can still complete without blocking
+	done := make(chan ProviderCheckResult, 1)
+	go func() {
+		done <- p.CheckAccess(ctx)
+	}()
+
+	select {
+	case res := <-done:
+		return res
+	case <-ctx.Done():
+		log.Debug("Provider check timed out", "provider", p.ProviderName(), "timeout", timeout)
+		return EmptyProviderCheckResult{Error: fmt.Errorf("check timed out after %s", timeout)}
+	}
+}
+
+// appRepoProvisioningChecker is implemented by source control providers which can check the
+// webhooks and deploy keys of application repositories.
+type appRepoProvisioningChecker interface {
+	CheckAppRepoProvisioning(ctx context.Context) ProviderCheckResult
+}
+
+// CheckAppRepos checks the application repositories of the source control provider have the
+// expected webhooks and deploy keys, printing the results and returning an AccessError if any
+// are missing.
+func CheckAppRepos(ctx context.Context, sc Provider) error {
+	c, ok := sc.(appRepoProvisioningChecker)
+	if !ok {
+		return util.NewConfigErrorf("application repository check not supported for %s", sc.ProviderName())
+	}
+
+	res := c.CheckAppRepoProvisioning(ctx)
+	if _, rows := res.ToTable(); len(rows) == 0 {
+		util.Infof("%s application repositories: nothing to check", sc.ProviderName())
+		return nil
+	}
+
+	printTable(sc.ProviderName()+" application repositories", res)
+	return checkResultError(sc.ProviderName(), res)
+}
+
+// checkResultError returns an AccessError joining every failed row in the check result,
+// rows with warnings don't fail the check. Failed rows without an error are reported by
+// their first column.
+func checkResultError(providerName string, r ProviderCheckResult) error {
+	_, rows := r.ToTable()
+
+	var errs []any
+	for _, v := range rows {
+		if v.Level() != SeverityError {
+			continue
+		}
+
+		switch {
+		case v.Error != nil:
+			errs = append(errs, v.Error)
+		case len(v.Data) > 0 && v.Data[0] != "":
+			errs = append(errs, fmt.Errorf("%s check failed", v.Data[0]))
+		default:
+			errs = append(errs, fmt.Errorf("check failed"))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// wrapped rather than joined so the message stays on one line
+	format := strings.TrimSuffix(strings.Repeat("%w; ", len(errs)), "; ")
+	return util.NewAccessError(providerName, fmt.Errorf(format, errs...))
+}
+
+// PrintResultTable prints the result as a titled status table, as the provider checks do.
+func PrintResultTable(title string, r ProviderCheckResult) {
+	printTable(title, r)
 }
 
 // printTable formats and prints the provider check results as a table.
@@ -154,12 +295,12 @@ func printTable(providerName string, r ProviderCheckResult) {
 	lock.Lock()
 	defer lock.Unlock()
 
-	util.Msg(providerName)
+	util.Info(providerName)
 	util.PrintRowStatusTable(headers, rs, func(i int, row []string) util.RowStatus {
-		v := rows[i]
-		if !v.Status {
+		switch rows[i].Level() {
+		case SeverityError:
 			return util.StatusError
-		} else if v.Error != nil {
+		case SeverityWarn:
 			return util.StatusWarning
 		}
 
@@ -16,10 +16,15 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
 )
 
 type TestProviderCheckResult struct {
@@ -50,8 +55,162 @@ func TestProviderCheck(t *testing.T) {
 		dnsProviderClient: NewEmptyProvider("testdns", fmt.Errorf("testing")),
 	})
 
-	// TODO: have this return something or take a callback so we can make assertions
+	err := Check(context.Background(), &opts)
+
+	var accessErr util.AccessError
+	if !errors.As(err, &accessErr) {
+		t.Errorf("expected access error from failed dns check, found %v", err)
+		return
+	}
+
+	if !strings.Contains(err.Error(), "testdns access failed") {
+		t.Errorf("expected access error for testdns provider, found %v", err)
+	}
+}
+
+func TestProviderCheckEmptyProvider(t *testing.T) {
+	opts := ProviderCheckOpts{
+		checks: []Provider{NewEmptyProvider("test", nil)},
+	}
+
+	// empty provider always reports a failed status
+	err := Check(context.Background(), &opts)
+	if err == nil {
+		t.Error("expected error from empty provider check")
+	}
+
+	err = Check(context.Background(), &ProviderCheckOpts{})
+	if err != nil {
+		t.Errorf("unexpected error with no checks, %v", err)
+	}
+}
+
+func TestProviderCheckFilter(t *testing.T) {
+	aws := testCheckProvider{name: "AWS", checked: &atomic.Int32{}}
+	github := testCheckProvider{name: "Github", checked: &atomic.Int32{}}
+	k8s := testCheckProvider{name: "Kubernetes", checked: &atomic.Int32{}}
+
+	opts := ProviderCheckOpts{
+		checks: []Provider{aws, github, k8s},
+	}
+
+	err := opts.Filter([]string{"github"})
+	if err != nil {
+		t.Fatalf("unexpected error filtering provider checks, %v", err)
+	}
+
+	// test providers report an empty result, only the providers checked matter here
 	Check(context.Background(), &opts)
+
+	if github.checked.Load() != 1 {
+		t.Errorf("expected github check to run once, found %d", github.checked.Load())
+	}
+
+	if aws.checked.Load() != 0 || k8s.checked.Load() != 0 {
+		t.Errorf("expected aws and kubernetes checks to be skipped, found %d and %d", aws.checked.Load(), k8s.checked.Load())
+	}
+}
+
+type testSlowCheckProvider struct {
+	name  string
+	delay time.Duration
+}
+
+func (p testSlowCheckProvider) ProviderName() string {
+	return p.name
+}
+
+func (p testSlowCheckProvider) CheckAccess(context.Context) ProviderCheckResult {
+	time.Sleep(p.delay)
+	return TestProviderCheckResult{rows: []ProviderCheckResultRow{{Status: true}}}
+}
+
+func TestProviderCheckTimeout(t *testing.T) {
+	opts := ProviderCheckOpts{
+		checks: []Provider{
+			testSlowCheckProvider{name: "slow", delay: 2 * time.Second},
+			testSlowCheckProvider{name: "fast"},
+		},
+		timeout: 50 * time.Millisecond,
+	}
+
+	start := time.Now()
+	err := Check(context.Background(), &opts)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected check to return after the timeout, took %s", elapsed)
+	}
+
+	var accessErr util.AccessError
+	if !errors.As(err, &accessErr) {
+		t.Fatalf("expected access error from timed out check, found %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "slow access failed") || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error for slow provider, found %v", err)
+	}
+
+	if strings.Contains(err.Error(), "fast") {
+		t.Errorf("unexpected error for fast provider, %v", err)
+	}
+}
+
+func TestProviderCheckAccessWithTimeout(t *testing.T) {
+	res := checkAccessWithTimeout(context.Background(), testSlowCheckProvider{name: "test", delay: 10 * time.Millisecond}, 0)
+	if err := checkResultError("test", res); err != nil {
+		t.Errorf("unexpected error with timeout disabled, %v", err)
+	}
+
+	res = checkAccessWithTimeout(context.Background(), testSlowCheckProvider{name: "test", delay: 10 * time.Millisecond}, time.Second)
+	if err := checkResultError("test", res); err != nil {
+		t.Errorf("unexpected error within timeout, %v", err)
+	}
+
+	res = checkAccessWithTimeout(context.Background(), testSlowCheckProvider{name: "test", delay: time.Second}, 10*time.Millisecond)
+	if err := checkResultError("test", res); err == nil || !strings.Contains(err.Error(), "check timed out after 10ms") {
+		t.Errorf("expected timeout error, found %v", err)
+	}
+}
+
+func TestProviderCheckResultErrorJoined(t *testing.T) {
+	rowErr := errors.New("token expired")
+	res := TestProviderCheckResult{rows: []ProviderCheckResultRow{
+		{Status: false, Error: rowErr, Data: []string{"first"}},
+		{Status: true, Data: []string{"ok"}},
+		{Status: false, Data: []string{"second"}},
+		{Status: true, Error: errors.New("just a warning"), Data: []string{"third"}},
+	}}
+
+	err := checkResultError("test", res)
+	if err == nil || err.Error() != "test access failed, token expired; second check failed" {
+		t.Errorf("expected every failed row in the error, found %v", err)
+	}
+
+	if !errors.Is(err, rowErr) {
+		t.Errorf("expected the row error to be wrapped, found %v", err)
+	}
+}
+
+func TestProviderCheckFilterUnknown(t *testing.T) {
+	opts := ProviderCheckOpts{
+		checks: []Provider{NewEmptyProvider("test", nil)},
+	}
+
+	err := opts.Filter([]string{"github", "nope"})
+
+	var cfgErr util.ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("expected config error for unknown provider, found %v", err)
+	}
+
+	// kubernetes isn't part of the provider checks
+	err = opts.Filter([]string{"kubernetes"})
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("expected config error for the kubernetes provider, found %v", err)
+	}
+
+	if len(opts.checks) != 1 {
+		t.Errorf("expected checks to be unchanged after invalid filter, found %v", opts.checks)
+	}
 }
 
 func TestProviderCheckPrintTableEmpty(t *testing.T) {
@@ -77,3 +236,74 @@ func TestProviderCheckPrintTablePadded(t *testing.T) {
 
 	printTable(name, res)
 }
+
+// testResultCheckProvider reports a fixed check result.
+type testResultCheckProvider struct {
+	name string
+	res  ProviderCheckResult
+}
+
+func (p testResultCheckProvider) ProviderName() string {
+	return p.name
+}
+
+func (p testResultCheckProvider) CheckAccess(context.Context) ProviderCheckResult {
+	return p.res
+}
+
+func TestProviderCheckResultRowLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      ProviderCheckResultRow
+		expected CheckSeverity
+	}{
+		{"ok", ProviderCheckResultRow{Status: true}, SeverityOk},
+		{"failed", ProviderCheckResultRow{Status: false}, SeverityError},
+		{"passed with error", ProviderCheckResultRow{Status: true, Error: fmt.Errorf("optional")}, SeverityWarn},
+		{"explicit warn", ProviderCheckResultRow{Status: false, Error: fmt.Errorf("optional"), Severity: SeverityWarn}, SeverityWarn},
+		{"explicit error", ProviderCheckResultRow{Status: true, Severity: SeverityError}, SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.row.Level(); actual != tt.expected {
+				t.Errorf("unexpected check row severity, expected %v, found %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProviderCheckWarningsDoNotFail(t *testing.T) {
+	opts := ProviderCheckOpts{
+		checks: []Provider{testResultCheckProvider{
+			name: "test",
+			res: TestProviderCheckResult{
+				headers: []string{"col1"},
+				rows: []ProviderCheckResultRow{
+					{Data: []string{"ok"}, Status: true},
+					{Data: []string{"warn"}, Status: true, Error: fmt.Errorf("missing optional scope")},
+					{Data: []string{"explicit warn"}, Error: fmt.Errorf("missing optional scope"), Severity: SeverityWarn},
+				},
+			},
+		}},
+	}
+
+	err := Check(context.Background(), &opts)
+	if err != nil {
+		t.Errorf("unexpected error from check with only warnings, %v", err)
+	}
+
+	opts.checks = append(opts.checks, testResultCheckProvider{
+		name: "failing",
+		res: TestProviderCheckResult{
+			rows: []ProviderCheckResultRow{
+				{Data: []string{"error"}, Status: true, Error: fmt.Errorf("denied"), Severity: SeverityError},
+			},
+		},
+	})
+
+	err = Check(context.Background(), &opts)
+	if err == nil || !strings.Contains(err.Error(), "failing access failed") {
+		t.Errorf("expected access error from check with an error row, found %v", err)
+	}
+}
@@ -17,7 +17,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/user"
 	"strings"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 )
@@ -37,12 +40,85 @@ type CloudProviderClient interface {
 	DestroyStateBackend(ctx context.Context) error
 	// KubeconfigInfo retrieves the kubeconfig information for the cloud provider.
 	KubeconfigInfo(ctx context.Context) (KubeconfigInfo, error)
+	// KubeconfigUserInfo returns the kubeconfig user auth block (e.g. an exec plugin) for the cloud provider,
+	// or nil to use the static token from KubeconfigInfo.
+	KubeconfigUserInfo(cfg schema.QuartzConfig, kc KubeconfigInfo) *schema.KubeconfigUserInfo
 	// PrintConfig prints the cloud provider configuration.
 	PrintConfig()
 	// PrintClusterInfo prints information about the cloud provider's cluster.
 	PrintClusterInfo(ctx context.Context) error
 	// PrepareAccount prepares the cloud provider account for use.
 	PrepareAccount(ctx context.Context) error
+	// AcquireRunLock acquires the cluster-wide lock for a quartz run, failing if another run holds it.
+	AcquireRunLock(ctx context.Context, operation string) error
+	// ReleaseRunLock releases the cluster-wide lock acquired by AcquireRunLock.
+	ReleaseRunLock(ctx context.Context) error
+	// ReconcileAdministrators grants the cloud principals among the administrators admin access to the cluster,
+	// returning the principals which were newly granted access.
+	ReconcileAdministrators(ctx context.Context, administrators []string) ([]string, error)
+}
+
+// KubeconfigUserInfoFunc supplies the kubeconfig user auth block for a cluster.
+// Returning nil falls back to the static token from KubeconfigInfo.
+type KubeconfigUserInfoFunc func(cfg schema.QuartzConfig, kc KubeconfigInfo) *schema.KubeconfigUserInfo
+
+// defaultKubeconfigUserInfo returns the user auth strategy for the named cloud provider,
+// used when the KubeconfigInfo was not populated by a cloud provider client.
+func defaultKubeconfigUserInfo(provider string) KubeconfigUserInfoFunc {
+	switch strings.ToLower(provider) {
+	case "aws":
+		return awsKubeconfigUserInfo
+	}
+
+	return nil
+}
+
+// RunLockError is returned when another quartz run already holds the cluster lock.
+type RunLockError struct {
+	Lock      string // The lock identifier (e.g., table item key or file path).
+	Operation string // The operation of the run holding the lock.
+	Who       string // The user and host of the run holding the lock.
+	Created   string // When the lock was acquired.
+}
+
+// Error returns the error message for the RunLockError.
+func (e RunLockError) Error() string {
+	return fmt.Sprintf("cluster is locked by another quartz %s run (who: %s, since: %s), if that run is no longer active remove the lock %s",
+		e.Operation, e.Who, e.Created, e.Lock)
+}
+
+// runLockInfo holds the details recorded with a run lock.
+type runLockInfo struct {
+	Operation string `json:"operation"`
+	Who       string `json:"who"`
+	Created   string `json:"created"`
+}
+
+// newRunLockInfo creates the run lock details for the current process.
+func newRunLockInfo(operation string) runLockInfo {
+	who := "unknown"
+	if u, err := user.Current(); err == nil {
+		who = u.Username
+	}
+	if h, err := os.Hostname(); err == nil {
+		who = fmt.Sprintf("%s@%s", who, h)
+	}
+
+	return runLockInfo{
+		Operation: operation,
+		Who:       fmt.Sprintf("%s (pid %d)", who, os.Getpid()),
+		Created:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// lockError converts the run lock details into a RunLockError for the given lock.
+func (i runLockInfo) lockError(lock string) RunLockError {
+	return RunLockError{
+		Lock:      lock,
+		Operation: i.Operation,
+		Who:       i.Who,
+		Created:   i.Created,
+	}
 }
 
 // CloudProviderIdentity represents the identity of a cloud provider account.
@@ -61,19 +137,21 @@ type CloudProviderStateBackend struct {
 
 // CloudProviderClientOpts contains options for creating a cloud provider client.
 type CloudProviderClientOpts struct {
-	Provider string              // The name of the cloud provider (e.g., "aws", "local").
-	Name     string              // The name of the cloud provider client.
-	Region   string              // The region for the cloud provider.
-	cfg      schema.QuartzConfig // The Quartz configuration.
+	Provider             string              // The name of the cloud provider (e.g., "aws", "local").
+	Name                 string              // The name of the cloud provider client.
+	Region               string              // The region for the cloud provider.
+	SkipRegionValidation bool                // Skip warning about regions unknown to the provider.
+	cfg                  schema.QuartzConfig // The Quartz configuration.
 }
 
 // NewCloudProviderClient creates a new cloud provider client using the provided Quartz configuration.
 func NewCloudProviderClient(ctx context.Context, cfg schema.QuartzConfig) (CloudProviderClient, error) {
 	return NewCloudProviderClientWithOpts(ctx, CloudProviderClientOpts{
-		Provider: cfg.Providers.Cloud,
-		Name:     cfg.Name,
-		Region:   cfg.Aws.Region,
-		cfg:      cfg,
+		Provider:             cfg.Providers.Cloud,
+		Name:                 cfg.Name,
+		Region:               cfg.Aws.Region,
+		SkipRegionValidation: cfg.Aws.SkipRegionValidation,
+		cfg:                  cfg,
 	})
 }
 
@@ -83,7 +161,14 @@ func NewCloudProviderClientWithOpts(ctx context.Context, o CloudProviderClientOp
 
 	switch provider {
 	case "aws":
-		return NewLazyAwsClient(ctx, o.Name, o.Region)
+		c, err := NewLazyAwsClientWithEndpoint(ctx, o.Name, o.Region, o.cfg.Aws.EndpointUrl, o.cfg.Aws.S3UsePathStyle)
+		c.skipRegionValidation = o.SkipRegionValidation
+		c.stateEncryption = o.cfg.State.Encryption
+		c.stateKmsKeyArn = o.cfg.State.KmsKeyArn
+		c.stateDynamodbPitr = o.cfg.State.DynamodbPitr
+		c.tags = o.cfg.Aws.Tags
+		c.eksReadyTimeout = time.Duration(o.cfg.Aws.EksReadyTimeoutSeconds) * time.Second
+		return c, err
 
 	case "local":
 		return LocalClient{Name: o.Name}, nil
@@ -14,12 +14,17 @@
 
 package provider
 
-import "context"
+import (
+	"context"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
 
 // TestCloudProviderClient is a mock implementation of the CloudProviderClient interface for testing purposes.
 type TestCloudProviderClient struct {
-	kubeconfig KubeconfigInfo   // Mock kubeconfig information.
-	errs       map[string]error // Mock errors for specific methods.
+	kubeconfig KubeconfigInfo             // Mock kubeconfig information.
+	userInfo   *schema.KubeconfigUserInfo // Mock kubeconfig user auth block.
+	errs       map[string]error           // Mock errors for specific methods.
 }
 
 // TestCloudProviderCheckResult is a mock implementation of the ProviderCheckResult interface for testing purposes.
@@ -79,6 +84,11 @@ func (c TestCloudProviderClient) KubeconfigInfo(ctx context.Context) (Kubeconfig
 	return c.kubeconfig, c.errs["provider__cloud__KubeconfigInfo"]
 }
 
+// KubeconfigUserInfo returns the mock kubeconfig user auth block for the test cloud provider.
+func (c TestCloudProviderClient) KubeconfigUserInfo(cfg schema.QuartzConfig, kc KubeconfigInfo) *schema.KubeconfigUserInfo {
+	return c.userInfo
+}
+
 // PrintConfig is a placeholder for printing the configuration of the test cloud provider.
 // TODO: Implement this method.
 func (c TestCloudProviderClient) PrintConfig() {
@@ -98,6 +108,24 @@ func (c TestCloudProviderClient) PrepareAccount(ctx context.Context) error {
 	return c.errs["provider__cloud__PrepareAccount"]
 }
 
+// AcquireRunLock performs a mock run lock acquisition for the test cloud provider.
+// Returns a mock error if configured.
+func (c TestCloudProviderClient) AcquireRunLock(ctx context.Context, operation string) error {
+	return c.errs["provider__cloud__AcquireRunLock"]
+}
+
+// ReleaseRunLock performs a mock run lock release for the test cloud provider.
+// Returns a mock error if configured.
+func (c TestCloudProviderClient) ReleaseRunLock(ctx context.Context) error {
+	return c.errs["provider__cloud__ReleaseRunLock"]
+}
+
+// ReconcileAdministrators performs a mock administrator reconciliation for the test cloud provider.
+// Returns a mock error if configured.
+func (c TestCloudProviderClient) ReconcileAdministrators(ctx context.Context, administrators []string) ([]string, error) {
+	return nil, c.errs["provider__cloud__ReconcileAdministrators"]
+}
+
 // ToTable converts the TestCloudProviderCheckResult into table headers and rows for display.
 // Always returns empty headers and rows.
 func (r TestCloudProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow) {
@@ -16,9 +16,14 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
-	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/MetroStar/quartzctl/internal/log"
 )
 
 // ProviderFactory is responsible for creating and managing provider clients.
@@ -30,6 +35,7 @@ type ProviderFactory struct {
 	dnsProviderClient   DnsProviderClient        // The DNS provider client.
 	scProviderClient    Provider                 // The source control provider client.
 	imgProviderClient   Provider                 // The image registry provider client.
+	ironbankClient      Provider                 // The Ironbank registry credentials provider client.
 	k8sClient           KubernetesProviderClient // The Kubernetes provider client.
 }
 
@@ -57,12 +63,6 @@ func NewProviderFactory(cfg schema.QuartzConfig, secrets schema.QuartzSecrets, o
 	return factory
 }
 
-// NewSourceControlProviderClient creates a new source control provider client.
-func NewSourceControlProviderClient(ctx context.Context, cfg schema.QuartzConfig, secrets schema.QuartzSecrets) (Provider, error) {
-	p, err := NewGithubClient(util.NewHttpClientFactory(), "Github", cfg, secrets.Github)
-	return p, err
-}
-
 // Kubernetes returns the Kubernetes provider client, initializing it if necessary.
 func (f *ProviderFactory) Kubernetes(ctx context.Context) (KubernetesProviderClient, error) {
 	if f.k8sClient != nil {
@@ -76,8 +76,16 @@ func (f *ProviderFactory) Kubernetes(ctx context.Context) (KubernetesProviderCli
 
 	i, err := cp.KubeconfigInfo(ctx)
 	if err != nil {
-		return nil, err
+		// only use the in-cluster service account when there's no cloud cluster to target at all, never when
+		// looking up the cloud cluster fails, or a transient error would silently act on the pod's own cluster
+		if !errors.Is(err, ErrNoCloudCluster) || !InCluster() {
+			return nil, err
+		}
+
+		log.Debug("No cloud cluster configured, using in-cluster config", "provider", cp.ProviderName())
+		i = KubeconfigInfo{}
 	}
+	i.UserInfo = cp.KubeconfigUserInfo
 
 	api, err := NewKubernetesApi(ctx, f.cfg, &i)
 	if err != nil {
@@ -153,6 +161,109 @@ func (f *ProviderFactory) ImageRegistry(ctx context.Context) (Provider, error) {
 	return f.imgProviderClient, nil
 }
 
+// Ironbank returns the Ironbank registry credentials provider client, initializing it if necessary.
+// Returns nil if no ironbank credentials are configured.
+func (f *ProviderFactory) Ironbank(ctx context.Context) (Provider, error) {
+	if f.ironbankClient != nil {
+		return f.ironbankClient, nil
+	}
+
+	c, err := NewIronbankProviderClient(ctx, f.secrets)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	f.ironbankClient = c
+	return f.ironbankClient, nil
+}
+
+// Providers returns an iterator over every configured provider that supports access checks,
+// keyed by the provider kind (e.g. Cloud, Dns). Providers are initialized as needed, a provider
+// that fails to initialize is yielded as an EmptyProvider named after the configured provider
+// carrying the error, and a provider implementation serving more than one kind is only yielded once.
+//
+// NOTE: the Kubernetes provider is not included as this is primarily used to check
+// provider configurations before the platform is installed.
+func (f *ProviderFactory) Providers(ctx context.Context) iter.Seq2[string, Provider] {
+	getters := []struct {
+		name     string
+		provider string // the configured provider's name, matching its client's ProviderName
+		get      func(ctx context.Context) (Provider, error)
+	}{
+		{"Cloud", configuredProviderName(f.cfg.Providers.Cloud), func(ctx context.Context) (Provider, error) { return f.Cloud(ctx) }},
+		{"Dns", configuredProviderName(f.cfg.Providers.Dns), func(ctx context.Context) (Provider, error) { return f.Dns(ctx) }},
+		{"SourceControl", f.sourceControlProviderName(), f.SourceControl},
+		{"ImageRegistry", f.imageRegistryProviderName(), f.ImageRegistry},
+		{"Ironbank", "Ironbank", f.Ironbank},
+	}
+
+	return func(yield func(string, Provider) bool) {
+		var seen []reflect.Type
+
+		for _, g := range getters {
+			p, err := g.get(ctx)
+
+			if p == nil {
+				if err == nil {
+					// not configured, nothing to check
+					continue
+				}
+
+				// named after the provider so it can still be filtered by name
+				p = NewEmptyProvider(g.provider, err)
+			} else {
+				// don't yield the same provider twice
+				t := reflect.TypeOf(p)
+				if slices.Contains(seen, t) {
+					continue
+				}
+				seen = append(seen, t)
+			}
+
+			if !yield(g.name, p) {
+				return
+			}
+		}
+	}
+}
+
+// providerNames maps the configured provider names to the names their clients report.
+var providerNames = map[string]string{
+	"aws":        AWS_PROVIDER,
+	"local":      "Local",
+	"cloudflare": "Cloudflare",
+	"github":     "Github",
+	"gitea":      "Gitea",
+}
+
+// configuredProviderName returns the name the client for a configured provider reports,
+// or the configured value itself for an unsupported provider.
+func configuredProviderName(provider string) string {
+	if n, ok := providerNames[strings.ToLower(provider)]; ok {
+		return n
+	}
+
+	return provider
+}
+
+// sourceControlProviderName returns the name of the configured source control provider, GitHub unless Gitea is configured.
+func (f *ProviderFactory) sourceControlProviderName() string {
+	if strings.EqualFold(f.cfg.Providers.SourceControl, "gitea") {
+		return "Gitea"
+	}
+
+	return "Github"
+}
+
+// imageRegistryProviderName returns the name of the image registry provider, GitHub when images are mirrored, otherwise Ironbank.
+func (f *ProviderFactory) imageRegistryProviderName() string {
+	if f.cfg.Mirror.ImageRepository.Enabled {
+		return "Github"
+	}
+
+	return "Ironbank"
+}
+
 // WithConfig sets the Quartz configuration and returns the updated factory.
 func WithConfig(c schema.QuartzConfig) ProviderFactoryOption {
 	return func(f *ProviderFactory) {
@@ -195,6 +306,13 @@ func WithImageRegistryProvider(p Provider) ProviderFactoryOption {
 	}
 }
 
+// WithIronbankProvider sets the Ironbank registry credentials provider client and returns the updated factory.
+func WithIronbankProvider(p Provider) ProviderFactoryOption {
+	return func(f *ProviderFactory) {
+		f.ironbankClient = p
+	}
+}
+
 // WithKubernetesProvider sets the Kubernetes provider client and returns the updated factory.
 func WithKubernetesProvider(p KubernetesProviderClient) ProviderFactoryOption {
 	return func(f *ProviderFactory) {
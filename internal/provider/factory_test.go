@@ -17,6 +17,11 @@ package provider
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
@@ -53,6 +58,22 @@ func TestProviderFactoryLoadSourceControl(t *testing.T) {
 	t.Logf("source control provider -> %v", sc)
 }
 
+func TestProviderFactoryLoadSourceControlGitea(t *testing.T) {
+	f := newTestProviderFactory()
+	f.cfg.Providers.SourceControl = "gitea"
+	f.cfg.Gitea = schema.GiteaConfig{Url: "https://gitea.example.com"}
+	f.secrets.Gitea = schema.GiteaCredentials{Username: "test-user", Token: "supersecrettoken"}
+
+	sc, err := f.SourceControl(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error in provider factory load gitea source control, %v", err)
+	}
+
+	if sc.ProviderName() != "Gitea" {
+		t.Errorf("unexpected source control provider, expected %v, found %v", "Gitea", sc.ProviderName())
+	}
+}
+
 func TestProviderFactoryLoadImageRegistry(t *testing.T) {
 	f := newTestProviderFactory()
 	ir, err := f.ImageRegistry(context.Background())
@@ -83,6 +104,193 @@ func TestProviderFactoryLoadKubernetes(t *testing.T) {
 	t.Logf("kubernetes provider -> %v", k8s)
 }
 
+func TestProviderFactoryLoadKubernetesInCluster(t *testing.T) {
+	withInCluster(t, "https://in-cluster")
+
+	// a failed cloud cluster lookup must not fall back to the pod's own cluster
+	f := newTestProviderFactory()
+	f.cloudProviderClient = TestCloudProviderClient{
+		errs: map[string]error{"provider__cloud__KubeconfigInfo": fmt.Errorf("simulating throttled describe cluster")},
+	}
+
+	_, err := f.Kubernetes(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("expected the kubeconfig lookup error in-cluster, found %v", err)
+	}
+
+	// without a cloud cluster the in-cluster service account is used
+	f = newTestProviderFactory()
+	f.cloudProviderClient = LocalClient{Name: "testcluster"}
+
+	k8s, err := f.Kubernetes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error in provider factory load in-cluster kubernetes, %v", err)
+	}
+
+	if k8s == nil {
+		t.Errorf("expected an in-cluster kubernetes provider")
+	}
+
+	// outside a cluster the local provider has no kubernetes cluster
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	f = newTestProviderFactory()
+	f.cloudProviderClient = LocalClient{Name: "testcluster"}
+
+	_, err = f.Kubernetes(context.Background())
+	if !errors.Is(err, ErrNoCloudCluster) {
+		t.Errorf("expected a no cloud cluster error outside the cluster, found %v", err)
+	}
+}
+
+func TestProviderFactoryLoadIronbank(t *testing.T) {
+	f := NewProviderFactory(schema.QuartzConfig{}, schema.QuartzSecrets{})
+	ib, err := f.Ironbank(context.Background())
+	if err != nil || ib != nil {
+		t.Errorf("expected no ironbank provider without credentials, found %v, %v", ib, err)
+	}
+
+	f = NewProviderFactory(schema.QuartzConfig{}, schema.QuartzSecrets{
+		Ironbank: schema.IronbankCredentials{Username: "testuser", Password: "supersecretpassword"},
+	})
+	ib, err = f.Ironbank(context.Background())
+	if err != nil || ib == nil || ib.ProviderName() != "Ironbank" {
+		t.Errorf("unexpected ironbank provider, %v, %v", ib, err)
+	}
+
+	f = NewProviderFactory(schema.QuartzConfig{}, schema.QuartzSecrets{
+		Ironbank: schema.IronbankCredentials{Username: "testuser"},
+	})
+	ib, err = f.Ironbank(context.Background())
+	if err == nil || ib != nil {
+		t.Errorf("expected error for incomplete ironbank credentials, found %v, %v", ib, err)
+	}
+}
+
+type testCheckProvider struct {
+	name    string
+	checked *atomic.Int32
+}
+
+func (p testCheckProvider) ProviderName() string {
+	return p.name
+}
+
+func (p testCheckProvider) CheckAccess(context.Context) ProviderCheckResult {
+	p.checked.Add(1)
+	return EmptyProviderCheckResult{}
+}
+
+// testOtherCheckProvider is a distinct provider type so it isn't deduplicated with testCheckProvider
+type testOtherCheckProvider struct {
+	testCheckProvider
+}
+
+func TestProviderFactoryProviders(t *testing.T) {
+	sc := testCheckProvider{name: "testsc", checked: &atomic.Int32{}}
+	img := testOtherCheckProvider{testCheckProvider{name: "testimg", checked: &atomic.Int32{}}}
+
+	f := NewProviderFactory(schema.QuartzConfig{
+		Providers: schema.ProvidersConfig{
+			Cloud: "local",
+		},
+	}, schema.QuartzSecrets{},
+		WithSourceControlProvider(sc),
+		WithImageRegistryProvider(img))
+
+	var kinds []string
+	for kind := range f.Providers(context.Background()) {
+		kinds = append(kinds, kind)
+	}
+
+	// dns isn't configured so it's skipped
+	expected := []string{"Cloud", "SourceControl", "ImageRegistry"}
+	if !slices.Equal(expected, kinds) {
+		t.Errorf("unexpected providers, expected %v, found %v", expected, kinds)
+	}
+
+	opts := NewProviderCheckOpts(context.Background(), *f)
+	Check(context.Background(), &opts)
+
+	if c := sc.checked.Load(); c != 1 {
+		t.Errorf("expected source control provider to be checked once, found %d", c)
+	}
+
+	if c := img.checked.Load(); c != 1 {
+		t.Errorf("expected image registry provider to be checked once, found %d", c)
+	}
+}
+
+func TestProviderFactoryProvidersInitErrorFilter(t *testing.T) {
+	sc := testCheckProvider{name: "testsc", checked: &atomic.Int32{}}
+
+	// cloudflare without credentials fails to initialize
+	f := NewProviderFactory(schema.QuartzConfig{
+		Providers: schema.ProvidersConfig{
+			Cloud: "local",
+			Dns:   "cloudflare",
+		},
+	}, schema.QuartzSecrets{},
+		WithSourceControlProvider(sc),
+		WithImageRegistryProvider(sc))
+
+	opts := NewProviderCheckOpts(context.Background(), *f)
+	if err := opts.Filter([]string{"cloudflare"}); err != nil {
+		t.Fatalf("unexpected error filtering providers, %v", err)
+	}
+
+	if len(opts.checks) != 1 {
+		t.Fatalf("expected the failed provider to be filtered by its name, found %v", opts.checks)
+	}
+
+	p, ok := opts.checks[0].(EmptyProvider)
+	if !ok || p.ProviderName() != "Cloudflare" || p.Error == nil {
+		t.Errorf("expected an empty cloudflare provider carrying the init error, found %v", opts.checks[0])
+	}
+}
+
+func TestProviderFactoryProvidersIronbank(t *testing.T) {
+	sc := testCheckProvider{name: "testsc", checked: &atomic.Int32{}}
+	img := testOtherCheckProvider{testCheckProvider{name: "testimg", checked: &atomic.Int32{}}}
+
+	f := NewProviderFactory(schema.QuartzConfig{},
+		schema.QuartzSecrets{Ironbank: schema.IronbankCredentials{Username: "testuser", Password: "supersecretpassword"}},
+		WithCloudProvider(NewTestCloudProviderClient()),
+		WithSourceControlProvider(sc),
+		WithImageRegistryProvider(img))
+
+	var kinds []string
+	for kind := range f.Providers(context.Background()) {
+		kinds = append(kinds, kind)
+	}
+
+	// ironbank credentials are checked even when the image registry is a mirror
+	expected := []string{"Cloud", "SourceControl", "ImageRegistry", "Ironbank"}
+	if !slices.Equal(expected, kinds) {
+		t.Errorf("unexpected providers, expected %v, found %v", expected, kinds)
+	}
+}
+
+func TestProviderFactoryProvidersDeduplicated(t *testing.T) {
+	sc := testCheckProvider{name: "testsc", checked: &atomic.Int32{}}
+	img := testCheckProvider{name: "testimg", checked: &atomic.Int32{}}
+
+	f := NewProviderFactory(schema.QuartzConfig{},
+		schema.QuartzSecrets{},
+		WithCloudProvider(NewTestCloudProviderClient()),
+		WithSourceControlProvider(sc),
+		WithImageRegistryProvider(img))
+
+	var names []string
+	for _, p := range f.Providers(context.Background()) {
+		names = append(names, p.ProviderName())
+	}
+
+	expected := []string{NewTestCloudProviderClient().ProviderName(), "testsc"}
+	if !slices.Equal(expected, names) {
+		t.Errorf("unexpected providers, expected %v, found %v", expected, names)
+	}
+}
+
 func newTestProviderFactory() *ProviderFactory {
 	f := &ProviderFactory{
 		cfg: schema.QuartzConfig{
@@ -0,0 +1,234 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// GiteaClient represents a client for interacting with the Gitea API.
+type GiteaClient struct {
+	providerName string                  // The name of the provider.
+	baseUrl      string                  // The base url of the Gitea instance.
+	cfg          schema.QuartzConfig     // The Quartz configuration.
+	creds        schema.GiteaCredentials // The Gitea credentials.
+	httpClient   util.HttpClientFactory  // The HTTP client factory for making requests.
+}
+
+// GiteaCheckAccessResult represents the result of a Gitea repository access check.
+type GiteaCheckAccessResult struct {
+	Organization string // The organization name.
+	Repository   string // The repository name.
+	Error        error  // Any error encountered during the access check.
+
+	Name  string // The full name of the repository.
+	Pull  bool   // Indicates if the user has pull access.
+	Push  bool   // Indicates if the user has push access.
+	Admin bool   // Indicates if the user has admin access.
+}
+
+// GiteaProviderCheckResult represents the result of a Gitea provider check.
+type GiteaProviderCheckResult struct {
+	Status  bool                     // Indicates if the check was successful.
+	Results []GiteaCheckAccessResult // The results of the access checks.
+	Error   error                    // Any error encountered during the check.
+}
+
+// giteaRepository is the subset of the Gitea repository api response used for access checks.
+type giteaRepository struct {
+	FullName    string `json:"full_name"`
+	Permissions struct {
+		Admin bool `json:"admin"`
+		Push  bool `json:"push"`
+		Pull  bool `json:"pull"`
+	} `json:"permissions"`
+}
+
+// NewGiteaClient creates a new Gitea client with the specified configuration and credentials.
+// Returns an error if the credentials or the Gitea url are missing.
+func NewGiteaClient(httpClient util.HttpClientFactory, providerName string, cfg schema.QuartzConfig, creds schema.GiteaCredentials) (GiteaClient, error) {
+	if creds.Token == "" {
+		return GiteaClient{}, fmt.Errorf("gitea credentials not found")
+	}
+
+	if cfg.Gitea.Url == "" {
+		return GiteaClient{}, fmt.Errorf("gitea.url required")
+	}
+
+	if providerName == "" {
+		providerName = "Gitea"
+	}
+
+	return GiteaClient{
+		providerName: providerName,
+		baseUrl:      strings.TrimSuffix(cfg.Gitea.Url, "/"),
+		cfg:          cfg,
+		creds:        creds,
+		httpClient:   httpClient,
+	}, nil
+}
+
+// ProviderName returns the name of the Gitea provider.
+func (c GiteaClient) ProviderName() string {
+	return c.providerName
+}
+
+// CheckAccess performs an access check for the Gitea provider.
+// It returns a GiteaProviderCheckResult containing the results of the check.
+func (c GiteaClient) CheckAccess(ctx context.Context) ProviderCheckResult {
+	r, err := c.CheckGiteaRepoAccess(ctx)
+
+	return GiteaProviderCheckResult{
+		Status:  err == nil,
+		Results: r,
+		Error:   err,
+	}
+}
+
+// CheckGiteaRepoAccess checks access to the configured Gitea repositories.
+// It returns a list of GiteaCheckAccessResult and an error if any issues are encountered.
+func (c GiteaClient) CheckGiteaRepoAccess(ctx context.Context) ([]GiteaCheckAccessResult, error) {
+	client := c.httpClient.NewClient()
+
+	resch := make(chan GiteaCheckAccessResult)
+	wg := sync.WaitGroup{}
+
+	for _, r := range c.Repositories() {
+		wg.Add(1)
+		go func(ri schema.RepositoryConfig) {
+			log.Debug("Gitea access check start", "org", ri.Organization, "repo", ri.Name)
+			defer wg.Done()
+
+			res := GiteaCheckAccessResult{
+				Organization: ri.Organization,
+				Repository:   ri.Name,
+			}
+
+			repo, err := c.getRepository(ctx, client, ri.Organization, ri.Name)
+			if err == nil {
+				log.Debug("Gitea access check result", "name", repo.FullName, "permissions", repo.Permissions)
+				res.Name = repo.FullName
+				res.Pull = repo.Permissions.Pull
+				res.Push = repo.Permissions.Push
+				res.Admin = repo.Permissions.Admin
+			} else {
+				log.Info("Gitea access check error", "name", ri.Name, "err", err)
+				res.Error = err
+			}
+
+			resch <- res
+		}(r)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resch)
+	}()
+
+	var res []GiteaCheckAccessResult
+	var errs []error
+	for r := range resch {
+		res = append(res, r)
+		if r.Error != nil {
+			errs = append(errs, r.Error)
+		}
+	}
+
+	if len(errs) > 0 {
+		return res, errors.Join(errs...)
+	}
+
+	return res, nil
+}
+
+// getRepository retrieves a repository, including the token's permissions on it, from the Gitea api.
+func (c GiteaClient) getRepository(ctx context.Context, client *http.Client, org string, name string) (giteaRepository, error) {
+	var repo giteaRepository
+
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s", c.baseUrl, url.PathEscape(org), url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return repo, err
+	}
+
+	req.Header.Add("Authorization", "token "+c.creds.Token)
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return repo, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return repo, fmt.Errorf("gitea repository %s/%s status %s", org, name, resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&repo)
+	return repo, err
+}
+
+// ToTable converts the GiteaProviderCheckResult into table headers and rows for display.
+func (r GiteaProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow) {
+	headers := []string{"Repository", "Pull", "Push", "Admin"}
+	var rows []ProviderCheckResultRow
+
+	for _, r := range r.Results {
+		if r.Error != nil {
+			rows = append(rows, ProviderCheckResultRow{
+				Status: false,
+				Error:  r.Error,
+				Data:   []string{fmt.Sprintf("%s/%s", r.Organization, r.Repository)},
+			})
+			continue
+		}
+
+		var err error
+		if !r.Pull {
+			err = fmt.Errorf("insufficient permissions")
+		}
+
+		rows = append(rows, ProviderCheckResultRow{
+			Status: err == nil,
+			Error:  err,
+			Data: []string{
+				r.Name,
+				strconv.FormatBool(r.Pull),
+				strconv.FormatBool(r.Push),
+				strconv.FormatBool(r.Admin),
+			},
+		})
+	}
+
+	return headers, rows
+}
+
+// Repositories retrieves the list of repositories configured in the Quartz configuration.
+func (c GiteaClient) Repositories() []schema.RepositoryConfig {
+	return configuredRepositories(c.cfg)
+}
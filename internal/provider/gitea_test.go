@@ -0,0 +1,164 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+func TestProviderGiteaClientProviderName(t *testing.T) {
+	_, err := NewGiteaClient(nil, "", schema.QuartzConfig{}, schema.GiteaCredentials{})
+	if err == nil {
+		t.Error("expected error from gitea client for missing required arguments")
+	}
+
+	_, err = NewGiteaClient(nil, "", schema.QuartzConfig{}, schema.GiteaCredentials{Token: "supersecrettoken"})
+	if err == nil {
+		t.Error("expected error from gitea client for missing url")
+	}
+
+	c, err := NewGiteaClient(nil, "", schema.QuartzConfig{
+		Gitea: schema.GiteaConfig{Url: "https://gitea.example.com"},
+	}, schema.GiteaCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Errorf("unexpected error from gitea client constructor, %v", err)
+	}
+
+	if c.ProviderName() != "Gitea" {
+		t.Errorf("unexpected default provider name from gitea client, expected %v, found %v", "Gitea", c.ProviderName())
+	}
+}
+
+// TestProviderGiteaClientCheckAccess tests the Gitea client access check functionality.
+// It validates that the client sends the access token to the configured host and reports
+// repository permissions and errors.
+func TestProviderGiteaClientCheckAccess(t *testing.T) {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			if req.Method != "GET" {
+				t.Errorf("unexpected http request method, expected %v, found %v", "GET", req.Method)
+			}
+
+			if req.URL.Host != "gitea.example.com" || !strings.HasPrefix(req.URL.Path, "/api/v1/repos/example/") {
+				t.Errorf("unexpected http request url, found %v", req.URL.String())
+			}
+
+			if req.Header.Get("Authorization") != "token supersecrettoken" {
+				t.Errorf("unexpected authorization header, found %v", req.Header.Get("Authorization"))
+			}
+
+			if strings.HasSuffix(req.URL.Path, "error") {
+				return &http.Response{
+					StatusCode: 404,
+					Status:     "404 Not Found",
+					Body:       io.NopCloser(bytes.NewBufferString("")),
+					Header:     http.Header{},
+				}
+			}
+
+			var repo giteaRepository
+			repo.FullName = strings.TrimPrefix(req.URL.Path, "/api/v1/repos/")
+			repo.Permissions.Pull = !strings.HasSuffix(req.URL.Path, "nopull")
+			repo.Permissions.Push = true
+			b, _ := json.Marshal(repo)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(b)),
+				Header:     http.Header{},
+			}
+		},
+	}
+
+	cfg := schema.QuartzConfig{
+		Gitea: schema.GiteaConfig{Url: "https://gitea.example.com/"},
+		Gitops: schema.GitopsConfig{
+			Core: schema.RepositoryConfig{
+				Name:         "testinfrarepo",
+				Organization: "example",
+				RepoUrl:      "https://gitea.example.com/example/testinfrarepo",
+			},
+			Apps: schema.RepositoryConfig{
+				Name:         "nopull",
+				Organization: "example",
+				RepoUrl:      "https://gitea.example.com/example/nopull",
+			},
+		},
+		Applications: map[string]schema.ApplicationRepositoryConfig{
+			"testapp1": {
+				Name:         "testapp1",
+				Organization: "example",
+				RepoUrl:      "https://gitea.example.com/example/testapp1",
+			},
+			"error": {
+				Name:         "error",
+				Organization: "example",
+				RepoUrl:      "https://gitea.example.com/example/error",
+			},
+		},
+	}
+
+	c, err := NewGiteaClient(httpClient, "", cfg, schema.GiteaCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from gitea client constructor, %v", err)
+	}
+
+	res := c.CheckAccess(context.Background())
+	switch r := res.(type) {
+	case GiteaProviderCheckResult:
+		if r.Status ||
+			r.Error == nil {
+			t.Errorf("expected error from gitea check access not found, %v", r)
+		}
+
+		if len(r.Results) != 4 {
+			t.Errorf("unexpected result set value from gitea check access, %v", r)
+		}
+	default:
+		t.Errorf("unexpected response type from gitea check access, %v", r)
+	}
+
+	headers, rows := res.ToTable()
+	if len(headers) != 4 || len(rows) != 4 {
+		t.Errorf("unexpected response from gitea check access table, %v, %v", headers, rows)
+	}
+
+	errorCount := 0
+	for _, r := range rows {
+		if !r.Status ||
+			r.Error != nil {
+			errorCount = errorCount + 1
+		}
+	}
+
+	// the missing repo and the repo without pull access
+	if errorCount != 2 {
+		t.Errorf("expected 2 errors, found %v", errorCount)
+	}
+}
@@ -16,12 +16,17 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
@@ -40,6 +45,27 @@ type GithubClient struct {
 	cfg          schema.QuartzConfig      // The Quartz configuration.
 	creds        schema.GithubCredentials // The GitHub credentials.
 	httpClient   util.HttpClientFactory   // The HTTP client factory for making requests.
+	cachePath    string                   // The path of the on-disk access check cache, empty disables the cache.
+	cacheTtl     time.Duration            // How long cached access check results remain valid.
+}
+
+// githubAccessCacheEntry represents a cached successful repository access check.
+type githubAccessCacheEntry struct {
+	Checked  time.Time `json:"checked"`
+	Name     string    `json:"name"`
+	Pull     bool      `json:"pull"`
+	Push     bool      `json:"push"`
+	Triage   bool      `json:"triage"`
+	Maintain bool      `json:"maintain"`
+	Admin    bool      `json:"admin"`
+	Packages bool      `json:"packages"`
+
+	FineGrained        bool     `json:"fine_grained,omitempty"`
+	MissingScopes      []string `json:"missing_scopes,omitempty"`
+	MissingPermissions []string `json:"missing_permissions,omitempty"`
+
+	Branch        string `json:"branch,omitempty"`
+	MissingBranch bool   `json:"missing_branch,omitempty"`
 }
 
 // GithubCheckAccessResult represents the result of a GitHub repository access check.
@@ -55,6 +81,30 @@ type GithubCheckAccessResult struct {
 	Maintain bool   // Indicates if the user has maintain access.
 	Admin    bool   // Indicates if the user has admin access.
 	Packages bool   // Indicates if the user has access to packages.
+
+	FineGrained        bool     // Indicates a fine-grained token, which reports repository permissions instead of scopes.
+	MissingScopes      []string // Required scopes not granted to a classic token.
+	MissingPermissions []string // Repository permissions a fine-grained token lacks for the required scopes.
+
+	Branch        string // The configured branch, checked against the repository refs.
+	MissingBranch bool   // Indicates the configured branch doesn't exist in the repository.
+}
+
+// githubScopePermissions maps required classic scopes to the repository permission a
+// fine-grained token needs in their place.
+var githubScopePermissions = map[string]string{
+	"repo":            "pull",
+	"admin:repo_hook": "admin",
+}
+
+// githubImpliedScopes maps GitHub OAuth scopes to the narrower scopes they grant.
+var githubImpliedScopes = map[string][]string{
+	"repo":            {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":       {"write:org", "read:org"},
+	"write:org":       {"read:org"},
+	"admin:repo_hook": {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook": {"read:repo_hook"},
+	"write:packages":  {"read:packages"},
 }
 
 // GithubProviderCheckResult represents the result of a GitHub provider check.
@@ -75,12 +125,19 @@ func NewGithubClient(httpClient util.HttpClientFactory, providerName string, cfg
 		providerName = "Github"
 	}
 
-	return GithubClient{
+	c := GithubClient{
 		providerName: providerName,
 		cfg:          cfg,
 		creds:        creds,
 		httpClient:   httpClient,
-	}, nil
+	}
+
+	if cfg.Tmp != "" && cfg.Github.CacheTtlSeconds > 0 {
+		c.cachePath = cfg.GithubCachePath()
+		c.cacheTtl = time.Duration(cfg.Github.CacheTtlSeconds) * time.Second
+	}
+
+	return c, nil
 }
 
 // ProviderName returns the name of the GitHub provider.
@@ -111,8 +168,17 @@ func (c GithubClient) CheckGithubRepoAccess(ctx context.Context) ([]GithubCheckA
 	resch := make(chan GithubCheckAccessResult)
 	wg := sync.WaitGroup{}
 
+	cache := c.loadCache()
+	var cached []GithubCheckAccessResult
+
 	repositories := c.Repositories()
 	for _, r := range repositories {
+		if e, ok := cache[c.cacheKey(r.Organization, r.Name)]; ok && time.Since(e.Checked) < c.cacheTtl && e.Branch == r.Branch {
+			log.Debug("Github access check cached", "org", r.Organization, "repo", r.Name, "checked", e.Checked)
+			cached = append(cached, e.result(r))
+			continue
+		}
+
 		wg.Add(1)
 		go func(ri schema.RepositoryConfig) {
 			log.Debug("Github access check start", "org", ri.Organization, "repo", ri.Name)
@@ -124,6 +190,7 @@ func (c GithubClient) CheckGithubRepoAccess(ctx context.Context) ([]GithubCheckA
 				Organization: ri.Organization,
 				Repository:   ri.Name,
 				Error:        err,
+				Branch:       ri.Branch,
 			}
 
 			if err == nil {
@@ -138,9 +205,21 @@ func (c GithubClient) CheckGithubRepoAccess(ctx context.Context) ([]GithubCheckA
 				res.Maintain = repo.Permissions["maintain"]
 				res.Triage = repo.Permissions["triage"]
 				res.Admin = repo.Permissions["admin"]
-				res.Packages = slices.ContainsFunc(resp.Header.Values("X-Oauth-Scopes"), func(s string) bool {
-					return strings.Contains(s, "read:packages") || strings.Contains(s, "write:packages")
-				})
+
+				// classic tokens always send the scopes header, even when empty, fine-grained tokens omit it
+				if scopes, ok := resp.Header["X-Oauth-Scopes"]; ok {
+					res.Packages = slices.ContainsFunc(scopes, func(s string) bool {
+						return strings.Contains(s, "read:packages") || strings.Contains(s, "write:packages")
+					})
+					res.MissingScopes = c.missingScopes(scopes)
+				} else {
+					res.FineGrained = true
+					res.MissingPermissions = c.missingPermissions(repo.Permissions)
+				}
+
+				if ri.Branch != "" {
+					res.MissingBranch = githubBranchMissing(ctx, client, ri)
+				}
 			} else {
 				log.Info("Github access check error", "name", ri.Name, "err", err)
 			}
@@ -154,15 +233,22 @@ func (c GithubClient) CheckGithubRepoAccess(ctx context.Context) ([]GithubCheckA
 		close(resch)
 	}()
 
-	var res []GithubCheckAccessResult
+	res := cached
 	var errs []error
 	for r := range resch {
 		res = append(res, r)
 		if r.Error != nil {
 			errs = append(errs, r.Error)
+			continue
+		}
+
+		if cache != nil {
+			cache[c.cacheKey(r.Organization, r.Repository)] = newGithubAccessCacheEntry(r)
 		}
 	}
 
+	c.saveCache(cache)
+
 	if len(errs) > 0 {
 		return res, errors.Join(errs...)
 	}
@@ -170,6 +256,125 @@ func (c GithubClient) CheckGithubRepoAccess(ctx context.Context) ([]GithubCheckA
 	return res, nil
 }
 
+// githubBranchMissing checks the repository's configured branch exists via the refs API.
+// Only a not found response is reported as missing, other errors are logged and ignored.
+func githubBranchMissing(ctx context.Context, client *github.Client, r schema.RepositoryConfig) bool {
+	_, resp, err := client.Git.GetRef(ctx, r.Organization, r.Name, "heads/"+r.Branch)
+	if err == nil {
+		return false
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		log.Warn("Github branch not found", "org", r.Organization, "repo", r.Name, "branch", r.Branch)
+		return true
+	}
+
+	log.Warn("Github branch check error", "org", r.Organization, "repo", r.Name, "branch", r.Branch, "err", err)
+	return false
+}
+
+// ClearCache removes any cached repository access check results so the next check queries GitHub.
+func (c GithubClient) ClearCache() error {
+	if c.cachePath == "" {
+		return nil
+	}
+
+	err := os.Remove(c.cachePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// loadCache reads the cached access check results, returning nil when the cache is disabled.
+// A missing or unreadable cache file is treated as empty.
+func (c GithubClient) loadCache() map[string]githubAccessCacheEntry {
+	if c.cachePath == "" {
+		return nil
+	}
+
+	cache := map[string]githubAccessCacheEntry{}
+
+	b, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(b, &cache); err != nil {
+		log.Debug("Ignoring invalid github access cache", "path", c.cachePath, "err", err)
+		return map[string]githubAccessCacheEntry{}
+	}
+
+	return cache
+}
+
+// saveCache writes the access check results to disk, failures are logged and otherwise ignored.
+func (c GithubClient) saveCache(cache map[string]githubAccessCacheEntry) {
+	if c.cachePath == "" || cache == nil {
+		return
+	}
+
+	b, err := json.Marshal(cache)
+	if err == nil {
+		err = os.WriteFile(c.cachePath, b, 0600)
+	}
+
+	if err != nil {
+		log.Warn("Failed to write github access cache", "path", c.cachePath, "err", err)
+	}
+}
+
+// cacheKey returns the cache key for a repository, org/repo plus a hash of the credentials so
+// results cached for one token are never reported for another.
+func (c GithubClient) cacheKey(org string, repo string) string {
+	h := sha256.Sum256([]byte(c.creds.Username + ":" + c.creds.Token))
+	return fmt.Sprintf("%s/%s@%x", org, repo, h[:8])
+}
+
+// newGithubAccessCacheEntry creates a cache entry from a successful access check result.
+func newGithubAccessCacheEntry(r GithubCheckAccessResult) githubAccessCacheEntry {
+	return githubAccessCacheEntry{
+		Checked:  time.Now(),
+		Name:     r.Name,
+		Pull:     r.Pull,
+		Push:     r.Push,
+		Triage:   r.Triage,
+		Maintain: r.Maintain,
+		Admin:    r.Admin,
+		Packages: r.Packages,
+
+		FineGrained:        r.FineGrained,
+		MissingScopes:      r.MissingScopes,
+		MissingPermissions: r.MissingPermissions,
+
+		Branch:        r.Branch,
+		MissingBranch: r.MissingBranch,
+	}
+}
+
+// result converts the cache entry back into an access check result for the repository.
+func (e githubAccessCacheEntry) result(r schema.RepositoryConfig) GithubCheckAccessResult {
+	return GithubCheckAccessResult{
+		Organization: r.Organization,
+		Repository:   r.Name,
+		Name:         e.Name,
+		Pull:         e.Pull,
+		Push:         e.Push,
+		Triage:       e.Triage,
+		Maintain:     e.Maintain,
+		Admin:        e.Admin,
+		Packages:     e.Packages,
+
+		FineGrained:        e.FineGrained,
+		MissingScopes:      e.MissingScopes,
+		MissingPermissions: e.MissingPermissions,
+
+		Branch:        e.Branch,
+		MissingBranch: e.MissingBranch,
+	}
+}
+
 // ToTable converts the GithubProviderCheckResult into table headers and rows for display.
 func (r GithubProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow) {
 	headers := []string{"Repository", "Pull", "Push", "Triage", "Maintain", "Admin", "Packages"}
@@ -186,12 +391,22 @@ func (r GithubProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow
 		}
 
 		var err error
-		if !r.Pull {
+		if len(r.MissingScopes) > 0 {
+			err = fmt.Errorf("token missing required scopes %s", strings.Join(r.MissingScopes, ", "))
+		} else if len(r.MissingPermissions) > 0 {
+			err = fmt.Errorf("fine-grained token missing required repository permissions %s", strings.Join(r.MissingPermissions, ", "))
+		} else if !r.Pull {
 			err = fmt.Errorf("insufficient permissions")
 		}
 
+		// a missing branch is only a warning, it's commonly created after the check
+		status := err == nil
+		if status && r.MissingBranch {
+			err = fmt.Errorf("branch %s not found", r.Branch)
+		}
+
 		rows = append(rows, ProviderCheckResultRow{
-			Status: err == nil,
+			Status: status,
 			Error:  err,
 			Data: []string{
 				r.Name,
@@ -200,7 +415,7 @@ func (r GithubProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow
 				strconv.FormatBool(r.Triage),
 				strconv.FormatBool(r.Maintain),
 				strconv.FormatBool(r.Admin),
-				strconv.FormatBool(r.Packages),
+				r.packagesAccess(),
 			},
 		})
 	}
@@ -208,20 +423,93 @@ func (r GithubProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow
 	return headers, rows
 }
 
-// Repositories retrieves the list of repositories configured in the Quartz configuration.
-func (c GithubClient) Repositories() []schema.RepositoryConfig {
-	repositories := []schema.RepositoryConfig{
-		c.cfg.Gitops.Core,
-		c.cfg.Gitops.Apps,
+// packagesAccess formats package access for display, fine-grained tokens can't access GitHub Packages
+// and report no scopes to check, so the value isn't applicable.
+func (r GithubCheckAccessResult) packagesAccess() string {
+	if r.FineGrained {
+		return "n/a"
+	}
+
+	return strconv.FormatBool(r.Packages)
+}
+
+// RequiredScopes returns the OAuth scopes the GitHub token needs for the configured features,
+// repo always, admin:repo_hook when webhooks are enabled, plus any github.required_scopes.
+func (c GithubClient) RequiredScopes() []string {
+	scopes := []string{"repo"}
+
+	if c.cfg.Github.Webhooks.Build || c.cfg.Github.Webhooks.Release {
+		scopes = append(scopes, "admin:repo_hook")
 	}
 
-	for _, app := range c.cfg.Applications {
-		a := app
-		r := a.RepositoryConfig()
-		if r.RepoUrl != "" {
-			repositories = append(repositories, r)
+	for _, s := range c.cfg.Github.RequiredScopes {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	return scopes
+}
+
+// missingScopes returns the required scopes not granted by the X-Oauth-Scopes header values.
+func (c GithubClient) missingScopes(header []string) []string {
+	granted := parseGithubScopes(header)
+
+	var missing []string
+	for _, s := range c.RequiredScopes() {
+		if !githubScopeGranted(granted, s) {
+			missing = append(missing, s)
 		}
 	}
 
-	return repositories
+	return missing
+}
+
+// missingPermissions returns the repository permissions a fine-grained token lacks for the required
+// scopes. Scopes without a repository permission equivalent (e.g. admin:org) can't be verified and are skipped.
+func (c GithubClient) missingPermissions(permissions map[string]bool) []string {
+	var missing []string
+	for _, s := range c.RequiredScopes() {
+		perm, ok := githubScopePermissions[s]
+		if !ok {
+			log.Debug("Unable to verify scope for fine-grained github token", "scope", s)
+			continue
+		}
+
+		if !permissions[perm] && !slices.Contains(missing, perm) {
+			missing = append(missing, perm)
+		}
+	}
+
+	return missing
+}
+
+// parseGithubScopes splits the comma separated X-Oauth-Scopes header values into individual scopes.
+func parseGithubScopes(header []string) []string {
+	var scopes []string
+	for _, h := range header {
+		for _, s := range strings.Split(h, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// githubScopeGranted checks if the scope was granted directly or implied by a broader granted scope.
+func githubScopeGranted(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope || slices.Contains(githubImpliedScopes[g], scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Repositories retrieves the list of repositories configured in the Quartz configuration.
+func (c GithubClient) Repositories() []schema.RepositoryConfig {
+	return configuredRepositories(c.cfg)
 }
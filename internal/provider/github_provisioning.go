@@ -0,0 +1,189 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/google/go-github/v63/github"
+)
+
+// GithubRepoProvisioningResult represents the webhook and deploy key check of an application repository.
+type GithubRepoProvisioningResult struct {
+	Organization string // The organization name.
+	Repository   string // The repository name.
+	Error        error  // Any error encountered listing the webhooks or deploy keys.
+
+	Webhook   bool     // Indicates the expected webhook is present.
+	DeployKey bool     // Indicates the expected deploy key is present.
+	Missing   []string // The expected webhook and/or deploy key not found.
+}
+
+// GithubProvisioningCheckResult represents the result of the application repository provisioning check.
+type GithubProvisioningCheckResult struct {
+	WebhookUrl string                         // The expected webhook payload url, empty when webhooks are disabled.
+	DeployKey  string                         // The expected deploy key title, empty when not checked.
+	Results    []GithubRepoProvisioningResult // The results per application repository.
+}
+
+// WebhookUrl returns the payload url expected on application repository webhooks,
+// github.webhooks.url if set, otherwise the cluster's jenkins github-webhook endpoint.
+// Returns an empty string if webhooks are disabled.
+func (c GithubClient) WebhookUrl() string {
+	if !c.cfg.Github.Webhooks.Build && !c.cfg.Github.Webhooks.Release {
+		return ""
+	}
+
+	if c.cfg.Github.Webhooks.Url != "" {
+		return c.cfg.Github.Webhooks.Url
+	}
+
+	return fmt.Sprintf("https://jenkins.%s/github-webhook/", c.cfg.Dns.Domain)
+}
+
+// CheckAppRepoProvisioning checks each GitHub application repository has the webhook pointing at
+// the cluster's CI ingress when webhooks are enabled, and the github.deploy_key deploy key when set.
+func (c GithubClient) CheckAppRepoProvisioning(ctx context.Context) ProviderCheckResult {
+	res := GithubProvisioningCheckResult{
+		WebhookUrl: c.WebhookUrl(),
+		DeployKey:  c.cfg.Github.DeployKey,
+	}
+
+	if res.WebhookUrl == "" && res.DeployKey == "" {
+		log.Info("No webhook or deploy key expected on application repositories, skipping check")
+		return res
+	}
+
+	client := github.NewClient(c.httpClient.NewClient()).WithAuthToken(c.creds.Token)
+
+	for _, r := range githubApplicationRepositories(c.cfg) {
+		res.Results = append(res.Results, c.checkRepoProvisioning(ctx, client, r, res.WebhookUrl, res.DeployKey))
+	}
+
+	return res
+}
+
+// checkRepoProvisioning looks up the webhooks and deploy keys of a single repository.
+func (c GithubClient) checkRepoProvisioning(ctx context.Context, client *github.Client, r schema.RepositoryConfig, webhookUrl string, deployKey string) GithubRepoProvisioningResult {
+	res := GithubRepoProvisioningResult{
+		Organization: r.Organization,
+		Repository:   r.Name,
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	if webhookUrl != "" {
+		hooks, _, err := client.Repositories.ListHooks(ctx, r.Organization, r.Name, opts)
+		if err != nil {
+			res.Error = err
+			return res
+		}
+
+		res.Webhook = slices.ContainsFunc(hooks, func(h *github.Hook) bool {
+			return h.Config != nil && sameWebhookUrl(h.Config.GetURL(), webhookUrl)
+		})
+		if !res.Webhook {
+			res.Missing = append(res.Missing, fmt.Sprintf("webhook %s", webhookUrl))
+		}
+	}
+
+	if deployKey != "" {
+		keys, _, err := client.Repositories.ListKeys(ctx, r.Organization, r.Name, opts)
+		if err != nil {
+			res.Error = err
+			return res
+		}
+
+		res.DeployKey = slices.ContainsFunc(keys, func(k *github.Key) bool {
+			return k.GetTitle() == deployKey
+		})
+		if !res.DeployKey {
+			res.Missing = append(res.Missing, fmt.Sprintf("deploy key %s", deployKey))
+		}
+	}
+
+	log.Debug("Github provisioning check result", "org", r.Organization, "repo", r.Name, "missing", res.Missing)
+	return res
+}
+
+// sameWebhookUrl compares webhook urls ignoring case and a trailing slash.
+func sameWebhookUrl(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/"))
+}
+
+// githubApplicationRepositories returns the application repositories hosted on GitHub, ordered by key.
+func githubApplicationRepositories(cfg schema.QuartzConfig) []schema.RepositoryConfig {
+	var r []schema.RepositoryConfig
+	for _, key := range slices.Sorted(maps.Keys(cfg.Applications)) {
+		app := cfg.Applications[key]
+		if app.RepoUrl == "" || (app.Provider != "" && !strings.EqualFold(app.Provider, "github")) {
+			continue
+		}
+
+		r = append(r, app.RepositoryConfig())
+	}
+
+	return r
+}
+
+// ToTable converts the GithubProvisioningCheckResult into table headers and rows for display.
+// Missing webhooks and deploy keys are listed in full in their own column.
+func (r GithubProvisioningCheckResult) ToTable() ([]string, []ProviderCheckResultRow) {
+	headers := []string{"Repository", "Webhook", "Deploy Key", "Missing"}
+	var rows []ProviderCheckResultRow
+
+	for _, res := range r.Results {
+		name := fmt.Sprintf("%s/%s", res.Organization, res.Repository)
+		if res.Error != nil {
+			rows = append(rows, ProviderCheckResultRow{
+				Status: false,
+				Error:  res.Error,
+				Data:   []string{name},
+			})
+			continue
+		}
+
+		rows = append(rows, ProviderCheckResultRow{
+			Status: len(res.Missing) == 0,
+			Data: []string{
+				name,
+				provisioningStatus(r.WebhookUrl != "", res.Webhook),
+				provisioningStatus(r.DeployKey != "", res.DeployKey),
+				strings.Join(res.Missing, ", "),
+			},
+		})
+	}
+
+	return headers, rows
+}
+
+// provisioningStatus formats a provisioning check for display, n/a when it wasn't checked.
+func provisioningStatus(checked bool, present bool) string {
+	if !checked {
+		return "n/a"
+	}
+
+	if present {
+		return "present"
+	}
+
+	return "missing"
+}
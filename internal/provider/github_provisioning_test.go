@@ -0,0 +1,198 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/google/go-github/v63/github"
+)
+
+// newGithubHooksKeysMock mocks the GitHub repository hooks and deploy keys APIs, repositories
+// named "provisioned" have the expected webhook and deploy key, "error" fails, others have neither.
+func newGithubHooksKeysMock(t *testing.T) util.HttpClientFactoryMock {
+	return util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			if req.Method != "GET" {
+				t.Errorf("unexpected http request method, expected %v, found %v", "GET", req.Method)
+			}
+
+			if strings.Contains(req.URL.Path, "/error/") {
+				return &http.Response{
+					StatusCode: 500,
+					Body:       io.NopCloser(bytes.NewBufferString("")),
+					Header:     http.Header{},
+				}
+			}
+
+			provisioned := strings.Contains(req.URL.Path, "/provisioned/")
+
+			var body []byte
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/hooks"):
+				hooks := []*github.Hook{
+					{Config: &github.HookConfig{URL: github.String("https://other.example.com/hook")}},
+				}
+				if provisioned {
+					hooks = append(hooks, &github.Hook{Config: &github.HookConfig{URL: github.String("https://JENKINS.mytest.example.com/github-webhook")}})
+				}
+				body, _ = json.Marshal(hooks)
+			case strings.HasSuffix(req.URL.Path, "/keys"):
+				keys := []*github.Key{{Title: github.String("someone-else")}}
+				if provisioned {
+					keys = append(keys, &github.Key{Title: github.String("quartz-argocd")})
+				}
+				body, _ = json.Marshal(keys)
+			default:
+				t.Errorf("unexpected http request path %s", req.URL.Path)
+			}
+
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(body)),
+				Header:     http.Header{},
+			}
+		},
+	}
+}
+
+func newGithubProvisioningTestClient(t *testing.T, webhooks bool, deployKey string) GithubClient {
+	cfg := schema.QuartzConfig{
+		Dns: schema.DnsConfig{Domain: "mytest.example.com"},
+		Github: schema.GithubConfig{
+			Webhooks:  schema.GithubWebhooks{Build: webhooks},
+			DeployKey: deployKey,
+		},
+		Applications: map[string]schema.ApplicationRepositoryConfig{
+			"provisioned": {Name: "provisioned", Organization: "example", RepoUrl: "https://github.com/example/provisioned"},
+			"missing":     {Name: "missing", Organization: "example", RepoUrl: "https://github.com/example/missing"},
+			"external":    {Name: "external", Organization: "example", Type: "external"},
+			"gitea":       {Name: "gitea", Organization: "example", Provider: "gitea", RepoUrl: "https://gitea.example.com/example/gitea"},
+		},
+	}
+
+	c, err := NewGithubClient(newGithubHooksKeysMock(t), "", cfg, schema.GithubCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from github client constructor, %v", err)
+	}
+
+	return c
+}
+
+func TestProviderGithubCheckAppRepoProvisioning(t *testing.T) {
+	c := newGithubProvisioningTestClient(t, true, "quartz-argocd")
+
+	res := c.CheckAppRepoProvisioning(context.Background()).(GithubProvisioningCheckResult)
+	if res.WebhookUrl != "https://jenkins.mytest.example.com/github-webhook/" {
+		t.Errorf("unexpected webhook url, found %s", res.WebhookUrl)
+	}
+
+	if len(res.Results) != 2 {
+		t.Fatalf("expected the two github application repositories checked, found %v", res.Results)
+	}
+
+	// ordered by application key
+	missing, provisioned := res.Results[0], res.Results[1]
+	if !provisioned.Webhook || !provisioned.DeployKey || len(provisioned.Missing) != 0 {
+		t.Errorf("expected webhook and deploy key present, found %+v", provisioned)
+	}
+
+	if missing.Webhook || missing.DeployKey || len(missing.Missing) != 2 {
+		t.Errorf("expected webhook and deploy key missing, found %+v", missing)
+	}
+
+	// listed in full in the table rather than a truncated error
+	_, rows := res.ToTable()
+	if rows[0].Status || rows[0].Error != nil || rows[0].Data[3] != "webhook https://jenkins.mytest.example.com/github-webhook/, deploy key quartz-argocd" {
+		t.Errorf("unexpected missing row, %+v", rows[0])
+	}
+
+	if !rows[1].Status || rows[1].Data[1] != "present" || rows[1].Data[2] != "present" {
+		t.Errorf("unexpected provisioned row, %+v", rows[1])
+	}
+}
+
+func TestProviderGithubCheckAppRepoProvisioningWebhookOnly(t *testing.T) {
+	c := newGithubProvisioningTestClient(t, true, "")
+	c.cfg.Github.Webhooks.Url = "https://ci.example.com/github-webhook/"
+
+	res := c.CheckAppRepoProvisioning(context.Background()).(GithubProvisioningCheckResult)
+
+	// neither repository has the custom url
+	for _, r := range res.Results {
+		if r.Webhook || r.DeployKey || len(r.Missing) != 1 || r.Missing[0] != "webhook https://ci.example.com/github-webhook/" {
+			t.Errorf("unexpected result for %s, %+v", r.Repository, r)
+		}
+	}
+
+	_, rows := res.ToTable()
+	if rows[0].Data[2] != "n/a" {
+		t.Errorf("expected deploy key not checked, found %s", rows[0].Data[2])
+	}
+
+	// every failing repository is reported, not just the first
+	err := CheckAppRepos(context.Background(), c)
+	for _, r := range res.Results {
+		name := r.Organization + "/" + r.Repository
+		if err == nil || !strings.Contains(err.Error(), name+" check failed") {
+			t.Errorf("expected %s in the app repository check error, found %v", name, err)
+		}
+	}
+}
+
+func TestProviderGithubCheckAppRepoProvisioningDisabled(t *testing.T) {
+	c := newGithubProvisioningTestClient(t, false, "")
+
+	res := c.CheckAppRepoProvisioning(context.Background()).(GithubProvisioningCheckResult)
+	if len(res.Results) != 0 {
+		t.Errorf("expected nothing checked, found %v", res.Results)
+	}
+}
+
+func TestProviderGithubCheckAppRepoProvisioningError(t *testing.T) {
+	c := newGithubProvisioningTestClient(t, false, "quartz-argocd")
+	c.cfg.Applications = map[string]schema.ApplicationRepositoryConfig{
+		"error": {Name: "error", Organization: "example", RepoUrl: "https://github.com/example/error"},
+	}
+
+	res := c.CheckAppRepoProvisioning(context.Background())
+	_, rows := res.ToTable()
+	if len(rows) != 1 || rows[0].Status || rows[0].Error == nil {
+		t.Errorf("expected error row, found %+v", rows)
+	}
+
+	err := CheckAppRepos(context.Background(), c)
+	if err == nil {
+		t.Errorf("expected access error from app repository check")
+	}
+}
+
+func TestProviderCheckAppReposUnsupported(t *testing.T) {
+	err := CheckAppRepos(context.Background(), LocalClient{})
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported error, found %v", err)
+	}
+}
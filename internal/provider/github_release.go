@@ -0,0 +1,68 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/google/go-github/v63/github"
+	"github.com/hashicorp/go-version"
+)
+
+// GithubRelease represents the latest published release of a GitHub repository.
+type GithubRelease struct {
+	Tag string // The release tag, e.g. v1.2.3.
+	Url string // The release page url.
+}
+
+// GithubLatestRelease looks up the latest published release of the repository. The lookup is
+// unauthenticated, so only public repositories are supported.
+func GithubLatestRelease(ctx context.Context, httpClient util.HttpClientFactory, owner string, repo string) (GithubRelease, error) {
+	client := github.NewClient(httpClient.NewClient())
+
+	r, _, err := client.Repositories.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		return GithubRelease{}, err
+	}
+
+	return GithubRelease{
+		Tag: r.GetTagName(),
+		Url: r.GetHTMLURL(),
+	}, nil
+}
+
+// IsReleaseVersion returns true if v is a semver version, false for development builds like dev.
+func IsReleaseVersion(v string) bool {
+	_, err := version.NewSemver(v)
+	return err == nil
+}
+
+// CompareReleaseVersions compares two semver release versions, with or without a leading v.
+// Returns -1, 0 or 1 if current is older, the same or newer than latest.
+func CompareReleaseVersions(current string, latest string) (int, error) {
+	c, err := version.NewSemver(current)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %s, %w", current, err)
+	}
+
+	l, err := version.NewSemver(latest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid release version %s, %w", latest, err)
+	}
+
+	return c.Compare(l), nil
+}
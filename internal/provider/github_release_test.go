@@ -0,0 +1,77 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderGithubLatestRelease(t *testing.T) {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			assert.Equal(t, "api.github.com", req.URL.Host)
+			assert.Equal(t, "/repos/example/repo/releases/latest", req.URL.Path)
+
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"tag_name":"v1.2.0","html_url":"https://example.com/v1.2.0"}`)),
+				Header:     http.Header{},
+			}
+		},
+	}
+
+	r, err := GithubLatestRelease(context.Background(), httpClient, "example", "repo")
+	assert.NoError(t, err)
+	assert.Equal(t, GithubRelease{Tag: "v1.2.0", Url: "https://example.com/v1.2.0"}, r)
+}
+
+func TestProviderCompareReleaseVersions(t *testing.T) {
+	tests := []struct {
+		current  string
+		latest   string
+		expected int
+	}{
+		{"v1.1.0", "v1.2.0", -1},
+		{"1.2.0", "v1.2.0", 0},
+		{"v1.10.0", "v1.9.3", 1},
+		{"v1.2.0-rc.1", "v1.2.0", -1},
+	}
+
+	for _, tt := range tests {
+		c, err := CompareReleaseVersions(tt.current, tt.latest)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, c, "%s vs %s", tt.current, tt.latest)
+	}
+
+	_, err := CompareReleaseVersions("dev", "v1.2.0")
+	assert.Error(t, err)
+
+	_, err = CompareReleaseVersions("v1.2.0", "latest")
+	assert.Error(t, err)
+}
+
+func TestProviderIsReleaseVersion(t *testing.T) {
+	assert.True(t, IsReleaseVersion("v1.2.0"))
+	assert.True(t, IsReleaseVersion("1.2.0-rc.1"))
+	assert.False(t, IsReleaseVersion("dev"))
+	assert.False(t, IsReleaseVersion(""))
+}
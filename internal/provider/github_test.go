@@ -20,8 +20,12 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/util"
@@ -155,3 +159,464 @@ func TestProviderGithubClientCheckAccess(t *testing.T) {
 		t.Errorf("expected 4 errors, found %v", errorCount)
 	}
 }
+
+// newCachedGithubTestClient creates a github client with the access cache enabled in a temp dir,
+// counting the api requests made through it.
+func newCachedGithubTestClient(t *testing.T, calls *atomic.Int32) GithubClient {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			calls.Add(1)
+			repo, _ := json.Marshal(github.Repository{
+				FullName:    github.String(strings.TrimPrefix(req.URL.Path, "/repos/")),
+				Permissions: map[string]bool{"pull": true},
+			})
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(repo)),
+				Header:     http.Header{},
+			}
+		},
+	}
+
+	cfg := schema.QuartzConfig{
+		Tmp:    t.TempDir(),
+		Github: schema.GithubConfig{CacheTtlSeconds: 300},
+		Gitops: schema.GitopsConfig{
+			Core: schema.RepositoryConfig{Name: "testinfrarepo", Organization: "example"},
+			Apps: schema.RepositoryConfig{Name: "testappsrepo", Organization: "example"},
+		},
+	}
+
+	c, err := NewGithubClient(httpClient, "", cfg, schema.GithubCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from github client constructor, %v", err)
+	}
+
+	return c
+}
+
+func TestProviderGithubClientCheckAccessCacheHit(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := newCachedGithubTestClient(t, calls)
+
+	_, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	res, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from cached github access check, %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 api requests across both checks, found %d", calls.Load())
+	}
+
+	if len(res) != 2 {
+		t.Fatalf("expected 2 cached results, found %v", res)
+	}
+
+	for _, r := range res {
+		if !r.Pull || r.Name != r.Organization+"/"+r.Repository {
+			t.Errorf("unexpected cached github access result, %v", r)
+		}
+	}
+}
+
+func TestProviderGithubClientCheckAccessCacheMiss(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := newCachedGithubTestClient(t, calls)
+
+	_, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	// a repository added since the last check is not in the cache
+	c.cfg.Applications = map[string]schema.ApplicationRepositoryConfig{
+		"testapp1": {
+			Name:         "testapp1",
+			Organization: "example",
+			RepoUrl:      "https://github.com/example/testapp1",
+		},
+	}
+
+	res, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected only the new repository to be requested, found %d requests", calls.Load())
+	}
+
+	if len(res) != 3 {
+		t.Errorf("expected 3 results, found %v", res)
+	}
+
+	// refresh discards the cache entirely
+	if err := c.ClearCache(); err != nil {
+		t.Fatalf("unexpected error clearing github access cache, %v", err)
+	}
+
+	if _, err := os.Stat(c.cachePath); !os.IsNotExist(err) {
+		t.Errorf("expected github access cache to be removed, %v", err)
+	}
+
+	c.CheckGithubRepoAccess(context.Background())
+	if calls.Load() != 6 {
+		t.Errorf("expected all repositories to be requested after clearing the cache, found %d requests", calls.Load())
+	}
+}
+
+func TestProviderGithubClientCheckAccessCacheCredentials(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := newCachedGithubTestClient(t, calls)
+
+	_, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	// results cached for one token aren't reused for another
+	c.creds.Token = "othersecrettoken"
+	_, err = c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("expected the repositories to be requested again with other credentials, found %d requests", calls.Load())
+	}
+
+	for k := range c.loadCache() {
+		if strings.Contains(k, "supersecrettoken") || strings.Contains(k, "othersecrettoken") {
+			t.Errorf("expected the cache key to hash the credentials, found %s", k)
+		}
+	}
+}
+
+func TestProviderGithubClientCheckAccessCacheExpiry(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := newCachedGithubTestClient(t, calls)
+
+	_, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	cache := c.loadCache()
+	for k, e := range cache {
+		e.Checked = time.Now().Add(-c.cacheTtl - time.Second)
+		cache[k] = e
+	}
+	c.saveCache(cache)
+
+	_, err = c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("expected expired entries to be requested again, found %d requests", calls.Load())
+	}
+}
+
+func TestProviderGithubClientCacheDisabled(t *testing.T) {
+	c, _ := NewGithubClient(nil, "", schema.QuartzConfig{
+		Tmp:    t.TempDir(),
+		Github: schema.GithubConfig{CacheTtlSeconds: 0},
+	}, schema.GithubCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+
+	if c.cachePath != "" || c.loadCache() != nil {
+		t.Errorf("expected github access cache to be disabled with a 0 ttl, found %v", c.cachePath)
+	}
+
+	if err := c.ClearCache(); err != nil {
+		t.Errorf("unexpected error clearing disabled github access cache, %v", err)
+	}
+}
+
+// newScopedGithubTestClient creates a github client whose api responses report the given repository
+// permissions and response headers, omitting X-Oauth-Scopes simulates a fine-grained token.
+func newScopedGithubTestClient(t *testing.T, cfg schema.GithubConfig, permissions map[string]bool, header http.Header) GithubClient {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			repo, _ := json.Marshal(github.Repository{
+				FullName:    github.String("example/testinfrarepo"),
+				Permissions: permissions,
+			})
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(repo)),
+				Header:     header.Clone(),
+			}
+		},
+	}
+
+	c, err := NewGithubClient(httpClient, "", schema.QuartzConfig{
+		Github: cfg,
+		Gitops: schema.GitopsConfig{
+			Core: schema.RepositoryConfig{Name: "testinfrarepo", Organization: "example"},
+			Apps: schema.RepositoryConfig{Name: "testappsrepo", Organization: "example"},
+		},
+	}, schema.GithubCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from github client constructor, %v", err)
+	}
+
+	return c
+}
+
+func TestProviderGithubClientCheckAccessScopes(t *testing.T) {
+	webhooks := schema.GithubConfig{Webhooks: schema.GithubWebhooks{Build: true}}
+	extra := schema.GithubConfig{RequiredScopes: []string{"workflow", "read:org"}}
+
+	tests := []struct {
+		name    string
+		cfg     schema.GithubConfig
+		scopes  string
+		missing []string
+	}{
+		{"all granted", webhooks, "repo, admin:repo_hook", nil},
+		{"missing repo", webhooks, "admin:repo_hook, read:packages", []string{"repo"}},
+		{"missing hook", webhooks, "repo, read:packages", []string{"admin:repo_hook"}},
+		{"hook not required", schema.GithubConfig{}, "repo", nil},
+		{"implied by broader scope", extra, "repo, workflow, admin:org", nil},
+		{"missing extra scopes", extra, "repo, public_repo", []string{"workflow", "read:org"}},
+		{"no scopes", schema.GithubConfig{}, "", []string{"repo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newScopedGithubTestClient(t, tt.cfg, map[string]bool{"pull": true}, http.Header{
+				"X-Oauth-Scopes": []string{tt.scopes},
+			})
+
+			res := c.CheckAccess(context.Background())
+			_, rows := res.ToTable()
+			if len(rows) != 2 {
+				t.Fatalf("unexpected rows from github check access table, %v", rows)
+			}
+
+			for _, r := range res.(GithubProviderCheckResult).Results {
+				if !slices.Equal(r.MissingScopes, tt.missing) {
+					t.Errorf("unexpected missing scopes, expected %v, found %v", tt.missing, r.MissingScopes)
+				}
+			}
+
+			for _, row := range rows {
+				if len(tt.missing) == 0 {
+					if !row.Status || row.Error != nil {
+						t.Errorf("unexpected error row for granted scopes, %v", row)
+					}
+					continue
+				}
+
+				if row.Status || row.Error == nil {
+					t.Errorf("expected error row for missing scopes, %v", row)
+					continue
+				}
+
+				for _, m := range tt.missing {
+					if !strings.Contains(row.Error.Error(), m) {
+						t.Errorf("expected missing scope %s in table error, found %v", m, row.Error)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestProviderGithubClientCheckAccessFineGrained(t *testing.T) {
+	full := map[string]bool{"pull": true, "push": true, "triage": true, "maintain": true, "admin": true}
+	webhooks := schema.GithubConfig{
+		Webhooks:       schema.GithubWebhooks{Build: true},
+		RequiredScopes: []string{"admin:org"},
+	}
+
+	c := newScopedGithubTestClient(t, webhooks, full, http.Header{})
+
+	res := c.CheckAccess(context.Background())
+	_, rows := res.ToTable()
+	for _, row := range rows {
+		if !row.Status || row.Error != nil {
+			t.Errorf("unexpected error row for fine-grained token with full permissions, %v", row)
+		}
+
+		if row.Data[6] != "n/a" {
+			t.Errorf("expected packages to be reported as n/a for fine-grained token, found %v", row.Data[6])
+		}
+	}
+
+	for _, r := range res.(GithubProviderCheckResult).Results {
+		if !r.FineGrained || len(r.MissingScopes) > 0 || len(r.MissingPermissions) > 0 {
+			t.Errorf("unexpected fine-grained github access result, %v", r)
+		}
+	}
+}
+
+func TestProviderGithubClientCheckAccessFineGrainedMissingPermissions(t *testing.T) {
+	webhooks := schema.GithubConfig{Webhooks: schema.GithubWebhooks{Release: true}}
+
+	c := newScopedGithubTestClient(t, webhooks, map[string]bool{"pull": true, "push": true}, http.Header{})
+
+	res := c.CheckAccess(context.Background())
+	_, rows := res.ToTable()
+	for _, row := range rows {
+		if row.Status || row.Error == nil || !strings.Contains(row.Error.Error(), "admin") {
+			t.Errorf("expected missing admin permission error row for fine-grained token, %v", row)
+		}
+	}
+
+	for _, r := range res.(GithubProviderCheckResult).Results {
+		if !slices.Equal(r.MissingPermissions, []string{"admin"}) {
+			t.Errorf("unexpected missing permissions for fine-grained token, %v", r.MissingPermissions)
+		}
+	}
+}
+
+// newBranchGithubTestClient creates a github client whose mock api only has the main branch
+// in every repository, counting the ref lookups made through it.
+func newBranchGithubTestClient(t *testing.T, refCalls *atomic.Int32) GithubClient {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			if strings.Contains(req.URL.Path, "/git/ref/") {
+				refCalls.Add(1)
+				if !strings.HasSuffix(req.URL.Path, "/git/ref/heads/main") {
+					return &http.Response{
+						StatusCode: 404,
+						Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Not Found"}`)),
+						Header:     http.Header{},
+					}
+				}
+
+				ref, _ := json.Marshal(github.Reference{Ref: github.String("refs/heads/main")})
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewBuffer(ref)),
+					Header:     http.Header{},
+				}
+			}
+
+			repo, _ := json.Marshal(github.Repository{
+				FullName:    github.String(strings.TrimPrefix(req.URL.Path, "/repos/")),
+				Permissions: map[string]bool{"pull": true},
+			})
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(repo)),
+				Header:     http.Header{"X-Oauth-Scopes": []string{"repo"}},
+			}
+		},
+	}
+
+	cfg := schema.QuartzConfig{
+		Tmp:    t.TempDir(),
+		Github: schema.GithubConfig{CacheTtlSeconds: 300},
+		Gitops: schema.GitopsConfig{
+			Core: schema.RepositoryConfig{Name: "testinfrarepo", Organization: "example", Branch: "main"},
+			Apps: schema.RepositoryConfig{Name: "testappsrepo", Organization: "example", Branch: "mytest"},
+		},
+	}
+
+	c, err := NewGithubClient(httpClient, "", cfg, schema.GithubCredentials{
+		Username: "testuser",
+		Token:    "supersecrettoken",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from github client constructor, %v", err)
+	}
+
+	return c
+}
+
+func TestProviderGithubClientCheckAccessBranches(t *testing.T) {
+	refCalls := &atomic.Int32{}
+	c := newBranchGithubTestClient(t, refCalls)
+
+	res := c.CheckAccess(context.Background())
+	if !res.(GithubProviderCheckResult).Status {
+		t.Errorf("expected a missing branch not to fail the github check, %v", res)
+	}
+
+	if refCalls.Load() != 2 {
+		t.Errorf("expected a ref lookup per repository, found %d", refCalls.Load())
+	}
+
+	for _, r := range res.(GithubProviderCheckResult).Results {
+		expected := r.Branch == "mytest"
+		if r.MissingBranch != expected {
+			t.Errorf("unexpected missing branch for %s branch %s, expected %v, found %v", r.Repository, r.Branch, expected, r.MissingBranch)
+		}
+	}
+
+	_, rows := res.ToTable()
+	warnings := 0
+	for _, row := range rows {
+		if row.Level() == SeverityWarn {
+			warnings++
+			if !strings.Contains(row.Error.Error(), "branch mytest not found") {
+				t.Errorf("unexpected missing branch warning, %v", row.Error)
+			}
+		}
+	}
+
+	if warnings != 1 {
+		t.Errorf("expected 1 missing branch warning, found %d", warnings)
+	}
+
+	if err := checkResultError("Github", res); err != nil {
+		t.Errorf("expected no access error for a missing branch, %v", err)
+	}
+}
+
+func TestProviderGithubClientCheckAccessBranchCache(t *testing.T) {
+	refCalls := &atomic.Int32{}
+	c := newBranchGithubTestClient(t, refCalls)
+
+	_, err := c.CheckGithubRepoAccess(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from github access check, %v", err)
+	}
+
+	// cached results keep the branch check
+	res, _ := c.CheckGithubRepoAccess(context.Background())
+	if refCalls.Load() != 2 {
+		t.Errorf("expected cached branch checks, found %d ref lookups", refCalls.Load())
+	}
+
+	missing := 0
+	for _, r := range res {
+		if r.MissingBranch {
+			missing++
+		}
+	}
+
+	if missing != 1 {
+		t.Errorf("expected 1 cached missing branch, found %d", missing)
+	}
+
+	// a changed branch is checked again
+	c.cfg.Gitops.Apps.Branch = "main"
+	res, _ = c.CheckGithubRepoAccess(context.Background())
+	if refCalls.Load() != 3 {
+		t.Errorf("expected the changed branch checked again, found %d ref lookups", refCalls.Load())
+	}
+
+	for _, r := range res {
+		if r.MissingBranch {
+			t.Errorf("unexpected missing branch after changing it, %v", r)
+		}
+	}
+}
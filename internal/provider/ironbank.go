@@ -17,29 +17,41 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/MetroStar/quartzctl/internal/util"
 )
 
-// IronbankClient represents a client for interacting with the Ironbank API.
+// IronbankDefaultRegistry is the registry host checked when ironbank.registry isn't set.
+const IronbankDefaultRegistry = "registry1.dso.mil"
+
+// IronbankClient represents a client for verifying Ironbank registry credentials.
 type IronbankClient struct {
-	providerName string                 // The name of the provider.
-	username     string                 // The username for authentication.
-	password     string                 // The password for authentication.
-	httpClient   util.HttpClientFactory // The HTTP client factory for making requests.
+	providerName string                // The name of the provider.
+	registry     string                // The registry host to authenticate against.
+	username     string                // The username for authentication.
+	password     string                // The password for authentication.
+	auth         RegistryAuthenticator // The registry auth client performing the handshake.
 }
 
 // IronbankCheckAccessResult represents the result of an Ironbank access check.
 type IronbankCheckAccessResult struct {
-	StatusCode int    // The HTTP status code returned by the Ironbank API.
+	Registry   string // The registry host authenticated against.
+	StatusCode int    // The HTTP status code of the final authenticated registry request.
 	Username   string // The username used for the access check.
 	Error      error  // Any error encountered during the access check.
 }
 
-// NewIronbankClient creates a new IronbankClient instance with the specified credentials.
+// NewIronbankClient creates a new IronbankClient instance with the specified credentials,
+// authenticating against the default Ironbank registry.
 // Returns an error if the username or password is missing.
 func NewIronbankClient(httpClient util.HttpClientFactory, providerName string, username string, password string) (*IronbankClient, error) {
+	return NewIronbankRegistryClient(NewOciRegistryClient(httpClient), providerName, "", username, password)
+}
+
+// NewIronbankRegistryClient creates a new IronbankClient authenticating against the registry host,
+// or the default Ironbank registry if empty, with the given registry auth client.
+// Returns an error if the username or password is missing.
+func NewIronbankRegistryClient(auth RegistryAuthenticator, providerName string, registry string, username string, password string) (*IronbankClient, error) {
 	if username == "" || password == "" {
 		return nil, fmt.Errorf("ironbank user/password not found")
 	}
@@ -48,11 +60,16 @@ func NewIronbankClient(httpClient util.HttpClientFactory, providerName string, u
 		providerName = "Ironbank"
 	}
 
+	if registry == "" {
+		registry = IronbankDefaultRegistry
+	}
+
 	return &IronbankClient{
 		providerName: providerName,
+		registry:     registry,
 		username:     username,
 		password:     password,
-		httpClient:   httpClient,
+		auth:         auth,
 	}, nil
 }
 
@@ -61,58 +78,27 @@ func (c *IronbankClient) ProviderName() string {
 	return c.providerName
 }
 
-// CheckAccess performs an access check against the Ironbank API.
+// CheckAccess performs a registry auth handshake with the credentials against the Ironbank registry.
 // It returns an IronbankCheckAccessResult containing the result of the check.
 func (c *IronbankClient) CheckAccess(ctx context.Context) ProviderCheckResult {
-	ibUrl := "https://registry1.dso.mil/api/v2.0/projects/ironbank/repositories"
-
-	client := c.httpClient.NewClient()
-	req, err := http.NewRequest("GET", ibUrl, nil)
-	if err != nil {
-		return IronbankCheckAccessResult{
-			Error:    err,
-			Username: c.username,
-		}
-	}
-
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return IronbankCheckAccessResult{
-			Error:    err,
-			Username: c.username,
-		}
-	}
-
-	defer resp.Body.Close()
-
-	ok := 200 <= resp.StatusCode && resp.StatusCode < 300
-	if !ok {
-		return IronbankCheckAccessResult{
-			StatusCode: resp.StatusCode,
-			Username:   c.username,
-			Error:      fmt.Errorf("ironbank connection status %s", resp.Status),
-		}
-	}
+	status, err := c.auth.Authenticate(ctx, c.registry, RegistryCredentials{Username: c.username, Password: c.password})
 
 	return IronbankCheckAccessResult{
-		StatusCode: resp.StatusCode,
+		Registry:   c.registry,
+		StatusCode: status,
 		Username:   c.username,
-		Error:      nil,
+		Error:      err,
 	}
 }
 
 // ToTable converts the IronbankCheckAccessResult into table headers and rows for display.
 func (r IronbankCheckAccessResult) ToTable() ([]string, []ProviderCheckResultRow) {
-	headers := []string{"User", "Status"}
+	headers := []string{"Registry", "User", "Status"}
 	rows := []ProviderCheckResultRow{
 		{
 			Status: r.Error == nil,
 			Error:  r.Error,
-			Data:   []string{r.Username, fmt.Sprint(r.StatusCode)},
+			Data:   []string{r.Registry, r.Username, fmt.Sprint(r.StatusCode)},
 		},
 	}
 
@@ -17,9 +17,12 @@ package provider
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/util"
 )
@@ -81,3 +84,179 @@ func TestProviderIronbankClientCheckAccess(t *testing.T) {
 		t.Errorf("unexpected response from ironbank check access table, %v, %v", headers, rows)
 	}
 }
+
+// registryAuthenticatorMock accepts only the expected credentials, recording the registry authenticated against.
+type registryAuthenticatorMock struct {
+	expected RegistryCredentials
+	registry string
+}
+
+func (m *registryAuthenticatorMock) Authenticate(ctx context.Context, registry string, creds RegistryCredentials) (int, error) {
+	m.registry = registry
+	if creds != m.expected {
+		return http.StatusUnauthorized, fmt.Errorf("authentication failed for %s, status 401", registry)
+	}
+	return http.StatusOK, nil
+}
+
+func TestProviderIronbankClientCheckAccessCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		password string
+		expected string
+		status   bool
+	}{
+		{"valid", "", "supersecretpassword", IronbankDefaultRegistry, true},
+		{"invalid", "", "wrongpassword", IronbankDefaultRegistry, false},
+		{"configured registry", "registry.example.com", "supersecretpassword", "registry.example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &registryAuthenticatorMock{expected: RegistryCredentials{Username: "testuser", Password: "supersecretpassword"}}
+
+			c, err := NewIronbankRegistryClient(auth, "", tt.registry, "testuser", tt.password)
+			if err != nil {
+				t.Fatalf("unexpected error from ironbank client constructor, %v", err)
+			}
+
+			_, rows := c.CheckAccess(context.Background()).ToTable()
+			if len(rows) != 1 || rows[0].Status != tt.status || rows[0].Data[0] != tt.expected {
+				t.Errorf("unexpected ironbank check access rows, %+v", rows)
+			}
+
+			if auth.registry != tt.expected {
+				t.Errorf("unexpected registry authenticated against, expected %v, found %v", tt.expected, auth.registry)
+			}
+		})
+	}
+}
+
+func TestProviderOciRegistryClientAuthenticate(t *testing.T) {
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			resp := &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+				Header:     make(http.Header),
+			}
+
+			switch {
+			case req.URL.Host == "auth.example.com":
+				user, pass, _ := req.BasicAuth()
+				if user != "testuser" || pass != "supersecretpassword" {
+					resp.StatusCode = http.StatusUnauthorized
+					return resp
+				}
+				if req.URL.Query().Get("service") != "registry.example.com" {
+					t.Errorf("unexpected token service, %v", req.URL.String())
+				}
+				resp.Body = io.NopCloser(bytes.NewBufferString(`{"token":"testtoken"}`))
+			case req.Header.Get("Authorization") != "Bearer testtoken":
+				resp.StatusCode = http.StatusUnauthorized
+				resp.Header.Set("WWW-Authenticate", `Bearer realm="https://auth.example.com/token",service="registry.example.com"`)
+			}
+
+			return resp
+		},
+	}
+
+	c := NewOciRegistryClient(httpClient)
+
+	status, err := c.Authenticate(context.Background(), "registry.example.com", RegistryCredentials{Username: "testuser", Password: "supersecretpassword"})
+	if err != nil || status != http.StatusOK {
+		t.Errorf("unexpected result authenticating with valid credentials, %v, %v", status, err)
+	}
+
+	status, err = c.Authenticate(context.Background(), "registry.example.com", RegistryCredentials{Username: "testuser", Password: "wrongpassword"})
+	if err == nil || !strings.Contains(err.Error(), "authentication failed for registry.example.com") || status != http.StatusUnauthorized {
+		t.Errorf("expected authentication failure with invalid credentials, %v, %v", status, err)
+	}
+}
+
+func TestProviderOciRegistryParseAuthChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		expected  map[string]string
+	}{
+		{"simple", `Bearer realm="https://auth.example.com/token",service="registry.example.com"`, map[string]string{"realm": "https://auth.example.com/token", "service": "registry.example.com"}},
+		{"quoted comma", `Bearer realm="https://auth.example.com/token",scope="repository:a:pull,push",service="registry.example.com"`, map[string]string{"realm": "https://auth.example.com/token", "scope": "repository:a:pull,push", "service": "registry.example.com"}},
+		{"spaces and unquoted", `Bearer realm="https://auth.example.com/token", service=registry.example.com, error="insufficient_scope"`, map[string]string{"realm": "https://auth.example.com/token", "service": "registry.example.com", "error": "insufficient_scope"}},
+		{"escaped quote", `Basic realm="a \"quoted\" realm"`, map[string]string{"realm": `a "quoted" realm`}},
+		{"no params", `Basic`, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseAuthChallenge(tt.challenge)
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("unexpected challenge params, expected %v, found %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProviderOciRegistryClientTokenRenewal(t *testing.T) {
+	tokens := 0
+	current := ""
+
+	httpClient := util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			resp := &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+				Header:     make(http.Header),
+			}
+
+			switch {
+			case req.URL.Host == "auth.example.com":
+				if req.URL.Query().Get("scope") != "repository:a:pull,push" {
+					t.Errorf("unexpected token scope, %v", req.URL.String())
+				}
+				tokens++
+				current = fmt.Sprintf("token%d", tokens)
+				resp.Body = io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"token":"%s","expires_in":300}`, current)))
+			case req.Header.Get("Authorization") != "Bearer "+current:
+				resp.StatusCode = http.StatusUnauthorized
+				resp.Header.Set("WWW-Authenticate", `Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a:pull,push"`)
+			case req.Method == http.MethodPut:
+				if b, _ := io.ReadAll(req.Body); string(b) != `{}` {
+					t.Errorf("unexpected manifest body, %q", b)
+				}
+			}
+
+			return resp
+		},
+	}
+
+	c := NewOciRegistryClient(httpClient)
+	ref := ImageReference{Registry: "registry.example.com", Repository: "a", Tag: "1.0"}
+	m := RegistryManifest{MediaType: "application/vnd.oci.image.manifest.v1+json", Body: []byte(`{}`)}
+
+	if err := c.PutManifest(context.Background(), ref, m, RegistryCredentials{}); err != nil || tokens != 1 {
+		t.Fatalf("unexpected result pushing manifest, %v tokens, %v", tokens, err)
+	}
+
+	// cached until it expires
+	if err := c.PutManifest(context.Background(), ref, m, RegistryCredentials{}); err != nil || tokens != 1 {
+		t.Errorf("expected cached token to be reused, %v tokens, %v", tokens, err)
+	}
+
+	key, _ := authScope(ref, true)
+	if a := c.auth[key]; a.Expires.Before(time.Now().Add(4*time.Minute)) || a.Expires.After(time.Now().Add(5*time.Minute)) {
+		t.Errorf("unexpected token expiry, %v", a.Expires)
+	}
+
+	c.auth[key] = ociAuthorization{Header: c.auth[key].Header, Expires: time.Now().Add(-time.Second)}
+	if err := c.PutManifest(context.Background(), ref, m, RegistryCredentials{}); err != nil || tokens != 2 {
+		t.Errorf("expected expired token to be renewed, %v tokens, %v", tokens, err)
+	}
+
+	// revoked by the registry before it expired, re-authenticated once and the body replayed
+	current = "revoked"
+	if err := c.PutManifest(context.Background(), ref, m, RegistryCredentials{}); err != nil || tokens != 3 {
+		t.Errorf("expected rejected token to be renewed, %v tokens, %v", tokens, err)
+	}
+}
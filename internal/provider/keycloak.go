@@ -0,0 +1,314 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// KeycloakClient represents a client for interacting with the Keycloak admin API.
+type KeycloakClient struct {
+	providerName string                 // The name of the provider.
+	baseUrl      string                 // The base url of the Keycloak server.
+	username     string                 // The admin username, authenticated against the master realm.
+	password     string                 // The admin password.
+	httpClient   util.HttpClientFactory // The HTTP client factory for making requests.
+}
+
+// KeycloakCheckAccessResult represents the result of a Keycloak admin access check.
+type KeycloakCheckAccessResult struct {
+	Url      string // The base url of the Keycloak server.
+	Username string // The admin username used for the access check.
+	Error    error  // Any error encountered during the access check.
+}
+
+// KeycloakRealmExport contains the exported client configuration of a Keycloak realm.
+type KeycloakRealmExport struct {
+	Realm   string                   `json:"realm"`   // The realm name.
+	Clients []map[string]interface{} `json:"clients"` // The realm clients, including their protocol mappers.
+}
+
+// keycloakRedactedClientFields lists client fields removed from exports, keeping them safe to store and diff.
+var keycloakRedactedClientFields = []string{"secret", "registrationAccessToken"}
+
+// NewKeycloakClient creates a new KeycloakClient for the server at baseUrl with the given admin credentials.
+// Returns an error if the url or credentials are missing.
+func NewKeycloakClient(httpClient util.HttpClientFactory, providerName string, baseUrl string, username string, password string) (KeycloakClient, error) {
+	if baseUrl == "" {
+		return KeycloakClient{}, fmt.Errorf("keycloak url not found")
+	}
+
+	if username == "" || password == "" {
+		return KeycloakClient{}, fmt.Errorf("keycloak admin user/password not found")
+	}
+
+	if providerName == "" {
+		providerName = "Keycloak"
+	}
+
+	return KeycloakClient{
+		providerName: providerName,
+		baseUrl:      strings.TrimSuffix(baseUrl, "/"),
+		username:     username,
+		password:     password,
+		httpClient:   httpClient,
+	}, nil
+}
+
+// ProviderName returns the name of the Keycloak provider.
+func (c KeycloakClient) ProviderName() string {
+	return c.providerName
+}
+
+// CheckAccess verifies the admin credentials can obtain an access token.
+func (c KeycloakClient) CheckAccess(ctx context.Context) ProviderCheckResult {
+	_, err := c.accessToken(ctx, c.httpClient.NewClient())
+
+	return KeycloakCheckAccessResult{
+		Url:      c.baseUrl,
+		Username: c.username,
+		Error:    err,
+	}
+}
+
+// ExportRealms exports the clients and their protocol mappers for each of the given realms.
+// Client secrets are redacted from the export.
+func (c KeycloakClient) ExportRealms(ctx context.Context, realms []string) ([]KeycloakRealmExport, error) {
+	client := c.httpClient.NewClient()
+
+	token, err := c.accessToken(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []KeycloakRealmExport
+	for _, realm := range realms {
+		log.Debug("Exporting keycloak realm", "realm", realm)
+
+		var clients []map[string]interface{}
+		err := c.get(ctx, client, token, fmt.Sprintf("/admin/realms/%s/clients", url.PathEscape(realm)), &clients)
+		if err != nil {
+			return res, fmt.Errorf("failed to export keycloak realm %s, %w", realm, err)
+		}
+
+		for _, cl := range clients {
+			for _, f := range keycloakRedactedClientFields {
+				delete(cl, f)
+			}
+		}
+
+		res = append(res, KeycloakRealmExport{
+			Realm:   realm,
+			Clients: clients,
+		})
+	}
+
+	return res, nil
+}
+
+// RealmOtp returns the current OTP configuration of the realm, from its CONFIGURE_TOTP required action.
+// Enabled reflects whether the action is available and Required whether new users must configure OTP.
+func (c KeycloakClient) RealmOtp(ctx context.Context, realm string) (schema.ApplicationEnvironmentOtpConfig, error) {
+	client := c.httpClient.NewClient()
+
+	token, err := c.accessToken(ctx, client)
+	if err != nil {
+		return schema.ApplicationEnvironmentOtpConfig{}, err
+	}
+
+	action, err := c.otpRequiredAction(ctx, client, token, realm)
+	if err != nil {
+		return schema.ApplicationEnvironmentOtpConfig{}, err
+	}
+
+	return action.otp(), nil
+}
+
+// ReconcileRealmOtp updates the realm's CONFIGURE_TOTP required action to match the desired OTP
+// configuration. OTP can only be required when it's enabled. Returns true if the realm was changed.
+func (c KeycloakClient) ReconcileRealmOtp(ctx context.Context, realm string, desired schema.ApplicationEnvironmentOtpConfig) (bool, error) {
+	desired.Required = desired.Enabled && desired.Required
+	client := c.httpClient.NewClient()
+
+	token, err := c.accessToken(ctx, client)
+	if err != nil {
+		return false, err
+	}
+
+	action, err := c.otpRequiredAction(ctx, client, token, realm)
+	if err != nil {
+		return false, err
+	}
+
+	if action.otp() == desired {
+		log.Debug("Keycloak realm otp up to date", "realm", realm, "otp", desired)
+		return false, nil
+	}
+
+	action["enabled"] = desired.Enabled
+	action["defaultAction"] = desired.Required
+
+	log.Info("Updating keycloak realm otp", "realm", realm, "otp", desired)
+	err = c.do(ctx, client, token, "PUT", keycloakOtpActionPath(realm), action, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to update otp for keycloak realm %s, %w", realm, err)
+	}
+
+	return true, nil
+}
+
+// keycloakRequiredAction is a Keycloak required action representation, kept as a map so
+// unknown fields are preserved when it's written back.
+type keycloakRequiredAction map[string]interface{}
+
+// otp converts the required action state to the equivalent OTP configuration.
+func (a keycloakRequiredAction) otp() schema.ApplicationEnvironmentOtpConfig {
+	enabled, _ := a["enabled"].(bool)
+	required, _ := a["defaultAction"].(bool)
+
+	return schema.ApplicationEnvironmentOtpConfig{
+		Enabled:  enabled,
+		Required: enabled && required,
+	}
+}
+
+// otpRequiredAction retrieves the realm's CONFIGURE_TOTP required action.
+func (c KeycloakClient) otpRequiredAction(ctx context.Context, client *http.Client, token string, realm string) (keycloakRequiredAction, error) {
+	var action keycloakRequiredAction
+	err := c.get(ctx, client, token, keycloakOtpActionPath(realm), &action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otp for keycloak realm %s, %w", realm, err)
+	}
+
+	return action, nil
+}
+
+// keycloakOtpActionPath returns the admin API path of the realm's CONFIGURE_TOTP required action.
+func keycloakOtpActionPath(realm string) string {
+	return fmt.Sprintf("/admin/realms/%s/authentication/required-actions/CONFIGURE_TOTP", url.PathEscape(realm))
+}
+
+// accessToken authenticates the admin user against the master realm and returns an access token.
+func (c KeycloakClient) accessToken(ctx context.Context, client *http.Client) (string, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"client_id":  {"admin-cli"},
+		"username":   {c.username},
+		"password":   {c.password},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseUrl+"/realms/master/protocol/openid-connect/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("keycloak authentication status %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("keycloak authentication returned no access token")
+	}
+
+	return token.AccessToken, nil
+}
+
+// get performs an authenticated admin API request and decodes the json response into v.
+func (c KeycloakClient) get(ctx context.Context, client *http.Client, token string, path string, v interface{}) error {
+	return c.do(ctx, client, token, "GET", path, nil, v)
+}
+
+// do performs an authenticated admin API request with an optional json body, decoding
+// the json response into v when v isn't nil.
+func (c KeycloakClient) do(ctx context.Context, client *http.Client, token string, method string, path string, body interface{}, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, r)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Authorization", "Bearer "+token)
+	req.Header.Add("Accept", "application/json")
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("keycloak %s %s status %s", method, path, resp.Status)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ToTable converts the KeycloakCheckAccessResult into table headers and rows for display.
+func (r KeycloakCheckAccessResult) ToTable() ([]string, []ProviderCheckResultRow) {
+	headers := []string{"Url", "User"}
+	rows := []ProviderCheckResultRow{
+		{
+			Status: r.Error == nil,
+			Error:  r.Error,
+			Data:   []string{r.Url, r.Username},
+		},
+	}
+
+	return headers, rows
+}
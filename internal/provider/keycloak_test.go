@@ -0,0 +1,290 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// newKeycloakAdminApiMock mocks the keycloak token and realm clients admin endpoints,
+// failing authentication for any password other than adminpassword.
+func newKeycloakAdminApiMock(t *testing.T) util.HttpClientFactoryMock {
+	json200 := func(v interface{}) *http.Response {
+		b, _ := json.Marshal(v)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(b)),
+			Header:     http.Header{},
+		}
+	}
+	status := func(code int, s string) *http.Response {
+		return &http.Response{
+			StatusCode: code,
+			Status:     s,
+			Body:       io.NopCloser(bytes.NewBufferString("")),
+			Header:     http.Header{},
+		}
+	}
+
+	return util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			if req.URL.Host != "keycloak.example.com" {
+				t.Errorf("unexpected http request url, found %v", req.URL.String())
+			}
+
+			switch {
+			case req.URL.Path == "/realms/master/protocol/openid-connect/token":
+				req.ParseForm()
+				if req.Method != "POST" || req.PostForm.Get("client_id") != "admin-cli" || req.PostForm.Get("username") != "admin" {
+					t.Errorf("unexpected keycloak token request, %v %v", req.Method, req.PostForm)
+				}
+
+				if req.PostForm.Get("password") != "adminpassword" {
+					return status(401, "401 Unauthorized")
+				}
+				return json200(map[string]interface{}{"access_token": "testtoken"})
+
+			case strings.HasPrefix(req.URL.Path, "/admin/realms/"):
+				if req.Header.Get("Authorization") != "Bearer testtoken" {
+					return status(401, "401 Unauthorized")
+				}
+
+				realm := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/admin/realms/"), "/clients")
+				if realm == "missing" {
+					return status(404, "404 Not Found")
+				}
+
+				return json200([]map[string]interface{}{
+					{
+						"clientId": realm + "-app",
+						"secret":   "supersecret",
+						"protocolMappers": []map[string]interface{}{
+							{
+								"name":           "groups",
+								"protocol":       "openid-connect",
+								"protocolMapper": "oidc-group-membership-mapper",
+								"config": map[string]string{
+									"claim.name": "groups",
+								},
+							},
+						},
+					},
+				})
+			}
+
+			t.Errorf("unexpected keycloak request %s", req.URL.Path)
+			return status(404, "404 Not Found")
+		},
+	}
+}
+
+func TestProviderKeycloakClientProviderName(t *testing.T) {
+	_, err := NewKeycloakClient(nil, "", "", "admin", "adminpassword")
+	if err == nil {
+		t.Error("expected error from keycloak client for missing url")
+	}
+
+	_, err = NewKeycloakClient(nil, "", "https://keycloak.example.com", "admin", "")
+	if err == nil {
+		t.Error("expected error from keycloak client for missing credentials")
+	}
+
+	c, err := NewKeycloakClient(nil, "", "https://keycloak.example.com/", "admin", "adminpassword")
+	if err != nil {
+		t.Errorf("unexpected error from keycloak client constructor, %v", err)
+	}
+
+	if c.ProviderName() != "Keycloak" {
+		t.Errorf("unexpected default provider name from keycloak client, expected %v, found %v", "Keycloak", c.ProviderName())
+	}
+}
+
+func TestProviderKeycloakClientExportRealms(t *testing.T) {
+	c, _ := NewKeycloakClient(newKeycloakAdminApiMock(t), "", "https://keycloak.example.com/", "admin", "adminpassword")
+
+	res, err := c.ExportRealms(context.Background(), []string{"infra", "dev"})
+	if err != nil {
+		t.Fatalf("unexpected error from keycloak export, %v", err)
+	}
+
+	if len(res) != 2 || res[0].Realm != "infra" || res[1].Realm != "dev" {
+		t.Fatalf("unexpected realms in keycloak export, %v", res)
+	}
+
+	for _, r := range res {
+		if len(r.Clients) != 1 {
+			t.Fatalf("unexpected clients in keycloak export for %s, %v", r.Realm, r.Clients)
+		}
+
+		cl := r.Clients[0]
+		if cl["clientId"] != r.Realm+"-app" {
+			t.Errorf("unexpected client id in keycloak export, %v", cl["clientId"])
+		}
+
+		if _, ok := cl["secret"]; ok {
+			t.Errorf("expected client secret to be redacted from keycloak export, %v", cl)
+		}
+
+		mappers, ok := cl["protocolMappers"].([]interface{})
+		if !ok || len(mappers) != 1 {
+			t.Errorf("expected protocol mappers in keycloak export, %v", cl)
+		}
+	}
+
+	// round trips as json
+	b, err := json.Marshal(res)
+	if err != nil || !strings.Contains(string(b), `"protocolMapper":"oidc-group-membership-mapper"`) {
+		t.Errorf("unexpected keycloak export json, %v, %s", err, b)
+	}
+}
+
+func TestProviderKeycloakClientExportRealmsError(t *testing.T) {
+	c, _ := NewKeycloakClient(newKeycloakAdminApiMock(t), "", "https://keycloak.example.com", "admin", "adminpassword")
+
+	res, err := c.ExportRealms(context.Background(), []string{"infra", "missing"})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected error for missing keycloak realm, found %v", err)
+	}
+
+	if len(res) != 1 {
+		t.Errorf("expected realms exported before the error to be returned, %v", res)
+	}
+
+	c, _ = NewKeycloakClient(newKeycloakAdminApiMock(t), "", "https://keycloak.example.com", "admin", "wrong")
+	_, err = c.ExportRealms(context.Background(), []string{"infra"})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected authentication error from keycloak export, found %v", err)
+	}
+}
+
+func TestProviderKeycloakClientCheckAccess(t *testing.T) {
+	c, _ := NewKeycloakClient(newKeycloakAdminApiMock(t), "", "https://keycloak.example.com", "admin", "adminpassword")
+
+	_, rows := c.CheckAccess(context.Background()).ToTable()
+	if len(rows) != 1 || !rows[0].Status || rows[0].Error != nil {
+		t.Errorf("unexpected keycloak check access result, %v", rows)
+	}
+
+	c, _ = NewKeycloakClient(newKeycloakAdminApiMock(t), "", "https://keycloak.example.com", "admin", "wrong")
+
+	_, rows = c.CheckAccess(context.Background()).ToTable()
+	if len(rows) != 1 || rows[0].Status || rows[0].Error == nil {
+		t.Errorf("expected keycloak check access failure, %v", rows)
+	}
+}
+
+// newKeycloakOtpApiMock extends the admin api mock with the CONFIGURE_TOTP required action
+// endpoints, backed by the actions map keyed by realm, and counts the updates made.
+func newKeycloakOtpApiMock(t *testing.T, actions map[string]keycloakRequiredAction, puts *int) util.HttpClientFactoryMock {
+	admin := newKeycloakAdminApiMock(t)
+
+	return util.HttpClientFactoryMock{
+		Callback: func(req *http.Request) *http.Response {
+			realm, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/admin/realms/"), "/authentication/required-actions/CONFIGURE_TOTP")
+			if !ok {
+				return admin.Callback(req)
+			}
+
+			if req.Header.Get("Authorization") != "Bearer testtoken" {
+				t.Errorf("unexpected keycloak required action authorization, %v", req.Header.Get("Authorization"))
+			}
+
+			switch req.Method {
+			case "GET":
+				b, _ := json.Marshal(actions[realm])
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBuffer(b)), Header: http.Header{}}
+			case "PUT":
+				var action keycloakRequiredAction
+				if err := json.NewDecoder(req.Body).Decode(&action); err != nil {
+					t.Errorf("unexpected keycloak required action body, %v", err)
+				}
+				actions[realm] = action
+				*puts++
+				return &http.Response{StatusCode: 204, Body: io.NopCloser(bytes.NewBufferString("")), Header: http.Header{}}
+			}
+
+			t.Errorf("unexpected keycloak required action request %s", req.Method)
+			return &http.Response{StatusCode: 405, Status: "405 Method Not Allowed", Body: io.NopCloser(bytes.NewBufferString("")), Header: http.Header{}}
+		},
+	}
+}
+
+func TestProviderKeycloakClientReconcileRealmOtp(t *testing.T) {
+	actions := map[string]keycloakRequiredAction{
+		"infra": {"alias": "CONFIGURE_TOTP", "enabled": true, "defaultAction": false},
+		"dev":   {"alias": "CONFIGURE_TOTP", "enabled": true, "defaultAction": true},
+	}
+	var puts int
+	c, _ := NewKeycloakClient(newKeycloakOtpApiMock(t, actions, &puts), "", "https://keycloak.example.com", "admin", "adminpassword")
+
+	// infra is enabled but not required, require it
+	changed, err := c.ReconcileRealmOtp(context.Background(), "infra", schema.ApplicationEnvironmentOtpConfig{Enabled: true, Required: true})
+	if err != nil || !changed || puts != 1 {
+		t.Errorf("expected keycloak realm otp to be updated, changed %v, puts %v, err %v", changed, puts, err)
+	}
+
+	if actions["infra"]["defaultAction"] != true || actions["infra"]["enabled"] != true || actions["infra"]["alias"] != "CONFIGURE_TOTP" {
+		t.Errorf("unexpected keycloak required action after update, %v", actions["infra"])
+	}
+
+	// already matching, no update
+	changed, err = c.ReconcileRealmOtp(context.Background(), "infra", schema.ApplicationEnvironmentOtpConfig{Enabled: true, Required: true})
+	if err != nil || changed || puts != 1 {
+		t.Errorf("expected no keycloak realm otp update, changed %v, puts %v, err %v", changed, puts, err)
+	}
+
+	// disabling otp also clears the requirement
+	changed, err = c.ReconcileRealmOtp(context.Background(), "dev", schema.ApplicationEnvironmentOtpConfig{Enabled: false, Required: true})
+	if err != nil || !changed || puts != 2 {
+		t.Errorf("expected keycloak realm otp to be disabled, changed %v, puts %v, err %v", changed, puts, err)
+	}
+
+	if actions["dev"]["defaultAction"] != false || actions["dev"]["enabled"] != false {
+		t.Errorf("unexpected keycloak required action after disable, %v", actions["dev"])
+	}
+}
+
+func TestProviderKeycloakClientRealmOtp(t *testing.T) {
+	actions := map[string]keycloakRequiredAction{
+		"infra": {"alias": "CONFIGURE_TOTP", "enabled": true, "defaultAction": true},
+		"dev":   {"alias": "CONFIGURE_TOTP", "enabled": false, "defaultAction": true},
+	}
+	var puts int
+	c, _ := NewKeycloakClient(newKeycloakOtpApiMock(t, actions, &puts), "", "https://keycloak.example.com", "admin", "adminpassword")
+
+	otp, err := c.RealmOtp(context.Background(), "infra")
+	if err != nil || !otp.Enabled || !otp.Required {
+		t.Errorf("unexpected keycloak realm otp, %v, %v", otp, err)
+	}
+
+	// a default action that isn't enabled isn't required
+	otp, err = c.RealmOtp(context.Background(), "dev")
+	if err != nil || otp.Enabled || otp.Required {
+		t.Errorf("unexpected keycloak realm otp, %v, %v", otp, err)
+	}
+
+	if puts != 0 {
+		t.Errorf("unexpected keycloak required action updates reading realm otp, %v", puts)
+	}
+}
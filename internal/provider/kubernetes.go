@@ -15,8 +15,11 @@
 package provider
 
 import (
+	"bytes"
 	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -32,16 +35,24 @@ import (
 
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
 	"kmodules.xyz/client-go/tools/wait"
 	"sigs.k8s.io/yaml"
 )
@@ -56,16 +67,28 @@ type KubernetesProviderClient interface {
 	Provider
 	LookupKind(ctx context.Context, kind string) (schema.GroupVersionResource, error)
 	WaitConditionState(ctx context.Context, kind schema.GroupVersionResource, ns string, name string, state string, timeoutSeconds int) error
-	PrintClusterInfo(ctx context.Context)
+	WaitResourcesDeleted(ctx context.Context, kind schema.GroupVersionResource, ns string, name string, timeoutSeconds int, pollSeconds int) error
+	PrintClusterInfo(ctx context.Context, opts ClusterInfoOpts) []VirtualServiceInfo
+	GetAppConnectionInfo(ctx context.Context, name string, opts quartzSchema.ApplicationLookupConfig) KubernetesAppConnectionInfo
 	WriteKubeconfigFile(path string) error
+	WriteKubeconfig(w io.Writer) error
 	RefreshExternalSecrets(ctx context.Context) ([]KubernetesResource, error)
 	Export(ctx context.Context, cfg quartzSchema.ExportConfig) (map[string][]byte, error)
 	GetConfigMapValue(ctx context.Context, ns string, name string) (map[string]string, error)
+	ClearConfigMap(ctx context.Context, ns string, name string) error
+	SetConfigMapValue(ctx context.Context, ns string, name string, key string, value string) error
+	WaitConfigMapValue(ctx context.Context, ns string, name string, key string, value string, timeoutSeconds int) error
 	GetSecretValue(ctx context.Context, ns string, name string) (map[string]string, error)
-	Restart(ctx context.Context, kind schema.GroupVersionResource, ns string, name string) error
+	Restart(ctx context.Context, kind schema.GroupVersionResource, ns string, name string) ([]KubernetesResource, error)
+	PrintHealthSummary(ctx context.Context) error
+	WaitForRollout(ctx context.Context, kind schema.GroupVersionResource, ns string, name string, timeoutSeconds int, pollSeconds int) error
 	GetDaemonSetStatus(ctx context.Context, kind schema.GroupVersionResource, ns string, name string) (int64, int64, error)
-	CleanupStuckTerminatingPods(ctx context.Context, timeout time.Duration) ([]string, error)
+	CleanupStuckTerminatingPods(ctx context.Context, timeout time.Duration, opts TerminatingPodCleanupOpts) ([]TerminatingPodInfo, error)
 	ListVirtualServices(ctx context.Context) ([]VirtualServiceInfo, error)
+	ListEvents(ctx context.Context, ns string, all bool) ([]KubernetesEvent, error)
+	WatchEvents(ctx context.Context, ns string, all bool, onEvent func(KubernetesEvent)) error
+	NodeUsage(ctx context.Context) ([]KubernetesResourceUsage, error)
+	PodUsage(ctx context.Context, ns string) ([]KubernetesResourceUsage, error)
 }
 
 // KubernetesClient is the implementation of the Kubernetes provider client.
@@ -91,6 +114,7 @@ type KubeconfigInfo struct {
 	CertificateAuthority string
 	Token                string
 	Expiration           time.Time
+	UserInfo             KubeconfigUserInfoFunc // Cloud provider supplied user auth, defaults by configured cloud when nil.
 }
 
 // KubernetesAppConnectionInfo contains information about an application's connection in Kubernetes.
@@ -102,6 +126,32 @@ type KubernetesAppConnectionInfo struct {
 	Error          error
 }
 
+// TerminatingPodInfo describes a pod deleted after being stuck in Terminating state.
+type TerminatingPodInfo struct {
+	Namespace      string
+	Name           string
+	Node           string
+	TerminatingFor time.Duration // age of the pod's deletionTimestamp when it was deleted
+	Forced         bool          // deleted with a zero grace period
+}
+
+// ClusterInfoOpts controls which applications PrintClusterInfo reports and how.
+type ClusterInfoOpts struct {
+	Apps []string // application config keys or descriptions to limit the summary to, all when empty
+	Wide bool     // add the namespace, admin secret and ingress columns each row was looked up from
+}
+
+// TerminatingPodCleanupOpts controls how CleanupStuckTerminatingPods deletes stuck pods.
+type TerminatingPodCleanupOpts struct {
+	Force            bool // delete with a zero grace period instead of the pod's own
+	RemoveFinalizers bool // clear the pod's finalizers before a forced delete
+}
+
+// String returns the pod as namespace/name.
+func (p TerminatingPodInfo) String() string {
+	return fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+}
+
 // KubernetesResource represents a Kubernetes resource.
 type KubernetesResource struct {
 	Name      string
@@ -118,6 +168,68 @@ type VirtualServiceInfo struct {
 	Gateways  []string
 }
 
+// KubernetesEvent is a summary of a Kubernetes event.
+type KubernetesEvent struct {
+	Time      time.Time
+	Namespace string
+	Type      string // Normal or Warning
+	Reason    string
+	Object    string // kind/name of the involved object
+	Message   string
+}
+
+// KubernetesResourceUsage is the CPU and memory usage of a node or pod, from the metrics API.
+type KubernetesResourceUsage struct {
+	Namespace              string // empty for nodes
+	Name                   string
+	CPUMillis              int64
+	MemoryBytes            int64
+	AllocatableCPUMillis   int64 // nodes only, 0 if unknown
+	AllocatableMemoryBytes int64 // nodes only, 0 if unknown
+}
+
+// KubernetesHealthSummary summarizes the health of the cluster after an install.
+type KubernetesHealthSummary struct {
+	NodesReady  int
+	NodesTotal  int
+	DaemonSets  []KubernetesDaemonSetHealth // critical DaemonSets, from the stage daemonset checks
+	FailingPods []string                    // namespace/name (reason) of pods neither Running and ready nor Completed
+}
+
+// KubernetesDaemonSetHealth is the readiness of a critical DaemonSet.
+type KubernetesDaemonSetHealth struct {
+	Namespace string
+	Name      string
+	Ready     int64
+	Desired   int64
+	Error     error
+}
+
+// Healthy reports whether the DaemonSet has all desired pods ready.
+func (d KubernetesDaemonSetHealth) Healthy() bool {
+	return d.Error == nil && d.Ready >= d.Desired
+}
+
+// Healthy reports whether all nodes are ready, all critical DaemonSets are ready and no pods are failing.
+func (h KubernetesHealthSummary) Healthy() bool {
+	if h.NodesTotal == 0 || h.NodesReady < h.NodesTotal || len(h.FailingPods) > 0 {
+		return false
+	}
+
+	return !slices.ContainsFunc(h.DaemonSets, func(d KubernetesDaemonSetHealth) bool {
+		return !d.Healthy()
+	})
+}
+
+// MeshOnly reports whether the VirtualService is only bound to the internal mesh gateway,
+// i.e. not exposed through an ingress gateway. Istio binds a VirtualService without gateways
+// to the mesh, so those are mesh-only too.
+func (vs VirtualServiceInfo) MeshOnly() bool {
+	return !slices.ContainsFunc(vs.Gateways, func(gw string) bool {
+		return gw != "mesh"
+	})
+}
+
 type KubernetesProviderCheckResult struct {
 	Status bool
 	Error  error
@@ -191,7 +303,11 @@ func (c KubernetesClient) WriteKubeconfig(w io.Writer) error {
 }
 
 // PrintClusterInfo prints information about the cluster and its applications.
-func (c KubernetesClient) PrintClusterInfo(ctx context.Context) {
+// When app names are given, only matching applications (by config key or description,
+// case-insensitive) are looked up and discovered services are not listed.
+// Returns the additional services discovered outside the configured applications.
+func (c KubernetesClient) PrintClusterInfo(ctx context.Context, opts ClusterInfoOpts) []VirtualServiceInfo {
+	filter := opts.Apps
 	apps := map[string]quartzSchema.ApplicationLookupConfig{}
 	configuredIngressNames := make(map[string]bool)
 
@@ -208,6 +324,12 @@ func (c KubernetesClient) PrintClusterInfo(ctx context.Context) {
 		if name == "" {
 			name = k
 		}
+
+		if len(filter) > 0 && !slices.ContainsFunc(filter, func(f string) bool {
+			return strings.EqualFold(f, k) || strings.EqualFold(f, name)
+		}) {
+			continue
+		}
 		apps[name] = v.Lookup
 
 		// Track configured ingress names to exclude from discovery
@@ -215,39 +337,62 @@ func (c KubernetesClient) PrintClusterInfo(ctx context.Context) {
 			configuredIngressNames[v.Lookup.Ingress.Name] = true
 		}
 	}
-	c.PrintClusterAppInfo(ctx, apps)
+	if len(filter) > 0 {
+		if len(apps) == 0 {
+			log.Warn("No applications matched", "apps", filter)
+		}
+
+		c.PrintClusterAppInfo(ctx, apps, opts.Wide)
+		return nil
+	}
+
+	c.PrintClusterAppInfo(ctx, apps, opts.Wide)
 
 	// Print additional discovered VirtualServices
-	c.PrintDiscoveredVirtualServices(ctx, configuredIngressNames)
+	return c.PrintDiscoveredVirtualServices(ctx, configuredIngressNames)
 }
 
-// PrintDiscoveredVirtualServices prints VirtualServices that are not in the configured applications.
-func (c KubernetesClient) PrintDiscoveredVirtualServices(ctx context.Context, excludeNames map[string]bool) {
+// DiscoverVirtualServices returns the externally gated VirtualServices that are not in the
+// configured applications (excludeNames), sorted by namespace and name.
+func (c KubernetesClient) DiscoverVirtualServices(ctx context.Context, excludeNames map[string]bool) ([]VirtualServiceInfo, error) {
 	virtualServices, err := c.ListVirtualServices(ctx)
 	if err != nil {
-		log.Debug("Failed to list VirtualServices", "error", err)
-		return
+		return nil, err
 	}
 
-	var rows [][]string
+	var res []VirtualServiceInfo
 	for _, vs := range virtualServices {
 		// Skip VirtualServices that are in the configured apps
 		if excludeNames[vs.Name] {
 			continue
 		}
 
-		// Skip mesh-only gateways (internal services)
-		isMeshOnly := true
-		for _, gw := range vs.Gateways {
-			if gw != "mesh" {
-				isMeshOnly = false
-				break
-			}
-		}
-		if isMeshOnly && len(vs.Gateways) > 0 {
+		// Skip mesh-only gateways (internal services), those without any gateways are kept
+		if vs.MeshOnly() && len(vs.Gateways) > 0 {
 			continue
 		}
 
+		res = append(res, vs)
+	}
+
+	return res, nil
+}
+
+// PrintDiscoveredVirtualServices prints VirtualServices that are not in the configured applications.
+// Returns the services printed.
+func (c KubernetesClient) PrintDiscoveredVirtualServices(ctx context.Context, excludeNames map[string]bool) []VirtualServiceInfo {
+	discovered, err := c.DiscoverVirtualServices(ctx, excludeNames)
+	if err != nil {
+		log.Debug("Failed to list VirtualServices", "error", err)
+		return nil
+	}
+
+	if len(discovered) == 0 {
+		return nil
+	}
+
+	var rows [][]string
+	for _, vs := range discovered {
 		// Get the first host for display
 		host := ""
 		if len(vs.Hosts) > 0 {
@@ -257,18 +402,17 @@ func (c KubernetesClient) PrintDiscoveredVirtualServices(ctx context.Context, ex
 		rows = append(rows, []string{vs.Name, vs.Namespace, fmt.Sprintf("https://%s", host)})
 	}
 
-	if len(rows) == 0 {
-		return
-	}
-
 	fmt.Println() // Add spacing
 	util.Printf("Additional Services")
 	headers := []string{"Name", "Namespace", "URL"}
 	util.PrintTable(headers, rows)
+
+	return discovered
 }
 
 // PrintClusterAppInfo prints detailed information about the specified applications in the cluster.
-func (c KubernetesClient) PrintClusterAppInfo(ctx context.Context, apps map[string]quartzSchema.ApplicationLookupConfig) {
+// The wide view adds the namespace, admin secret and ingress each row was looked up from.
+func (c KubernetesClient) PrintClusterAppInfo(ctx context.Context, apps map[string]quartzSchema.ApplicationLookupConfig, wide bool) {
 	ch := make(chan KubernetesAppConnectionInfo, len(apps))
 
 	tctx, cancel := context.WithTimeout(ctx, 15*time.Second)
@@ -309,16 +453,24 @@ func (c KubernetesClient) PrintClusterAppInfo(ctx context.Context, apps map[stri
 	for range apps {
 		i := <-ch
 
+		row := []string{i.Name, fmt.Sprintf("https://%s", i.PublicEndpoint), i.AdminUsername, i.AdminPassword}
+		if wide {
+			row = append(row, appLookupWideColumns(apps[i.Name])...)
+		}
+
 		if i.Error != nil {
 			hasError = true
-			rows = append(rows, []string{i.Name, fmt.Sprintf("https://%s", i.PublicEndpoint), i.AdminUsername, i.AdminPassword, i.Error.Error()})
+			rows = append(rows, append(row, i.Error.Error()))
 			continue
 		}
 
-		rows = append(rows, []string{i.Name, fmt.Sprintf("https://%s", i.PublicEndpoint), i.AdminUsername, i.AdminPassword})
+		rows = append(rows, row)
 	}
 
 	headers := []string{"Application", "URL", "Admin User", "Admin Password"}
+	if wide {
+		headers = append(headers, "Namespace", "Secret", "Ingress")
+	}
 	if hasError {
 		headers = append(headers, "Error")
 	}
@@ -331,6 +483,22 @@ func (c KubernetesClient) PrintClusterAppInfo(ctx context.Context, apps map[stri
 	util.PrintTable(headers, rows)
 }
 
+// appLookupWideColumns returns the namespace, admin secret and ingress columns of the wide
+// app info view. The namespace is the secret's, falling back to the ingress'; an ingress in
+// another namespace is shown as namespace/name.
+func appLookupWideColumns(opts quartzSchema.ApplicationLookupConfig) []string {
+	secret := opts.AdminCredentials.Secret
+	ingress := opts.Ingress
+
+	ns := cmp.Or(secret.Namespace, ingress.Namespace)
+	ingressName := ingress.Name
+	if ingressName != "" && ingress.Namespace != "" && ingress.Namespace != ns {
+		ingressName = fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
+	}
+
+	return []string{ns, secret.Name, ingressName}
+}
+
 // RefreshExternalSecrets triggers a refresh of external secrets in the cluster.
 func (c KubernetesClient) RefreshExternalSecrets(ctx context.Context) ([]KubernetesResource, error) {
 	// https://external-secrets.io/latest/introduction/faq/#can-i-manually-trigger-a-secret-refresh
@@ -415,7 +583,247 @@ func (c KubernetesClient) ListVirtualServices(ctx context.Context) (result []Vir
 	return result, nil
 }
 
+// ListEvents returns the events in the namespace (all namespaces if empty), oldest first.
+// Only Warning events are returned unless all is set.
+func (c KubernetesClient) ListEvents(ctx context.Context, ns string, all bool) ([]KubernetesEvent, error) {
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := clientset.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var result []KubernetesEvent
+	for _, e := range list.Items {
+		if !all && e.Type == corev1.EventTypeNormal {
+			continue
+		}
+		result = append(result, newKubernetesEvent(e))
+	}
+
+	slices.SortStableFunc(result, func(a, b KubernetesEvent) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return result, nil
+}
+
+// eventsRewatchDelay is the pause before listing events again once a watch can't be resumed.
+const eventsRewatchDelay = time.Second
+
+// WatchEvents streams the events in the namespace (all namespaces if empty) to onEvent until the
+// context is cancelled. Only Warning events are streamed unless all is set. Watches closed by the
+// server are resumed from the last resource version seen, and restarted from the current one when
+// that version has expired, so following runs until interrupted.
+func (c KubernetesClient) WatchEvents(ctx context.Context, ns string, all bool, onEvent func(KubernetesEvent)) error {
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	events := clientset.CoreV1().Events(ns)
+	lw := &cache.ListWatch{
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return events.Watch(ctx, opts)
+		},
+	}
+
+	for {
+		// only events from now on, the resource version of an empty page is the current one
+		list, err := events.List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to watch events: %w", err)
+		}
+
+		w, err := watchtools.NewRetryWatcherWithContext(ctx, list.ResourceVersion, lw)
+		if err != nil {
+			return fmt.Errorf("failed to watch events: %w", err)
+		}
+
+		streamEvents(ctx, w, all, onEvent)
+		w.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		log.Debug("Event watch could not be resumed, restarting", "namespace", ns)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventsRewatchDelay):
+		}
+	}
+}
+
+// streamEvents passes the watched events to onEvent until the context is cancelled or the
+// watcher stops, e.g. on an expired resource version.
+func streamEvents(ctx context.Context, w *watchtools.RetryWatcher, all bool, onEvent func(KubernetesEvent)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case res, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+
+			if res.Type == watch.Error {
+				log.Debug("Event watch error", "err", apierrors.FromObject(res.Object))
+				continue
+			}
+
+			e, ok := res.Object.(*corev1.Event)
+			if !ok || res.Type == watch.Deleted {
+				continue
+			}
+			if !all && e.Type == corev1.EventTypeNormal {
+				continue
+			}
+			onEvent(newKubernetesEvent(*e))
+		}
+	}
+}
+
+// newKubernetesEvent summarizes an event, using the most recent of its timestamps.
+func newKubernetesEvent(e corev1.Event) KubernetesEvent {
+	t := e.LastTimestamp.Time
+	if t.IsZero() {
+		t = e.EventTime.Time
+	}
+	if t.IsZero() {
+		t = e.CreationTimestamp.Time
+	}
+
+	return KubernetesEvent{
+		Time:      t,
+		Namespace: e.Namespace,
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Object:    fmt.Sprintf("%s/%s", strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name),
+		Message:   strings.TrimSpace(e.Message),
+	}
+}
+
+// NodeUsage returns the CPU and memory usage of each node from the metrics API, with the node's
+// allocatable resources when available, sorted by name.
+func (c KubernetesClient) NodeUsage(ctx context.Context) ([]KubernetesResourceUsage, error) {
+	var result []KubernetesResourceUsage
+	err := c.forEachMetrics(ctx, "NodeMetrics.metrics.k8s.io", "", func(item unstructured.Unstructured) {
+		usage, _, _ := unstructured.NestedStringMap(item.Object, "usage")
+		cpu, mem := parseResourceUsage(usage)
+		result = append(result, KubernetesResourceUsage{
+			Name:        item.GetName(),
+			CPUMillis:   cpu,
+			MemoryBytes: mem,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// allocatable resources are only used for percentages, skip them if the nodes can't be listed
+	clientset, err := c.api.ClientSet()
+	if err == nil {
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Debug("Failed to list nodes for allocatable resources", "error", err)
+		} else {
+			for i, u := range result {
+				idx := slices.IndexFunc(nodes.Items, func(n corev1.Node) bool { return n.Name == u.Name })
+				if idx < 0 {
+					continue
+				}
+				a := nodes.Items[idx].Status.Allocatable
+				result[i].AllocatableCPUMillis = a.Cpu().MilliValue()
+				result[i].AllocatableMemoryBytes = a.Memory().Value()
+			}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b KubernetesResourceUsage) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+// PodUsage returns the CPU and memory usage of each pod in the namespace (all namespaces if empty)
+// from the metrics API, summed across its containers and sorted by namespace and name.
+func (c KubernetesClient) PodUsage(ctx context.Context, ns string) ([]KubernetesResourceUsage, error) {
+	var result []KubernetesResourceUsage
+	err := c.forEachMetrics(ctx, "PodMetrics.metrics.k8s.io", ns, func(item unstructured.Unstructured) {
+		u := KubernetesResourceUsage{
+			Namespace: item.GetNamespace(),
+			Name:      item.GetName(),
+		}
+
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, container := range containers {
+			cm, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, _, _ := unstructured.NestedStringMap(cm, "usage")
+			cpu, mem := parseResourceUsage(usage)
+			u.CPUMillis += cpu
+			u.MemoryBytes += mem
+		}
+
+		result = append(result, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(result, func(a, b KubernetesResourceUsage) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+// forEachMetrics lists the metrics API resources of the given kind, returning a descriptive
+// error if the metrics API isn't served, e.g. metrics-server isn't installed.
+func (c KubernetesClient) forEachMetrics(ctx context.Context, kind string, ns string, onEachItem func(unstructured.Unstructured)) error {
+	gvr, err := c.LookupKind(ctx, kind)
+	if err != nil {
+		return fmt.Errorf("metrics API is not available, is metrics-server installed? %w", err)
+	}
+
+	err = c.ForEachDynamicResources(ctx, gvr, ns, onEachItem)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+// parseResourceUsage parses the cpu (in millicores) and memory (in bytes) quantities of a metrics usage map,
+// unparsable or missing quantities are treated as 0.
+func parseResourceUsage(usage map[string]string) (int64, int64) {
+	var cpu, mem int64
+	if q, err := apiresource.ParseQuantity(usage["cpu"]); err == nil {
+		cpu = q.MilliValue()
+	}
+	if q, err := apiresource.ParseQuantity(usage["memory"]); err == nil {
+		mem = q.Value()
+	}
+
+	return cpu, mem
+}
+
 // Export exports Kubernetes resources based on the provided configuration.
+// Objects which fail to export are skipped, the exported objects are returned along with the joined errors.
 func (c KubernetesClient) Export(ctx context.Context, cfg quartzSchema.ExportConfig) (map[string][]byte, error) {
 	res := make(map[string][]byte)
 	errs := []error{}
@@ -423,35 +831,37 @@ func (c KubernetesClient) Export(ctx context.Context, cfg quartzSchema.ExportCon
 	for _, s := range cfg.Objects {
 		util.Printf("Export %s %s/%s", s.Kind, s.Namespace, s.Name)
 
+		exportErr := func(err error) error {
+			log.Warn("Failed to export object", "kind", s.Kind, "ns", s.Namespace, "name", s.Name, "err", err)
+			return fmt.Errorf("failed to export %s %s/%s, %w", s.Kind, s.Namespace, s.Name, err)
+		}
+
 		k, err := c.LookupKind(ctx, s.Kind)
 		if err != nil {
-			// TODO: log
-			errs = append(errs, err)
+			errs = append(errs, exportErr(err))
 			continue
 		}
 
 		o, err := c.GetDynamicResource(ctx, k, s.Namespace, s.Name)
 		if err != nil {
-			// TODO: log
-			errs = append(errs, err)
+			errs = append(errs, exportErr(err))
 			continue
 		}
 
 		for k, v := range cfg.Annotations {
 			err = unstructured.SetNestedField(o, v, "metadata", "annotations", k)
 			if err != nil {
-				errs = append(errs, err)
+				errs = append(errs, exportErr(err))
 			}
 		}
 
 		y, err := yaml.Marshal(o)
 		if err != nil {
-			// TODO: log
-			errs = append(errs, err)
+			errs = append(errs, exportErr(err))
 			continue
 		}
 
-		res[fmt.Sprintf("%s.%s.yaml", s.Namespace, s.Name)] = y
+		res[s.Key()] = y
 	}
 
 	return res, errors.Join(errs...)
@@ -495,6 +905,57 @@ func (c KubernetesClient) WaitConditionState(ctx context.Context, kind schema.Gr
 	return err
 }
 
+// WaitResourcesDeleted polls until no resources of a specific kind remain in the cluster,
+// optionally filtered by namespace and name. Resources still pending finalization are
+// considered present, so this blocks until they are fully removed or the timeout elapses.
+func (c KubernetesClient) WaitResourcesDeleted(ctx context.Context, kind schema.GroupVersionResource, ns string, name string, timeoutSeconds int, pollSeconds int) error {
+	t := timeoutSeconds
+	if t <= 0 {
+		// default timeout if not specified, 10 minutes
+		t = 600
+	}
+
+	poll := pollSeconds
+	if poll <= 0 {
+		poll = 5
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(t)*time.Second)
+	defer cancel()
+
+	for {
+		var remaining []string
+		err := c.ForEachDynamicResources(tctx, kind, ns, func(item unstructured.Unstructured) {
+			if name != "" && !strings.EqualFold(item.GetName(), name) {
+				return
+			}
+
+			remaining = append(remaining, fmt.Sprintf("%s/%s", item.GetNamespace(), item.GetName()))
+		})
+
+		if apierrors.IsNotFound(err) {
+			// the resource type itself is gone, nothing left to wait on
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		log.Debug("Waiting for resources to be deleted", "kind", kind.Resource, "remaining", remaining)
+
+		select {
+		case <-tctx.Done():
+			return fmt.Errorf("timed out waiting for %s to be deleted, remaining %v", kind.Resource, remaining)
+		case <-time.After(time.Duration(poll) * time.Second):
+		}
+	}
+}
+
 // GetConfigMapValue retrieves the key-value pairs from a ConfigMap.
 func (c KubernetesClient) GetConfigMapValue(ctx context.Context, ns string, name string) (map[string]string, error) {
 	clientset, err := c.api.ClientSet()
@@ -516,6 +977,142 @@ func (c KubernetesClient) GetConfigMapValue(ctx context.Context, ns string, name
 	return res, nil
 }
 
+// ClearConfigMap removes all key-value pairs from a ConfigMap, keeping the ConfigMap itself.
+func (c KubernetesClient) ClearConfigMap(ctx context.Context, ns string, name string) error {
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	cms := clientset.CoreV1().ConfigMaps(ns)
+	cm, err := cms.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	cm.Data = nil
+	cm.BinaryData = nil
+	_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// SetConfigMapValue sets a single key in a ConfigMap, creating the ConfigMap if it doesn't exist.
+func (c KubernetesClient) SetConfigMapValue(ctx context.Context, ns string, name string, key string, value string) error {
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	cms := clientset.CoreV1().ConfigMaps(ns)
+	cm, err := cms.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = cms.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Data:       map[string]string{key: value},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = value
+
+	_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// WaitConfigMapValue watches a ConfigMap until the key is set to the expected value (case-insensitive),
+// or just present when value is empty. The ConfigMap doesn't need to exist yet.
+func (c KubernetesClient) WaitConfigMapValue(ctx context.Context, ns string, name string, key string, value string, timeoutSeconds int) error {
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	t := timeoutSeconds
+	if t <= 0 {
+		// default timeout if not specified, 10 minutes
+		t = 600
+	}
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(t)*time.Second)
+	defer cancel()
+
+	matches := func(cm *corev1.ConfigMap) bool {
+		v, ok := cm.Data[key]
+		return ok && (value == "" || strings.EqualFold(v, value))
+	}
+
+	timedOut := func() error {
+		if value == "" {
+			return fmt.Errorf("timed out waiting for key %s in configmap %s/%s", key, ns, name)
+		}
+		return fmt.Errorf("timed out waiting for key %s in configmap %s/%s to be %q", key, ns, name, value)
+	}
+
+	cms := clientset.CoreV1().ConfigMaps(ns)
+	rv := ""
+	cm, err := cms.Get(tctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if matches(cm) {
+			return nil
+		}
+		rv = cm.ResourceVersion
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+
+	// the server closes watches periodically, keep watching from the last seen version until the timeout
+	for {
+		w, err := cms.Watch(tctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: rv,
+		})
+		if err != nil {
+			if tctx.Err() != nil {
+				return timedOut()
+			}
+			return fmt.Errorf("failed to watch configmap %s/%s: %w", ns, name, err)
+		}
+
+		closed := false
+		for !closed {
+			select {
+			case <-tctx.Done():
+				w.Stop()
+				return timedOut()
+			case res, ok := <-w.ResultChan():
+				if !ok {
+					closed = true
+					continue
+				}
+
+				if res.Type == watch.Error {
+					// e.g. the resource version is too old, restart from the current state
+					log.Debug("Configmap watch error, restarting", "name", name, "namespace", ns, "object", res.Object)
+					rv = ""
+					continue
+				}
+
+				cm, ok := res.Object.(*corev1.ConfigMap)
+				if !ok {
+					continue
+				}
+				rv = cm.ResourceVersion
+
+				if res.Type != watch.Deleted && matches(cm) {
+					w.Stop()
+					return nil
+				}
+			}
+		}
+	}
+}
+
 // GetSecret retrieves a Secret from the cluster.
 func (c KubernetesClient) GetSecret(ctx context.Context, ns string, name string) (*corev1.Secret, error) {
 	clientset, err := c.api.ClientSet()
@@ -543,6 +1140,8 @@ func (c KubernetesClient) GetSecretValue(ctx context.Context, ns string, name st
 }
 
 // GetAppConnectionInfo retrieves connection information for an application.
+// The admin password is read from the credentials secret, the username from the
+// credentials configmap when one is configured, otherwise from the secret.
 func (c KubernetesClient) GetAppConnectionInfo(ctx context.Context, name string, opts quartzSchema.ApplicationLookupConfig) KubernetesAppConnectionInfo {
 	res := KubernetesAppConnectionInfo{
 		Name: name,
@@ -557,15 +1156,39 @@ func (c KubernetesClient) GetAppConnectionInfo(ctx context.Context, name string,
 			username, ok := credentials[opts.AdminCredentials.Secret.UsernameKey]
 			if !ok {
 				username = opts.AdminCredentials.Username
+			} else if username, err = decodeCredential(username, opts.AdminCredentials.Secret.UsernameDecode); err != nil {
+				errs = append(errs, fmt.Errorf("failed to decode admin username, %w", err))
 			}
 			res.AdminUsername = username
 
-			res.AdminPassword = credentials[opts.AdminCredentials.Secret.PasswordKey]
+			password, err := decodeCredential(credentials[opts.AdminCredentials.Secret.PasswordKey], opts.AdminCredentials.Secret.PasswordDecode)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to decode admin password, %w", err))
+			}
+			res.AdminPassword = password
 		}
 	} else {
 		log.Debug("No admin credentials secret provided", "app", name)
 	}
 
+	if cm := opts.AdminCredentials.ConfigMap; cm.Name != "" {
+		ns := cmp.Or(cm.Namespace, opts.AdminCredentials.Secret.Namespace)
+		key := cmp.Or(cm.UsernameKey, "username")
+
+		data, err := c.GetConfigMapValue(ctx, ns, cm.Name)
+		if err != nil {
+			errs = append(errs, err)
+		} else if username, ok := data[key]; ok {
+			res.AdminUsername = username
+		} else {
+			errs = append(errs, fmt.Errorf("admin username key %s not found in configmap %s/%s", key, ns, cm.Name))
+		}
+
+		if res.AdminUsername == "" {
+			res.AdminUsername = opts.AdminCredentials.Username
+		}
+	}
+
 	if opts.Ingress.Name != "" {
 		var ingressKind schema.GroupVersionResource
 		if opts.Ingress.Kind != "" &&
@@ -608,6 +1231,44 @@ func (c KubernetesClient) GetAppConnectionInfo(ctx context.Context, name string,
 	return res
 }
 
+// decodeCredential extracts a credential from a raw secret value: as is, base64 decoded,
+// or from a JSON document at a jsonpath (e.g. .data.admin.password).
+func decodeCredential(v string, d quartzSchema.ApplicationLookupDecodeConfig) (string, error) {
+	switch strings.ToLower(d.Type) {
+	case "", "none":
+		return v, nil
+	case "base64":
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v))
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case "jsonpath":
+		var doc interface{}
+		if err := json.Unmarshal([]byte(v), &doc); err != nil {
+			return "", err
+		}
+
+		path := d.Path
+		if !strings.HasPrefix(path, "{") {
+			path = fmt.Sprintf("{%s}", path)
+		}
+
+		jp := jsonpath.New("credential")
+		if err := jp.Parse(path); err != nil {
+			return "", err
+		}
+
+		var buf bytes.Buffer
+		if err := jp.Execute(&buf, doc); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	return "", fmt.Errorf("unsupported decode type %s, must be one of none, base64, jsonpath", d.Type)
+}
+
 // LookupKind looks up the GroupVersionResource for a given kind.
 func (c KubernetesClient) LookupKind(ctx context.Context, kind string) (schema.GroupVersionResource, error) {
 	c.cache.mutex.Lock()
@@ -669,10 +1330,12 @@ func (c KubernetesClient) GetDynamicResource(ctx context.Context, kind schema.Gr
 	return res.Object, nil
 }
 
-// CleanupStuckTerminatingPods force-deletes pods that have been stuck in Terminating
-// state for longer than the specified timeout. This handles scenarios where pods
-// cannot terminate gracefully due to CNI issues or other infrastructure problems.
-func (c KubernetesClient) CleanupStuckTerminatingPods(ctx context.Context, timeout time.Duration) ([]string, error) {
+// CleanupStuckTerminatingPods deletes pods that have been stuck in Terminating state
+// for longer than the specified timeout. This handles scenarios where pods cannot
+// terminate gracefully due to CNI issues or other infrastructure problems. The delete
+// is only forced (grace period 0, optionally without finalizers) when opts.Force is set.
+// Returns the pods deleted along with how long each had been terminating and its node.
+func (c KubernetesClient) CleanupStuckTerminatingPods(ctx context.Context, timeout time.Duration, opts TerminatingPodCleanupOpts) ([]TerminatingPodInfo, error) {
 	clientset, err := c.api.ClientSet()
 	if err != nil {
 		return nil, err
@@ -683,9 +1346,13 @@ func (c KubernetesClient) CleanupStuckTerminatingPods(ctx context.Context, timeo
 		return nil, err
 	}
 
-	var cleaned []string
-	gracePeriod := int64(0)
-	deleteOpts := metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
+	var cleaned []TerminatingPodInfo
+	var longest time.Duration
+	deleteOpts := metav1.DeleteOptions{}
+	if opts.Force {
+		gracePeriod := int64(0)
+		deleteOpts.GracePeriodSeconds = &gracePeriod
+	}
 
 	for _, pod := range pods.Items {
 		// Check if pod is terminating (has a deletionTimestamp)
@@ -699,18 +1366,42 @@ func (c KubernetesClient) CleanupStuckTerminatingPods(ctx context.Context, timeo
 			continue
 		}
 
-		log.Info("Force-deleting stuck terminating pod",
+		log.Info("Deleting stuck terminating pod",
 			"namespace", pod.Namespace,
 			"name", pod.Name,
+			"node", pod.Spec.NodeName,
+			"force", opts.Force,
 			"terminating_for", terminatingDuration.Round(time.Second).String())
 
+		if opts.Force && opts.RemoveFinalizers && len(pod.Finalizers) > 0 {
+			patch := []byte(`{"metadata":{"finalizers":null}}`)
+			_, err := clientset.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+			if err != nil {
+				log.Warn("Failed to remove pod finalizers", "namespace", pod.Namespace, "name", pod.Name, "err", err)
+			}
+		}
+
 		err := clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOpts)
 		if err != nil {
-			log.Warn("Failed to force-delete pod", "namespace", pod.Namespace, "name", pod.Name, "err", err)
+			log.Warn("Failed to delete pod", "namespace", pod.Namespace, "name", pod.Name, "err", err)
 			continue
 		}
 
-		cleaned = append(cleaned, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		cleaned = append(cleaned, TerminatingPodInfo{
+			Namespace:      pod.Namespace,
+			Name:           pod.Name,
+			Node:           pod.Spec.NodeName,
+			TerminatingFor: terminatingDuration,
+			Forced:         opts.Force,
+		})
+		longest = max(longest, terminatingDuration)
+	}
+
+	if len(cleaned) > 0 {
+		log.Info("Deleted stuck terminating pods",
+			"count", len(cleaned),
+			"force", opts.Force,
+			"longest_terminating", longest.Round(time.Second).String())
 	}
 
 	return cleaned, nil
@@ -794,18 +1485,196 @@ func (c KubernetesClient) GetDaemonSetStatus(ctx context.Context, kind schema.Gr
 	return ready, desired, nil
 }
 
+// HealthSummary collects node readiness, the readiness of the critical DaemonSets
+// configured in the stage checks and the pods that are neither Running and ready nor Completed.
+func (c KubernetesClient) HealthSummary(ctx context.Context) (KubernetesHealthSummary, error) {
+	var res KubernetesHealthSummary
+
+	clientset, err := c.api.ClientSet()
+	if err != nil {
+		return res, err
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, err
+	}
+
+	res.NodesTotal = len(nodes.Items)
+	for _, n := range nodes.Items {
+		if slices.ContainsFunc(n.Status.Conditions, func(cond corev1.NodeCondition) bool {
+			return cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue
+		}) {
+			res.NodesReady++
+		}
+	}
+
+	kind := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	for _, ds := range c.criticalDaemonSets() {
+		ready, desired, err := c.GetDaemonSetStatus(ctx, kind, ds.Namespace, ds.Name)
+		res.DaemonSets = append(res.DaemonSets, KubernetesDaemonSetHealth{
+			Namespace: ds.Namespace,
+			Name:      ds.Name,
+			Ready:     ready,
+			Desired:   desired,
+			Error:     err,
+		})
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, err
+	}
+
+	for _, pod := range pods.Items {
+		if reason := podUnhealthyReason(pod); reason != "" {
+			res.FailingPods = append(res.FailingPods, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, reason))
+		}
+	}
+	slices.Sort(res.FailingPods)
+
+	return res, nil
+}
+
+// podUnhealthyReason returns why the pod is unhealthy, or empty for a Completed pod or a Running
+// pod with all its containers ready. A Running phase alone isn't enough, a container restarting
+// in CrashLoopBackOff leaves the pod Running. Waiting containers report their reason (e.g.
+// CrashLoopBackOff, ImagePullBackOff), otherwise the phase or NotReady is reported.
+func podUnhealthyReason(pod corev1.Pod) string {
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return ""
+	}
+
+	statuses := slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses)
+	for _, cs := range statuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "ContainerCreating" && w.Reason != "PodInitializing" {
+			return w.Reason
+		}
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return string(pod.Status.Phase)
+	}
+
+	if slices.ContainsFunc(pod.Status.ContainerStatuses, func(cs corev1.ContainerStatus) bool {
+		return !cs.Ready
+	}) {
+		return "NotReady"
+	}
+
+	return ""
+}
+
+// criticalDaemonSets returns the DaemonSets checked by enabled stages, sorted by namespace and name.
+func (c KubernetesClient) criticalDaemonSets() []quartzSchema.StageChecksDaemonSetConfig {
+	seen := map[string]bool{}
+	var res []quartzSchema.StageChecksDaemonSetConfig
+	for _, s := range c.cfg.Stages {
+		if s.Disabled {
+			continue
+		}
+
+		for _, chk := range s.Checks {
+			for _, ds := range chk.DaemonSet {
+				k := ds.Namespace + "/" + ds.Name
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+				res = append(res, ds)
+			}
+		}
+	}
+
+	slices.SortFunc(res, func(lhs, rhs quartzSchema.StageChecksDaemonSetConfig) int {
+		return cmp.Or(cmp.Compare(lhs.Namespace, rhs.Namespace), cmp.Compare(lhs.Name, rhs.Name))
+	})
+
+	return res
+}
+
+// PrintHealthSummary prints the cluster health summary as a status table.
+func (c KubernetesClient) PrintHealthSummary(ctx context.Context) error {
+	h, err := c.HealthSummary(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows, status := healthSummaryRows(h)
+
+	util.Msg("Cluster health")
+	util.PrintRowStatusTable([]string{"Check", "Result"}, rows, func(i int, row []string) util.RowStatus {
+		return status[i]
+	})
+
+	return nil
+}
+
+// healthSummaryRows renders the health summary as table rows with a status per row.
+func healthSummaryRows(h KubernetesHealthSummary) ([][]string, []util.RowStatus) {
+	var rows [][]string
+	var status []util.RowStatus
+
+	add := func(ok bool, check string, result string) {
+		rows = append(rows, []string{check, result})
+		if ok {
+			status = append(status, util.StatusOk)
+			return
+		}
+		status = append(status, util.StatusError)
+	}
+
+	add(h.NodesTotal > 0 && h.NodesReady == h.NodesTotal, "Nodes", fmt.Sprintf("%d/%d ready", h.NodesReady, h.NodesTotal))
+
+	var notReady []KubernetesDaemonSetHealth
+	for _, d := range h.DaemonSets {
+		if !d.Healthy() {
+			notReady = append(notReady, d)
+		}
+	}
+
+	if len(h.DaemonSets) > 0 && len(notReady) == 0 {
+		add(true, "Critical DaemonSets", fmt.Sprintf("%d/%d ready", len(h.DaemonSets), len(h.DaemonSets)))
+	}
+
+	for _, d := range notReady {
+		result := fmt.Sprintf("%d/%d pods ready", d.Ready, d.Desired)
+		if d.Error != nil {
+			result = util.TruncateStringE(d.Error.Error(), 60)
+		}
+		add(false, fmt.Sprintf("DaemonSet %s/%s", d.Namespace, d.Name), result)
+	}
+
+	if len(h.FailingPods) == 0 {
+		add(true, "Pods", "all Running and ready or Completed")
+		return rows, status
+	}
+
+	// list a handful of the failing pods, the count covers the rest
+	listed := h.FailingPods[:min(len(h.FailingPods), 5)]
+	result := fmt.Sprintf("%d unhealthy: %s", len(h.FailingPods), strings.Join(listed, ", "))
+	if len(listed) < len(h.FailingPods) {
+		result = result + ", ..."
+	}
+	add(false, "Pods", result)
+
+	return rows, status
+}
+
 // Restart restarts resources of a specific kind in the cluster.
-func (c KubernetesClient) Restart(ctx context.Context, kind schema.GroupVersionResource, ns string, name string) error {
+// Returns the resources a rollout was triggered for.
+func (c KubernetesClient) Restart(ctx context.Context, kind schema.GroupVersionResource, ns string, name string) ([]KubernetesResource, error) {
 	validRes := []string{"Deployments", "DaemonSets", "StatefulSets"}
 	if !slices.ContainsFunc(validRes, func(s string) bool {
 		return strings.EqualFold(s, kind.Resource)
 	}) {
-		return fmt.Errorf("unsupported resource type %s, must be one of %v", kind.Resource, validRes)
+		return nil, fmt.Errorf("unsupported resource type %s, must be one of %v", kind.Resource, validRes)
 	}
 
 	// for each item in result, update spec/template/metadata/annoations to trigger rollout
 	timestamp := time.Now().UTC().Format(time.RFC3339)
-	return c.ForEachDynamicResources(ctx, kind, ns, func(item unstructured.Unstructured) {
+	var res []KubernetesResource
+	err := c.ForEachDynamicResources(ctx, kind, ns, func(item unstructured.Unstructured) {
 		n := item.GetName()
 		ns := item.GetNamespace()
 
@@ -833,8 +1702,88 @@ func (c KubernetesClient) Restart(ctx context.Context, kind schema.GroupVersionR
 		_, ierr := c.Update(ctx, kind, ns, &item)
 		if ierr != nil {
 			log.Info("Error updating dynamic resource", "kind", kind, "name", n, "ns", ns, "err", ierr)
+			return
 		}
+
+		res = append(res, KubernetesResource{
+			Name:      n,
+			Namespace: ns,
+			Kind:      kind,
+			Item:      item,
+		})
 	})
+
+	return res, err
+}
+
+// WaitForRollout polls a Deployment, DaemonSet or StatefulSet until its latest rollout
+// has completed (all replicas updated and available) or the timeout elapses.
+func (c KubernetesClient) WaitForRollout(ctx context.Context, kind schema.GroupVersionResource, ns string, name string, timeoutSeconds int, pollSeconds int) error {
+	t := timeoutSeconds
+	if t <= 0 {
+		// default timeout if not specified, 10 minutes
+		t = 600
+	}
+
+	poll := pollSeconds
+	if poll <= 0 {
+		poll = 5
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(t)*time.Second)
+	defer cancel()
+
+	for {
+		obj, err := c.GetDynamicResource(tctx, kind, ns, name)
+		if err != nil {
+			return err
+		}
+
+		done, status := rolloutStatus(kind, unstructured.Unstructured{Object: obj})
+		if done {
+			return nil
+		}
+
+		log.Debug("Waiting for rollout", "kind", kind.Resource, "namespace", ns, "name", name, "status", status)
+
+		select {
+		case <-tctx.Done():
+			return fmt.Errorf("timed out waiting for rollout of %s %s/%s, %s", kind.Resource, ns, name, status)
+		case <-time.After(time.Duration(poll) * time.Second):
+		}
+	}
+}
+
+// rolloutStatus reports whether the latest rollout of a workload has completed,
+// along with a short description of its progress.
+func rolloutStatus(kind schema.GroupVersionResource, item unstructured.Unstructured) (bool, string) {
+	observed, _, _ := unstructured.NestedInt64(item.Object, "status", "observedGeneration")
+	if observed < item.GetGeneration() {
+		return false, "waiting for the rollout to be observed"
+	}
+
+	if strings.EqualFold(kind.Resource, "DaemonSets") {
+		desired, _, _ := unstructured.NestedInt64(item.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(item.Object, "status", "updatedNumberScheduled")
+		available, _, _ := unstructured.NestedInt64(item.Object, "status", "numberAvailable")
+
+		return updated >= desired && available >= desired, fmt.Sprintf("%d of %d updated, %d available", updated, desired, available)
+	}
+
+	desired, found, _ := unstructured.NestedInt64(item.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	updated, _, _ := unstructured.NestedInt64(item.Object, "status", "updatedReplicas")
+	ready, _, _ := unstructured.NestedInt64(item.Object, "status", "readyReplicas")
+	if strings.EqualFold(kind.Resource, "Deployments") {
+		total, _, _ := unstructured.NestedInt64(item.Object, "status", "replicas")
+		available, _, _ := unstructured.NestedInt64(item.Object, "status", "availableReplicas")
+
+		return updated >= desired && total <= updated && available >= desired, fmt.Sprintf("%d of %d updated, %d available", updated, desired, available)
+	}
+
+	return updated >= desired && ready >= desired, fmt.Sprintf("%d of %d updated, %d ready", updated, desired, ready)
 }
 
 // requestServiceAccountToken requests a token for a service account.
@@ -868,26 +1817,17 @@ func (kc KubeconfigInfo) ToKubeconfigYamlBytes(cfg quartzSchema.QuartzConfig) []
 
 // Kubeconfig converts the KubeconfigInfo to a Kubeconfig structure.
 func (kc KubeconfigInfo) Kubeconfig(cfg quartzSchema.QuartzConfig) quartzSchema.Kubeconfig {
-	var user quartzSchema.KubeconfigUserInfo
-	if !cfg.Auth.ServiceAccount.Enabled && cfg.Providers.Cloud == "aws" {
-		bin, _ := os.Executable()
-		user = quartzSchema.KubeconfigUserInfo{
-			Exec: &quartzSchema.KubeconfigUserExec{
-				ApiVersion: "client.authentication.k8s.io/v1beta1",
-				Command:    bin,
-				Args: []string{
-					"aws",
-					"get-eks-token",
-					"--cluster",
-					cfg.Name,
-					"--region",
-					cfg.Aws.Region,
-				},
-			},
-		}
-	} else {
-		user = quartzSchema.KubeconfigUserInfo{
-			Token: &kc.Token,
+	userInfo := kc.UserInfo
+	if userInfo == nil {
+		userInfo = defaultKubeconfigUserInfo(cfg.Providers.Cloud)
+	}
+
+	user := quartzSchema.KubeconfigUserInfo{
+		Token: &kc.Token,
+	}
+	if userInfo != nil {
+		if u := userInfo(cfg, kc); u != nil {
+			user = *u
 		}
 	}
 
@@ -16,8 +16,10 @@ package provider
 
 import (
 	"context"
+	"os"
 
 	quartzSchema "github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -25,6 +27,15 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+var (
+	// inClusterTokenFile is the service account token mounted into pods, its presence along with
+	// the service host env indicates quartz is running inside the cluster.
+	inClusterTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// inClusterConfig builds the rest config from the mounted service account, replaced in tests.
+	inClusterConfig = rest.InClusterConfig
+)
+
 // KubernetesApi defines the interface for interacting with Kubernetes APIs.
 type KubernetesApi interface {
 	// ClientSet returns a Kubernetes clientset for interacting with core Kubernetes resources.
@@ -69,9 +80,16 @@ func (api KubernetesApiImpl) DiscoveryClient() (discovery.DiscoveryInterface, er
 }
 
 // newRestConfig creates a new REST configuration for Kubernetes API access.
-// It uses the provided Quartz configuration and kubeconfig information.
+// It uses the provided Quartz configuration and kubeconfig information, or the in-cluster
+// service account config when running in a pod without an endpoint or KUBECONFIG.
 // If service account authentication is enabled, it exchanges the cloud provider token for a Kubernetes service account token.
 func newRestConfig(ctx context.Context, cfg quartzSchema.QuartzConfig, i *KubeconfigInfo) (*rest.Config, error) {
+	// running inside the cluster, e.g. as a job, without an explicit cluster to target
+	if i.Endpoint == "" && os.Getenv("KUBECONFIG") == "" && InCluster() {
+		log.Debug("Using in-cluster service account config")
+		return inClusterConfig()
+	}
+
 	// rest client using native credentials for a given cloud provider
 	kubeconfig := i.ToKubeconfigYamlBytes(cfg)
 	kc1, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
@@ -99,3 +117,13 @@ func newRestConfig(ctx context.Context, cfg quartzSchema.QuartzConfig, i *Kubeco
 
 	return kc2, nil
 }
+
+// InCluster returns true if quartz is running inside a Kubernetes pod with a mounted service account.
+func InCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+
+	_, err := os.Stat(inClusterTokenFile)
+	return err == nil
+}
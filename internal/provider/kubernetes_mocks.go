@@ -17,20 +17,39 @@ package provider
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	fakeDiscoveryClient "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	fakeDynamicClient "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	fakeClientSet "k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
 )
 
 // KubernetesApiMock is a mock implementation of the IKubernetesApi interface for testing purposes.
 type KubernetesApiMock struct {
-	err            error                     // The error to return for API calls.
-	clientObjects  []runtime.Object          // The client objects to include in the fake clientset.
-	dynamicObjects []runtime.Object          // The dynamic objects to include in the fake dynamic client.
-	resources      []*metav1.APIResourceList // The API resources to include in the fake discovery client.
+	err            error                                  // The error to return for API calls.
+	clientObjects  []runtime.Object                       // The client objects to include in the fake clientset.
+	dynamicObjects []runtime.Object                       // The dynamic objects to include in the fake dynamic client.
+	resources      []*metav1.APIResourceList              // The API resources to include in the fake discovery client.
+	reactors       []kubernetesMockReactor                // The reactors to prepend to the fake dynamic client.
+	clientReactors []kubernetesMockReactor                // The reactors to prepend to the fake clientset.
+	watchReactors  []kubernetesMockWatchReactor           // The watch reactors to prepend to the fake clientset.
+	listKinds      map[schema.GroupVersionResource]string // Additional list kinds registered with the fake dynamic client.
+}
+
+// kubernetesMockWatchReactor is a watch reaction function registered against the fake clientset.
+type kubernetesMockWatchReactor struct {
+	resource string
+	fn       k8sTesting.WatchReactionFunc
+}
+
+// kubernetesMockReactor is a reaction function registered against the fake dynamic client.
+type kubernetesMockReactor struct {
+	verb     string
+	resource string
+	fn       k8sTesting.ReactionFunc
 }
 
 // NewKubernetesApiMock creates a new instance of KubernetesApiMock with default API resources.
@@ -71,6 +90,37 @@ func (api *KubernetesApiMock) WithDynamicObjects(objects ...runtime.Object) *Kub
 	return api
 }
 
+// WithDynamicReactor registers a reaction function on the mock dynamic client, allowing
+// tests to simulate resources that change between calls.
+func (api *KubernetesApiMock) WithDynamicReactor(verb string, resource string, fn k8sTesting.ReactionFunc) *KubernetesApiMock {
+	api.reactors = append(api.reactors, kubernetesMockReactor{verb: verb, resource: resource, fn: fn})
+	return api
+}
+
+// WithDynamicListKind registers the list kind of a resource with the mock dynamic client, allowing
+// tests to serve lists of resources whose names can't be guessed from their kind through a reactor.
+func (api *KubernetesApiMock) WithDynamicListKind(gvr schema.GroupVersionResource, listKind string) *KubernetesApiMock {
+	if api.listKinds == nil {
+		api.listKinds = map[schema.GroupVersionResource]string{}
+	}
+	api.listKinds[gvr] = listKind
+	return api
+}
+
+// WithClientReactor registers a reaction function on the mock clientset, allowing
+// tests to inspect or intercept typed client calls.
+func (api *KubernetesApiMock) WithClientReactor(verb string, resource string, fn k8sTesting.ReactionFunc) *KubernetesApiMock {
+	api.clientReactors = append(api.clientReactors, kubernetesMockReactor{verb: verb, resource: resource, fn: fn})
+	return api
+}
+
+// WithClientWatchReactor registers a watch reaction function on the mock clientset, allowing
+// tests to feed events to a watch.
+func (api *KubernetesApiMock) WithClientWatchReactor(resource string, fn k8sTesting.WatchReactionFunc) *KubernetesApiMock {
+	api.watchReactors = append(api.watchReactors, kubernetesMockWatchReactor{resource: resource, fn: fn})
+	return api
+}
+
 // WithError sets the error to be returned by the mock API.
 func (api *KubernetesApiMock) WithError(err error) *KubernetesApiMock {
 	api.err = err
@@ -85,12 +135,29 @@ func (api *KubernetesApiMock) AddResources(res ...*metav1.APIResourceList) *Kube
 
 // ClientSet returns a fake Kubernetes clientset populated with the mock client objects.
 func (api KubernetesApiMock) ClientSet() (kubernetes.Interface, error) {
-	return fakeClientSet.NewSimpleClientset(api.clientObjects...), api.err
+	c := fakeClientSet.NewSimpleClientset(api.clientObjects...)
+	for _, r := range api.clientReactors {
+		c.PrependReactor(r.verb, r.resource, r.fn)
+	}
+	for _, r := range api.watchReactors {
+		c.PrependWatchReactor(r.resource, r.fn)
+	}
+
+	return c, api.err
 }
 
 // DynamicClient returns a fake dynamic client populated with the mock dynamic objects.
 func (api KubernetesApiMock) DynamicClient() (dynamic.Interface, error) {
-	return fakeDynamicClient.NewSimpleDynamicClient(runtime.NewScheme(), api.dynamicObjects...), api.err
+	c := fakeDynamicClient.NewSimpleDynamicClient(runtime.NewScheme(), api.dynamicObjects...)
+	if len(api.listKinds) > 0 {
+		// the custom list kinds client doesn't register list kinds for the dynamic objects, only use it when needed
+		c = fakeDynamicClient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), api.listKinds, api.dynamicObjects...)
+	}
+	for _, r := range api.reactors {
+		c.PrependReactor(r.verb, r.resource, r.fn)
+	}
+
+	return c, api.err
 }
 
 // DiscoveryClient returns a fake discovery client populated with the mock API resources.
@@ -20,17 +20,30 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/util"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	k8sSchema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	k8sTesting "k8s.io/client-go/testing"
 )
 
 func TestProviderKubernetesClientCtor(t *testing.T) {
@@ -104,6 +117,7 @@ func TestProviderKubernetesClientCtorErr(t *testing.T) {
 		Auth:      schema.DefaultAuthConfig(),
 		Aws:       schema.AwsConfig{Region: "test-region"},
 	}
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
 	i := &KubeconfigInfo{}
 	_, err := NewKubernetesApi(context.Background(), cfg, i)
 	if err == nil {
@@ -112,6 +126,77 @@ func TestProviderKubernetesClientCtorErr(t *testing.T) {
 	}
 }
 
+// withInCluster mocks the in-cluster environment, a service host and mounted service account token.
+func withInCluster(t *testing.T, host string) {
+	t.Setenv("KUBECONFIG", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+	token := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(token, []byte("testtoken"), 0600)
+
+	origToken, origConfig := inClusterTokenFile, inClusterConfig
+	t.Cleanup(func() {
+		inClusterTokenFile, inClusterConfig = origToken, origConfig
+	})
+
+	inClusterTokenFile = token
+	inClusterConfig = func() (*rest.Config, error) {
+		return &rest.Config{Host: host}, nil
+	}
+}
+
+func TestProviderKubernetesInCluster(t *testing.T) {
+	withInCluster(t, "https://in-cluster")
+	if !InCluster() {
+		t.Error("expected in-cluster with service host and token present")
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if InCluster() {
+		t.Error("expected not in-cluster without service host")
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	inClusterTokenFile = filepath.Join(t.TempDir(), "missing")
+	if InCluster() {
+		t.Error("expected not in-cluster without service account token")
+	}
+}
+
+func TestProviderKubernetesRestConfigInCluster(t *testing.T) {
+	withInCluster(t, "https://in-cluster")
+	cfg := schema.QuartzConfig{
+		Name: "testcluster",
+		Auth: schema.DefaultAuthConfig(),
+	}
+
+	r, err := newRestConfig(context.Background(), cfg, &KubeconfigInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error from in-cluster rest config, %v", err)
+	}
+
+	if r.Host != "https://in-cluster" {
+		t.Errorf("expected in-cluster config to be selected, found host %s", r.Host)
+	}
+
+	// an explicit endpoint takes precedence over the in-cluster config
+	r, err = newRestConfig(context.Background(), cfg, &KubeconfigInfo{
+		Cluster:              "testcluster",
+		Context:              "testcluster",
+		User:                 "testuser",
+		Endpoint:             "http://nowhere.example.com",
+		CertificateAuthority: base64.StdEncoding.EncodeToString([]byte("mytestcert")),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from endpoint rest config, %v", err)
+	}
+
+	if r.Host != "http://nowhere.example.com" {
+		t.Errorf("expected endpoint config to be selected, found host %s", r.Host)
+	}
+}
+
 func TestProviderKubernetesClientProviderName(t *testing.T) {
 	api := NewKubernetesApiMock()
 	kubeconfig := KubeconfigInfo{}
@@ -188,6 +273,80 @@ func TestProviderKubernetesClientWriteKubeconfig(t *testing.T) {
 	}
 }
 
+func TestProviderKubeconfigInfoCloudUserInfo(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		Name: "mytestcluster",
+		Providers: schema.ProvidersConfig{
+			Cloud: "aws",
+		},
+		Auth: schema.DefaultAuthConfig(),
+	}
+
+	cp := NewTestCloudProviderClient()
+	cp.userInfo = &schema.KubeconfigUserInfo{
+		Exec: &schema.KubeconfigUserExec{
+			ApiVersion: "client.authentication.k8s.io/v1",
+			Command:    "mycloud",
+			Args:       []string{"get-token", "--cluster", "mytestcluster"},
+		},
+	}
+
+	kc := KubeconfigInfo{Cluster: "mytestcluster", User: "mytestuser", Token: "static"}
+	kc.UserInfo = cp.KubeconfigUserInfo
+
+	k := string(kc.ToKubeconfigYamlBytes(cfg))
+	if !strings.Contains(k, "command: mycloud") || !strings.Contains(k, "get-token") {
+		t.Errorf("expected cloud provider exec block in kubeconfig, %v", k)
+	}
+	if strings.Contains(k, "get-eks-token") || strings.Contains(k, "static") {
+		t.Errorf("unexpected default user auth in kubeconfig, %v", k)
+	}
+
+	// nil from the provider falls back to the static token
+	cp.userInfo = nil
+	kc.UserInfo = cp.KubeconfigUserInfo
+
+	u := kc.Kubeconfig(cfg).Users[0].User
+	if u.Exec != nil || u.Token == nil || *u.Token != "static" {
+		t.Errorf("expected static token user auth, %v", u)
+	}
+}
+
+func TestProviderAwsKubeconfigUserInfo(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		Name: "mytestcluster",
+		Providers: schema.ProvidersConfig{
+			Cloud: "aws",
+		},
+		Aws: schema.AwsConfig{
+			Region: "us-test-1",
+		},
+		Auth: schema.DefaultAuthConfig(),
+	}
+
+	c := AwsClient{}
+	kc := KubeconfigInfo{Cluster: "arn", User: "arn", Context: "arn"}
+
+	// provider supplied and configured default produce identical output
+	expected := kc.ToKubeconfigYamlBytes(cfg)
+	kc.UserInfo = c.KubeconfigUserInfo
+	if !bytes.Equal(expected, kc.ToKubeconfigYamlBytes(cfg)) {
+		t.Errorf("unexpected difference between aws provider and default kubeconfig")
+	}
+
+	u := kc.Kubeconfig(cfg).Users[0].User
+	if u.Exec == nil || strings.Join(u.Exec.Args, " ") != "aws get-eks-token --cluster mytestcluster --region us-test-1" {
+		t.Errorf("unexpected aws exec block, %v", u.Exec)
+	}
+
+	// service account mode uses the exchanged static token
+	cfg.Auth.ServiceAccount.Enabled = true
+	u = kc.Kubeconfig(cfg).Users[0].User
+	if u.Exec != nil || u.Token == nil {
+		t.Errorf("expected static token for service account mode, %v", u)
+	}
+}
+
 func TestProviderKubernetesClientWriteKubeconfigFile(t *testing.T) {
 	api := NewKubernetesApiMock()
 	cfg := schema.QuartzConfig{
@@ -386,236 +545,1429 @@ func TestProviderKubernetesClientGetConfigMapValue(t *testing.T) {
 	}
 }
 
-func TestProviderKubernetesClientGetSecretValue(t *testing.T) {
-	secret := corev1.Secret{}
-	secret.Name = "testsecret1"
-	secret.Namespace = "testns1"
-	secret.Data = map[string][]byte{
-		"key1": []byte("val1"),
-		"key2": []byte("val2"),
+func TestProviderKubernetesClientClearConfigMap(t *testing.T) {
+	cm := corev1.ConfigMap{}
+	cm.Name = "testcm1"
+	cm.Namespace = "testns1"
+	cm.Data = map[string]string{
+		"key1": "val1",
 	}
-	api := NewKubernetesApiMock().WithClientObjects(&secret)
 
-	cfg := schema.QuartzConfig{}
-	kubeconfig := KubeconfigInfo{}
+	var updated *corev1.ConfigMap
+	api := NewKubernetesApiMock().WithClientObjects(&cm).WithClientReactor("update", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		updated = action.(k8sTesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+		return false, nil, nil
+	})
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
 	if err != nil {
 		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
 		return
 	}
 
-	m, err := c.GetSecretValue(context.Background(), "testns1", "testsecret1")
+	err = c.ClearConfigMap(context.Background(), "testns1", "testcm1")
 	if err != nil {
-		t.Errorf("unexpected error from kubernetes client get secret, %v", err)
+		t.Errorf("unexpected error from kubernetes client clear cm, %v", err)
 		return
 	}
 
-	if len(m) != 2 ||
-		m["key1"] != "val1" ||
-		m["key2"] != "val2" {
-		t.Errorf("unexpected response from kubernetes client get secret, %v", m)
+	if updated == nil || updated.Name != "testcm1" || len(updated.Data) != 0 {
+		t.Errorf("expected the configmap to be updated with no data, found %v", updated)
+	}
+
+	err = c.ClearConfigMap(context.Background(), "testns1", "missing")
+	if err == nil {
+		t.Errorf("expected an error clearing a missing configmap")
 	}
 }
 
-func TestProviderKubernetesClientGetDynamicResource(t *testing.T) {
-	api := NewKubernetesApiMock().WithDynamicObjects(
-		newK8sObject("external-secrets.io/v1beta1", "ExternalSecret", "testns1", "testobj1"),
+func TestProviderKubernetesClientSetConfigMapValue(t *testing.T) {
+	cm := corev1.ConfigMap{}
+	cm.Name = "testcm1"
+	cm.Namespace = "testns1"
+	cm.Data = map[string]string{
+		"key1": "val1",
+	}
+
+	var updated, created *corev1.ConfigMap
+	api := NewKubernetesApiMock().WithClientObjects(&cm).
+		WithClientReactor("update", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			updated = action.(k8sTesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+			return false, nil, nil
+		}).
+		WithClientReactor("create", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			created = action.(k8sTesting.CreateAction).GetObject().(*corev1.ConfigMap)
+			return false, nil, nil
+		})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	err = c.SetConfigMapValue(context.Background(), "testns1", "testcm1", "key2", "val2")
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client set cm value, %v", err)
+	}
+
+	if updated == nil || updated.Data["key1"] != "val1" || updated.Data["key2"] != "val2" {
+		t.Errorf("expected the configmap to be updated keeping existing keys, found %v", updated)
+	}
+
+	err = c.SetConfigMapValue(context.Background(), "testns1", "missing", "key1", "val1")
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client set missing cm value, %v", err)
+	}
+
+	if created == nil || created.Name != "missing" || created.Namespace != "testns1" || created.Data["key1"] != "val1" {
+		t.Errorf("expected the missing configmap to be created, found %v", created)
+	}
+}
+
+// withConfigMapWatch registers a watch reactor on the mock which sends each of the ConfigMaps
+// as a modification after a short delay.
+func withConfigMapWatch(api *KubernetesApiMock, cms ...*corev1.ConfigMap) *KubernetesApiMock {
+	return api.WithClientWatchReactor("configmaps", func(action k8sTesting.Action) (bool, watch.Interface, error) {
+		fw := watch.NewFake()
+		go func() {
+			for _, cm := range cms {
+				time.Sleep(20 * time.Millisecond)
+				fw.Modify(cm)
+			}
+		}()
+		return true, fw, nil
+	})
+}
+
+func TestProviderKubernetesClientWaitConfigMapValue(t *testing.T) {
+	newCm := func(data map[string]string) *corev1.ConfigMap {
+		cm := corev1.ConfigMap{}
+		cm.Name = "testcm1"
+		cm.Namespace = "testns1"
+		cm.Data = data
+		return &cm
+	}
+
+	// already set
+	api := NewKubernetesApiMock().WithClientObjects(newCm(map[string]string{"ready": "TRUE"}))
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	err = c.WaitConfigMapValue(context.Background(), "testns1", "testcm1", "ready", "true", 1)
+	if err != nil {
+		t.Errorf("unexpected error waiting for an already set configmap value, %v", err)
+	}
+
+	// updated after a delay, through an intermediate value
+	api = withConfigMapWatch(NewKubernetesApiMock().WithClientObjects(newCm(map[string]string{"ready": "false"})),
+		newCm(map[string]string{"ready": "pending"}),
+		newCm(map[string]string{"ready": "true"}),
 	)
+	c, _ = NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
 
-	cfg := schema.QuartzConfig{}
-	kubeconfig := KubeconfigInfo{}
+	start := time.Now()
+	err = c.WaitConfigMapValue(context.Background(), "testns1", "testcm1", "ready", "true", 5)
+	if err != nil {
+		t.Errorf("unexpected error waiting for an updated configmap value, %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("expected the wait to return as soon as the value is set, took %v", time.Since(start))
+	}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	// created after a delay, any value
+	api = withConfigMapWatch(NewKubernetesApiMock(), newCm(map[string]string{"ready": "yes"}))
+	c, _ = NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+
+	err = c.WaitConfigMapValue(context.Background(), "testns1", "testcm1", "ready", "", 5)
 	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+		t.Errorf("unexpected error waiting for a created configmap key, %v", err)
 	}
+}
 
-	kind, _ := c.LookupKind(context.Background(), "ExternalSecret")
-	res, err := c.GetDynamicResource(context.Background(), kind, "testns1", "testobj1")
+func TestProviderKubernetesClientWaitConfigMapValueTimeout(t *testing.T) {
+	cm := corev1.ConfigMap{}
+	cm.Name = "testcm1"
+	cm.Namespace = "testns1"
+	cm.Data = map[string]string{"ready": "false"}
+
+	api := withConfigMapWatch(NewKubernetesApiMock().WithClientObjects(&cm), &cm)
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
 	if err != nil {
-		t.Errorf("unexpected error from kubernetes client get dynamic, %v", err)
-		return
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
 	}
 
-	actualName, found, err := unstructured.NestedString(res, "metadata", "name")
-	if err != nil ||
-		!found ||
-		actualName != "testobj1" {
-		t.Errorf("unexpected response from kubernetes client get dynamic, %v", res)
+	err = c.WaitConfigMapValue(context.Background(), "testns1", "testcm1", "ready", "true", 1)
+	if err == nil || !strings.Contains(err.Error(), `timed out waiting for key ready in configmap testns1/testcm1 to be "true"`) {
+		t.Errorf("expected a timeout error, found %v", err)
 	}
 }
 
-func TestProviderKubernetesClientGetAppConnectionInfo(t *testing.T) {
-	secret := corev1.Secret{}
-	secret.Name = "test-secret"
-	secret.Namespace = "test"
-	secret.Data = map[string][]byte{
-		"password": []byte("supersecretpassword"),
+// newTestEvent creates an event for a pod in testns1, last seen the given number of minutes ago.
+func newTestEvent(name string, eventType string, reason string, minutesAgo int) *corev1.Event {
+	e := corev1.Event{
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " message",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "testpod1"},
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Duration(minutesAgo) * time.Minute)),
+	}
+	e.Name = name
+	e.Namespace = "testns1"
+	return &e
+}
+
+func TestProviderKubernetesClientListEvents(t *testing.T) {
+	api := NewKubernetesApiMock().WithClientObjects(
+		newTestEvent("event1", corev1.EventTypeWarning, "BackOff", 1),
+		newTestEvent("event2", corev1.EventTypeNormal, "Pulled", 3),
+		newTestEvent("event3", corev1.EventTypeWarning, "FailedMount", 5),
+	)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
 	}
 
-	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test")
-	unstructured.SetNestedStringSlice(vs.Object, []string{"testapp.example.com"}, "spec", "hosts")
+	res, err := c.ListEvents(context.Background(), "testns1", false)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client list events, %v", err)
+	}
 
-	api := NewKubernetesApiMock().
-		WithClientObjects(&secret).
-		WithDynamicObjects(vs)
+	if len(res) != 2 || res[0].Reason != "FailedMount" || res[1].Reason != "BackOff" {
+		t.Errorf("expected the warning events oldest first, found %v", res)
+	}
 
-	opts := schema.NewApplicationLookupConfig("test", "test-secret", "test-admin", "username", "password", "")
-	// opts.Ingress = schema.ApplicationLookupIngressConfig{
-	// 	Kind:    "VirtualService",
-	// 	Group:   "networking.istio.io",
-	// 	Version: "v1beta1",
-	// }
+	if res[0].Object != "pod/testpod1" || res[0].Message != "FailedMount message" || res[0].Namespace != "testns1" {
+		t.Errorf("unexpected event summary, found %v", res[0])
+	}
 
-	cfg := schema.QuartzConfig{
-		Core: schema.InfrastructureEnvironmentConfig{
-			Applications: map[string]schema.InfrastructureApplicationConfig{
-				"test": {
-					Lookup: opts,
+	res, err = c.ListEvents(context.Background(), "testns1", true)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client list all events, %v", err)
+	}
+
+	if len(res) != 3 || res[1].Reason != "Pulled" {
+		t.Errorf("expected all events oldest first, found %v", res)
+	}
+}
+
+// withTestEventWatches serves an event list at resource version 10 and the fake watches in turn,
+// recording the resource version each watch started from and counting the lists.
+func withTestEventWatches(api *KubernetesApiMock, lists *atomic.Int32, versions *[]string, watches ...*watch.FakeWatcher) *KubernetesApiMock {
+	var mu sync.Mutex
+	return api.
+		WithClientReactor("list", "events", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			lists.Add(1)
+			return true, &corev1.EventList{ListMeta: metav1.ListMeta{ResourceVersion: "10"}}, nil
+		}).
+		WithClientWatchReactor("events", func(action k8sTesting.Action) (bool, watch.Interface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			*versions = append(*versions, action.(k8sTesting.WatchActionImpl).WatchRestrictions.ResourceVersion)
+			if len(*versions) > len(watches) {
+				return true, watch.NewFake(), nil
+			}
+			return true, watches[len(*versions)-1], nil
+		})
+}
+
+// withResourceVersion sets the event's resource version, watches resume from the last one seen.
+func withResourceVersion(e *corev1.Event, rv string) *corev1.Event {
+	e.ResourceVersion = rv
+	return e
+}
+
+func TestProviderKubernetesClientWatchEvents(t *testing.T) {
+	first, second := watch.NewFake(), watch.NewFake()
+	var lists atomic.Int32
+	var versions []string
+	api := withTestEventWatches(NewKubernetesApiMock(), &lists, &versions, first, second)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	go func() {
+		first.Add(withResourceVersion(newTestEvent("event1", corev1.EventTypeWarning, "BackOff", 0), "11"))
+		first.Add(withResourceVersion(newTestEvent("event2", corev1.EventTypeNormal, "Pulled", 0), "12"))
+		first.Delete(withResourceVersion(newTestEvent("event3", corev1.EventTypeWarning, "Deleted", 0), "13"))
+		// closed by the server, the watch resumes instead of returning
+		first.Stop()
+		second.Modify(withResourceVersion(newTestEvent("event1", corev1.EventTypeWarning, "Unhealthy", 0), "14"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var res []KubernetesEvent
+	err = c.WatchEvents(ctx, "testns1", false, func(e KubernetesEvent) {
+		res = append(res, e)
+		if e.Reason == "Unhealthy" {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client watch events, %v", err)
+	}
+
+	if len(res) != 2 || res[0].Reason != "BackOff" || res[1].Reason != "Unhealthy" {
+		t.Errorf("expected the added and modified warning events, found %v", res)
+	}
+
+	if lists.Load() != 1 || len(versions) != 2 || versions[0] != "10" || versions[1] != "13" {
+		t.Errorf("expected the closed watch to resume from the last resource version, %v lists, %v", lists.Load(), versions)
+	}
+}
+
+func TestProviderKubernetesClientWatchEventsExpired(t *testing.T) {
+	first, second := watch.NewFake(), watch.NewFake()
+	var lists atomic.Int32
+	var versions []string
+	api := withTestEventWatches(NewKubernetesApiMock(), &lists, &versions, first, second)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	go func() {
+		first.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonExpired})
+		second.Add(withResourceVersion(newTestEvent("event1", corev1.EventTypeWarning, "BackOff", 0), "11"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var res []KubernetesEvent
+	err = c.WatchEvents(ctx, "testns1", true, func(e KubernetesEvent) {
+		res = append(res, e)
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client watch events, %v", err)
+	}
+
+	if len(res) != 1 || res[0].Reason != "BackOff" {
+		t.Errorf("expected the event from the restarted watch, found %v", res)
+	}
+
+	if lists.Load() != 2 {
+		t.Errorf("expected the expired watch to restart from a new list, %v lists, %v", lists.Load(), versions)
+	}
+}
+
+func TestProviderKubernetesClientWatchEventsCancel(t *testing.T) {
+	var lists atomic.Int32
+	var versions []string
+	api := withTestEventWatches(NewKubernetesApiMock(), &lists, &versions)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = c.WatchEvents(ctx, "testns1", true, func(e KubernetesEvent) {})
+	if err != nil {
+		t.Errorf("expected no error when the watch is cancelled, found %v", err)
+	}
+}
+
+// metricsApiResources is the metrics.k8s.io API served by metrics-server.
+var metricsApiResources = &metav1.APIResourceList{
+	GroupVersion: "metrics.k8s.io/v1beta1",
+	APIResources: []metav1.APIResource{
+		{Name: "nodes", Namespaced: false, Kind: "NodeMetrics"},
+		{Name: "pods", Namespaced: true, Kind: "PodMetrics"},
+	},
+}
+
+// withTestMetrics registers dynamic reactors serving node and pod metrics.
+func withTestMetrics(api *KubernetesApiMock) *KubernetesApiMock {
+	list := func(items ...map[string]interface{}) *unstructured.UnstructuredList {
+		l := &unstructured.UnstructuredList{}
+		for _, i := range items {
+			l.Items = append(l.Items, unstructured.Unstructured{Object: i})
+		}
+		return l
+	}
+	container := func(name string, cpu string, mem string) interface{} {
+		return map[string]interface{}{
+			"name":  name,
+			"usage": map[string]interface{}{"cpu": cpu, "memory": mem},
+		}
+	}
+
+	return api.AddResources(metricsApiResources).
+		WithDynamicListKind(k8sSchema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}, "NodeMetricsList").
+		WithDynamicListKind(k8sSchema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}, "PodMetricsList").
+		WithDynamicReactor("list", "nodes", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, list(
+				map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "node2"},
+					"usage":    map[string]interface{}{"cpu": "1500m", "memory": "2Gi"},
 				},
-			},
-		},
+				map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "node1"},
+					"usage":    map[string]interface{}{"cpu": "250m", "memory": "512Mi"},
+				},
+			), nil
+		}).
+		WithDynamicReactor("list", "pods", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, list(
+				map[string]interface{}{
+					"metadata":   map[string]interface{}{"namespace": "testns1", "name": "testpod1"},
+					"containers": []interface{}{container("app", "100m", "64Mi"), container("sidecar", "20m", "32Mi")},
+				},
+				map[string]interface{}{
+					"metadata":   map[string]interface{}{"namespace": "app1", "name": "testpod2"},
+					"containers": []interface{}{container("app", "1", "1Gi")},
+				},
+			), nil
+		})
+}
+
+func TestProviderKubernetesClientNodeUsage(t *testing.T) {
+	node := corev1.Node{}
+	node.Name = "node1"
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    apiresource.MustParse("2"),
+		corev1.ResourceMemory: apiresource.MustParse("4Gi"),
+	}
+
+	api := withTestMetrics(NewKubernetesApiMock().WithClientObjects(&node))
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	res, err := c.NodeUsage(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client node usage, %v", err)
+	}
+
+	expected := []KubernetesResourceUsage{
+		{Name: "node1", CPUMillis: 250, MemoryBytes: 512 * 1024 * 1024, AllocatableCPUMillis: 2000, AllocatableMemoryBytes: 4 * 1024 * 1024 * 1024},
+		{Name: "node2", CPUMillis: 1500, MemoryBytes: 2 * 1024 * 1024 * 1024},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("unexpected node usage, expected %v, found %v", expected, res)
+	}
+}
+
+func TestProviderKubernetesClientPodUsage(t *testing.T) {
+	api := withTestMetrics(NewKubernetesApiMock())
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	res, err := c.PodUsage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client pod usage, %v", err)
+	}
+
+	expected := []KubernetesResourceUsage{
+		{Namespace: "app1", Name: "testpod2", CPUMillis: 1000, MemoryBytes: 1024 * 1024 * 1024},
+		{Namespace: "testns1", Name: "testpod1", CPUMillis: 120, MemoryBytes: 96 * 1024 * 1024},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("unexpected pod usage, expected %v, found %v", expected, res)
+	}
+}
+
+func TestProviderKubernetesClientUsageNoMetricsApi(t *testing.T) {
+	c, err := NewKubernetesClient(NewKubernetesApiMock(), KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+	// don't reuse kinds cached by other tests serving the metrics API
+	c.cache = &KubernetesLookupCache{mutex: &sync.Mutex{}, kinds: map[string]k8sSchema.GroupVersionResource{}}
+
+	_, err = c.NodeUsage(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "metrics-server") {
+		t.Errorf("expected a metrics API not available error, found %v", err)
+	}
+
+	_, err = c.PodUsage(context.Background(), "testns1")
+	if err == nil || !strings.Contains(err.Error(), "metrics-server") {
+		t.Errorf("expected a metrics API not available error, found %v", err)
+	}
+}
+
+func TestProviderKubernetesClientGetSecretValue(t *testing.T) {
+	secret := corev1.Secret{}
+	secret.Name = "testsecret1"
+	secret.Namespace = "testns1"
+	secret.Data = map[string][]byte{
+		"key1": []byte("val1"),
+		"key2": []byte("val2"),
+	}
+	api := NewKubernetesApiMock().WithClientObjects(&secret)
+
+	cfg := schema.QuartzConfig{}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+
+	m, err := c.GetSecretValue(context.Background(), "testns1", "testsecret1")
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client get secret, %v", err)
+		return
+	}
+
+	if len(m) != 2 ||
+		m["key1"] != "val1" ||
+		m["key2"] != "val2" {
+		t.Errorf("unexpected response from kubernetes client get secret, %v", m)
+	}
+}
+
+func TestProviderKubernetesClientGetDynamicResource(t *testing.T) {
+	api := NewKubernetesApiMock().WithDynamicObjects(
+		newK8sObject("external-secrets.io/v1beta1", "ExternalSecret", "testns1", "testobj1"),
+	)
+
+	cfg := schema.QuartzConfig{}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+
+	kind, _ := c.LookupKind(context.Background(), "ExternalSecret")
+	res, err := c.GetDynamicResource(context.Background(), kind, "testns1", "testobj1")
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client get dynamic, %v", err)
+		return
+	}
+
+	actualName, found, err := unstructured.NestedString(res, "metadata", "name")
+	if err != nil ||
+		!found ||
+		actualName != "testobj1" {
+		t.Errorf("unexpected response from kubernetes client get dynamic, %v", res)
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfo(t *testing.T) {
+	secret := corev1.Secret{}
+	secret.Name = "test-secret"
+	secret.Namespace = "test"
+	secret.Data = map[string][]byte{
+		"password": []byte("supersecretpassword"),
+	}
+
+	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test")
+	unstructured.SetNestedStringSlice(vs.Object, []string{"testapp.example.com"}, "spec", "hosts")
+
+	api := NewKubernetesApiMock().
+		WithClientObjects(&secret).
+		WithDynamicObjects(vs)
+
+	opts := schema.NewApplicationLookupConfig("test", "test-secret", "test-admin", "username", "password", "")
+	// opts.Ingress = schema.ApplicationLookupIngressConfig{
+	// 	Kind:    "VirtualService",
+	// 	Group:   "networking.istio.io",
+	// 	Version: "v1beta1",
+	// }
+
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"test": {
+					Lookup: opts,
+				},
+			},
+		},
+	}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+
+	if res.Error != nil ||
+		res.Name != "TestApp" ||
+		res.AdminUsername != "test-admin" ||
+		res.AdminPassword != "supersecretpassword" ||
+		res.PublicEndpoint != "testapp.example.com" {
+		t.Errorf("unexpected response from kubernetes client get app info, %v", res)
+	}
+
+	c.PrintClusterInfo(context.Background(), ClusterInfoOpts{})
+}
+
+func TestProviderKubernetesClientPrintClusterInfoFilter(t *testing.T) {
+	api := NewKubernetesApiMock()
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"argocd": {
+					Description: "ArgoCD",
+					Lookup:      schema.NewApplicationLookupConfig("argocd", "argocd-secret", "admin", "", "password", ""),
+				},
+				"jenkins": {
+					Description: "Jenkins",
+					Lookup:      schema.NewApplicationLookupConfig("jenkins", "jenkins-secret", "admin", "", "password", ""),
+				},
+				"keycloak": {
+					Lookup: schema.NewApplicationLookupConfig("keycloak", "keycloak-secret", "admin", "", "password", ""),
+				},
+			},
+		},
+	}
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	// tables are printed directly to stdout
+	r, w, _ := os.Pipe()
+	defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+	os.Stdout = w
+
+	// match by config key and by description
+	c.PrintClusterInfo(context.Background(), ClusterInfoOpts{Apps: []string{"argocd", "KEYCLOAK"}})
+	w.Close()
+
+	b, _ := io.ReadAll(r)
+	out := string(b)
+	if !strings.Contains(out, "ArgoCD") || !strings.Contains(out, "keycloak") {
+		t.Errorf("expected requested apps in cluster info, %v", out)
+	}
+	if strings.Contains(out, "Jenkins") {
+		t.Errorf("unexpected filtered app in cluster info, %v", out)
+	}
+}
+
+func TestProviderKubernetesClientPrintClusterInfoWide(t *testing.T) {
+	secret := corev1.Secret{}
+	secret.Name = "argocd-secret"
+	secret.Namespace = "argocd"
+	secret.Data = map[string][]byte{
+		"password": []byte("supersecretpassword"),
+	}
+
+	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "argocd", "argocd-vs")
+	unstructured.SetNestedStringSlice(vs.Object, []string{"argocd.example.com"}, "spec", "hosts")
+
+	api := NewKubernetesApiMock().
+		WithClientObjects(&secret).
+		WithDynamicObjects(vs)
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"argocd": {
+					Description: "ArgoCD",
+					Lookup:      schema.NewApplicationLookupConfig("argocd", "argocd-secret", "admin", "", "password", "argocd-vs"),
+				},
+			},
+		},
+	}
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	print := func(opts ClusterInfoOpts) string {
+		// tables are printed directly to stdout
+		r, w, _ := os.Pipe()
+		defer func(v *os.File) { os.Stdout = v }(os.Stdout)
+		os.Stdout = w
+
+		c.PrintClusterInfo(context.Background(), opts)
+		w.Close()
+
+		b, _ := io.ReadAll(r)
+		return string(b)
+	}
+
+	out := print(ClusterInfoOpts{Apps: []string{"argocd"}, Wide: true})
+	for _, want := range []string{"Namespace", "Secret", "Ingress", "argocd-secret", "argocd-vs", "argocd.example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in wide cluster info, %v", want, out)
+		}
+	}
+
+	out = print(ClusterInfoOpts{Apps: []string{"argocd"}})
+	if !strings.Contains(out, "argocd.example.com") || strings.Contains(out, "argocd-secret") || strings.Contains(out, "Ingress") {
+		t.Errorf("unexpected wide columns in default cluster info, %v", out)
+	}
+}
+
+func TestProviderKubernetesAppLookupWideColumns(t *testing.T) {
+	opts := schema.NewApplicationLookupConfig("argocd", "argocd-secret", "admin", "", "", "argocd-vs")
+	cols := appLookupWideColumns(opts)
+	if strings.Join(cols, ",") != "argocd,argocd-secret,argocd-vs" {
+		t.Errorf("unexpected wide columns, %v", cols)
+	}
+
+	// ingress in another namespace is qualified
+	opts.Ingress.Namespace = "istio-system"
+	cols = appLookupWideColumns(opts)
+	if strings.Join(cols, ",") != "argocd,argocd-secret,istio-system/argocd-vs" {
+		t.Errorf("unexpected wide columns (ingress namespace), %v", cols)
+	}
+
+	// no secret, namespace falls back to the ingress
+	opts.AdminCredentials.Secret = schema.ApplicationLookupCredentialsSecretConfig{}
+	cols = appLookupWideColumns(opts)
+	if strings.Join(cols, ",") != "istio-system,,argocd-vs" {
+		t.Errorf("unexpected wide columns (no secret), %v", cols)
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfoConfigMap(t *testing.T) {
+	secret := corev1.Secret{}
+	secret.Name = "test-secret"
+	secret.Namespace = "test"
+	secret.Data = map[string][]byte{
+		"username": []byte("secret-admin"),
+		"password": []byte("supersecretpassword"),
+	}
+
+	cm := corev1.ConfigMap{}
+	cm.Name = "test-config"
+	cm.Namespace = "test"
+	cm.Data = map[string]string{
+		"admin-user": "configmap-admin",
+	}
+
+	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test")
+	unstructured.SetNestedStringSlice(vs.Object, []string{"testapp.example.com"}, "spec", "hosts")
+
+	api := NewKubernetesApiMock().
+		WithClientObjects(&secret, &cm).
+		WithDynamicObjects(vs)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		configMap schema.ApplicationLookupCredentialsConfigMapConfig
+		username  string
+		err       string
+	}{
+		{
+			name:     "secret only",
+			username: "secret-admin",
+		},
+		{
+			name:      "configmap username",
+			configMap: schema.ApplicationLookupCredentialsConfigMapConfig{Name: "test-config", UsernameKey: "admin-user"},
+			username:  "configmap-admin",
+		},
+		{
+			name:      "configmap explicit namespace",
+			configMap: schema.ApplicationLookupCredentialsConfigMapConfig{Name: "test-config", Namespace: "test", UsernameKey: "admin-user"},
+			username:  "configmap-admin",
+		},
+		{
+			name:      "configmap key missing",
+			configMap: schema.ApplicationLookupCredentialsConfigMapConfig{Name: "test-config"},
+			username:  "secret-admin",
+			err:       "admin username key username not found in configmap test/test-config",
+		},
+		{
+			name:      "configmap missing",
+			configMap: schema.ApplicationLookupCredentialsConfigMapConfig{Name: "missing", UsernameKey: "admin-user"},
+			username:  "secret-admin",
+			err:       "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := schema.NewApplicationLookupConfig("test", "test-secret", "static-admin", "", "", "")
+			opts.AdminCredentials.ConfigMap = tt.configMap
+
+			res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+			if res.AdminUsername != tt.username {
+				t.Errorf("unexpected admin username, expected %s, found %s", tt.username, res.AdminUsername)
+			}
+
+			// the password always comes from the secret
+			if res.AdminPassword != "supersecretpassword" {
+				t.Errorf("unexpected admin password, found %s", res.AdminPassword)
+			}
+
+			if tt.err == "" && res.Error != nil {
+				t.Errorf("unexpected error from kubernetes client get app info, %v", res.Error)
+			}
+
+			if tt.err != "" && (res.Error == nil || !strings.Contains(res.Error.Error(), tt.err)) {
+				t.Errorf("expected error containing %s from kubernetes client get app info, found %v", tt.err, res.Error)
+			}
+		})
+	}
+}
+
+func TestProviderKubernetesDecodeCredential(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		decode   schema.ApplicationLookupDecodeConfig
+		expected string
+		err      string
+	}{
+		{name: "plain", value: "hunter2", expected: "hunter2"},
+		{name: "none", value: "hunter2", decode: schema.ApplicationLookupDecodeConfig{Type: "none"}, expected: "hunter2"},
+		{name: "base64", value: base64.StdEncoding.EncodeToString([]byte("hunter2")), decode: schema.ApplicationLookupDecodeConfig{Type: "base64"}, expected: "hunter2"},
+		{name: "base64 invalid", value: "not base64!", decode: schema.ApplicationLookupDecodeConfig{Type: "base64"}, err: "illegal base64"},
+		{name: "jsonpath", value: `{"data":{"admin":{"password":"hunter2"}}}`, decode: schema.ApplicationLookupDecodeConfig{Type: "jsonpath", Path: ".data.admin.password"}, expected: "hunter2"},
+		{name: "jsonpath braces", value: `{"data":{"admin":{"password":"hunter2"}}}`, decode: schema.ApplicationLookupDecodeConfig{Type: "JSONPath", Path: "{.data.admin.password}"}, expected: "hunter2"},
+		{name: "jsonpath missing", value: `{"data":{}}`, decode: schema.ApplicationLookupDecodeConfig{Type: "jsonpath", Path: ".data.admin.password"}, err: "not found"},
+		{name: "jsonpath invalid json", value: "hunter2", decode: schema.ApplicationLookupDecodeConfig{Type: "jsonpath", Path: ".password"}, err: "invalid character"},
+		{name: "unsupported", value: "hunter2", decode: schema.ApplicationLookupDecodeConfig{Type: "rot13"}, err: "unsupported decode type rot13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := decodeCredential(tt.value, tt.decode)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expected error containing %s, found %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error decoding credential, %v", err)
+			}
+
+			if v != tt.expected {
+				t.Errorf("unexpected decoded credential, expected %s, found %s", tt.expected, v)
+			}
+		})
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfoDecode(t *testing.T) {
+	secret := corev1.Secret{}
+	secret.Name = "test-secret"
+	secret.Namespace = "test"
+	secret.Data = map[string][]byte{
+		"username": []byte(base64.StdEncoding.EncodeToString([]byte("test-admin"))),
+		"config":   []byte(`{"data":{"admin":{"password":"supersecretpassword"}}}`),
+	}
+
+	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test")
+	unstructured.SetNestedStringSlice(vs.Object, []string{"testapp.example.com"}, "spec", "hosts")
+
+	api := NewKubernetesApiMock().
+		WithClientObjects(&secret).
+		WithDynamicObjects(vs)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	opts := schema.NewApplicationLookupConfig("test", "test-secret", "", "username", "config", "")
+	opts.AdminCredentials.Secret.UsernameDecode = schema.ApplicationLookupDecodeConfig{Type: "base64"}
+	opts.AdminCredentials.Secret.PasswordDecode = schema.ApplicationLookupDecodeConfig{Type: "jsonpath", Path: ".data.admin.password"}
+
+	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+	if res.Error != nil ||
+		res.AdminUsername != "test-admin" ||
+		res.AdminPassword != "supersecretpassword" {
+		t.Errorf("unexpected response from kubernetes client get app info, %v", res)
+	}
+
+	opts.AdminCredentials.Secret.PasswordDecode.Path = ".data.missing"
+	res = c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+	if res.Error == nil || !strings.Contains(res.Error.Error(), "failed to decode admin password") {
+		t.Errorf("expected decode error from kubernetes client get app info, %v", res.Error)
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfoEmpty(t *testing.T) {
+	api := NewKubernetesApiMock()
+
+	opts := schema.NewApplicationLookupConfig("", "", "", "", "", "")
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"test": {
+					Lookup: opts,
+				},
+			},
+		},
+	}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+
+	if res.Error != nil {
+		t.Errorf("unexpected response from kubernetes client get app info, %v", res)
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfoMissingResources(t *testing.T) {
+	api := NewKubernetesApiMock()
+
+	opts := schema.NewApplicationLookupConfig("test", "test-secret", "", "username", "password", "test")
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"test": {
+					Lookup: opts,
+				},
+			},
+		},
+	}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+
+	if res.Error == nil {
+		t.Error("expected error from kubernetes client get app info not found")
+	}
+
+	if !strings.Contains(res.Error.Error(), "secrets \"test-secret\" not found") ||
+		!strings.Contains(res.Error.Error(), "virtualservices.networking.istio.io \"test\" not found") {
+		t.Errorf("invalid error from kubernetes client get app info, found %v", res.Error)
+	}
+}
+
+func TestProviderKubernetesClientGetAppConnectionInfoNoVirtualServiceCrd(t *testing.T) {
+	api := &KubernetesApiMock{}
+
+	opts := schema.NewApplicationLookupConfig("test", "test-secret", "", "username", "password", "")
+	cfg := schema.QuartzConfig{
+		Core: schema.InfrastructureEnvironmentConfig{
+			Applications: map[string]schema.InfrastructureApplicationConfig{
+				"test": {
+					Lookup: opts,
+				},
+			},
+		},
+	}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+
+	if res.Error == nil {
+		t.Error("expected error from kubernetes client get app info missing crd")
+		return
+	}
+}
+
+func TestProviderKubernetesClientWaitConditionState(t *testing.T) {
+	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test-vs")
+	unstructured.SetNestedSlice(vs.Object, []interface{}{
+		map[string]interface{}{"status": "True", "type": "THINKING..."},
+	}, "status", "conditions")
+
+	api := NewKubernetesApiMock().WithDynamicObjects(vs)
+
+	cfg := schema.QuartzConfig{}
+	kubeconfig := KubeconfigInfo{}
+
+	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+
+	kind, err := c.LookupKind(context.Background(), "VirtualService")
+	if err != nil {
+		t.Errorf("failed to lookup virtualservice kind, %v", err)
+		return
+	}
+
+	err = c.WaitConditionState(context.Background(), kind, "test", "test-vs", "DONE", 1)
+	if err == nil {
+		t.Error("this should have timed out")
+	}
+
+	err = c.WaitConditionState(context.Background(), kind, "test", "test-vs", "THINKING...", 1)
+	if err != nil {
+		t.Errorf("unexpected response from kubernetes client wait, %v", err)
+	}
+}
+
+func TestProviderKubernetesClientWaitResourcesDeleted(t *testing.T) {
+	polls := 0
+	api := NewKubernetesApiMock().
+		WithDynamicObjects(newK8sObject("external-secrets.io/v1beta1", "ExternalSecret", "testns1", "testobj1")).
+		WithDynamicReactor("list", "externalsecrets", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			polls = polls + 1
+			if polls == 1 {
+				// first poll falls through to the tracker, resource still present
+				return false, nil, nil
+			}
+
+			// resource finalized and removed on subsequent polls
+			return true, &unstructured.UnstructuredList{Object: map[string]interface{}{}}, nil
+		})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+
+	kind := k8sSchema.GroupVersionResource{
+		Group:    "external-secrets.io",
+		Version:  "v1beta1",
+		Resource: "externalsecrets",
+	}
+
+	err = c.WaitResourcesDeleted(context.Background(), kind, "testns1", "testobj1", 10, 1)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client wait deleted, %v", err)
+	}
+
+	if polls != 2 {
+		t.Errorf("unexpected poll count from kubernetes client wait deleted, expected 2, found %d", polls)
+	}
+}
+
+func TestProviderKubernetesClientWaitResourcesDeletedTimeout(t *testing.T) {
+	api := NewKubernetesApiMock().WithDynamicObjects(
+		newK8sObject("external-secrets.io/v1beta1", "ExternalSecret", "testns1", "testobj1"),
+		newK8sObject("external-secrets.io/v1beta1", "ExternalSecret", "testns1", "testobj2"),
+	)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
+		return
+	}
+
+	kind := k8sSchema.GroupVersionResource{
+		Group:    "external-secrets.io",
+		Version:  "v1beta1",
+		Resource: "externalsecrets",
+	}
+
+	err = c.WaitResourcesDeleted(context.Background(), kind, "testns1", "testobj1", 1, 1)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout from kubernetes client wait deleted, %v", err)
+	}
+
+	// name filter excludes everything present, nothing to wait on
+	err = c.WaitResourcesDeleted(context.Background(), kind, "testns1", "testobj3", 1, 1)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client wait deleted (no match), %v", err)
+	}
+}
+
+func newK8sDeployment(namespace, name string, replicas, updated, available int64) *unstructured.Unstructured {
+	o := newK8sObject("apps/v1", "Deployment", namespace, name)
+	o.Object["spec"] = map[string]interface{}{
+		"replicas": replicas,
+	}
+	o.Object["status"] = map[string]interface{}{
+		"replicas":          updated,
+		"updatedReplicas":   updated,
+		"availableReplicas": available,
+	}
+	return o
+}
+
+func TestProviderKubernetesClientRestart(t *testing.T) {
+	api := NewKubernetesApiMock().WithDynamicObjects(
+		newK8sDeployment("testns1", "testdeploy1", 1, 1, 1),
+		newK8sDeployment("testns1", "testdeploy2", 1, 1, 1),
+	)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	kind := k8sSchema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	res, err := c.Restart(context.Background(), kind, "testns1", "")
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client restart, %v", err)
+	}
+
+	if len(res) != 2 {
+		t.Fatalf("unexpected restarted resource count, expected 2, found %d", len(res))
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(res[0].Item.Object, "spec", "template", "metadata", "annotations")
+	if annotations["kubectl.kubernetes.io/restartedAt"] == "" {
+		t.Errorf("expected restart annotation on restarted resource, found %v", annotations)
+	}
+
+	res, err = c.Restart(context.Background(), kind, "testns1", "testdeploy2")
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client restart (by name), %v", err)
+	}
+
+	if len(res) != 1 || res[0].Name != "testdeploy2" {
+		t.Errorf("unexpected restarted resources (by name), %v", res)
+	}
+
+	_, err = c.Restart(context.Background(), k8sSchema.GroupVersionResource{Resource: "configmaps"}, "testns1", "")
+	if err == nil || !strings.Contains(err.Error(), "unsupported resource type") {
+		t.Errorf("expected unsupported resource error from kubernetes client restart, %v", err)
+	}
+}
+
+func TestProviderKubernetesClientWaitForRollout(t *testing.T) {
+	gets := 0
+	api := NewKubernetesApiMock().
+		WithDynamicObjects(newK8sDeployment("testns1", "testdeploy1", 2, 2, 2)).
+		WithDynamicReactor("get", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			gets = gets + 1
+			if gets == 1 {
+				// first poll sees the rollout in progress
+				return true, newK8sDeployment("testns1", "testdeploy1", 2, 1, 1), nil
+			}
+
+			return false, nil, nil
+		})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	kind := k8sSchema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	err = c.WaitForRollout(context.Background(), kind, "testns1", "testdeploy1", 10, 1)
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client wait for rollout, %v", err)
+	}
+
+	if gets != 2 {
+		t.Errorf("unexpected poll count from kubernetes client wait for rollout, expected 2, found %d", gets)
 	}
-	kubeconfig := KubeconfigInfo{}
+}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+func TestProviderKubernetesClientWaitForRolloutTimeout(t *testing.T) {
+	api := NewKubernetesApiMock().WithDynamicObjects(newK8sDeployment("testns1", "testdeploy1", 2, 1, 1))
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
 	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
 	}
-	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
 
-	if res.Error != nil ||
-		res.Name != "TestApp" ||
-		res.AdminUsername != "test-admin" ||
-		res.AdminPassword != "supersecretpassword" ||
-		res.PublicEndpoint != "testapp.example.com" {
-		t.Errorf("unexpected response from kubernetes client get app info, %v", res)
+	kind := k8sSchema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	err = c.WaitForRollout(context.Background(), kind, "testns1", "testdeploy1", 1, 1)
+	if err == nil || !strings.Contains(err.Error(), "timed out waiting for rollout") {
+		t.Errorf("expected timeout from kubernetes client wait for rollout, %v", err)
+	}
+
+	err = c.WaitForRollout(context.Background(), kind, "testns1", "missing", 1, 1)
+	if err == nil {
+		t.Errorf("expected not found error from kubernetes client wait for rollout")
 	}
+}
 
-	c.PrintClusterInfo(context.Background())
+func TestProviderKubernetesRolloutStatus(t *testing.T) {
+	deployments := k8sSchema.GroupVersionResource{Resource: "deployments"}
+	daemonsets := k8sSchema.GroupVersionResource{Resource: "daemonsets"}
+	statefulsets := k8sSchema.GroupVersionResource{Resource: "statefulsets"}
+
+	stale := newK8sDeployment("testns1", "stale", 1, 1, 1)
+	stale.SetGeneration(2)
+
+	ds := newK8sObject("apps/v1", "DaemonSet", "testns1", "ds")
+	ds.Object["status"] = map[string]interface{}{
+		"desiredNumberScheduled": int64(3),
+		"updatedNumberScheduled": int64(3),
+		"numberAvailable":        int64(2),
+	}
+
+	sts := newK8sObject("apps/v1", "StatefulSet", "testns1", "sts")
+	sts.Object["status"] = map[string]interface{}{
+		"updatedReplicas": int64(1),
+		"readyReplicas":   int64(1),
+	}
+
+	tests := []struct {
+		name string
+		kind k8sSchema.GroupVersionResource
+		item *unstructured.Unstructured
+		done bool
+	}{
+		{name: "deployment complete", kind: deployments, item: newK8sDeployment("testns1", "d", 2, 2, 2), done: true},
+		{name: "deployment updating", kind: deployments, item: newK8sDeployment("testns1", "d", 2, 1, 2), done: false},
+		{name: "deployment unavailable", kind: deployments, item: newK8sDeployment("testns1", "d", 2, 2, 1), done: false},
+		{name: "generation not observed", kind: deployments, item: stale, done: false},
+		{name: "daemonset unavailable", kind: daemonsets, item: ds, done: false},
+		{name: "statefulset default replicas", kind: statefulsets, item: sts, done: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, status := rolloutStatus(tt.kind, *tt.item)
+			if done != tt.done {
+				t.Errorf("unexpected rollout status, expected %v, found %v (%s)", tt.done, done, status)
+			}
+		})
+	}
 }
 
-func TestProviderKubernetesClientGetAppConnectionInfoEmpty(t *testing.T) {
-	api := NewKubernetesApiMock()
+func newHealthTestNode(name string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
 
-	opts := schema.NewApplicationLookupConfig("", "", "", "", "", "")
-	cfg := schema.QuartzConfig{
-		Core: schema.InfrastructureEnvironmentConfig{
-			Applications: map[string]schema.InfrastructureApplicationConfig{
-				"test": {
-					Lookup: opts,
-				},
-			},
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
 		},
 	}
-	kubeconfig := KubeconfigInfo{}
+}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
-	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+func newHealthTestPod(ns, name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Status:     corev1.PodStatus{Phase: phase},
 	}
-	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
+}
 
-	if res.Error != nil {
-		t.Errorf("unexpected response from kubernetes client get app info, %v", res)
+// withHealthTestContainer adds a container status to the pod, waiting with the reason if set.
+func withHealthTestContainer(pod *corev1.Pod, ready bool, waiting string) *corev1.Pod {
+	cs := corev1.ContainerStatus{Name: fmt.Sprintf("c%d", len(pod.Status.ContainerStatuses)), Ready: ready}
+	if waiting != "" {
+		cs.State.Waiting = &corev1.ContainerStateWaiting{Reason: waiting}
+	} else {
+		cs.State.Running = &corev1.ContainerStateRunning{}
 	}
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, cs)
+	return pod
 }
 
-func TestProviderKubernetesClientGetAppConnectionInfoMissingResources(t *testing.T) {
-	api := NewKubernetesApiMock()
+func TestProviderPodUnhealthyReason(t *testing.T) {
+	initWaiting := newHealthTestPod("app", "init", corev1.PodPending)
+	initWaiting.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "init", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected string
+	}{
+		{"running ready", withHealthTestContainer(newHealthTestPod("app", "web", corev1.PodRunning), true, ""), ""},
+		{"completed", newHealthTestPod("app", "job", corev1.PodSucceeded), ""},
+		{"crash loop", withHealthTestContainer(withHealthTestContainer(newHealthTestPod("app", "web", corev1.PodRunning), true, ""), false, "CrashLoopBackOff"), "CrashLoopBackOff"},
+		{"not ready", withHealthTestContainer(newHealthTestPod("app", "web", corev1.PodRunning), false, ""), "NotReady"},
+		{"pending creating", withHealthTestContainer(newHealthTestPod("app", "web", corev1.PodPending), false, "ContainerCreating"), "Pending"},
+		{"init image pull", initWaiting, "ImagePullBackOff"},
+		{"failed", newHealthTestPod("app", "web", corev1.PodFailed), "Failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := podUnhealthyReason(*tt.pod); actual != tt.expected {
+				t.Errorf("unexpected unhealthy reason, expected %q, found %q", tt.expected, actual)
+			}
+		})
+	}
+}
 
-	opts := schema.NewApplicationLookupConfig("test", "test-secret", "", "username", "password", "test")
-	cfg := schema.QuartzConfig{
-		Core: schema.InfrastructureEnvironmentConfig{
-			Applications: map[string]schema.InfrastructureApplicationConfig{
-				"test": {
-					Lookup: opts,
+func newHealthTestDaemonSet(ns, name string, ready, desired int64) *unstructured.Unstructured {
+	ds := newK8sObject("apps/v1", "DaemonSet", ns, name)
+	ds.Object["status"] = map[string]interface{}{
+		"desiredNumberScheduled": desired,
+		"numberReady":            ready,
+	}
+	return ds
+}
+
+func newHealthTestConfig() schema.QuartzConfig {
+	return schema.QuartzConfig{
+		Stages: map[string]schema.StageConfig{
+			"istio": {
+				Checks: map[string]schema.StageChecksConfig{
+					"cni": {DaemonSet: []schema.StageChecksDaemonSetConfig{{Namespace: "istio-system", Name: "istio-cni"}}},
+				},
+			},
+			"bigbang": {
+				Checks: map[string]schema.StageChecksConfig{
+					"cni":   {DaemonSet: []schema.StageChecksDaemonSetConfig{{Namespace: "istio-system", Name: "istio-cni"}}},
+					"proxy": {DaemonSet: []schema.StageChecksDaemonSetConfig{{Namespace: "kube-system", Name: "kube-proxy"}}},
+				},
+			},
+			"disabled": {
+				Disabled: true,
+				Checks: map[string]schema.StageChecksConfig{
+					"other": {DaemonSet: []schema.StageChecksDaemonSetConfig{{Namespace: "other", Name: "other"}}},
 				},
 			},
 		},
 	}
-	kubeconfig := KubeconfigInfo{}
+}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
+func TestProviderKubernetesClientHealthSummaryHealthy(t *testing.T) {
+	api := NewKubernetesApiMock().
+		WithClientObjects(
+			newHealthTestNode("node1", true),
+			newHealthTestNode("node2", true),
+			newHealthTestPod("app", "web", corev1.PodRunning),
+			newHealthTestPod("app", "job", corev1.PodSucceeded),
+		).
+		WithDynamicObjects(
+			newHealthTestDaemonSet("istio-system", "istio-cni", 2, 2),
+			newHealthTestDaemonSet("kube-system", "kube-proxy", 2, 2),
+		)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, newHealthTestConfig())
 	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
 	}
-	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
 
-	if res.Error == nil {
-		t.Error("expected error from kubernetes client get app info not found")
+	h, err := c.HealthSummary(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client health summary, %v", err)
 	}
 
-	if !strings.Contains(res.Error.Error(), "secrets \"test-secret\" not found") ||
-		!strings.Contains(res.Error.Error(), "virtualservices.networking.istio.io \"test\" not found") {
-		t.Errorf("invalid error from kubernetes client get app info, found %v", res.Error)
+	if !h.Healthy() {
+		t.Errorf("expected healthy cluster, found %+v", h)
 	}
-}
 
-func TestProviderKubernetesClientGetAppConnectionInfoNoVirtualServiceCrd(t *testing.T) {
-	api := &KubernetesApiMock{}
+	if h.NodesReady != 2 || h.NodesTotal != 2 {
+		t.Errorf("unexpected node counts, expected 2/2, found %d/%d", h.NodesReady, h.NodesTotal)
+	}
 
-	opts := schema.NewApplicationLookupConfig("test", "test-secret", "", "username", "password", "")
-	cfg := schema.QuartzConfig{
-		Core: schema.InfrastructureEnvironmentConfig{
-			Applications: map[string]schema.InfrastructureApplicationConfig{
-				"test": {
-					Lookup: opts,
-				},
-			},
-		},
+	// deduplicated across stages, disabled stages skipped
+	if len(h.DaemonSets) != 2 || h.DaemonSets[0].Name != "istio-cni" || h.DaemonSets[1].Name != "kube-proxy" {
+		t.Errorf("unexpected critical daemonsets, %+v", h.DaemonSets)
 	}
-	kubeconfig := KubeconfigInfo{}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
-	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+	rows, status := healthSummaryRows(h)
+	for i, st := range status {
+		if st != util.StatusOk {
+			t.Errorf("unexpected status for row %v, %s", rows[i], st)
+		}
 	}
-	res := c.GetAppConnectionInfo(context.Background(), "TestApp", opts)
 
-	if res.Error == nil {
-		t.Error("expected error from kubernetes client get app info missing crd")
-		return
+	err = c.PrintHealthSummary(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error from kubernetes client print health summary, %v", err)
 	}
 }
 
-func TestProviderKubernetesClientWaitConditionState(t *testing.T) {
-	vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", "test", "test-vs")
-	unstructured.SetNestedSlice(vs.Object, []interface{}{
-		map[string]interface{}{"status": "True", "type": "THINKING..."},
-	}, "status", "conditions")
+func TestProviderKubernetesClientHealthSummaryDegraded(t *testing.T) {
+	api := NewKubernetesApiMock().
+		WithClientObjects(
+			newHealthTestNode("node1", true),
+			newHealthTestNode("node2", false),
+			newHealthTestPod("app", "web", corev1.PodRunning),
+			newHealthTestPod("app", "pending", corev1.PodPending),
+			newHealthTestPod("app", "failed", corev1.PodFailed),
+			withHealthTestContainer(newHealthTestPod("app", "crashing", corev1.PodRunning), false, "CrashLoopBackOff"),
+			withHealthTestContainer(newHealthTestPod("app", "starting", corev1.PodRunning), false, ""),
+		).
+		WithDynamicObjects(
+			newHealthTestDaemonSet("istio-system", "istio-cni", 1, 2),
+		)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, newHealthTestConfig())
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
 
-	api := NewKubernetesApiMock().WithDynamicObjects(vs)
+	h, err := c.HealthSummary(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client health summary, %v", err)
+	}
 
-	cfg := schema.QuartzConfig{}
-	kubeconfig := KubeconfigInfo{}
+	if h.Healthy() {
+		t.Errorf("expected degraded cluster, found %+v", h)
+	}
 
-	c, err := NewKubernetesClient(api, kubeconfig, cfg)
-	if err != nil {
-		t.Errorf("unexpected error from kubernetes client constructor, %v", err)
-		return
+	if h.NodesReady != 1 || h.NodesTotal != 2 {
+		t.Errorf("unexpected node counts, expected 1/2, found %d/%d", h.NodesReady, h.NodesTotal)
 	}
 
-	kind, err := c.LookupKind(context.Background(), "VirtualService")
-	if err != nil {
-		t.Errorf("failed to lookup virtualservice kind, %v", err)
-		return
+	expectedPods := []string{"app/crashing (CrashLoopBackOff)", "app/failed (Failed)", "app/pending (Pending)", "app/starting (NotReady)"}
+	if strings.Join(h.FailingPods, ",") != strings.Join(expectedPods, ",") {
+		t.Errorf("unexpected failing pods, expected %v, found %v", expectedPods, h.FailingPods)
 	}
 
-	err = c.WaitConditionState(context.Background(), kind, "test", "test-vs", "DONE", 1)
-	if err == nil {
-		t.Error("this should have timed out")
+	rows, status := healthSummaryRows(h)
+	expected := map[string]string{
+		"Nodes":                            "1/2 ready",
+		"DaemonSet istio-system/istio-cni": "1/2 pods ready",
+		"Pods":                             "4 unhealthy: app/crashing (CrashLoopBackOff), app/failed (Failed), app/pending (Pending), app/starting (NotReady)",
 	}
 
-	err = c.WaitConditionState(context.Background(), kind, "test", "test-vs", "THINKING...", 1)
+	found := map[string]bool{}
+	for i, row := range rows {
+		if status[i] != util.StatusError {
+			t.Errorf("unexpected status for row %v, %s", row, status[i])
+		}
+
+		if want, ok := expected[row[0]]; ok {
+			found[row[0]] = true
+			if row[1] != want {
+				t.Errorf("unexpected result for %s, expected %s, found %s", row[0], want, row[1])
+			}
+		}
+	}
+
+	if len(found) != len(expected) {
+		t.Errorf("missing health rows, expected %v, found %v", expected, rows)
+	}
+
+	// kube-proxy is missing from the cluster, reported as not ready
+	if !strings.Contains(strings.Join(rows[len(rows)-2], " "), "kube-system/kube-proxy") {
+		t.Errorf("expected missing daemonset row, found %v", rows)
+	}
+}
+
+func TestProviderKubernetesClientHealthSummaryError(t *testing.T) {
+	api := NewKubernetesApiMock().WithClientReactor("list", "nodes", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, os.ErrPermission
+	})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
 	if err != nil {
-		t.Errorf("unexpected response from kubernetes client wait, %v", err)
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	err = c.PrintHealthSummary(context.Background())
+	if err == nil {
+		t.Errorf("expected error from kubernetes client print health summary")
 	}
 }
 
@@ -766,7 +2118,7 @@ func TestProviderKubernetesClientCleanupStuckTerminatingPods(t *testing.T) {
 	}
 
 	// Test with no pods - should return empty list
-	cleaned, err := c.CleanupStuckTerminatingPods(context.Background(), 5*60*1000000000) // 5 minutes in nanoseconds
+	cleaned, err := c.CleanupStuckTerminatingPods(context.Background(), 5*60*1000000000, TerminatingPodCleanupOpts{}) // 5 minutes in nanoseconds
 	if err != nil {
 		t.Errorf("unexpected error from CleanupStuckTerminatingPods, %v", err)
 		return
@@ -777,6 +2129,123 @@ func TestProviderKubernetesClientCleanupStuckTerminatingPods(t *testing.T) {
 	}
 }
 
+func TestProviderKubernetesClientCleanupStuckTerminatingPodsAges(t *testing.T) {
+	terminatingPod := func(name string, node string, age time.Duration) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+		if age > 0 {
+			ts := metav1.NewTime(time.Now().Add(-age))
+			pod.DeletionTimestamp = &ts
+		}
+		return pod
+	}
+
+	api := NewKubernetesApiMock().WithClientObjects(
+		terminatingPod("running", "node-a", 0),
+		terminatingPod("recent", "node-a", 2*time.Minute),
+		terminatingPod("stuck", "node-b", 10*time.Minute),
+		terminatingPod("stuck-longer", "", 2*time.Hour),
+	)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	cleaned, err := c.CleanupStuckTerminatingPods(context.Background(), 5*time.Minute, TerminatingPodCleanupOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from CleanupStuckTerminatingPods, %v", err)
+	}
+
+	if len(cleaned) != 2 {
+		t.Fatalf("expected 2 cleaned pods, got %v", cleaned)
+	}
+
+	byName := map[string]TerminatingPodInfo{}
+	for _, p := range cleaned {
+		byName[p.String()] = p
+	}
+
+	stuck, ok := byName["test/stuck"]
+	if !ok || stuck.Node != "node-b" || stuck.TerminatingFor < 10*time.Minute || stuck.TerminatingFor > 11*time.Minute {
+		t.Errorf("unexpected cleaned pod info for test/stuck, %+v", stuck)
+	}
+
+	longer, ok := byName["test/stuck-longer"]
+	if !ok || longer.Node != "" || longer.TerminatingFor < 2*time.Hour {
+		t.Errorf("unexpected cleaned pod info for test/stuck-longer, %+v", longer)
+	}
+}
+
+func TestProviderKubernetesClientCleanupStuckTerminatingPodsForce(t *testing.T) {
+	ts := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "test",
+			Name:              "stuck",
+			DeletionTimestamp: &ts,
+			Finalizers:        []string{"example.com/finalizer"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        TerminatingPodCleanupOpts
+		expectGrace *int64
+		expectPatch bool
+	}{
+		{name: "default", opts: TerminatingPodCleanupOpts{}},
+		{name: "finalizers without force", opts: TerminatingPodCleanupOpts{RemoveFinalizers: true}},
+		{name: "force", opts: TerminatingPodCleanupOpts{Force: true}, expectGrace: new(int64)},
+		{name: "force and finalizers", opts: TerminatingPodCleanupOpts{Force: true, RemoveFinalizers: true}, expectGrace: new(int64), expectPatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deletes []metav1.DeleteOptions
+			var patches int
+			api := NewKubernetesApiMock().
+				WithClientObjects(pod.DeepCopy()).
+				WithClientReactor("delete", "pods", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+					deletes = append(deletes, action.(k8sTesting.DeleteAction).GetDeleteOptions())
+					return false, nil, nil
+				}).
+				WithClientReactor("patch", "pods", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+					patches++
+					if p := string(action.(k8sTesting.PatchAction).GetPatch()); p != `{"metadata":{"finalizers":null}}` {
+						t.Errorf("unexpected pod patch, %s", p)
+					}
+					return false, nil, nil
+				})
+
+			c, _ := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+			cleaned, err := c.CleanupStuckTerminatingPods(context.Background(), 5*time.Minute, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error from CleanupStuckTerminatingPods, %v", err)
+			}
+
+			if len(cleaned) != 1 || cleaned[0].Forced != tt.opts.Force {
+				t.Errorf("unexpected cleaned pods, %+v", cleaned)
+			}
+
+			if len(deletes) != 1 {
+				t.Fatalf("expected 1 pod delete, found %v", len(deletes))
+			}
+
+			grace := deletes[0].GracePeriodSeconds
+			if (grace == nil) != (tt.expectGrace == nil) || (grace != nil && *grace != *tt.expectGrace) {
+				t.Errorf("unexpected delete grace period, expected %v, found %v", tt.expectGrace, grace)
+			}
+
+			if (patches == 1) != tt.expectPatch {
+				t.Errorf("unexpected finalizer patches, expected %v, found %v", tt.expectPatch, patches)
+			}
+		})
+	}
+}
+
 func newK8sObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
 	o := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -861,6 +2330,27 @@ func TestProviderKubernetesClientListVirtualServices(t *testing.T) {
 	}
 }
 
+func TestProviderVirtualServiceInfoMeshOnly(t *testing.T) {
+	tests := []struct {
+		gateways []string
+		expected bool
+	}{
+		{gateways: nil, expected: true},
+		{gateways: []string{}, expected: true},
+		{gateways: []string{"mesh"}, expected: true},
+		{gateways: []string{"mesh", "mesh"}, expected: true},
+		{gateways: []string{"istio-system/main-gateway"}, expected: false},
+		{gateways: []string{"mesh", "istio-system/main-gateway"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		vs := VirtualServiceInfo{Name: "test", Gateways: tt.gateways}
+		if vs.MeshOnly() != tt.expected {
+			t.Errorf("unexpected mesh only for gateways %v, expected %v", tt.gateways, tt.expected)
+		}
+	}
+}
+
 func TestProviderKubernetesClientListVirtualServicesNotFound(t *testing.T) {
 	// Test when VirtualService CRD doesn't exist
 	api := NewKubernetesApiMock()
@@ -1048,6 +2538,61 @@ func TestProviderKubernetesClientPrintDiscoveredVirtualServices(t *testing.T) {
 	c.PrintDiscoveredVirtualServices(context.Background(), map[string]bool{"my-service": true})
 }
 
+func TestProviderKubernetesClientDiscoverVirtualServices(t *testing.T) {
+	newVs := func(ns string, name string, gateways ...interface{}) *unstructured.Unstructured {
+		vs := newK8sObject("networking.istio.io/v1beta1", "VirtualService", ns, name)
+		vs.Object["spec"] = map[string]interface{}{
+			"hosts":    []interface{}{name + ".example.com"},
+			"gateways": gateways,
+		}
+		return vs
+	}
+
+	api := NewKubernetesApiMock().WithDynamicObjects(
+		newVs("app1", "my-service", "istio-system/main-gateway"),
+		newVs("app1", "configured-app", "istio-system/main-gateway"),
+		newVs("app2", "internal-service", "mesh"),
+		newVs("app2", "mixed-service", "mesh", "istio-system/main-gateway"),
+		newVs("app3", "default-gateway"),
+	)
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	res, err := c.DiscoverVirtualServices(context.Background(), map[string]bool{"configured-app": true})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client discover virtual services, %v", err)
+	}
+
+	var names []string
+	for _, vs := range res {
+		names = append(names, vs.Namespace+"/"+vs.Name)
+	}
+
+	// configured apps and mesh-only services are skipped
+	expected := "app1/my-service,app2/mixed-service,app3/default-gateway"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("unexpected discovered virtual services, expected %s, found %v", expected, names)
+	}
+
+	printed := c.PrintDiscoveredVirtualServices(context.Background(), map[string]bool{"configured-app": true})
+	if len(printed) != len(res) {
+		t.Errorf("unexpected printed virtual services, expected %v, found %v", res, printed)
+	}
+
+	all := c.PrintClusterInfo(context.Background(), ClusterInfoOpts{})
+	if len(all) != 4 {
+		t.Errorf("unexpected discovered virtual services from cluster info, expected 4, found %v", all)
+	}
+
+	filtered := c.PrintClusterInfo(context.Background(), ClusterInfoOpts{Apps: []string{"missing"}})
+	if filtered != nil {
+		t.Errorf("unexpected discovered virtual services from filtered cluster info, %v", filtered)
+	}
+}
+
 func TestProviderKubernetesClientPrintDiscoveredVirtualServicesNoCRD(t *testing.T) {
 	// Test when VirtualService CRD doesn't exist - should handle gracefully
 	api := NewKubernetesApiMock()
@@ -1061,7 +2606,15 @@ func TestProviderKubernetesClientPrintDiscoveredVirtualServicesNoCRD(t *testing.
 	}
 
 	// Should not panic when CRD doesn't exist
-	c.PrintDiscoveredVirtualServices(context.Background(), map[string]bool{})
+	res := c.PrintDiscoveredVirtualServices(context.Background(), map[string]bool{})
+	if res != nil {
+		t.Errorf("unexpected discovered virtual services without the CRD, %v", res)
+	}
+
+	_, err = c.DiscoverVirtualServices(context.Background(), map[string]bool{})
+	if err == nil {
+		t.Errorf("expected error discovering virtual services without the CRD")
+	}
 }
 
 func TestProviderKubernetesClientExport(t *testing.T) {
@@ -1138,7 +2691,7 @@ func TestProviderKubernetesClientExport(t *testing.T) {
 	}
 
 	// Verify result contains the expected file
-	expectedKey := "export-ns.export-test-cm.yaml"
+	expectedKey := "configmap/export-ns/export-test-cm"
 	if _, exists := result[expectedKey]; !exists {
 		t.Errorf("expected key %s in export result, got keys: %v", expectedKey, result)
 		return
@@ -1196,6 +2749,109 @@ func TestProviderKubernetesClientExportWithErrors(t *testing.T) {
 	}
 }
 
+func TestProviderKubernetesClientExportPartial(t *testing.T) {
+	api := NewKubernetesApiMock().
+		WithDynamicObjects(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "present-cm",
+					"namespace": "export-ns",
+				},
+			},
+		}).
+		AddResources(&metav1.APIResourceList{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+			},
+		})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	result, err := c.Export(context.Background(), schema.ExportConfig{
+		Objects: []schema.ExportObjectConfig{
+			{Kind: "ConfigMap", Namespace: "export-ns", Name: "missing-cm"},
+			{Kind: "ConfigMap", Namespace: "export-ns", Name: "present-cm"},
+			{Kind: "NotAKind", Namespace: "export-ns", Name: "unknown"},
+		},
+	})
+
+	if err == nil {
+		t.Fatal("expected error for missing objects")
+	}
+
+	for _, e := range []string{"ConfigMap export-ns/missing-cm", "NotAKind export-ns/unknown"} {
+		if !strings.Contains(err.Error(), "failed to export "+e) {
+			t.Errorf("expected error for %s, found %v", e, err)
+		}
+	}
+
+	if len(result) != 1 {
+		t.Errorf("expected only the present object to be exported, found %v", result)
+	}
+
+	if _, ok := result["configmap/export-ns/present-cm"]; !ok {
+		t.Errorf("expected present object in export result, found %v", result)
+	}
+}
+
+func TestProviderKubernetesClientExportSameName(t *testing.T) {
+	api := NewKubernetesApiMock().
+		WithDynamicObjects(
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "app", "namespace": "export-ns"},
+			}},
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": "app", "namespace": "export-ns"},
+			}},
+		).
+		AddResources(&metav1.APIResourceList{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+				{Name: "secrets", Namespaced: true, Kind: "Secret"},
+			},
+		})
+
+	c, err := NewKubernetesClient(api, KubeconfigInfo{}, schema.QuartzConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from kubernetes client constructor, %v", err)
+	}
+
+	var buf bytes.Buffer
+	util.SetWriter(&buf)
+	defer util.SetWriter(&bytes.Buffer{})
+
+	result, err := c.Export(context.Background(), schema.ExportConfig{
+		Objects: []schema.ExportObjectConfig{
+			{Kind: "ConfigMap", Namespace: "export-ns", Name: "app"},
+			{Kind: "Secret", Namespace: "export-ns", Name: "app"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from export, %v", err)
+	}
+
+	for key, kind := range map[string]string{"configmap/export-ns/app": "kind: ConfigMap", "secret/export-ns/app": "kind: Secret"} {
+		if !strings.Contains(string(result[key]), kind) {
+			t.Errorf("expected %s exported as %s, found %s", key, kind, result[key])
+		}
+	}
+}
+
 func TestProviderKubernetesClientExportUnknownKind(t *testing.T) {
 	// Test Export with unknown Kind
 	api := NewKubernetesApiMock()
@@ -16,12 +16,22 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 
+	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
 	"github.com/MetroStar/quartzctl/internal/util"
 )
 
+// ErrNoCloudCluster is returned by cloud providers which don't manage a cluster to build a kubeconfig for,
+// e.g. the local provider.
+var ErrNoCloudCluster = errors.New("no cloud cluster configured")
+
 // LocalClient represents a local provider client.
 type LocalClient struct {
 	Name string // The name of the local cluster.
@@ -38,10 +48,28 @@ func (c LocalClient) CheckConfig() error {
 	return nil
 }
 
+// LocalProviderCheckResult is the access check result for the local provider, which has no account to check.
+type LocalProviderCheckResult struct {
+	Name string // The name of the local cluster.
+}
+
 // CheckAccess performs an access check for the local provider.
-// Always returns an EmptyProviderCheckResult as no access check is required.
+// Always passes as there is no cloud account to access.
 func (c LocalClient) CheckAccess(ctx context.Context) ProviderCheckResult {
-	return EmptyProviderCheckResult{}
+	return LocalProviderCheckResult{Name: c.Name}
+}
+
+// ToTable converts the LocalProviderCheckResult into table headers and a single passing row.
+func (r LocalProviderCheckResult) ToTable() ([]string, []ProviderCheckResultRow) {
+	headers := []string{"Cluster", "Access"}
+	rows := []ProviderCheckResultRow{
+		{
+			Status: true,
+			Data:   []string{r.Name, "not applicable, no cloud account"},
+		},
+	}
+
+	return headers, rows
 }
 
 // CurrentIdentity returns the identity of the local provider.
@@ -80,7 +108,12 @@ func (c LocalClient) DestroyStateBackend(_ context.Context) error {
 
 // KubeconfigInfo returns an error as kubeconfig information is not supported for the local provider.
 func (c LocalClient) KubeconfigInfo(ctx context.Context) (KubeconfigInfo, error) {
-	return KubeconfigInfo{}, fmt.Errorf("not supported at this time")
+	return KubeconfigInfo{}, fmt.Errorf("not supported at this time, %w", ErrNoCloudCluster)
+}
+
+// KubeconfigUserInfo returns nil as the local provider uses the static token from KubeconfigInfo.
+func (c LocalClient) KubeconfigUserInfo(_ schema.QuartzConfig, _ KubeconfigInfo) *schema.KubeconfigUserInfo {
+	return nil
 }
 
 // PrintConfig prints the configuration of the local provider.
@@ -98,6 +131,50 @@ func (c LocalClient) PrintClusterInfo(ctx context.Context) error {
 	return nil
 }
 
+// AcquireRunLock creates an exclusive lock file for the local cluster.
+// Returns a RunLockError if the lock file already exists.
+func (c LocalClient) AcquireRunLock(_ context.Context, operation string) error {
+	path := c.runLockPath()
+	info := newRunLockInfo(operation)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		var held runLockInfo
+		if b, rerr := os.ReadFile(path); rerr == nil {
+			_ = json.Unmarshal(b, &held)
+		}
+		return held.lockError(path)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(info)
+}
+
+// ReleaseRunLock removes the lock file for the local cluster.
+func (c LocalClient) ReleaseRunLock(_ context.Context) error {
+	err := os.Remove(c.runLockPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// runLockPath returns the path of the lock file for the local cluster.
+func (c LocalClient) runLockPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("quartz-%s.lock", c.Name))
+}
+
+// ReconcileAdministrators performs no operation for the local provider.
+// Always returns nil as there are no cloud principals to grant cluster access to.
+func (c LocalClient) ReconcileAdministrators(ctx context.Context, administrators []string) ([]string, error) {
+	return nil, nil
+}
+
 // PrepareAccount performs no operation for the local provider.
 // Always returns nil as no account preparation is required.
 func (c LocalClient) PrepareAccount(ctx context.Context) error {
@@ -16,6 +16,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -35,6 +36,7 @@ func TestProviderLocalClient(t *testing.T) {
 	c.PrintConfig()
 	c.PrintClusterInfo(context.Background())
 	c.PrepareAccount(context.Background())
+	c.ReconcileAdministrators(context.Background(), []string{"arn:aws:iam::123456789:role/admin"})
 
 	if name != "Local" ||
 		cfgRes != nil ||
@@ -43,3 +45,37 @@ func TestProviderLocalClient(t *testing.T) {
 		t.Errorf("unexpected result from local client provider")
 	}
 }
+
+func TestProviderLocalClientCheckAccess(t *testing.T) {
+	c := LocalClient{Name: "test"}
+
+	err := checkResultError(c.ProviderName(), c.CheckAccess(context.Background()))
+	if err != nil {
+		t.Errorf("expected the local provider access check to pass, found %v", err)
+	}
+}
+
+func TestProviderLocalClientRunLock(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	c := LocalClient{Name: "test"}
+
+	if err := c.AcquireRunLock(context.Background(), "install"); err != nil {
+		t.Fatalf("unexpected error acquiring local run lock, %v", err)
+	}
+
+	// simulate a second process attempting to run against the same cluster
+	var lockErr RunLockError
+	err := c.AcquireRunLock(context.Background(), "clean")
+	if !errors.As(err, &lockErr) || lockErr.Operation != "install" {
+		t.Errorf("expected run lock error held by install, got %v", err)
+	}
+
+	if err := c.ReleaseRunLock(context.Background()); err != nil {
+		t.Errorf("unexpected error releasing local run lock, %v", err)
+	}
+
+	if err := c.AcquireRunLock(context.Background(), "clean"); err != nil {
+		t.Errorf("unexpected error reacquiring local run lock, %v", err)
+	}
+	c.ReleaseRunLock(context.Background())
+}
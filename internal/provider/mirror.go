@@ -0,0 +1,323 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+)
+
+// ImageReference represents a parsed registry artifact reference, registry/repository:tag or @digest.
+type ImageReference struct {
+	Registry   string // The registry host, e.g. registry1.dso.mil.
+	Repository string // The repository path within the registry.
+	Tag        string // The tag, empty when referenced by digest.
+	Digest     string // The digest, empty when referenced by tag.
+}
+
+// ParseImageReference parses a fully qualified artifact reference, the registry host is required.
+func ParseImageReference(s string) (ImageReference, error) {
+	host, rest, ok := strings.Cut(s, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return ImageReference{}, fmt.Errorf("invalid reference %s, registry host required", s)
+	}
+
+	ref := ImageReference{Registry: host}
+
+	if repo, digest, ok := strings.Cut(rest, "@"); ok {
+		ref.Repository, ref.Digest = repo, digest
+	} else if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Repository, ref.Tag = rest[:i], rest[i+1:]
+	} else {
+		ref.Repository, ref.Tag = rest, "latest"
+	}
+
+	if ref.Repository == "" || (ref.Tag == "" && ref.Digest == "") {
+		return ImageReference{}, fmt.Errorf("invalid reference %s", s)
+	}
+
+	return ref, nil
+}
+
+// Version returns the digest if set, otherwise the tag.
+func (r ImageReference) Version() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+
+	return r.Tag
+}
+
+// WithDigest returns a copy of the reference addressing the digest instead of the tag.
+func (r ImageReference) WithDigest(digest string) ImageReference {
+	r.Tag, r.Digest = "", digest
+	return r
+}
+
+func (r ImageReference) String() string {
+	if r.Digest != "" {
+		return r.Registry + "/" + r.Repository + "@" + r.Digest
+	}
+
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// ociDescriptor is the subset of an OCI content descriptor needed to copy the content.
+type ociDescriptor struct {
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// ociManifest is the subset of an image manifest or index needed to copy the referenced content.
+type ociManifest struct {
+	Config    *ociDescriptor  `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// MirrorArtifactResult represents the result of mirroring a single image or chart.
+type MirrorArtifactResult struct {
+	Kind   string // The artifact kind, image or chart.
+	Source string // The source reference.
+	Target string // The target reference, empty if the source is invalid.
+	Blobs  int    // The number of blobs copied, blobs already in the target are skipped.
+	Error  error  // Any error encountered mirroring the artifact.
+}
+
+// MirrorResult represents the result of mirroring every configured artifact.
+type MirrorResult struct {
+	Artifacts []MirrorArtifactResult
+}
+
+// MirrorClient copies the images and charts configured in mirror.image_repository to the target registry.
+type MirrorClient struct {
+	transport RegistryTransport
+	cfg       schema.MirrorImageRepositoryConfig
+	pullCreds RegistryCredentials // used for the dso.mil source registries
+	pushCreds RegistryCredentials // used for the target registry
+}
+
+// NewMirrorClient creates a MirrorClient pulling with the ironbank credentials and pushing with
+// the mirror credentials, see targetCredentials.
+func NewMirrorClient(transport RegistryTransport, cfg schema.MirrorConfig, secrets schema.QuartzSecrets) MirrorClient {
+	return MirrorClient{
+		transport: transport,
+		cfg:       cfg.ImageRepository,
+		pullCreds: RegistryCredentials{Username: secrets.Ironbank.Username, Password: secrets.Ironbank.Password},
+		pushCreds: targetCredentials(cfg.ImageRepository.Target, secrets),
+	}
+}
+
+// targetCredentials returns the push credentials for the target registry, the mirror credentials
+// when set, the github credentials for ghcr.io and anonymous access otherwise.
+func targetCredentials(target string, secrets schema.QuartzSecrets) RegistryCredentials {
+	if secrets.Mirror.Username != "" || secrets.Mirror.Password != "" {
+		return RegistryCredentials{Username: secrets.Mirror.Username, Password: secrets.Mirror.Password}
+	}
+
+	if host, _, _ := strings.Cut(target, "/"); host == "ghcr.io" {
+		return RegistryCredentials{Username: secrets.Github.Username, Password: secrets.Github.Token}
+	}
+
+	return RegistryCredentials{}
+}
+
+// Mirror copies every configured image and chart, continuing past failures.
+func (c MirrorClient) Mirror(ctx context.Context) MirrorResult {
+	var res MirrorResult
+
+	for _, a := range c.cfg.Images {
+		res.Artifacts = append(res.Artifacts, c.MirrorArtifact(ctx, "image", a))
+	}
+
+	for _, a := range c.cfg.Charts {
+		res.Artifacts = append(res.Artifacts, c.MirrorArtifact(ctx, "chart", a))
+	}
+
+	return res
+}
+
+// MirrorArtifact copies a single artifact from its source registry to the target, keeping its
+// repository path under the target, e.g. registry1.dso.mil/ironbank/nginx:1.0 to <target>/ironbank/nginx:1.0.
+func (c MirrorClient) MirrorArtifact(ctx context.Context, kind string, source string) MirrorArtifactResult {
+	res := MirrorArtifactResult{Kind: kind, Source: source}
+
+	src, dst, err := c.references(source)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	res.Target = dst.String()
+
+	log.Info("Mirroring artifact", "kind", kind, "source", src, "target", dst)
+	res.Blobs, res.Error = c.copyManifest(ctx, src, dst)
+	return res
+}
+
+// references resolves the source reference and its target, the source registry must be
+// one of mirror.image_repository.source_registries.
+func (c MirrorClient) references(source string) (ImageReference, ImageReference, error) {
+	src, err := ParseImageReference(source)
+	if err != nil {
+		return src, ImageReference{}, err
+	}
+
+	if !slices.Contains(c.cfg.SourceRegistries, src.Registry) {
+		return src, ImageReference{}, fmt.Errorf("registry %s not in mirror.image_repository.source_registries", src.Registry)
+	}
+
+	host, prefix, _ := strings.Cut(strings.TrimSuffix(c.cfg.Target, "/"), "/")
+	if host == "" {
+		return src, ImageReference{}, fmt.Errorf("mirror.image_repository.target required")
+	}
+
+	dst := src
+	dst.Registry = host
+	if prefix != "" {
+		dst.Repository = prefix + "/" + src.Repository
+	}
+
+	return src, dst, nil
+}
+
+// sourceCredentials returns the pull credentials for a source registry, ironbank credentials
+// for the dso.mil registries and anonymous access otherwise.
+func (c MirrorClient) sourceCredentials(registry string) RegistryCredentials {
+	if strings.HasSuffix(registry, "dso.mil") {
+		return c.pullCreds
+	}
+
+	return RegistryCredentials{}
+}
+
+// copyManifest copies the manifest along with the blobs and child manifests it references,
+// returning the number of blobs copied.
+func (c MirrorClient) copyManifest(ctx context.Context, src ImageReference, dst ImageReference) (int, error) {
+	m, err := c.transport.GetManifest(ctx, src, c.sourceCredentials(src.Registry))
+	if err != nil {
+		return 0, fmt.Errorf("pull %s, %w", src, err)
+	}
+
+	var parsed ociManifest
+	if err := json.Unmarshal(m.Body, &parsed); err != nil {
+		return 0, fmt.Errorf("invalid manifest for %s, %w", src, err)
+	}
+
+	copied := 0
+
+	// image indexes reference a manifest per platform, which are pushed by digest first
+	for _, d := range parsed.Manifests {
+		n, err := c.copyManifest(ctx, src.WithDigest(d.Digest), dst.WithDigest(d.Digest))
+		copied += n
+		if err != nil {
+			return copied, err
+		}
+	}
+
+	blobs := parsed.Layers
+	if parsed.Config != nil {
+		blobs = append([]ociDescriptor{*parsed.Config}, blobs...)
+	}
+
+	var errs []error
+	for _, b := range blobs {
+		ok, err := c.copyBlob(ctx, src, dst, b)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			copied++
+		}
+	}
+
+	if len(errs) > 0 {
+		return copied, errors.Join(errs...)
+	}
+
+	if err := c.transport.PutManifest(ctx, dst, m, c.pushCreds); err != nil {
+		return copied, fmt.Errorf("push %s, %w", dst, err)
+	}
+
+	return copied, nil
+}
+
+// copyBlob streams a blob to the target unless it's already there, returning true if it was copied.
+func (c MirrorClient) copyBlob(ctx context.Context, src ImageReference, dst ImageReference, d ociDescriptor) (bool, error) {
+	exists, err := c.transport.BlobExists(ctx, dst, d.Digest, c.pushCreds)
+	if err != nil {
+		return false, fmt.Errorf("push %s, %w", dst, err)
+	}
+
+	if exists {
+		log.Debug("Blob already mirrored", "target", dst, "digest", d.Digest)
+		return false, nil
+	}
+
+	r, size, err := c.transport.GetBlob(ctx, src, d.Digest, c.sourceCredentials(src.Registry))
+	if err != nil {
+		return false, fmt.Errorf("pull %s blob %s, %w", src, d.Digest, err)
+	}
+	defer r.Close()
+
+	if size < 0 {
+		size = d.Size
+	}
+
+	if err := c.transport.PutBlob(ctx, dst, d.Digest, size, r, c.pushCreds); err != nil {
+		return false, fmt.Errorf("push %s blob %s, %w", dst, d.Digest, err)
+	}
+
+	return true, nil
+}
+
+// Error returns an error listing the artifacts that failed to mirror, nil if all succeeded.
+func (r MirrorResult) Error() error {
+	var failed []string
+	for _, a := range r.Artifacts {
+		if a.Error != nil {
+			failed = append(failed, a.Source)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to mirror %d artifact(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// ToTable converts the MirrorResult into table headers and rows for display.
+func (r MirrorResult) ToTable() ([]string, []ProviderCheckResultRow) {
+	headers := []string{"Kind", "Source", "Target", "Blobs"}
+	var rows []ProviderCheckResultRow
+
+	for _, a := range r.Artifacts {
+		rows = append(rows, ProviderCheckResultRow{
+			Status: a.Error == nil,
+			Error:  a.Error,
+			Data:   []string{a.Kind, a.Source, a.Target, fmt.Sprint(a.Blobs)},
+		})
+	}
+
+	return headers, rows
+}
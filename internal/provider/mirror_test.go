@@ -0,0 +1,323 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
+
+// registryTransportMock is an in-memory registry transport keyed by registry/repository,
+// failing any request with credentials other than the expected ones for the registry.
+type registryTransportMock struct {
+	mu        sync.Mutex
+	manifests map[string]RegistryManifest // by reference string
+	blobs     map[string][]byte           // by registry/repository@digest
+	creds     map[string]RegistryCredentials
+}
+
+func newRegistryTransportMock() *registryTransportMock {
+	return &registryTransportMock{
+		manifests: map[string]RegistryManifest{},
+		blobs:     map[string][]byte{},
+		creds:     map[string]RegistryCredentials{},
+	}
+}
+
+func (m *registryTransportMock) auth(ref ImageReference, creds RegistryCredentials) error {
+	if expected, ok := m.creds[ref.Registry]; ok && expected != creds {
+		return fmt.Errorf("authentication failed for %s, status 401", ref.Registry)
+	}
+	return nil
+}
+
+func (m *registryTransportMock) blobKey(ref ImageReference, digest string) string {
+	return ref.Registry + "/" + ref.Repository + "@" + digest
+}
+
+func (m *registryTransportMock) GetManifest(ctx context.Context, ref ImageReference, creds RegistryCredentials) (RegistryManifest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.auth(ref, creds); err != nil {
+		return RegistryManifest{}, err
+	}
+	r, ok := m.manifests[ref.String()]
+	if !ok {
+		return RegistryManifest{}, fmt.Errorf("manifest %s not found", ref)
+	}
+	return r, nil
+}
+
+func (m *registryTransportMock) PutManifest(ctx context.Context, ref ImageReference, r RegistryManifest, creds RegistryCredentials) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.auth(ref, creds); err != nil {
+		return err
+	}
+	m.manifests[ref.String()] = r
+	return nil
+}
+
+func (m *registryTransportMock) BlobExists(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.auth(ref, creds); err != nil {
+		return false, err
+	}
+	_, ok := m.blobs[m.blobKey(ref, digest)]
+	return ok, nil
+}
+
+func (m *registryTransportMock) GetBlob(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (io.ReadCloser, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.auth(ref, creds); err != nil {
+		return nil, 0, err
+	}
+	b, ok := m.blobs[m.blobKey(ref, digest)]
+	if !ok {
+		return nil, 0, fmt.Errorf("blob %s not found", digest)
+	}
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), nil
+}
+
+func (m *registryTransportMock) PutBlob(ctx context.Context, ref ImageReference, digest string, size int64, r io.Reader, creds RegistryCredentials) error {
+	if err := m.auth(ref, creds); err != nil {
+		return err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[m.blobKey(ref, digest)] = b
+	return nil
+}
+
+// addImage adds a single platform image with a config and one layer to the mock registry.
+func (m *registryTransportMock) addImage(t *testing.T, ref string) {
+	r, err := ParseImageReference(ref)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %s, %v", ref, err)
+	}
+
+	m.blobs[m.blobKey(r, "sha256:config")] = []byte("{}")
+	m.blobs[m.blobKey(r, "sha256:layer")] = []byte("layer")
+	m.manifests[r.String()] = RegistryManifest{
+		MediaType: "application/vnd.oci.image.manifest.v1+json",
+		Body:      []byte(`{"config":{"digest":"sha256:config","size":2},"layers":[{"digest":"sha256:layer","size":5}]}`),
+	}
+}
+
+func newTestMirrorClient(transport RegistryTransport, images []string, charts []string) MirrorClient {
+	cfg := schema.NewMirrorConfig()
+	cfg.ImageRepository.Images = images
+	cfg.ImageRepository.Charts = charts
+
+	return NewMirrorClient(transport, cfg, schema.QuartzSecrets{
+		Ironbank: schema.IronbankCredentials{Username: "ibuser", Password: "ibpass"},
+		Github:   schema.GithubCredentials{Username: "ghuser", Token: "ghtoken"},
+	})
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := map[string]ImageReference{
+		"registry1.dso.mil/ironbank/nginx:1.27":   {Registry: "registry1.dso.mil", Repository: "ironbank/nginx", Tag: "1.27"},
+		"quay.io/org/app":                         {Registry: "quay.io", Repository: "org/app", Tag: "latest"},
+		"localhost:5000/app@sha256:abc":           {Registry: "localhost:5000", Repository: "app", Digest: "sha256:abc"},
+		"registry1.dso.mil:443/ironbank/nginx:v1": {Registry: "registry1.dso.mil:443", Repository: "ironbank/nginx", Tag: "v1"},
+	}
+
+	for s, expected := range tests {
+		actual, err := ParseImageReference(s)
+		if err != nil {
+			t.Errorf("unexpected error parsing %s, %v", s, err)
+		}
+
+		if actual != expected {
+			t.Errorf("incorrect reference parsed from %s, expected %+v, found %+v", s, expected, actual)
+		}
+	}
+
+	for _, s := range []string{"nginx:1.27", "library/nginx", "quay.io/"} {
+		if _, err := ParseImageReference(s); err == nil {
+			t.Errorf("expected error parsing %s", s)
+		}
+	}
+}
+
+func TestMirrorClientMirror(t *testing.T) {
+	m := newRegistryTransportMock()
+	m.creds["registry1.dso.mil"] = RegistryCredentials{Username: "ibuser", Password: "ibpass"}
+	m.creds["ghcr.io"] = RegistryCredentials{Username: "ghuser", Password: "ghtoken"}
+	m.addImage(t, "registry1.dso.mil/ironbank/nginx:1.27")
+	m.addImage(t, "quay.io/org/chart:0.1.0")
+
+	c := newTestMirrorClient(m, []string{"registry1.dso.mil/ironbank/nginx:1.27"}, []string{"quay.io/org/chart:0.1.0"})
+
+	res := c.Mirror(context.Background())
+	if err := res.Error(); err != nil {
+		t.Fatalf("unexpected error mirroring, %v", err)
+	}
+
+	if len(res.Artifacts) != 2 {
+		t.Fatalf("expected 2 artifacts mirrored, found %v", res.Artifacts)
+	}
+
+	expected := []string{
+		"ghcr.io/metrostar/quartz-pkgs/ironbank/nginx:1.27",
+		"ghcr.io/metrostar/quartz-pkgs/org/chart:0.1.0",
+	}
+	for i, a := range res.Artifacts {
+		if a.Target != expected[i] || a.Blobs != 2 {
+			t.Errorf("unexpected mirror result, %+v", a)
+		}
+
+		if _, ok := m.manifests[expected[i]]; !ok {
+			t.Errorf("expected manifest pushed to %s", expected[i])
+		}
+	}
+
+	if string(m.blobs["ghcr.io/metrostar/quartz-pkgs/ironbank/nginx@sha256:layer"]) != "layer" {
+		t.Errorf("expected layer pushed to the target")
+	}
+
+	// blobs already in the target are skipped
+	res = c.Mirror(context.Background())
+	for _, a := range res.Artifacts {
+		if a.Error != nil || a.Blobs != 0 {
+			t.Errorf("expected no blobs copied on a second mirror, %+v", a)
+		}
+	}
+}
+
+func TestMirrorClientMirrorIndex(t *testing.T) {
+	m := newRegistryTransportMock()
+	m.addImage(t, "quay.io/org/app@sha256:amd64")
+	m.manifests["quay.io/org/app:1.0"] = RegistryManifest{
+		MediaType: "application/vnd.oci.image.index.v1+json",
+		Body:      []byte(`{"manifests":[{"digest":"sha256:amd64","size":100}]}`),
+	}
+
+	c := newTestMirrorClient(m, []string{"quay.io/org/app:1.0"}, nil)
+
+	res := c.Mirror(context.Background())
+	if err := res.Error(); err != nil {
+		t.Fatalf("unexpected error mirroring index, %v", err)
+	}
+
+	for _, ref := range []string{"ghcr.io/metrostar/quartz-pkgs/org/app:1.0", "ghcr.io/metrostar/quartz-pkgs/org/app@sha256:amd64"} {
+		if _, ok := m.manifests[ref]; !ok {
+			t.Errorf("expected manifest pushed to %s", ref)
+		}
+	}
+}
+
+func TestMirrorClientMirrorAuthFailure(t *testing.T) {
+	m := newRegistryTransportMock()
+	m.addImage(t, "registry1.dso.mil/ironbank/nginx:1.27")
+	m.addImage(t, "quay.io/org/app:1.0")
+	m.creds["registry1.dso.mil"] = RegistryCredentials{Username: "ibuser", Password: "other"}
+	m.creds["ghcr.io"] = RegistryCredentials{Username: "ghuser", Password: "ghtoken"}
+
+	c := newTestMirrorClient(m, []string{"registry1.dso.mil/ironbank/nginx:1.27", "quay.io/org/app:1.0", "docker.io/library/nginx:1"}, nil)
+
+	res := c.Mirror(context.Background())
+	err := res.Error()
+	if err == nil || !strings.Contains(err.Error(), "failed to mirror 2 artifact(s): registry1.dso.mil/ironbank/nginx:1.27, docker.io/library/nginx:1") {
+		t.Errorf("unexpected mirror error, %v", err)
+	}
+
+	if a := res.Artifacts[0]; a.Error == nil || !strings.Contains(a.Error.Error(), "authentication failed for registry1.dso.mil") {
+		t.Errorf("expected pull authentication failure, %+v", a)
+	}
+
+	if a := res.Artifacts[1]; a.Error != nil {
+		t.Errorf("expected anonymous pull from quay.io to succeed, %+v", a)
+	}
+
+	if a := res.Artifacts[2]; a.Error == nil || !strings.Contains(a.Error.Error(), "not in mirror.image_repository.source_registries") {
+		t.Errorf("expected unknown source registry failure, %+v", a)
+	}
+
+	_, rows := res.ToTable()
+	if len(rows) != 3 || rows[0].Status || !rows[1].Status || rows[2].Status {
+		t.Errorf("unexpected mirror table rows, %+v", rows)
+	}
+}
+
+func TestMirrorClientMirrorPushAuthFailure(t *testing.T) {
+	m := newRegistryTransportMock()
+	m.addImage(t, "quay.io/org/app:1.0")
+	m.creds["ghcr.io"] = RegistryCredentials{Username: "ghuser", Password: "other"}
+
+	c := newTestMirrorClient(m, []string{"quay.io/org/app:1.0"}, nil)
+
+	res := c.Mirror(context.Background())
+	if a := res.Artifacts[0]; a.Error == nil || !strings.Contains(a.Error.Error(), "push ghcr.io/metrostar/quartz-pkgs/org/app:1.0") {
+		t.Errorf("expected push authentication failure, %+v", a)
+	}
+}
+
+func TestMirrorClientTargetCredentials(t *testing.T) {
+	github := schema.GithubCredentials{Username: "ghuser", Token: "ghtoken"}
+	mirror := schema.MirrorCredentials{Username: "muser", Password: "mpass"}
+
+	tests := []struct {
+		target   string
+		secrets  schema.QuartzSecrets
+		expected RegistryCredentials
+	}{
+		{"ghcr.io/metrostar/quartz-pkgs", schema.QuartzSecrets{Github: github}, RegistryCredentials{Username: "ghuser", Password: "ghtoken"}},
+		{"ghcr.io/metrostar/quartz-pkgs", schema.QuartzSecrets{Github: github, Mirror: mirror}, RegistryCredentials{Username: "muser", Password: "mpass"}},
+		{"harbor.example.com/quartz", schema.QuartzSecrets{Github: github, Mirror: mirror}, RegistryCredentials{Username: "muser", Password: "mpass"}},
+		{"harbor.example.com/quartz", schema.QuartzSecrets{Github: github}, RegistryCredentials{}},
+	}
+
+	for _, tt := range tests {
+		actual := targetCredentials(tt.target, tt.secrets)
+		if actual != tt.expected {
+			t.Errorf("incorrect credentials for %s, expected %+v, found %+v", tt.target, tt.expected, actual)
+		}
+	}
+}
+
+func TestMirrorClientMirrorTargetCredentials(t *testing.T) {
+	m := newRegistryTransportMock()
+	m.addImage(t, "quay.io/org/app:1.0")
+	m.creds["harbor.example.com"] = RegistryCredentials{Username: "muser", Password: "mpass"}
+
+	cfg := schema.NewMirrorConfig()
+	cfg.ImageRepository.Target = "harbor.example.com/quartz"
+	cfg.ImageRepository.Images = []string{"quay.io/org/app:1.0"}
+
+	c := NewMirrorClient(m, cfg, schema.QuartzSecrets{
+		Github: schema.GithubCredentials{Username: "ghuser", Token: "ghtoken"},
+		Mirror: schema.MirrorCredentials{Username: "muser", Password: "mpass"},
+	})
+
+	res := c.Mirror(context.Background())
+	if a := res.Artifacts[0]; a.Error != nil || a.Target != "harbor.example.com/quartz/org/app:1.0" {
+		t.Errorf("expected artifact mirrored with the mirror credentials, %+v", a)
+	}
+}
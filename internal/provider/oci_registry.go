@@ -0,0 +1,472 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// ociManifestAccept lists the manifest media types accepted when pulling, image indexes included.
+var ociManifestAccept = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ociTokenDefaultExpiry is the bearer token lifetime assumed when the token response omits
+// expires_in, per the distribution token spec.
+const ociTokenDefaultExpiry = 60 * time.Second
+
+// ociTokenExpiryMargin is subtracted from a token's lifetime so it isn't used right as it expires.
+const ociTokenExpiryMargin = 10 * time.Second
+
+// RegistryCredentials represents the credentials for a container registry, empty for anonymous access.
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// RegistryManifest represents a manifest pulled from or pushed to a registry.
+type RegistryManifest struct {
+	MediaType string // The manifest media type.
+	Body      []byte // The raw manifest, pushed unchanged so the digest is preserved.
+}
+
+// RegistryTransport defines the registry operations needed to copy an artifact between registries.
+type RegistryTransport interface {
+	GetManifest(ctx context.Context, ref ImageReference, creds RegistryCredentials) (RegistryManifest, error)
+	PutManifest(ctx context.Context, ref ImageReference, m RegistryManifest, creds RegistryCredentials) error
+	BlobExists(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (bool, error)
+	GetBlob(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (io.ReadCloser, int64, error)
+	PutBlob(ctx context.Context, ref ImageReference, digest string, size int64, r io.Reader, creds RegistryCredentials) error
+}
+
+// RegistryAuthenticator defines the registry auth handshake used to verify credentials.
+type RegistryAuthenticator interface {
+	// Authenticate verifies the credentials against the registry host, returning the status code
+	// of the final authenticated request.
+	Authenticate(ctx context.Context, registry string, creds RegistryCredentials) (int, error)
+}
+
+// OciRegistryClient implements RegistryTransport against the OCI distribution api.
+type OciRegistryClient struct {
+	httpClient util.HttpClientFactory // The HTTP client factory for making requests.
+	scheme     string                 // The url scheme, https outside of tests.
+
+	mu   *sync.Mutex
+	auth map[string]ociAuthorization // authorization headers by host, repository and access
+}
+
+// ociAuthorization represents an authorization header and when it stops being valid.
+type ociAuthorization struct {
+	Header  string
+	Expires time.Time // zero for headers that don't expire, such as basic auth
+}
+
+// expired checks whether the authorization has to be renewed before its next use.
+func (a ociAuthorization) expired() bool {
+	return !a.Expires.IsZero() && !time.Now().Before(a.Expires)
+}
+
+// NewOciRegistryClient creates a new OciRegistryClient.
+func NewOciRegistryClient(httpClient util.HttpClientFactory) OciRegistryClient {
+	return OciRegistryClient{
+		httpClient: httpClient,
+		scheme:     "https",
+		mu:         &sync.Mutex{},
+		auth:       map[string]ociAuthorization{},
+	}
+}
+
+// GetManifest pulls the manifest for the reference's tag or digest.
+func (c OciRegistryClient) GetManifest(ctx context.Context, ref ImageReference, creds RegistryCredentials) (RegistryManifest, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.url(ref, "manifests", ref.Version()), nil, -1, ref, creds, false, func(req *http.Request) {
+		req.Header.Set("Accept", strings.Join(ociManifestAccept, ", "))
+	})
+	if err != nil {
+		return RegistryManifest{}, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return RegistryManifest{}, err
+	}
+
+	return RegistryManifest{MediaType: resp.Header.Get("Content-Type"), Body: b}, nil
+}
+
+// PutManifest pushes the manifest to the reference's tag or digest.
+func (c OciRegistryClient) PutManifest(ctx context.Context, ref ImageReference, m RegistryManifest, creds RegistryCredentials) error {
+	resp, err := c.do(ctx, http.MethodPut, c.url(ref, "manifests", ref.Version()), bytes.NewReader(m.Body), int64(len(m.Body)), ref, creds, true, func(req *http.Request) {
+		req.Header.Set("Content-Type", m.MediaType)
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// BlobExists checks whether the repository already has the blob.
+func (c OciRegistryClient) BlobExists(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.url(ref, "blobs", digest), nil, -1, ref, creds, true, nil)
+	if err != nil {
+		var regErr ociRegistryError
+		if errors.As(err, &regErr) && regErr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, resp.Body.Close()
+}
+
+// GetBlob opens the blob for reading, the caller closes the returned reader.
+func (c OciRegistryClient) GetBlob(ctx context.Context, ref ImageReference, digest string, creds RegistryCredentials) (io.ReadCloser, int64, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.url(ref, "blobs", digest), nil, -1, ref, creds, false, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// PutBlob uploads the blob in a single request, streaming it from the reader.
+func (c OciRegistryClient) PutBlob(ctx context.Context, ref ImageReference, digest string, size int64, r io.Reader, creds RegistryCredentials) error {
+	resp, err := c.do(ctx, http.MethodPost, c.url(ref, "blobs", "uploads/"), nil, 0, ref, creds, true, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	loc, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("blob upload for %s returned no location, %w", ref, err)
+	}
+
+	q := loc.Query()
+	q.Set("digest", digest)
+	loc.RawQuery = q.Encode()
+
+	resp, err = c.do(ctx, http.MethodPut, loc.String(), r, size, ref, creds, true, func(req *http.Request) {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// url builds the distribution api url for the reference's repository.
+func (c OciRegistryClient) url(ref ImageReference, kind string, id string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s/%s", c.scheme, ref.Registry, ref.Repository, kind, id)
+}
+
+// do sends an authorized request, returning an ociRegistryError for an unsuccessful status.
+// A 401 drops the cached authorization and retries once with a fresh one, as long as the
+// body can be replayed.
+func (c OciRegistryClient) do(ctx context.Context, method string, u string, body io.Reader, size int64, ref ImageReference, creds RegistryCredentials, push bool, prepare func(*http.Request)) (*http.Response, error) {
+	resp, err := c.send(ctx, method, u, body, size, ref, creds, push, prepare)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		seeker, replayable := body.(io.Seeker)
+		if body == nil || replayable {
+			resp.Body.Close()
+			c.invalidate(ref, push)
+
+			if replayable {
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+
+			log.Debug("Registry authorization rejected, re-authenticating", "registry", ref.Registry, "repository", ref.Repository)
+			resp, err = c.send(ctx, method, u, body, size, ref, creds, push, prepare)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, ociRegistryError{StatusCode: resp.StatusCode, Method: method, Url: u, Message: strings.TrimSpace(string(b))}
+	}
+
+	return resp, nil
+}
+
+// send sends a single authorized request, returning the response regardless of status.
+func (c OciRegistryClient) send(ctx context.Context, method string, u string, body io.Reader, size int64, ref ImageReference, creds RegistryCredentials, push bool, prepare func(*http.Request)) (*http.Response, error) {
+	auth, err := c.authorize(ctx, ref, creds, push)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	if prepare != nil {
+		prepare(req)
+	}
+
+	return c.httpClient.NewClient().Do(req)
+}
+
+// authScope returns the cache key and token scope for the repository access.
+func authScope(ref ImageReference, push bool) (string, string) {
+	scope := "repository:" + ref.Repository + ":pull"
+	if push {
+		scope += ",push"
+	}
+
+	return ref.Registry + "/" + scope, scope
+}
+
+// authorize returns the authorization header for the repository, following the registry's
+// bearer or basic auth challenge. Headers are cached until the bearer token expires.
+func (c OciRegistryClient) authorize(ctx context.Context, ref ImageReference, creds RegistryCredentials, push bool) (string, error) {
+	key, scope := authScope(ref, push)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if a, ok := c.auth[key]; ok && !a.expired() {
+		return a.Header, nil
+	}
+
+	auth, _, err := c.handshake(ctx, ref.Registry, scope, creds)
+	if err != nil {
+		return "", err
+	}
+
+	c.auth[key] = auth
+	return auth.Header, nil
+}
+
+// invalidate drops the cached authorization for the repository access.
+func (c OciRegistryClient) invalidate(ref ImageReference, push bool) {
+	key, _ := authScope(ref, push)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.auth, key)
+}
+
+// Authenticate pings the registry's /v2/ endpoint and completes its bearer or basic auth
+// challenge with the credentials, then verifies the resulting authorization is accepted.
+func (c OciRegistryClient) Authenticate(ctx context.Context, registry string, creds RegistryCredentials) (int, error) {
+	auth, status, err := c.handshake(ctx, registry, "", creds)
+	if err != nil || auth.Header == "" {
+		// failed, or the registry allows anonymous access so there's nothing more to verify
+		return status, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/v2/", c.scheme, registry), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", auth.Header)
+
+	resp, err := c.httpClient.NewClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("authentication failed for %s, status %d", registry, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// handshake pings the registry and follows its auth challenge, returning the authorization
+// for the scope (empty for anonymous access) and the status code of the last response.
+func (c OciRegistryClient) handshake(ctx context.Context, registry string, scope string, creds RegistryCredentials) (ociAuthorization, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/v2/", c.scheme, registry), nil)
+	if err != nil {
+		return ociAuthorization{}, 0, err
+	}
+
+	resp, err := c.httpClient.NewClient().Do(req)
+	if err != nil {
+		return ociAuthorization{}, 0, err
+	}
+	resp.Body.Close()
+
+	switch challenge := resp.Header.Get("WWW-Authenticate"); {
+	case resp.StatusCode != http.StatusUnauthorized:
+		// anonymous access
+		return ociAuthorization{}, resp.StatusCode, nil
+	case creds.Username == "" && strings.HasPrefix(strings.ToLower(challenge), "basic"):
+		return ociAuthorization{}, resp.StatusCode, fmt.Errorf("authentication required for %s, no credentials configured", registry)
+	case strings.HasPrefix(strings.ToLower(challenge), "basic"):
+		req.SetBasicAuth(creds.Username, creds.Password)
+		return ociAuthorization{Header: req.Header.Get("Authorization")}, resp.StatusCode, nil
+	case strings.HasPrefix(strings.ToLower(challenge), "bearer"):
+		return c.token(ctx, registry, challenge, scope, creds)
+	default:
+		return ociAuthorization{}, resp.StatusCode, fmt.Errorf("unsupported authentication challenge from %s, %q", registry, challenge)
+	}
+}
+
+// token requests a bearer token from the challenge's realm for the scope, if any. The token
+// expires after the response's expires_in, or the spec default when it's omitted.
+func (c OciRegistryClient) token(ctx context.Context, registry string, challenge string, scope string, creds RegistryCredentials) (ociAuthorization, int, error) {
+	params := parseAuthChallenge(challenge)
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return ociAuthorization{}, 0, fmt.Errorf("invalid authentication realm from %s, %q", registry, challenge)
+	}
+
+	q := realm.Query()
+	if s := params["service"]; s != "" {
+		q.Set("service", s)
+	}
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return ociAuthorization{}, 0, err
+	}
+
+	if creds.Username != "" {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient.NewClient().Do(req)
+	if err != nil {
+		return ociAuthorization{}, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ociAuthorization{}, resp.StatusCode, fmt.Errorf("authentication failed for %s, status %d", registry, resp.StatusCode)
+	}
+
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return ociAuthorization{}, resp.StatusCode, fmt.Errorf("invalid token response from %s, %w", registry, err)
+	}
+
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+
+	expiry := ociTokenDefaultExpiry
+	if t.ExpiresIn > 0 {
+		expiry = time.Duration(t.ExpiresIn) * time.Second
+	}
+	expiry = max(expiry-ociTokenExpiryMargin, expiry/2)
+
+	log.Debug("Registry token acquired", "registry", registry, "scope", scope, "expires_in", expiry)
+	return ociAuthorization{Header: "Bearer " + t.Token, Expires: time.Now().Add(expiry)}, resp.StatusCode, nil
+}
+
+// parseAuthChallenge parses the key=value parameters of a WWW-Authenticate header. Values may
+// be quoted strings containing commas and escaped characters, such as a scope listing several
+// actions.
+func parseAuthChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+
+	_, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for rest != "" {
+		rest = strings.TrimLeft(rest, ", \t")
+
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimLeft(rest[eq+1:], " \t")
+
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				value.WriteByte(rest[i])
+			}
+			rest = rest[min(i+1, len(rest)):]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(strings.TrimSpace(rest[:end]))
+			rest = rest[end:]
+		}
+
+		if key != "" {
+			params[key] = value.String()
+		}
+	}
+
+	return params
+}
+
+// ociRegistryError represents an unsuccessful registry api response.
+type ociRegistryError struct {
+	StatusCode int
+	Method     string
+	Url        string
+	Message    string
+}
+
+func (e ociRegistryError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d %s", e.Method, e.Url, e.StatusCode, e.Message)
+}
@@ -26,8 +26,29 @@ import (
 // Otherwise, it initializes a GitHub client.
 func NewImageRegistryProviderClient(ctx context.Context, cfg schema.QuartzConfig, secrets schema.QuartzSecrets) (Provider, error) {
 	if !cfg.Mirror.ImageRepository.Enabled {
-		return NewIronbankClient(util.NewHttpClientFactory(), "Ironbank", secrets.Ironbank.Username, secrets.Ironbank.Password)
+		return newIronbankProviderClient(secrets)
 	}
 
 	return NewGithubClient(util.NewHttpClientFactory(), "Github", cfg, secrets.Github)
 }
+
+// NewIronbankProviderClient creates the Ironbank provider client used to verify the ironbank
+// registry credentials, nil if no credentials are configured.
+func NewIronbankProviderClient(ctx context.Context, secrets schema.QuartzSecrets) (Provider, error) {
+	if secrets.Ironbank.Username == "" && secrets.Ironbank.Password == "" {
+		return nil, nil
+	}
+
+	return newIronbankProviderClient(secrets)
+}
+
+// newIronbankProviderClient creates an Ironbank client for the configured registry and credentials.
+func newIronbankProviderClient(secrets schema.QuartzSecrets) (Provider, error) {
+	ib := secrets.Ironbank
+	c, err := NewIronbankRegistryClient(NewOciRegistryClient(util.NewHttpClientFactory()), "Ironbank", ib.Registry, ib.Username, ib.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
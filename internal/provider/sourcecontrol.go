@@ -0,0 +1,56 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/util"
+)
+
+// NewSourceControlProviderClient creates a new source control provider client based on
+// providers.source_control, defaulting to GitHub.
+func NewSourceControlProviderClient(ctx context.Context, cfg schema.QuartzConfig, secrets schema.QuartzSecrets) (Provider, error) {
+	provider := strings.ToLower(cfg.Providers.SourceControl)
+
+	switch provider {
+	case "gitea":
+		p, err := NewGiteaClient(util.NewHttpClientFactory(), "Gitea", cfg, secrets.Gitea)
+		return p, err
+	}
+
+	p, err := NewGithubClient(util.NewHttpClientFactory(), "Github", cfg, secrets.Github)
+	return p, err
+}
+
+// configuredRepositories retrieves the gitops and application repositories configured in the Quartz configuration.
+func configuredRepositories(cfg schema.QuartzConfig) []schema.RepositoryConfig {
+	repositories := []schema.RepositoryConfig{
+		cfg.Gitops.Core,
+		cfg.Gitops.Apps,
+	}
+
+	for _, app := range cfg.Applications {
+		a := app
+		r := a.RepositoryConfig()
+		if r.RepoUrl != "" {
+			repositories = append(repositories, r)
+		}
+	}
+
+	return repositories
+}
@@ -117,41 +117,27 @@ func RunChecks(ctx context.Context, cfg schema.QuartzConfig, stage string, event
 				opts.OnStart(cr)
 			}
 
-			i := 1
 			ro := sc.RetryOpts()
-			for i <= ro.Limit {
-				cr.Error = sc.Run(ctx, cfg)
-				if cr.Error == nil || i == ro.Limit {
-					break
-				}
-
-				if opts.OnRetry != nil {
-					opts.OnRetry(cr, i)
-				}
-
-				if ro.WaitSeconds > 0 {
-					// Use exponential backoff: start with configured wait, cap at 60s
-					// Formula: min(baseWait * 2^(attempt-1), maxWait)
-					baseWait := ro.WaitSeconds
-					if baseWait < 10 {
-						baseWait = 10 // Minimum 10 seconds
+			ret := util.RetryOpts{
+				MaxAttempts: ro.Limit,
+				OnRetry: func(attempt int, _ time.Duration, err error) {
+					if opts.OnRetry != nil {
+						cr.Error = err
+						opts.OnRetry(cr, attempt)
 					}
-					maxWait := 60 // Maximum 60 seconds cap
-
-					waitTime := baseWait
-					// Apply exponential growth for retries 2+, capped at maxWait
-					if i > 1 {
-						// Calculate exponential delay, but cap growth after a few retries
-						factor := 1 << min(i-1, 3) // 2^(i-1), capped at 2^3 = 8x
-						waitTime = min(baseWait*factor, maxWait)
-					}
-
-					time.Sleep(time.Duration(waitTime) * time.Second)
-				}
+				},
+			}
 
-				i = i + 1
+			if ro.WaitSeconds > 0 {
+				// Use exponential backoff: start with configured wait (minimum 10s), cap at 60s
+				ret.BaseDelay = time.Duration(max(ro.WaitSeconds, 10)) * time.Second
+				ret.MaxDelay = 60 * time.Second
 			}
 
+			cr.Error = util.Retry(ctx, ret, func(ctx context.Context) error {
+				return sc.Run(ctx, cfg)
+			})
+
 			if opts.OnComplete != nil {
 				opts.OnComplete(cr)
 			}
@@ -246,7 +232,7 @@ func postEventChecks(sc schema.StageConfig, event string, providerFactory provid
 }
 
 // appendChecks appends the specified stage checks to the result slice.
-// Handles HTTP, Kubernetes, DaemonSet, and state checks.
+// Handles HTTP, Kubernetes, DaemonSet, state, and ConfigMap checks.
 func appendChecks(r []StageCheck, s schema.StageChecksConfig, providerFactory provider.ProviderFactory) []StageCheck {
 	for _, hc := range s.Http {
 		ihc := hc
@@ -268,5 +254,10 @@ func appendChecks(r []StageCheck, s schema.StageChecksConfig, providerFactory pr
 		r = append(r, NewStateStageCheck(isc, providerFactory))
 	}
 
+	for _, cc := range s.ConfigMap {
+		icc := cc
+		r = append(r, NewConfigMapStageCheck(icc, providerFactory))
+	}
+
 	return r
 }
@@ -0,0 +1,97 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/provider"
+)
+
+// ConfigMapStageCheck represents a stage check which watches a ConfigMap until a key reaches the expected value.
+// Unlike the state check it doesn't poll, it returns as soon as the ConfigMap is updated.
+type ConfigMapStageCheck struct {
+	src             schema.StageChecksConfigMapConfig // The configuration for the ConfigMap stage check.
+	providerFactory provider.ProviderFactory          // The provider factory for accessing Kubernetes resources.
+}
+
+// NewConfigMapStageCheck creates a new ConfigMapStageCheck instance with the specified configuration and provider factory.
+func NewConfigMapStageCheck(src schema.StageChecksConfigMapConfig, providerFactory provider.ProviderFactory) ConfigMapStageCheck {
+	return ConfigMapStageCheck{
+		src:             src,
+		providerFactory: providerFactory,
+	}
+}
+
+// Run executes the ConfigMap stage check, watching the ConfigMap until the key reaches the expected value
+// or the timeout elapses. The state ConfigMap is watched when no name is configured.
+func (c ConfigMapStageCheck) Run(ctx context.Context, cfg schema.QuartzConfig) error {
+	if c.src.Key == "" {
+		return errors.New("key required for configmap check")
+	}
+
+	ns, name := c.configMap(cfg)
+	if ns == "" || name == "" {
+		return errors.New("name and namespace required for configmap check")
+	}
+
+	kube, err := c.providerFactory.Kubernetes(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Watching configmap", "name", name, "namespace", ns, "key", c.src.Key, "value", c.src.Value)
+	return kube.WaitConfigMapValue(ctx, ns, name, c.src.Key, c.src.Value, c.src.Timeout)
+}
+
+// configMap returns the namespace and name of the ConfigMap to watch, defaulting to the state ConfigMap.
+func (c ConfigMapStageCheck) configMap(cfg schema.QuartzConfig) (string, string) {
+	if c.src.Name == "" {
+		return cfg.State.ConfigMapNamespace, cfg.State.ConfigMapName
+	}
+	return c.src.Namespace, c.src.Name
+}
+
+// Id returns the unique identifier of the ConfigMap stage check.
+// The identifier includes the ConfigMap, key and expected value being checked.
+func (c ConfigMapStageCheck) Id() string {
+	id := c.src.Key
+	if c.src.Name != "" {
+		id = fmt.Sprintf("%s/%s %s", c.src.Namespace, c.src.Name, c.src.Key)
+	}
+
+	if c.src.Value == "" {
+		return id
+	}
+	return fmt.Sprintf("%s - %s", id, c.src.Value)
+}
+
+// Type returns the type of the stage check, which is "configmap".
+func (c ConfigMapStageCheck) Type() string {
+	return "configmap"
+}
+
+// RetryOpts returns the retry configuration for the ConfigMap stage check.
+// The check waits on its own until the timeout, so it only allows one attempt.
+func (c ConfigMapStageCheck) RetryOpts() schema.StageChecksRetryConfig {
+	return schema.StageChecksRetryConfig{
+		Limit:       1,
+		WaitSeconds: -1,
+	}
+}
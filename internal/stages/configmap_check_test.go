@@ -0,0 +1,136 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+	"github.com/MetroStar/quartzctl/internal/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+// newTestConfigMapFactory creates a provider factory whose ConfigMap bootstrap/ready has ready=false,
+// and whose watch sets ready=true after a delay.
+func newTestConfigMapFactory(cfg schema.QuartzConfig) *provider.ProviderFactory {
+	newCm := func(value string) *corev1.ConfigMap {
+		cm := corev1.ConfigMap{}
+		cm.Name = "ready"
+		cm.Namespace = "bootstrap"
+		cm.Data = map[string]string{"ready": value}
+		return &cm
+	}
+
+	api := provider.NewKubernetesApiMock().
+		WithClientObjects(newCm("false")).
+		WithClientWatchReactor("configmaps", func(action k8sTesting.Action) (bool, watch.Interface, error) {
+			fw := watch.NewFake()
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				fw.Modify(newCm("true"))
+			}()
+			return true, fw, nil
+		})
+
+	k8s, _ := provider.NewKubernetesClient(api, provider.KubeconfigInfo{}, cfg)
+	return provider.NewProviderFactory(cfg, schema.QuartzSecrets{}, provider.WithKubernetesProvider(k8s))
+}
+
+func TestStagesConfigMapCheckRunHappy(t *testing.T) {
+	cfg := schema.QuartzConfig{}
+	f := newTestConfigMapFactory(cfg)
+
+	c := NewConfigMapStageCheck(schema.StageChecksConfigMapConfig{
+		Name:      "ready",
+		Namespace: "bootstrap",
+		Key:       "ready",
+		Value:     "true",
+		Timeout:   5,
+	}, *f)
+
+	if c.Id() != "bootstrap/ready ready - true" || c.Type() != "configmap" || c.RetryOpts().Limit != 1 {
+		t.Errorf("unexpected properties of configmap check, %v, %v, %v", c.Id(), c.Type(), c.RetryOpts())
+	}
+
+	err := c.Run(context.Background(), cfg)
+	if err != nil {
+		t.Errorf("unexpected error in configmap check, %v", err)
+	}
+}
+
+func TestStagesConfigMapCheckRunTimeout(t *testing.T) {
+	cfg := schema.QuartzConfig{}
+	f := newTestConfigMapFactory(cfg)
+
+	c := NewConfigMapStageCheck(schema.StageChecksConfigMapConfig{
+		Name:      "ready",
+		Namespace: "bootstrap",
+		Key:       "ready",
+		Value:     "done",
+		Timeout:   1,
+	}, *f)
+
+	err := c.Run(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout error in configmap check, found %v", err)
+	}
+}
+
+func TestStagesConfigMapCheckRunStateDefault(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		State: schema.StateConfig{ConfigMapNamespace: "bootstrap", ConfigMapName: "ready"},
+	}
+	f := newTestConfigMapFactory(cfg)
+
+	c := NewConfigMapStageCheck(schema.StageChecksConfigMapConfig{
+		Key:     "ready",
+		Timeout: 5,
+	}, *f)
+
+	if c.Id() != "ready" {
+		t.Errorf("unexpected id of configmap check, %v", c.Id())
+	}
+
+	err := c.Run(context.Background(), cfg)
+	if err != nil {
+		t.Errorf("unexpected error in configmap check against the state configmap, %v", err)
+	}
+}
+
+func TestStagesConfigMapCheckRunInvalid(t *testing.T) {
+	c := NewConfigMapStageCheck(schema.StageChecksConfigMapConfig{
+		Name: "ready",
+	}, provider.ProviderFactory{})
+
+	err := c.Run(context.Background(), schema.QuartzConfig{})
+	if err == nil {
+		t.Errorf("expected an error in configmap check without a key")
+	}
+
+	c = NewConfigMapStageCheck(schema.StageChecksConfigMapConfig{
+		Key: "ready",
+	}, provider.ProviderFactory{})
+
+	err = c.Run(context.Background(), schema.QuartzConfig{})
+	if err == nil {
+		t.Errorf("expected an error in configmap check without a configmap")
+	}
+}
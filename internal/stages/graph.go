@@ -0,0 +1,90 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
+
+// stageEdge represents a dependency between two stages, from the stage that has to run first.
+type stageEdge struct {
+	from string
+	to   string
+}
+
+// StagesGraph renders the inter-stage dependency graph in Graphviz DOT format. Edges point from
+// a stage to the stages depending on it, either through stage output vars (labeled with the
+// outputs used) or explicit dependencies (dashed). Manual stages are drawn with a dashed border.
+func StagesGraph(stages map[string]schema.StageConfig) string {
+	edges := map[stageEdge][]string{}
+
+	for k, v := range stages {
+		for _, d := range v.Dependencies {
+			e := stageEdge{from: d, to: k}
+			if _, ok := edges[e]; !ok {
+				edges[e] = nil
+			}
+		}
+
+		for _, vc := range v.Vars {
+			if vc.Stage.Name == "" {
+				continue
+			}
+
+			e := stageEdge{from: vc.Stage.Name, to: k}
+			if !slices.Contains(edges[e], vc.Stage.Output) {
+				edges[e] = append(edges[e], vc.Stage.Output)
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph stages {\n")
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, k := range slices.Sorted(maps.Keys(stages)) {
+		if stages[k].Manual {
+			fmt.Fprintf(&b, "  %q [style=dashed];\n", k)
+			continue
+		}
+		fmt.Fprintf(&b, "  %q;\n", k)
+	}
+
+	keys := slices.SortedFunc(maps.Keys(edges), func(x, y stageEdge) int {
+		if c := strings.Compare(x.from, y.from); c != 0 {
+			return c
+		}
+		return strings.Compare(x.to, y.to)
+	})
+
+	for _, e := range keys {
+		outputs := edges[e]
+		if len(outputs) == 0 {
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed];\n", e.from, e.to)
+			continue
+		}
+
+		slices.Sort(outputs)
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.from, e.to, strings.Join(outputs, ", "))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
+
+func TestStagesGraph(t *testing.T) {
+	manual := NewStageConfig("manual")
+	manual.Manual = true
+	manual.Dependencies = []string{"root", "hasdependencies"}
+
+	stages := LoadStages(map[string]schema.StageConfig{
+		"manual": manual,
+	}, filepath.Join("testdata", "TestLoadStagesHappy"))
+
+	actual := StagesGraph(stages)
+
+	expected := []string{
+		`"root" -> "second" [label="name"];`,
+		`"root" -> "third" [label="name"];`,
+		`"root" -> "hasdependencies" [label="name"];`,
+		`"second" -> "hasdependencies" [label="name"];`,
+		`"third" -> "hasdependencies" [style=dashed];`,
+		`"root" -> "manual" [style=dashed];`,
+		`"hasdependencies" -> "manual" [style=dashed];`,
+		`"manual" [style=dashed];`,
+	}
+
+	if !strings.HasPrefix(actual, "digraph stages {\n") || !strings.HasSuffix(actual, "}\n") {
+		t.Errorf("expected a digraph, got %s", actual)
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(actual, e) {
+			t.Errorf("expected graph to contain %s, got\n%s", e, actual)
+		}
+	}
+
+	if strings.Contains(actual, `"second" -> "third"`) {
+		t.Errorf("unexpected edge between independent stages, got\n%s", actual)
+	}
+
+	if actual != StagesGraph(stages) {
+		t.Errorf("expected stable graph output")
+	}
+}
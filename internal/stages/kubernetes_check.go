@@ -55,7 +55,7 @@ func (c KubernetesStageCheck) Run(ctx context.Context, _ schema.QuartzConfig) er
 	}
 
 	if c.src.Restart {
-		err = kube.Restart(ctx, kind, c.src.Namespace, c.src.Name)
+		_, err = kube.Restart(ctx, kind, c.src.Namespace, c.src.Name)
 		if err != nil {
 			return err
 		}
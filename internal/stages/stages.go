@@ -17,8 +17,10 @@ package stages
 import (
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -32,7 +34,10 @@ import (
 	"github.com/knadh/koanf/v2"
 )
 
-const configFileName = "stage.yaml"
+const (
+	configFileName   = "stage.yaml"
+	defaultMatrixVar = "matrix"
+)
 
 // NewStageConfig creates a new StageConfig with default values for the specified stage ID.
 func NewStageConfig(id string) schema.StageConfig {
@@ -116,6 +121,9 @@ func LoadStages(base map[string]schema.StageConfig, paths ...string) map[string]
 		t = util.MergeMaps(t, s)
 	}
 
+	// expand matrix stages into one copy per entry
+	t = expandStageMatrix(t)
+
 	// update stage order based on explicitly defined dependencies
 	final, err := processStageDependencies(t)
 	if err != nil {
@@ -155,6 +163,66 @@ func processStagePath(root string) (map[string]schema.StageConfig, error) {
 	return r, nil
 }
 
+// expandStageMatrix replaces each stage defining a matrix with one stage per matrix entry.
+// Expanded stages are keyed and identified as <id>-<matrix key> and receive the matrix value
+// as the input variable named by MatrixVar. Dependencies on a matrix stage are rewritten to
+// depend on all of its expanded stages. Expanded stages always run in their own working
+// directory, sharing the source directory's .terraform would leave each of them on the backend
+// of whichever sibling was initialized last.
+func expandStageMatrix(stages map[string]schema.StageConfig) map[string]schema.StageConfig {
+	expanded := make(map[string][]string)
+	r := make(map[string]schema.StageConfig, len(stages))
+
+	for k, v := range stages {
+		if len(v.Matrix) == 0 {
+			r[k] = v
+			continue
+		}
+
+		varName := v.MatrixVar
+		if varName == "" {
+			varName = defaultMatrixVar
+		}
+
+		for _, mk := range slices.Sorted(maps.Keys(v.Matrix)) {
+			s := v
+			s.Id = fmt.Sprintf("%s-%s", v.Id, mk)
+			s.Description = fmt.Sprintf("%s (%s)", v.Description, mk)
+			s.Matrix = nil
+			s.WorkingDir = true
+			s.Vars = maps.Clone(v.Vars)
+			if s.Vars == nil {
+				s.Vars = make(map[string]schema.StageVarsConfig)
+			}
+			s.Vars[varName] = schema.StageVarsConfig{Value: v.Matrix[mk]}
+
+			key := fmt.Sprintf("%s-%s", k, mk)
+			log.Debug("Expanded matrix stage", "stage", k, "key", key)
+			r[key] = s
+			expanded[k] = append(expanded[k], key)
+		}
+	}
+
+	if len(expanded) == 0 {
+		return r
+	}
+
+	for k, v := range r {
+		var deps []string
+		for _, d := range v.Dependencies {
+			if e, ok := expanded[d]; ok {
+				deps = append(deps, e...)
+				continue
+			}
+			deps = append(deps, d)
+		}
+		v.Dependencies = deps
+		r[k] = v
+	}
+
+	return r
+}
+
 // processStageDependencies adjusts the order of stages to ensure that all dependencies precede the dependent stages.
 // Returns the updated map of stages or an error if a circular dependency is detected.
 func processStageDependencies(stages map[string]schema.StageConfig) (map[string]schema.StageConfig, error) {
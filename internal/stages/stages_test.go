@@ -69,6 +69,56 @@ func TestLoadStagesHappy(t *testing.T) {
 	}
 }
 
+// TestExpandStageMatrix tests that a stage with a matrix expands into one
+// stage per entry with the matrix value injected as an input variable.
+func TestExpandStageMatrix(t *testing.T) {
+	regional := NewStageConfig("regional")
+	regional.Order = 5
+	regional.Vars["existing"] = schema.StageVarsConfig{Value: "kept"}
+	regional.Matrix = map[string]string{
+		"east": "us-east-1",
+		"west": "us-west-2",
+	}
+
+	after := NewStageConfig("after")
+	after.Dependencies = []string{"regional"}
+
+	actual := expandStageMatrix(map[string]schema.StageConfig{
+		"regional": regional,
+		"after":    after,
+	})
+
+	if _, found := actual["regional"]; found {
+		t.Errorf("expected matrix stage to be replaced by its expansions")
+	}
+
+	for k, v := range map[string]string{"regional-east": "us-east-1", "regional-west": "us-west-2"} {
+		s, found := actual[k]
+		if !found {
+			t.Errorf("result map missing expanded stage %s", k)
+			continue
+		}
+
+		if s.Id != k || s.Order != 5 || len(s.Matrix) != 0 || !s.WorkingDir {
+			t.Errorf("incorrect expanded stage %s, got %v", k, s)
+		}
+
+		if s.Vars[defaultMatrixVar].Value != v || s.Vars["existing"].Value != "kept" {
+			t.Errorf("incorrect vars for expanded stage %s, got %v", k, s.Vars)
+		}
+	}
+
+	// the source stage vars are not shared between expansions
+	if len(regional.Vars) != 1 {
+		t.Errorf("unexpected modification of source stage vars, %v", regional.Vars)
+	}
+
+	deps := actual["after"].Dependencies
+	if strings.Join(deps, ",") != "regional-east,regional-west" {
+		t.Errorf("incorrect dependencies on matrix stage, got %v", deps)
+	}
+}
+
 // TestProcessStagePathHappy tests the successful processing of stage paths
 // and compares the expected and actual results for correctness.
 func TestProcessStagePathHappy(t *testing.T) {
@@ -40,7 +40,8 @@ func NewStateStageCheck(src schema.StageChecksStateConfig, providerFactory provi
 }
 
 // Run executes the state stage check by validating the state stored in a Kubernetes ConfigMap.
-// It ensures the specified key exists and matches the expected value.
+// It ensures the specified key exists and, unless only presence is required,
+// that the stored value matches the expected value (case-insensitive).
 func (c StateStageCheck) Run(ctx context.Context, cfg schema.QuartzConfig) error {
 	if !cfg.State.Enabled {
 		log.Error("Quartz platform state tracking disabled, skipping stage check", "stage", c.Id())
@@ -62,12 +63,17 @@ func (c StateStageCheck) Run(ctx context.Context, cfg schema.QuartzConfig) error
 	val, ok := data[c.src.Key]
 	if !ok {
 		log.Debug("Requested configmap key not found", "key", c.src.Key)
-		return fmt.Errorf("key not found, %s", c.src.Key)
+		return fmt.Errorf("key not found, %s in configmap %s/%s", c.src.Key, cfg.State.ConfigMapNamespace, cfg.State.ConfigMapName)
+	}
+
+	if c.src.Present {
+		log.Debug("State check succeeded, key present", "id", c.Id(), "key", c.src.Key)
+		return nil
 	}
 
 	if !strings.EqualFold(val, c.src.Value) {
 		log.Debug("Requested configmap key found but incorrect value", "key", c.src.Key, "expected", c.src.Value, "actual", val)
-		return fmt.Errorf("value failed to match, expected %s, found %s", c.src.Value, val)
+		return fmt.Errorf("value failed to match for key %s, expected %q, found %q", c.src.Key, c.src.Value, val)
 	}
 
 	log.Debug("State check succeeded", "id", c.Id(), "key", c.src.Key, "value", c.src.Value)
@@ -77,6 +83,9 @@ func (c StateStageCheck) Run(ctx context.Context, cfg schema.QuartzConfig) error
 // Id returns the unique identifier of the state stage check.
 // The identifier includes the key and expected value being checked.
 func (c StateStageCheck) Id() string {
+	if c.src.Present {
+		return c.src.Key
+	}
 	return fmt.Sprintf("%s - %s", c.src.Key, c.src.Value)
 }
 
@@ -180,3 +180,67 @@ func TestStagesStateCheckRunConfigmapNotFound(t *testing.T) {
 		t.Errorf("invalid error message, expected %s, found %v", "configmaps \"quartz-install-state\" not found", err)
 	}
 }
+
+func TestStagesStateCheckRunExpectedValue(t *testing.T) {
+	cfg := schema.QuartzConfig{
+		State: schema.NewStateConfig(),
+	}
+
+	cm := corev1.ConfigMap{}
+	cm.Name = cfg.State.ConfigMapName
+	cm.Namespace = cfg.State.ConfigMapNamespace
+	cm.Data = map[string]string{
+		"key1": "true",
+		"key3": "",
+	}
+
+	api := provider.NewKubernetesApiMock().WithClientObjects(&cm)
+	kubeconfig := provider.KubeconfigInfo{}
+	k8s, _ := provider.NewKubernetesClient(api, kubeconfig, cfg)
+	f := provider.NewProviderFactory(cfg, schema.QuartzSecrets{}, provider.WithKubernetesProvider(k8s))
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		present bool
+		wantErr []string
+	}{
+		{name: "present and correct", key: "key1", value: "true"},
+		{name: "present and correct case insensitive", key: "key1", value: "TRUE"},
+		{name: "present only", key: "key1", present: true},
+		{name: "present and wrong", key: "key1", value: "false", wantErr: []string{"value failed to match", "key1", `expected "false"`, `found "true"`}},
+		{name: "empty value", key: "key3"},
+		{name: "empty value but set", key: "key1", wantErr: []string{"value failed to match", `expected ""`, `found "true"`}},
+		{name: "absent", key: "key2", value: "true", wantErr: []string{"key not found", "key2", cfg.State.ConfigMapName}},
+		{name: "absent presence only", key: "key2", present: true, wantErr: []string{"key not found", "key2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewStateStageCheck(schema.StageChecksStateConfig{
+				Key:     tt.key,
+				Value:   tt.value,
+				Present: tt.present,
+			}, *f)
+
+			err := c.Run(context.Background(), cfg)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error in state check, %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error in state check, found nil")
+			}
+
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("invalid error message, expected to contain %s, found %v", want, err)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +29,7 @@ import (
 	"github.com/MetroStar/quartzctl/internal/config"
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
 
 	"github.com/hashicorp/go-version"
 	hcInstall "github.com/hashicorp/hc-install"
@@ -40,6 +43,9 @@ import (
 var (
 	instance *TerraformClient
 	tfOnce   sync.Once
+
+	pluginCacheMu    sync.Mutex      // guards pluginCacheInUse
+	pluginCacheInUse map[string]bool // plugin cache directories used by running inits
 )
 
 // TerraformClient is a wrapper for the Terraform CLI, providing functionality for managing Terraform operations.
@@ -50,19 +56,33 @@ type TerraformClient struct {
 	execPath string
 	cfg      config.Settings
 
+	mu          sync.Mutex // guards the caches below, stages may run concurrently (e.g. init-all, validate-all)
 	clientCache map[string]*tfexec.Terraform
+	workDirs    map[string]string // stage id to the working directory copied this run
+	workspaces  map[string]string // terraform directory to the workspace selected this run
 }
 
 // TfOpts represents options for configuring a Terraform instance.
 type TfOpts struct {
 	dir    string    // The directory where Terraform will operate.
+	stage  string    // The stage id, used for per-stage log paths.
 	stdout io.Writer // The writer for standard output.
 	stderr io.Writer // The writer for standard error.
 }
 
+// TerraformInitMode controls how init treats an existing backend association.
+type TerraformInitMode string
+
+const (
+	InitReconfigure  TerraformInitMode = "reconfigure"   // discard the existing backend association (-reconfigure), the default
+	InitMigrateState TerraformInitMode = "migrate-state" // copy existing state to the configured backend (-force-copy)
+)
+
 // TerraformInitOpts represents options for initializing Terraform with backend configuration.
 type TerraformInitOpts struct {
-	BackendConfig []string // The backend configuration options.
+	BackendConfig      []string          // The backend configuration options, key=value.
+	BackendConfigFiles []string          // The backend configuration files, applied before the key=value options.
+	Mode               TerraformInitMode // Reconfigure or migrate state, empty for InitReconfigure.
 }
 
 // TfExecTerraformLogger defines the interface for configuring Terraform logging.
@@ -121,25 +141,228 @@ func (c *TerraformClient) Cleanup(ctx context.Context) error {
 	return c.installer.Remove(ctx)
 }
 
-// getTf retrieves a cached Terraform instance for the specified directory.
+// getTf retrieves a cached Terraform instance for the specified directory and stage.
 // If no instance exists, it creates a new one.
-func (c *TerraformClient) getTf(dir string) (*tfexec.Terraform, error) {
-	if i, found := c.clientCache[dir]; found {
+func (c *TerraformClient) getTf(dir string, stage string) (*tfexec.Terraform, error) {
+	key := stage + ":" + dir // matrix stages share a directory
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, found := c.clientCache[key]; found {
 		return i, nil
 	}
 
-	i, err := c.newTf(dir)
+	i, err := c.newTf(dir, stage)
 	if err != nil {
 		return nil, err
 	}
 
-	c.clientCache[dir] = i
+	c.clientCache[key] = i
 	return i, nil
 }
 
+// stageTf retrieves the Terraform instance for the stage, running in the stage's working
+// directory copy when working_dir is set, otherwise in the stage source directory.
+func (c *TerraformClient) stageTf(stage schema.StageConfig) (*tfexec.Terraform, error) {
+	dir, err := c.stageDir(stage)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getTf(dir, stage.Id)
+}
+
+// stageDir returns the directory terraform runs in for the stage. With working_dir set, the
+// stage source is copied to <tmp>/stages/<id> once per run so init and apply don't write to
+// the source. Terraform's own files (.terraform and local state) in the copy are kept between runs.
+// Local modules outside the stage, e.g. ../modules/one, are copied alongside it keeping their
+// layout relative to the stage, so relative module sources still resolve.
+func (c *TerraformClient) stageDir(stage schema.StageConfig) (string, error) {
+	if !stage.WorkingDir {
+		return stage.Path, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if dir, found := c.workDirs[stage.Id]; found {
+		return dir, nil
+	}
+
+	base := filepath.Join(c.cfg.Config.Tmp, "stages", stage.Id)
+	dir, err := syncStageWorkingDir(stage.Path, base)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy stage %s to working directory %s, %w", stage.Id, base, err)
+	}
+	log.Debug("Copied stage to working directory", "stage", stage.Id, "src", stage.Path, "dir", dir)
+
+	if c.workDirs == nil {
+		c.workDirs = make(map[string]string)
+	}
+	c.workDirs[stage.Id] = dir
+	return dir, nil
+}
+
+// syncStageWorkingDir copies the stage source and the local modules it references from outside
+// the stage directory under base, keeping their relative layout. Returns the directory the
+// stage was copied to.
+func syncStageWorkingDir(src string, base string) (string, error) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		return "", err
+	}
+
+	mods, err := localModuleDirs(src)
+	if err != nil {
+		return "", err
+	}
+
+	root := src
+	for _, m := range mods {
+		if isSubDir(m, src) {
+			return "", fmt.Errorf("module source %s contains the stage directory", m)
+		}
+		for !isSubDir(root, m) {
+			root = filepath.Dir(root)
+		}
+	}
+
+	rel, _ := filepath.Rel(root, src)
+	dir := filepath.Join(base, rel)
+	if err := syncWorkingDir(src, dir); err != nil {
+		return "", err
+	}
+
+	for _, m := range mods {
+		rel, _ := filepath.Rel(root, m)
+		dst := filepath.Join(base, rel)
+		if err := os.RemoveAll(dst); err != nil {
+			return "", err
+		}
+
+		err := util.CopyDir(m, dst, func(rel string, d os.DirEntry) bool {
+			return d.Name() == ".terraform"
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// moduleSourceRegexp matches local module sources, e.g. source = "../modules/one".
+var moduleSourceRegexp = regexp.MustCompile(`(?m)^\s*source\s*=\s*"(\.\.?/[^"]*)"`)
+
+// localModuleDirs returns the local module directories outside dir referenced by the terraform
+// files in dir, following nested local modules. Directories inside another returned directory
+// are left out.
+func localModuleDirs(dir string) ([]string, error) {
+	var res []string
+	visited := map[string]bool{}
+
+	var walk func(string) error
+	walk = func(d string) error {
+		if visited[d] {
+			return nil
+		}
+		visited[d] = true
+
+		files, err := filepath.Glob(filepath.Join(d, "*.tf"))
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return err
+			}
+
+			for _, m := range moduleSourceRegexp.FindAllStringSubmatch(string(b), -1) {
+				mod := filepath.Join(d, m[1])
+				if !isSubDir(dir, mod) && !slices.Contains(res, mod) {
+					res = append(res, mod)
+				}
+				if err := walk(mod); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(dir); err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(slices.Clone(res), func(m string) bool {
+		return slices.ContainsFunc(res, func(o string) bool {
+			return o != m && isSubDir(o, m)
+		})
+	}), nil
+}
+
+// isSubDir returns true if dir is parent or one of its subdirectories.
+func isSubDir(parent string, dir string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// workingDirKeep lists the terraform managed entries kept in a working directory between runs.
+var workingDirKeep = []string{".terraform", "terraform.tfstate", "terraform.tfstate.backup", "terraform.tfstate.d"}
+
+// syncWorkingDir replaces the contents of dst with the src directory, keeping terraform's
+// own files in dst. State in src is only copied when dst has none yet.
+func syncWorkingDir(src string, dst string) error {
+	if err := os.MkdirAll(dst, 0740); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dst)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if slices.Contains(workingDirKeep, e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return util.CopyDir(src, dst, func(rel string, d os.DirEntry) bool {
+		if d.Name() == ".terraform" {
+			return true
+		}
+
+		if !slices.Contains(workingDirKeep, rel) {
+			return false
+		}
+
+		_, err := os.Stat(filepath.Join(dst, rel))
+		return err == nil
+	})
+}
+
 // newTf creates a new Terraform instance for the specified directory with default options.
-func (c *TerraformClient) newTf(dir string) (*tfexec.Terraform, error) {
-	return c.newTfOpts(&TfOpts{dir: dir, stdout: os.Stdout, stderr: os.Stderr})
+// Terraform output is streamed live to the console, or discarded when terraform.quiet is set.
+func (c *TerraformClient) newTf(dir string, stage string) (*tfexec.Terraform, error) {
+	stdout, stderr := util.ConsoleWriter(), util.ConsoleWriter()
+	if c.cfg.Config.Terraform.Quiet {
+		log.Debug("Terraform quiet, discarding output", "dir", dir)
+		stdout, stderr = io.Discard, io.Discard
+	}
+
+	return c.newTfOpts(&TfOpts{dir: dir, stage: stage, stdout: stdout, stderr: stderr})
 }
 
 // newTfOpts creates a new Terraform instance with the specified options.
@@ -163,7 +386,7 @@ func (c *TerraformClient) newTfOpts(opts *TfOpts) (*tfexec.Terraform, error) {
 		tf.SetStderr(opts.stderr)
 	}
 
-	initLog(tf, c.cfg.Config)
+	initLog(tf, c.cfg.Config, opts.stage)
 
 	return tf, nil
 }
@@ -200,26 +423,43 @@ func install(ctx context.Context, v string, dir string) (string, *hcInstall.Inst
 	return execPath, installer, err
 }
 
+// LogPath resolves the configured terraform log path, expanding the $name, $date and
+// $stage (also {stage}) placeholders.
+func LogPath(cfg schema.QuartzConfig, stage string, now time.Time) string {
+	path, _ := filepath.Abs(cfg.Log.Terraform.Path)
+	path = strings.ReplaceAll(path, "$name", cfg.Name)
+	path = strings.ReplaceAll(path, "$date", now.Format("2006-01-02"))
+	path = strings.ReplaceAll(path, "$stage", stage)
+	path = strings.ReplaceAll(path, "{stage}", stage)
+	return path
+}
+
+// IsPerStageLogPath reports whether the configured terraform log path contains a stage placeholder,
+// writing one log file per stage instead of a shared file.
+func IsPerStageLogPath(cfg schema.QuartzConfig) bool {
+	return strings.Contains(cfg.Log.Terraform.Path, "$stage") || strings.Contains(cfg.Log.Terraform.Path, "{stage}")
+}
+
 // initLog configures logging for the Terraform instance based on the Quartz configuration.
-func initLog(tf TfExecTerraformLogger, cfg schema.QuartzConfig) {
+func initLog(tf TfExecTerraformLogger, cfg schema.QuartzConfig, stage string) {
 	if !cfg.Log.Terraform.Enabled ||
 		cfg.Log.Terraform.Path == "" {
 		log.Debug("Terraform logging disabled")
 		return
 	}
 
+	if stage == "" && IsPerStageLogPath(cfg) {
+		log.Debug("Terraform log path is per stage, skipping logging outside of a stage")
+		return
+	}
+
 	level := cfg.Log.Terraform.Level
 	log.Debug("Attempting to configure Terraform log", "rawpath", cfg.Log.Terraform.Path, "level", level)
 
-	path, _ := filepath.Abs(cfg.Log.Terraform.Path)
+	path := LogPath(cfg, stage, time.Now())
 	dir := filepath.Dir(path)
 	os.MkdirAll(dir, 0740) //nolint:errcheck
 
-	now := time.Now()
-
-	path = strings.ReplaceAll(path, "$name", cfg.Name)
-	path = strings.ReplaceAll(path, "$date", now.Format("2006-01-02"))
-
 	log.Info("Configuring Terraform log", "path", path, "level", level)
 	if err := tf.SetLogPath(path); err != nil {
 		log.Debug("Failed to set terraform log path", "err", err)
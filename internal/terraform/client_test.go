@@ -16,14 +16,23 @@ package terraform
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/MetroStar/quartzctl/internal/config"
 	"github.com/MetroStar/quartzctl/internal/config/schema"
 	"github.com/MetroStar/quartzctl/internal/log"
+	"github.com/MetroStar/quartzctl/internal/util"
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/knadh/koanf/v2"
 	"github.com/stretchr/testify/assert"
 )
@@ -111,6 +120,112 @@ func TestTerraformInit(t *testing.T) {
 	}
 }
 
+func TestTerraformMigrateState(t *testing.T) {
+	tf, err := newTestTfClient(t)
+	if err != nil {
+		t.Errorf("unexpected error from terraform client constructor, %v", err)
+	}
+
+	defer tf.Cleanup(context.Background())
+
+	// copy the simple stage so the backend block can be added without touching testdata
+	stage := newSimpleStageConfig()
+	dir := t.TempDir()
+	src, err := os.ReadFile(filepath.Join(stage.Path, "main.tf"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), src, 0600))
+	stage.Path = dir
+
+	// apply against the default local state
+	assert.NoError(t, tf.Init(context.Background(), stage, TerraformInitOpts{}))
+	assert.NoError(t, tf.Apply(context.Background(), stage))
+	assert.FileExists(t, filepath.Join(dir, "terraform.tfstate"))
+
+	// switch to a (local) backend at a new path and migrate
+	backend := []byte(`terraform {
+  backend "local" {}
+}
+`)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "backend.tf"), backend, 0600))
+
+	target := filepath.Join(t.TempDir(), "migrated.tfstate")
+	err = tf.MigrateState(context.Background(), stage, TerraformInitOpts{
+		BackendConfig: []string{"path=" + target},
+	})
+	if err != nil {
+		t.Errorf("unexpected error from terraform migrate state, %v", err)
+		return
+	}
+
+	b, err := os.ReadFile(target)
+	assert.NoError(t, err, "migrated state should exist at the new backend path")
+	assert.Contains(t, string(b), "my-test-cluster.example.com")
+}
+
+func TestTerraformForceUnlock(t *testing.T) {
+	tmpDir := t.TempDir()
+	argsFile := filepath.Join(tmpDir, "args")
+	execPath := filepath.Join(tmpDir, "terraform")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(execPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake terraform executable, %v", err)
+	}
+
+	tf := &TerraformClient{
+		execPath:    execPath,
+		clientCache: make(map[string]*tfexec.Terraform),
+	}
+
+	err := tf.ForceUnlock(context.Background(), schema.StageConfig{Id: "test", Path: tmpDir}, "9db590f1-b6fe-c5f2-2678-8804f089deba")
+	if err != nil {
+		t.Fatalf("unexpected error from terraform force-unlock, %v", err)
+	}
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("failed to read fake terraform args, %v", err)
+	}
+
+	assert.Equal(t, "force-unlock -no-color -force 9db590f1-b6fe-c5f2-2678-8804f089deba", strings.TrimSpace(string(args)))
+}
+
+func TestParseLockId(t *testing.T) {
+	lockErr := errors.New(`Error: Error acquiring the state lock
+
+Error message: ConditionalCheckFailedException: The conditional request failed
+Lock Info:
+  ID:        9db590f1-b6fe-c5f2-2678-8804f089deba
+  Path:      quartz-state/first/terraform.tfstate
+  Operation: OperationTypeApply
+  Who:       user@host
+  Version:   1.5.7
+  Created:   2025-01-01 00:00:00.000000000 +0000 UTC
+  Info:
+
+Terraform acquires a state lock to protect the state from being written
+by multiple users at the same time.`)
+
+	tests := []struct {
+		name  string
+		err   error
+		id    string
+		found bool
+	}{
+		{name: "lock error", err: lockErr, id: "9db590f1-b6fe-c5f2-2678-8804f089deba", found: true},
+		{name: "other error", err: errors.New("Error: Invalid resource type"), found: false},
+		{name: "lock error without id", err: errors.New("Error acquiring the state lock"), found: false},
+		{name: "nil", err: nil, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, found := ParseLockId(tt.err)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
 func TestTerraformValidate(t *testing.T) {
 	tf, err := newTestTfClient(t)
 	if err != nil {
@@ -148,6 +263,42 @@ func TestTerraformFormat(t *testing.T) {
 	}
 }
 
+func TestTerraformFormatCheck(t *testing.T) {
+	tf, err := newTestTfClient(t)
+	if err != nil {
+		t.Errorf("unexpected error from terraform client constructor, %v", err)
+	}
+
+	defer tf.Cleanup(context.Background())
+
+	stage := schema.StageConfig{Path: "./testdata/unformatted"}
+	files, err := tf.FormatCheck(context.Background(), stage)
+	if err != nil {
+		t.Errorf("unexpected error from terraform format check, %v", err)
+	}
+
+	expected := filepath.Join("testdata", "unformatted", "main.tf")
+	if len(files) != 1 || files[0] != expected {
+		t.Errorf("incorrect response from terraform format check, expected [%s], found %v", expected, files)
+	}
+
+	b, _ := os.ReadFile(expected)
+	if !strings.Contains(string(b), "value=var.value_input") {
+		t.Errorf("terraform format check rewrote %s", expected)
+	}
+}
+
+func TestTerraformProvidersLockOptions(t *testing.T) {
+	actual := providersLockOptions([]string{"linux_amd64", "linux_arm64"})
+	expected := []tfexec.ProvidersLockOption{
+		tfexec.Platform("linux_amd64"),
+		tfexec.Platform("linux_arm64"),
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, providersLockOptions(nil))
+}
+
 func TestTerraformPlan(t *testing.T) {
 	tf, err := newTestTfClient(t)
 	if err != nil {
@@ -228,6 +379,102 @@ func TestTerraformDestroy(t *testing.T) {
 	}
 }
 
+func TestTerraformDestroyTargets(t *testing.T) {
+	state := `{"format_version":"1.0","terraform_version":"1.5.7","values":{"root_module":{
+"resources":[{"address":"random_integer.include"},{"address":"random_integer.exclude"},{"address":"terraform_data.other"}],
+"child_modules":[{"address":"module.mod","resources":[{"address":"module.mod.random_integer.this"}]}]}}}`
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(statePath, []byte(state), 0600); err != nil {
+		t.Fatalf("unexpected error writing fake state, %v", err)
+	}
+	t.Setenv("FAKE_TF_SHOW_PATH", statePath)
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+		excluded []string
+	}{
+		{"include", []string{"random_integer.include"}, nil, []string{"-target=random_integer.include"}, []string{"-target=terraform_data.other"}},
+		{"exclude", nil, []string{"random_integer.exclude", "module.mod.*"}, []string{"-target=random_integer.include", "-target=terraform_data.other"}, []string{"-target=random_integer.exclude", "-target=module.mod.random_integer.this"}},
+		{"combined", []string{"random_integer.*", "module.mod.*"}, []string{"random_integer.exclude"}, []string{"-target=random_integer.include", "-target=module.mod.random_integer.this"}, []string{"-target=random_integer.exclude", "-target=terraform_data.other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := newFakeTfClient(t, true)
+			logPath := filepath.Join(t.TempDir(), "terraform.log")
+			tf.cfg.Config.Log.Terraform.Enabled = true
+			tf.cfg.Config.Log.Terraform.Path = logPath
+
+			stage := schema.StageConfig{
+				Id:           "targets",
+				Path:         t.TempDir(),
+				OverrideVars: true,
+				Destroy:      schema.StageDestroyConfig{Include: tt.include, Exclude: tt.exclude},
+			}
+			err := tf.Destroy(context.Background(), stage)
+			if err != nil {
+				t.Fatalf("unexpected error from terraform destroy, %v", err)
+			}
+
+			b, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("expected terraform log, %v", err)
+			}
+
+			args := string(b)
+			for _, e := range tt.expected {
+				if !strings.Contains(args, e) {
+					t.Errorf("expected %s in terraform destroy args, %s", e, args)
+				}
+			}
+			for _, e := range tt.excluded {
+				if strings.Contains(args, e) {
+					t.Errorf("unexpected %s in terraform destroy args, %s", e, args)
+				}
+			}
+		})
+	}
+}
+
+func TestTerraformDestroyTargetsResolved(t *testing.T) {
+	state := `{"format_version":"1.0","terraform_version":"1.5.7","values":{"root_module":{
+"resources":[{"address":"random_integer.include"},{"address":"random_integer.exclude"}]}}}`
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(statePath, []byte(state), 0600); err != nil {
+		t.Fatalf("unexpected error writing fake state, %v", err)
+	}
+	t.Setenv("FAKE_TF_SHOW_PATH", statePath)
+
+	tf := newFakeTfClient(t, true)
+
+	// no filters is a full destroy
+	targets, found, err := tf.DestroyTargets(context.Background(), schema.StageConfig{Id: "full", Path: t.TempDir()})
+	if err != nil || !found || targets != nil {
+		t.Errorf("expected a full destroy, found %v %v %v", targets, found, err)
+	}
+
+	targets, found, err = tf.DestroyTargets(context.Background(), schema.StageConfig{
+		Id:      "targets",
+		Path:    t.TempDir(),
+		Destroy: schema.StageDestroyConfig{Exclude: []string{"random_integer.exclude"}},
+	})
+	if err != nil || !found || !slices.Equal(targets, []string{"random_integer.include"}) {
+		t.Errorf("unexpected destroy targets, %v %v %v", targets, found, err)
+	}
+
+	_, found, err = tf.DestroyTargets(context.Background(), schema.StageConfig{
+		Id:      "none",
+		Path:    t.TempDir(),
+		Destroy: schema.StageDestroyConfig{Include: []string{"module.missing"}},
+	})
+	if err != nil || found {
+		t.Errorf("expected no matching destroy targets, %v %v", found, err)
+	}
+}
+
 func TestTerraformRefresh(t *testing.T) {
 	tf, err := newTestTfClient(t)
 	if err != nil {
@@ -267,6 +514,54 @@ func TestTerraformOutput(t *testing.T) {
 	}
 }
 
+func TestTerraformShow(t *testing.T) {
+	tf, err := newTestTfClient(t)
+	if err != nil {
+		t.Errorf("unexpected error from terraform client constructor, %v", err)
+	}
+
+	defer tf.Cleanup(context.Background())
+
+	stage := newSimpleStageConfig()
+	tf.Init(context.Background(), stage, TerraformInitOpts{})
+	tf.Apply(context.Background(), stage)
+	state, err := tf.Show(context.Background(), stage)
+	if err != nil {
+		t.Errorf("unexpected error from terraform show, %v", err)
+		return
+	}
+
+	addrs := StateAddresses(state)
+	if !slices.Contains(addrs, "terraform_data.simple") {
+		t.Errorf("expected terraform_data.simple in state, found %v", addrs)
+	}
+}
+
+func TestStateAddresses(t *testing.T) {
+	state := &tfjson.State{
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{{Address: "terraform_data.root"}},
+				ChildModules: []*tfjson.StateModule{
+					{
+						Address:   "module.one",
+						Resources: []*tfjson.StateResource{{Address: "module.one.terraform_data.child"}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []string{"terraform_data.root", "module.one.terraform_data.child"}
+	if actual := StateAddresses(state); !slices.Equal(actual, expected) {
+		t.Errorf("unexpected state addresses, expected %v, found %v", expected, actual)
+	}
+
+	if actual := StateAddresses(&tfjson.State{}); len(actual) != 0 {
+		t.Errorf("expected no addresses for an empty state, found %v", actual)
+	}
+}
+
 func TestTerraformApplyVars(t *testing.T) {
 	t.Setenv("TEST_TF_INPUT_1", "testvalue1")
 
@@ -333,7 +628,7 @@ func TestNewTerraformClient(t *testing.T) {
 	client, err := NewTerraformClient(context.Background(), cfg)
 	defer client.Cleanup(context.Background())
 	assert.NoError(t, err, "NewTerraformClient should not return an error")
-	assert.NotNil(t, client, "TerraformClient instance should not be nil")
+	assert.NotEmpty(t, client.execPath, "TerraformClient should have a terraform executable")
 	assert.Equal(t, "1.0.0", client.version, "Terraform version should match")
 }
 
@@ -366,11 +661,67 @@ func TestTerraformClient_getTf(t *testing.T) {
 	defer client.Cleanup(context.Background())
 	assert.NoError(t, err, "NewTerraformClient should not return an error")
 
-	tf, err := client.getTf(tmpDir)
+	tf, err := client.getTf(tmpDir, "")
 	assert.NoError(t, err, "getTf should not return an error")
 	assert.NotNil(t, tf, "Terraform instance should not be nil")
 }
 
+func TestTerraformClient_getTfConcurrent(t *testing.T) {
+	tf := newFakeTfClient(t, false)
+	dir := t.TempDir()
+
+	// validate-all and init-all look up stage clients from several goroutines
+	var wg sync.WaitGroup
+	found := make([]*tfexec.Terraform, 20)
+	for i := range found {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], _ = tf.getTf(dir, "stage"+strconv.Itoa(i%4))
+		}()
+	}
+	wg.Wait()
+
+	for i, f := range found {
+		if assert.NotNil(t, f) {
+			assert.Same(t, found[i%4], f, "stages should share a cached client")
+		}
+	}
+	assert.Len(t, tf.clientCache, 4)
+}
+
+func TestTerraformClient_stageTfConcurrent(t *testing.T) {
+	tf := newFakeTfClient(t, false)
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "main.tf"), `output "a" { value = 1 }`)
+
+	// working directory copies and workspace selections are cached alongside the clients
+	var wg sync.WaitGroup
+	found := make([]*tfexec.Terraform, 20)
+	for i := range found {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stage := schema.StageConfig{Id: "stage" + strconv.Itoa(i%4), Path: src, WorkingDir: true}
+			found[i], _ = tf.stageTf(stage)
+			if found[i] != nil {
+				tf.setWorkspace(found[i], "ws"+strconv.Itoa(i%4))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, f := range found {
+		if assert.NotNil(t, f) {
+			assert.Same(t, found[i%4], f, "stages should share a cached client")
+			assert.Equal(t, filepath.Join(tf.cfg.Config.Tmp, "stages", "stage"+strconv.Itoa(i%4)), f.WorkingDir())
+		}
+	}
+	assert.Len(t, tf.clientCache, 4)
+	assert.Len(t, tf.workDirs, 4)
+	assert.Len(t, tf.workspaces, 4)
+}
+
 func TestTerraformClient_newTfOpts(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := config.Settings{
@@ -409,6 +760,55 @@ func TestInstall(t *testing.T) {
 	assert.NoError(t, err, "Terraform binary should exist in the specified directory")
 }
 
+func TestTerraformLogPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := schema.QuartzConfig{
+		Name: "test",
+		Log: log.LogOptionsConfig{
+			Terraform: log.TerraformLogConfig{
+				Path: filepath.Join(tmpDir, "$name.$date.tf.log"),
+			},
+		},
+	}
+
+	actual := LogPath(cfg, "", time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC))
+	expected := filepath.Join(tmpDir, "test.2025-03-04.tf.log")
+	if actual != expected {
+		t.Errorf("incorrect terraform log path, expected %s, found %s", expected, actual)
+	}
+}
+
+func TestTerraformLogPathStage(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"$name.$stage.tf.log":  "test.first.tf.log",
+		"$name.{stage}.tf.log": "test.first.tf.log",
+		"terraform.log":        "terraform.log",
+	}
+
+	for path, expected := range tests {
+		cfg := schema.QuartzConfig{
+			Name: "test",
+			Log: log.LogOptionsConfig{
+				Terraform: log.TerraformLogConfig{
+					Path: filepath.Join(tmpDir, path),
+				},
+			},
+		}
+
+		actual := LogPath(cfg, "first", now)
+		if actual != filepath.Join(tmpDir, expected) {
+			t.Errorf("incorrect terraform log path for %s, expected %s, found %s", path, expected, actual)
+		}
+
+		if IsPerStageLogPath(cfg) != (path != "terraform.log") {
+			t.Errorf("incorrect per stage detection for %s", path)
+		}
+	}
+}
+
 func TestInitLog(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := schema.QuartzConfig{
@@ -430,13 +830,13 @@ func TestInitLog(t *testing.T) {
 
 	defer tfc.Cleanup(context.Background())
 
-	tf, err := tfc.getTf(t.TempDir())
+	tf, err := tfc.getTf(t.TempDir(), "")
 	if err != nil {
 		t.Errorf("unexpected error from tfexec client constructor, %v", err)
 		return
 	}
 
-	initLog(tf, cfg)
+	initLog(tf, cfg, "")
 
 	// do something to trigger logging
 	tf.Version(context.Background(), true)
@@ -446,6 +846,554 @@ func TestInitLog(t *testing.T) {
 	assert.NoError(t, err, "Terraform log file should exist")
 }
 
+// timedWriter records each write along with the time it happened.
+type timedWriter struct {
+	mu     sync.Mutex
+	writes []string
+	times  []time.Time
+}
+
+func (w *timedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	w.times = append(w.times, time.Now())
+	return len(p), nil
+}
+
+// newFakeTfClient creates a terraform client backed by a fake terraform script, which prints
+// a line, pauses and prints another line for any command other than version. The command is
+// appended to TF_LOG_PATH when logging is enabled, the environment is written to
+// FAKE_TF_ENV_PATH when set, and show prints the state json in FAKE_TF_SHOW_PATH when set.
+// Like the real thing, init writes .terraform and .terraform.lock.hcl to the working directory,
+// and workspace list, show, new and select keep the workspaces in .terraform.
+func newFakeTfClient(t *testing.T, quiet bool) *TerraformClient {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform requires a posix shell")
+	}
+
+	cfg, err := setupTestTfClient(t)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform client setup, %v", err)
+	}
+	cfg.Config.Terraform.Quiet = quiet
+
+	execPath := filepath.Join(t.TempDir(), "terraform")
+	script := `#!/bin/sh
+if [ "$1" = "version" ]; then
+  echo '{"terraform_version":"1.5.7","platform":"linux_amd64","provider_selections":{},"terraform_outdated":false}'
+  exit 0
+fi
+if [ -n "$TF_LOG_PATH" ]; then
+  echo "$*" >> "$TF_LOG_PATH"
+fi
+if [ -n "$FAKE_TF_ENV_PATH" ]; then
+  env > "$FAKE_TF_ENV_PATH"
+fi
+if [ "$1" = "workspace" ]; then
+  mkdir -p .terraform
+  current=$(cat .terraform/environment 2>/dev/null || echo default)
+  eval name=\${$#}
+  case "$2" in
+  list)
+    for w in default $(cat .terraform/workspaces 2>/dev/null); do
+      if [ "$w" = "$current" ]; then echo "* $w"; else echo "  $w"; fi
+    done ;;
+  show) echo "$current" ;;
+  new)
+    if grep -qx "$name" .terraform/workspaces 2>/dev/null; then echo "Workspace \"$name\" already exists" >&2; exit 1; fi
+    echo "$name" >> .terraform/workspaces
+    echo "$name" > .terraform/environment ;;
+  select)
+    if [ "$name" != "default" ] && ! grep -qx "$name" .terraform/workspaces 2>/dev/null; then echo "Workspace \"$name\" doesn't exist." >&2; exit 1; fi
+    echo "$name" > .terraform/environment ;;
+  esac
+  exit 0
+fi
+if [ "$1" = "init" ]; then
+  mkdir -p .terraform
+  echo "$*" > .terraform.lock.hcl
+  echo "$*" > .terraform/backend
+fi
+if [ "$1" = "apply" ] && [ -n "$FAKE_TF_BACKEND_PATH" ]; then
+  cat .terraform/backend > "$FAKE_TF_BACKEND_PATH"
+fi
+if [ "$1" = "show" ] && [ -n "$FAKE_TF_SHOW_PATH" ]; then
+  cat "$FAKE_TF_SHOW_PATH"
+  exit 0
+fi
+echo "Applying..."
+sleep 0.5
+echo "Apply complete!"
+`
+	if err := os.WriteFile(execPath, []byte(script), 0755); err != nil {
+		t.Fatalf("unexpected error writing fake terraform, %v", err)
+	}
+
+	return &TerraformClient{
+		version:     test_version,
+		cfg:         cfg,
+		execPath:    execPath,
+		clientCache: make(map[string]*tfexec.Terraform),
+	}
+}
+
+func TestTerraformApplyStreamsOutput(t *testing.T) {
+	w := &timedWriter{}
+	util.SetWriter(w)
+	defer util.SetWriter(os.Stderr)
+
+	tf := newFakeTfClient(t, false)
+	stage := schema.StageConfig{Id: "stream", Path: t.TempDir(), OverrideVars: true}
+
+	err := tf.Apply(context.Background(), stage)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform apply, %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := strings.Join(w.writes, "")
+	if !strings.Contains(out, "Applying...") || !strings.Contains(out, "Apply complete!") {
+		t.Fatalf("expected terraform output on the console writer, found %q", out)
+	}
+
+	// the first line has to reach the console before terraform exits, not once the run completes
+	if len(w.writes) < 2 {
+		t.Fatalf("expected incremental writes, found %d write(s)", len(w.writes))
+	}
+
+	if gap := w.times[len(w.times)-1].Sub(w.times[0]); gap < 250*time.Millisecond {
+		t.Errorf("expected output streamed as terraform runs, writes were %v apart", gap)
+	}
+}
+
+func TestTerraformApplyQuiet(t *testing.T) {
+	w := &timedWriter{}
+	util.SetWriter(w)
+	defer util.SetWriter(os.Stderr)
+
+	tf := newFakeTfClient(t, true)
+	stage := schema.StageConfig{Id: "quiet", Path: t.TempDir(), OverrideVars: true}
+
+	err := tf.Apply(context.Background(), stage)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform apply, %v", err)
+	}
+
+	if out := strings.Join(w.writes, ""); strings.Contains(out, "Apply") {
+		t.Errorf("expected terraform output discarded when quiet, found %q", out)
+	}
+}
+
+func TestTerraformApplyPerStageLog(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	dir := t.TempDir()
+	tf.cfg.Config.Log.Terraform.Enabled = true
+	tf.cfg.Config.Log.Terraform.Path = filepath.Join(dir, "$stage.tf.log")
+
+	// matrix stages share a directory but still get separate log files
+	path := t.TempDir()
+	for _, id := range []string{"first", "second"} {
+		stage := schema.StageConfig{Id: id, Path: path, OverrideVars: true}
+		err := tf.Apply(context.Background(), stage)
+		if err != nil {
+			t.Fatalf("unexpected error from terraform apply, %v", err)
+		}
+	}
+
+	for _, id := range []string{"first", "second"} {
+		b, err := os.ReadFile(filepath.Join(dir, id+".tf.log"))
+		if err != nil {
+			t.Fatalf("expected log file for stage %s, %v", id, err)
+		}
+
+		if !strings.Contains(string(b), "apply") {
+			t.Errorf("expected terraform apply logged for stage %s, found %q", id, string(b))
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".tf.log")); !os.IsNotExist(err) {
+		t.Errorf("expected no shared log file, found %v", err)
+	}
+}
+
+func TestTerraformApplySharedLog(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	logPath := filepath.Join(t.TempDir(), "terraform.log")
+	tf.cfg.Config.Log.Terraform.Enabled = true
+	tf.cfg.Config.Log.Terraform.Path = logPath
+
+	for _, id := range []string{"first", "second"} {
+		stage := schema.StageConfig{Id: id, Path: t.TempDir(), OverrideVars: true}
+		err := tf.Apply(context.Background(), stage)
+		if err != nil {
+			t.Fatalf("unexpected error from terraform apply, %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected shared log file, %v", err)
+	}
+
+	if n := strings.Count(string(b), "apply"); n != 2 {
+		t.Errorf("expected both applies in the shared log, found %d", n)
+	}
+}
+
+func TestTerraformInitMode(t *testing.T) {
+	tests := []struct {
+		mode     TerraformInitMode
+		expected string
+		excluded string
+	}{
+		{"", "-reconfigure", "-force-copy"},
+		{InitReconfigure, "-reconfigure", "-force-copy"},
+		{InitMigrateState, "-force-copy", "-reconfigure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			tf := newFakeTfClient(t, true)
+			logPath := filepath.Join(t.TempDir(), "terraform.log")
+			tf.cfg.Config.Log.Terraform.Enabled = true
+			tf.cfg.Config.Log.Terraform.Path = logPath
+
+			stage := schema.StageConfig{Id: "mode", Path: t.TempDir()}
+			err := tf.Init(context.Background(), stage, TerraformInitOpts{Mode: tt.mode, BackendConfig: []string{"key=value"}})
+			if err != nil {
+				t.Fatalf("unexpected error from terraform init, %v", err)
+			}
+
+			b, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("expected terraform log, %v", err)
+			}
+
+			args := string(b)
+			if !strings.Contains(args, tt.expected) || strings.Contains(args, tt.excluded) || !strings.Contains(args, "-backend-config=key=value") {
+				t.Errorf("unexpected terraform init args for mode %q, %s", tt.mode, args)
+			}
+		})
+	}
+}
+
+func TestTerraformInitBackendConfig(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	logPath := filepath.Join(t.TempDir(), "terraform.log")
+	tf.cfg.Config.Log.Terraform.Enabled = true
+	tf.cfg.Config.Log.Terraform.Path = logPath
+
+	file := filepath.Join(t.TempDir(), "backend.hcl")
+	os.WriteFile(file, []byte(`bucket = "from-file"`), 0600)
+
+	stage := schema.StageConfig{Id: "backend", Path: t.TempDir()}
+	err := tf.Init(context.Background(), stage, TerraformInitOpts{
+		BackendConfig:      []string{"key=inline"},
+		BackendConfigFiles: []string{file},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected terraform log, %v", err)
+	}
+
+	// the file comes first so the inline settings override it
+	args := string(b)
+	fileIdx := strings.Index(args, "-backend-config="+file)
+	inlineIdx := strings.Index(args, "-backend-config=key=inline")
+	if fileIdx < 0 || inlineIdx < 0 || fileIdx > inlineIdx {
+		t.Errorf("expected the backend config file before the inline backend config, %s", args)
+	}
+}
+
+func TestTerraformInitBackendConfigRelative(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	os.WriteFile("backend.hcl", []byte(`bucket = "from-file"`), 0600)
+
+	args, err := initOptions(TerraformInitOpts{BackendConfigFiles: []string{"backend.hcl"}})
+	if err != nil {
+		t.Fatalf("unexpected error building init options, %v", err)
+	}
+
+	expected := tfexec.BackendConfig(filepath.Join(dir, "backend.hcl"))
+	if !slices.ContainsFunc(args, func(o tfexec.InitOption) bool {
+		bc, ok := o.(*tfexec.BackendConfigOption)
+		return ok && *bc == *expected
+	}) {
+		t.Errorf("expected the backend config file resolved from the working directory, found %v", args)
+	}
+}
+
+func TestTerraformInitBackendConfigMissing(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+
+	missing := filepath.Join(t.TempDir(), "missing.hcl")
+	err := tf.Init(context.Background(), schema.StageConfig{Id: "backend", Path: t.TempDir()}, TerraformInitOpts{BackendConfigFiles: []string{missing}})
+	if err == nil || !strings.Contains(err.Error(), "backend config file "+missing+" not found") {
+		t.Errorf("expected a missing backend config file error, found %v", err)
+	}
+}
+
+func TestTerraformInitModeUnknown(t *testing.T) {
+	_, err := initOptions(TerraformInitOpts{Mode: "bogus"})
+	if err == nil || !strings.Contains(err.Error(), "unknown terraform init mode bogus") {
+		t.Errorf("expected unknown init mode error, found %v", err)
+	}
+}
+
+func TestTerraformWorkingDir(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "main.tf"), `output "a" { value = 1 }`)
+	writeTestFile(t, filepath.Join(src, "removed.tf"), `output "b" { value = 2 }`)
+
+	stage := schema.StageConfig{Id: "copied", Path: src, WorkingDir: true}
+	err := tf.Init(context.Background(), stage, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	// the source is untouched
+	entries, _ := os.ReadDir(src)
+	if len(entries) != 2 {
+		t.Errorf("expected the stage source to be unmodified, found %v", entries)
+	}
+
+	dir := filepath.Join(tf.cfg.Config.Tmp, "stages", "copied")
+	for _, f := range []string{"main.tf", "removed.tf", ".terraform.lock.hcl", ".terraform"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("expected %s in the working directory, %v", f, err)
+		}
+	}
+
+	// the next run picks up source changes and keeps terraform's files
+	if err := os.Remove(filepath.Join(src, "removed.tf")); err != nil {
+		t.Fatalf("unexpected error removing a stage source file, %v", err)
+	}
+	writeTestFile(t, filepath.Join(src, "main.tf"), `output "a" { value = 3 }`)
+	tf.workDirs = nil
+
+	if _, err := tf.stageDir(stage); err != nil {
+		t.Fatalf("unexpected error copying the stage, %v", err)
+	}
+
+	if b, _ := os.ReadFile(filepath.Join(dir, "main.tf")); string(b) != `output "a" { value = 3 }` {
+		t.Errorf("expected the updated source in the working directory, found %s", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "removed.tf")); !os.IsNotExist(err) {
+		t.Errorf("expected removed source files to be removed from the working directory, %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".terraform")); err != nil {
+		t.Errorf("expected .terraform to be kept in the working directory, %v", err)
+	}
+}
+
+func TestTerraformWorkingDirModules(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	root := t.TempDir()
+	for _, d := range []string{"stages/app/local", "modules/one", "modules/two"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0750); err != nil {
+			t.Fatalf("unexpected error creating %s, %v", d, err)
+		}
+	}
+	writeTestFile(t, filepath.Join(root, "stages/app/main.tf"), "module \"one\" {\n  source = \"../../modules/one\"\n}\nmodule \"local\" {\n  source = \"./local\"\n}\n")
+	writeTestFile(t, filepath.Join(root, "stages/app/local/main.tf"), `output "a" { value = 1 }`)
+	writeTestFile(t, filepath.Join(root, "modules/one/main.tf"), "module \"two\" {\n  source = \"../two\"\n}\n")
+	writeTestFile(t, filepath.Join(root, "modules/two/main.tf"), `output "b" { value = 2 }`)
+
+	stage := schema.StageConfig{Id: "app", Path: filepath.Join(root, "stages/app"), WorkingDir: true}
+	err := tf.Init(context.Background(), stage, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	// the stage keeps its position relative to the modules it references
+	base := filepath.Join(tf.cfg.Config.Tmp, "stages", "app")
+	for _, f := range []string{"stages/app/main.tf", "stages/app/local/main.tf", "stages/app/.terraform.lock.hcl", "modules/one/main.tf", "modules/two/main.tf"} {
+		if _, err := os.Stat(filepath.Join(base, f)); err != nil {
+			t.Errorf("expected %s in the working directory, %v", f, err)
+		}
+	}
+
+	// a module containing the stage can't be copied alongside it
+	writeTestFile(t, filepath.Join(root, "stages/app/main.tf"), "module \"all\" {\n  source = \"../\"\n}\n")
+	tf.workDirs = nil
+	if _, err := tf.stageDir(stage); err == nil || !strings.Contains(err.Error(), "contains the stage directory") {
+		t.Errorf("expected a module containing the stage to be rejected, found %v", err)
+	}
+}
+
+func TestTerraformWorkingDirMatrixBackend(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "main.tf"), `output "a" { value = 1 }`)
+
+	// expanded matrix stages share the source directory and run in their own working directory
+	east := schema.StageConfig{Id: "regional-east", Path: src, WorkingDir: true, OverrideVars: true}
+	west := schema.StageConfig{Id: "regional-west", Path: src, WorkingDir: true, OverrideVars: true}
+
+	for _, s := range []schema.StageConfig{east, west} {
+		err := tf.Init(context.Background(), s, TerraformInitOpts{BackendConfig: []string{"key=" + s.Id}})
+		if err != nil {
+			t.Fatalf("unexpected error from terraform init of %s, %v", s.Id, err)
+		}
+	}
+
+	backend := filepath.Join(t.TempDir(), "backend")
+	t.Setenv("FAKE_TF_BACKEND_PATH", backend)
+
+	if err := tf.Apply(context.Background(), east); err != nil {
+		t.Fatalf("unexpected error from terraform apply, %v", err)
+	}
+
+	b, err := os.ReadFile(backend)
+	if err != nil {
+		t.Fatalf("expected the backend used by apply to be recorded, %v", err)
+	}
+	if !strings.Contains(string(b), "-backend-config=key=regional-east") {
+		t.Errorf("expected apply of regional-east to use its own backend key, found %s", b)
+	}
+}
+
+func TestTerraformWorkingDirDisabled(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	src := t.TempDir()
+
+	err := tf.Init(context.Background(), schema.StageConfig{Id: "inplace", Path: src}, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(src, ".terraform.lock.hcl")); err != nil {
+		t.Errorf("expected init to run in the stage source, %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tf.cfg.Config.Tmp, "stages", "inplace")); !os.IsNotExist(err) {
+		t.Errorf("expected no working directory, %v", err)
+	}
+}
+
+func TestTerraformWorkingDirMissingSource(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+
+	stage := schema.StageConfig{Id: "missing", Path: filepath.Join(t.TempDir(), "missing"), WorkingDir: true}
+	err := tf.Init(context.Background(), stage, TerraformInitOpts{})
+	if err == nil || !strings.Contains(err.Error(), "failed to copy stage missing to working directory") {
+		t.Errorf("expected a working directory copy error, found %v", err)
+	}
+}
+
+func TestTerraformWorkspace(t *testing.T) {
+	tf, err := newTestTfClient(t)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform client constructor, %v", err)
+	}
+
+	defer tf.Cleanup(context.Background())
+
+	// the working directory copy goes under tmp, keep it out of the package directory
+	tf.cfg.Config.Tmp = t.TempDir()
+
+	stage := newSimpleStageConfig()
+	stage.Id = "simple"
+	stage.WorkingDir = true // keep workspace state out of testdata
+
+	err = tf.Init(context.Background(), stage, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	err = tf.WorkspaceNew(context.Background(), stage, "dev")
+	if err != nil {
+		t.Fatalf("unexpected error from terraform workspace new, %v", err)
+	}
+
+	ws, current, err := tf.WorkspaceList(context.Background(), stage)
+	if err != nil || current != "dev" || !slices.Contains(ws, "default") {
+		t.Errorf("unexpected terraform workspaces, %v %s %v", ws, current, err)
+	}
+
+	err = tf.WorkspaceSelect(context.Background(), stage, "default")
+	if err != nil {
+		t.Errorf("unexpected error from terraform workspace select, %v", err)
+	}
+
+	// the configured stage workspace is created and selected before apply
+	stage.Workspace = "staging"
+	err = tf.Apply(context.Background(), stage)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform apply, %v", err)
+	}
+
+	ws, current, err = tf.WorkspaceList(context.Background(), stage)
+	if err != nil || current != "staging" || !slices.Contains(ws, "dev") {
+		t.Errorf("unexpected terraform workspaces, %v %s %v", ws, current, err)
+	}
+}
+
+func TestTerraformStageWorkspace(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	stage := schema.StageConfig{Id: "ws", Path: t.TempDir(), OverrideVars: true, Workspace: "dev"}
+
+	// created when missing
+	err := tf.Init(context.Background(), stage, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	ws, current, err := tf.WorkspaceList(context.Background(), stage)
+	if err != nil || current != "dev" || !slices.Equal(ws, []string{"default", "dev"}) {
+		t.Errorf("expected the dev workspace to be created and selected, %v %s %v", ws, current, err)
+	}
+
+	// selected again after switching away
+	err = tf.WorkspaceSelect(context.Background(), stage, "default")
+	if err != nil {
+		t.Fatalf("unexpected error from terraform workspace select, %v", err)
+	}
+
+	err = tf.Apply(context.Background(), stage)
+	if err != nil {
+		t.Fatalf("unexpected error from terraform apply, %v", err)
+	}
+
+	_, current, _ = tf.WorkspaceList(context.Background(), stage)
+	if current != "dev" {
+		t.Errorf("expected the dev workspace to be selected before apply, found %s", current)
+	}
+
+	err = tf.WorkspaceSelect(context.Background(), stage, "missing")
+	var opErr OperationError
+	if !errors.As(err, &opErr) || opErr.Operation != OpWorkspace {
+		t.Errorf("expected a workspace operation error selecting a missing workspace, found %v", err)
+	}
+}
+
+func TestTerraformStageWorkspaceShared(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	dir := t.TempDir()
+	east := schema.StageConfig{Id: "app-east", Path: dir, OverrideVars: true, Workspace: "east"}
+	west := schema.StageConfig{Id: "app-west", Path: dir, OverrideVars: true, Workspace: "west"}
+
+	for _, s := range []schema.StageConfig{east, west, east} {
+		if err := tf.Refresh(context.Background(), s); err != nil {
+			t.Fatalf("unexpected error from terraform refresh, %v", err)
+		}
+
+		b, _ := os.ReadFile(filepath.Join(dir, ".terraform", "environment"))
+		if actual := strings.TrimSpace(string(b)); actual != s.Workspace {
+			t.Errorf("expected workspace %s selected for stage %s, found %s", s.Workspace, s.Id, actual)
+		}
+	}
+}
+
 // newSimpleStageConfig creates a simple stage configuration for testing purposes.
 func newSimpleStageConfig() schema.StageConfig {
 	return schema.StageConfig{
@@ -456,6 +1404,152 @@ func newSimpleStageConfig() schema.StageConfig {
 	}
 }
 
+// writeTestFile writes a test fixture file, failing the test on error.
+func writeTestFile(t *testing.T, path string, content string) {
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("unexpected error writing %s, %v", path, err)
+	}
+}
+
+// fakeTfInitEnv runs init with the fake terraform, returning the environment terraform ran with.
+func fakeTfInitEnv(t *testing.T, tf *TerraformClient) map[string]string {
+	envPath := filepath.Join(t.TempDir(), "env")
+	t.Setenv("FAKE_TF_ENV_PATH", envPath)
+
+	err := tf.Init(context.Background(), schema.StageConfig{Id: "cache", Path: t.TempDir()}, TerraformInitOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error from terraform init, %v", err)
+	}
+
+	b, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("unexpected error reading terraform environment, %v", err)
+	}
+
+	return util.EnvMap(strings.Split(strings.TrimSpace(string(b)), "\n"))
+}
+
+func TestTerraformInitPluginCache(t *testing.T) {
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	os.Unsetenv("TF_PLUGIN_CACHE_DIR")
+
+	tf := newFakeTfClient(t, true)
+	tf.cfg.Config.Terraform.PluginCache.Enabled = true
+
+	expected := filepath.Join(tf.cfg.Config.Tmp, "terraform-plugin-cache")
+	env := fakeTfInitEnv(t, tf)
+	if env["TF_PLUGIN_CACHE_DIR"] != expected {
+		t.Errorf("incorrect TF_PLUGIN_CACHE_DIR, expected %s, found %s", expected, env["TF_PLUGIN_CACHE_DIR"])
+	}
+
+	if fi, err := os.Stat(expected); err != nil || !fi.IsDir() {
+		t.Errorf("expected plugin cache dir %s created, %v", expected, err)
+	}
+
+	// a configured path is used instead
+	tf.cfg.Config.Terraform.PluginCache.Path = filepath.Join(t.TempDir(), "custom", "cache")
+	env = fakeTfInitEnv(t, tf)
+	if env["TF_PLUGIN_CACHE_DIR"] != tf.cfg.Config.Terraform.PluginCache.Path {
+		t.Errorf("incorrect TF_PLUGIN_CACHE_DIR, expected %s, found %s", tf.cfg.Config.Terraform.PluginCache.Path, env["TF_PLUGIN_CACHE_DIR"])
+	}
+
+	if _, err := os.Stat(tf.cfg.Config.Terraform.PluginCache.Path); err != nil {
+		t.Errorf("expected configured plugin cache dir created, %v", err)
+	}
+}
+
+func TestTerraformUsePluginCache(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "cache")
+
+	first := map[string]string{"TF_PLUGIN_CACHE_DIR": base}
+	releaseFirst := usePluginCache(first)
+
+	// a concurrent init gets its own directory instead of waiting
+	second := map[string]string{"TF_PLUGIN_CACHE_DIR": base}
+	releaseSecond := usePluginCache(second)
+	defer releaseSecond()
+
+	if first["TF_PLUGIN_CACHE_DIR"] != base || second["TF_PLUGIN_CACHE_DIR"] != base+"-1" {
+		t.Errorf("expected %s and %s-1, found %s and %s", base, base, first["TF_PLUGIN_CACHE_DIR"], second["TF_PLUGIN_CACHE_DIR"])
+	}
+
+	if _, err := os.Stat(base + "-1"); err != nil {
+		t.Errorf("expected the worker plugin cache created, %v", err)
+	}
+
+	// released directories are reused
+	releaseFirst()
+	third := map[string]string{"TF_PLUGIN_CACHE_DIR": base}
+	defer usePluginCache(third)()
+	if third["TF_PLUGIN_CACHE_DIR"] != base {
+		t.Errorf("expected the released cache %s reused, found %s", base, third["TF_PLUGIN_CACHE_DIR"])
+	}
+
+	// no cache configured
+	none := map[string]string{}
+	usePluginCache(none)()
+	if _, found := none["TF_PLUGIN_CACHE_DIR"]; found {
+		t.Errorf("expected no plugin cache set, found %v", none)
+	}
+}
+
+func TestTerraformInitPluginCacheConcurrent(t *testing.T) {
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	os.Unsetenv("TF_PLUGIN_CACHE_DIR")
+
+	tf := newFakeTfClient(t, true)
+	tf.cfg.Config.Terraform.PluginCache.Enabled = true
+
+	// each fake init takes at least 0.5s, inits sharing the cache must not run one at a time
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = tf.Init(context.Background(), schema.StageConfig{Id: "concurrent" + strconv.Itoa(i), Path: t.TempDir()}, TerraformInitOpts{})
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error from terraform init, %v", err)
+		}
+	}
+
+	if d := time.Since(start); d >= 950*time.Millisecond {
+		t.Errorf("expected concurrent inits to overlap, took %v", d)
+	}
+}
+
+func TestTerraformInitPluginCacheDisabled(t *testing.T) {
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	os.Unsetenv("TF_PLUGIN_CACHE_DIR")
+
+	tf := newFakeTfClient(t, true)
+	tf.cfg.Config.Terraform.PluginCache.Enabled = false
+
+	env := fakeTfInitEnv(t, tf)
+	if v, found := env["TF_PLUGIN_CACHE_DIR"]; found {
+		t.Errorf("expected no TF_PLUGIN_CACHE_DIR when disabled, found %s", v)
+	}
+}
+
+func TestTerraformInitPluginCacheEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TF_PLUGIN_CACHE_DIR", dir)
+
+	tf := newFakeTfClient(t, true)
+	tf.cfg.Config.Terraform.PluginCache.Enabled = true
+
+	env := fakeTfInitEnv(t, tf)
+	if env["TF_PLUGIN_CACHE_DIR"] != dir {
+		t.Errorf("expected TF_PLUGIN_CACHE_DIR from the environment kept, expected %s, found %s", dir, env["TF_PLUGIN_CACHE_DIR"])
+	}
+}
+
 func setupTestTfClient(t *testing.T) (config.Settings, error) {
 	tmp := t.TempDir()
 
@@ -475,7 +1569,7 @@ func setupTestTfClient(t *testing.T) (config.Settings, error) {
 		return lcr, err
 	}
 
-	return lcr, lcr.WriteJsonConfig(filepath.Join(tmp, "quartz.tfvars.json"), "settings", false)
+	return lcr, lcr.WriteJsonConfig(filepath.Join(tmp, "quartz.tfvars.json"), "settings", "", false)
 }
 
 func sharedTestTfClient(t *testing.T) (*TerraformClient, error) {
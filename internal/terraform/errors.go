@@ -0,0 +1,68 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Terraform operations reported by OperationError.
+const (
+	OpInit          = "init"
+	OpForceUnlock   = "force-unlock"
+	OpValidate      = "validate"
+	OpFormat        = "format"
+	OpProvidersLock = "providers lock"
+	OpPlan          = "plan"
+	OpApply         = "apply"
+	OpDestroy       = "destroy"
+	OpRefresh       = "refresh"
+	OpOutput        = "output"
+	OpShow          = "show"
+	OpWorkspace     = "workspace"
+)
+
+// OperationError indicates a terraform operation failed for a stage.
+type OperationError struct {
+	Stage     string // The stage id.
+	Operation string // The terraform operation, e.g. init, plan, apply or destroy.
+	Err       error  // The underlying error.
+}
+
+// NewOperationError wraps the provided error as an OperationError for the stage and operation.
+// Errors already attributed to the same stage and operation are returned unchanged.
+func NewOperationError(stage string, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var opErr OperationError
+	if errors.As(err, &opErr) && opErr.Stage == stage && opErr.Operation == op {
+		return err
+	}
+
+	return OperationError{Stage: stage, Operation: op, Err: err}
+}
+
+// Error returns the error message of the OperationError.
+func (e OperationError) Error() string {
+	return fmt.Sprintf("%s failed for stage %s: %v", e.Operation, e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying error of the OperationError.
+func (e OperationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
+
+func TestNewOperationError(t *testing.T) {
+	if err := NewOperationError("first", OpApply, nil); err != nil {
+		t.Errorf("expected nil for a nil error, found %v", err)
+	}
+
+	cause := errors.New("exit status 1")
+	err := NewOperationError("first", OpApply, cause)
+
+	if err.Error() != "apply failed for stage first: exit status 1" {
+		t.Errorf("unexpected operation error message, %v", err)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("expected the operation error to unwrap to the cause")
+	}
+
+	var opErr OperationError
+	if !errors.As(err, &opErr) || opErr.Stage != "first" || opErr.Operation != OpApply {
+		t.Errorf("unexpected operation error, %+v", opErr)
+	}
+
+	// already attributed errors aren't wrapped again
+	if again := NewOperationError("first", OpApply, err); again != err {
+		t.Errorf("expected the same error back, found %v", again)
+	}
+
+	// a different operation wraps the original
+	if other := NewOperationError("first", OpInit, err); other.Error() != "init failed for stage first: apply failed for stage first: exit status 1" {
+		t.Errorf("unexpected nested operation error message, %v", other)
+	}
+}
+
+func TestTerraformOperationErrors(t *testing.T) {
+	tf := newFakeTfClient(t, true)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	tests := []struct {
+		op  string
+		run func() error
+	}{
+		{OpInit, func() error {
+			return tf.Init(context.Background(), schema.StageConfig{Id: "broken", Path: missing}, TerraformInitOpts{})
+		}},
+		{OpPlan, func() error {
+			_, err := tf.Plan(context.Background(), schema.StageConfig{Id: "broken", Path: missing})
+			return err
+		}},
+		{OpApply, func() error {
+			return tf.Apply(context.Background(), schema.StageConfig{Id: "broken", Debug: schema.StageDebugConfig{Break: true}})
+		}},
+		{OpDestroy, func() error {
+			return tf.Destroy(context.Background(), schema.StageConfig{Id: "broken", Path: missing})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			err := tt.run()
+
+			var opErr OperationError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("expected an OperationError, found %v", err)
+			}
+
+			if opErr.Stage != "broken" || opErr.Operation != tt.op || opErr.Err == nil {
+				t.Errorf("unexpected operation error, %+v", opErr)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -32,6 +34,9 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+// lockIdPattern matches the lock ID line of Terraform's "Lock Info" error block.
+var lockIdPattern = regexp.MustCompile(`(?m)^\s*ID:\s+(\S+)`)
+
 // Version retrieves the version of the Terraform CLI.
 // It runs the `terraform version` command and returns the version string.
 func (c *TerraformClient) Version(ctx context.Context) (string, error) {
@@ -51,54 +56,180 @@ func (c *TerraformClient) Version(ctx context.Context) (string, error) {
 }
 
 // Init initializes the Terraform working directory for the specified stage.
-// It runs `terraform init -upgrade -reconfigure` with the provided backend configuration options.
+// It runs `terraform init -upgrade` with the provided backend configuration options, along with
+// -reconfigure, or -force-copy to migrate existing state, depending on the init mode.
 func (c *TerraformClient) Init(ctx context.Context, stage schema.StageConfig, opts TerraformInitOpts) error {
-	log.Debug("terraform init", "stage", stage)
+	log.Debug("terraform init", "stage", stage, "mode", opts.Mode)
+
+	args, err := initOptions(opts)
+	if err != nil {
+		return NewOperationError(stage.Id, OpInit, err)
+	}
+
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return NewOperationError(stage.Id, OpInit, err)
+	}
+
+	env := c.stageEnv(stage)
+	release := usePluginCache(env)
+	defer release()
+	setTfEnv(tf, stage, env)
+
+	if err := tf.Init(ctx, args...); err != nil {
+		return NewOperationError(stage.Id, OpInit, err)
+	}
+
+	return NewOperationError(stage.Id, OpInit, c.selectWorkspace(ctx, tf, stage))
+}
+
+// MigrateState re-initializes the Terraform working directory for the specified stage against
+// the provided backend configuration, copying any existing state to the new backend.
+// It runs `terraform init -upgrade -force-copy` without -reconfigure so Terraform migrates the state.
+func (c *TerraformClient) MigrateState(ctx context.Context, stage schema.StageConfig, opts TerraformInitOpts) error {
+	opts.Mode = InitMigrateState
+	return c.Init(ctx, stage, opts)
+}
+
+// initOptions builds the `terraform init` options for the init mode and backend configuration.
+// Backend config files are passed ahead of the inline key=value settings, which override them.
+func initOptions(opts TerraformInitOpts) ([]tfexec.InitOption, error) {
+	args := []tfexec.InitOption{tfexec.Upgrade(true)}
+
+	switch opts.Mode {
+	case "", InitReconfigure:
+		args = append(args, tfexec.Reconfigure(true))
+	case InitMigrateState:
+		// without -reconfigure terraform migrates the state, -force-copy skips the confirmation prompt
+		args = append(args, tfexec.ForceCopy(true))
+	default:
+		return nil, fmt.Errorf("unknown terraform init mode %s, expected %s or %s", opts.Mode, InitReconfigure, InitMigrateState)
+	}
+
+	// files first, later -backend-config values take precedence
+	for _, f := range opts.BackendConfigFiles {
+		// terraform resolves relative paths from the stage directory, not ours
+		path, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("backend config file %s not found, %w", f, err)
+		}
+
+		args = append(args, tfexec.BackendConfig(path))
+	}
 
-	var args []tfexec.InitOption
-	args = append(args, tfexec.Upgrade(true))
-	args = append(args, tfexec.Reconfigure(true))
 	for _, bc := range opts.BackendConfig {
 		args = append(args, tfexec.BackendConfig(bc))
 	}
 
-	tf, err := c.getTf(stage.Path)
+	return args, nil
+}
+
+// ForceUnlock removes a stuck state lock for the specified stage.
+// It runs `terraform force-unlock -force <lockId>`.
+func (c *TerraformClient) ForceUnlock(ctx context.Context, stage schema.StageConfig, lockId string) error {
+	log.Debug("terraform force-unlock", "stage", stage, "lockId", lockId)
+
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpForceUnlock, err)
 	}
 	c.setStageEnv(tf, stage)
-	return tf.Init(ctx, args...)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return NewOperationError(stage.Id, OpForceUnlock, err)
+	}
+	return NewOperationError(stage.Id, OpForceUnlock, tf.ForceUnlock(ctx, lockId))
+}
+
+// ParseLockId extracts the lock ID from a Terraform "Error acquiring the state lock" error.
+// It returns false if the error is not a state lock error or no ID could be found.
+func ParseLockId(err error) (string, bool) {
+	if err == nil || !strings.Contains(err.Error(), "state lock") {
+		return "", false
+	}
+
+	m := lockIdPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return "", false
+	}
+
+	return m[1], true
 }
 
 // Validate validates the Terraform configuration for the specified stage.
 // It runs `terraform validate` and returns the validation output.
 func (c *TerraformClient) Validate(ctx context.Context, stage schema.StageConfig) (*tfjson.ValidateOutput, error) {
 	log.Debug("terraform validate", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return nil, err
+		return nil, NewOperationError(stage.Id, OpValidate, err)
 	}
-	return tf.Validate(ctx)
+	v, err := tf.Validate(ctx)
+	return v, NewOperationError(stage.Id, OpValidate, err)
 }
 
 // Format formats the Terraform configuration files in the specified stage directory.
 // It runs `terraform fmt -recursive`.
 func (c *TerraformClient) Format(ctx context.Context, stage schema.StageConfig) error {
 	log.Debug("terraform fmt", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.getTf(stage.Path, stage.Id)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpFormat, err)
 	}
-	return tf.FormatWrite(ctx, tfexec.Recursive(true))
+	return NewOperationError(stage.Id, OpFormat, tf.FormatWrite(ctx, tfexec.Recursive(true)))
+}
+
+// FormatCheck checks the Terraform configuration files in the specified stage directory without
+// rewriting them. It runs `terraform fmt -check -recursive` and returns the files that would change.
+func (c *TerraformClient) FormatCheck(ctx context.Context, stage schema.StageConfig) ([]string, error) {
+	log.Debug("terraform fmt -check", "stage", stage)
+	tf, err := c.getTf(stage.Path, stage.Id)
+	if err != nil {
+		return nil, NewOperationError(stage.Id, OpFormat, err)
+	}
+
+	_, files, err := tf.FormatCheck(ctx, tfexec.Recursive(true))
+	if err != nil {
+		return nil, NewOperationError(stage.Id, OpFormat, err)
+	}
+
+	for i, f := range files {
+		files[i] = filepath.Join(stage.Path, f)
+	}
+
+	return files, nil
+}
+
+// ProvidersLock records provider checksums for the given platforms (e.g. linux_amd64, darwin_arm64)
+// in the stage's .terraform.lock.hcl. It runs `terraform providers lock -platform=...`.
+func (c *TerraformClient) ProvidersLock(ctx context.Context, stage schema.StageConfig, platforms []string) error {
+	log.Debug("terraform providers lock", "stage", stage, "platforms", platforms)
+	tf, err := c.getTf(stage.Path, stage.Id)
+	if err != nil {
+		return NewOperationError(stage.Id, OpProvidersLock, err)
+	}
+	return NewOperationError(stage.Id, OpProvidersLock, tf.ProvidersLock(ctx, providersLockOptions(platforms)...))
+}
+
+// providersLockOptions builds the `terraform providers lock` options for the platforms.
+func providersLockOptions(platforms []string) []tfexec.ProvidersLockOption {
+	var opts []tfexec.ProvidersLockOption
+	for _, p := range platforms {
+		opts = append(opts, tfexec.Platform(p))
+	}
+	return opts
 }
 
 // Plan creates an execution plan for the specified stage.
 // It runs `terraform plan` with the configured input variables and returns whether changes are required.
 func (c *TerraformClient) Plan(ctx context.Context, stage schema.StageConfig) (bool, error) {
 	log.Debug("terraform plan", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return false, err
+		return false, NewOperationError(stage.Id, OpPlan, err)
 	}
 
 	var vars []tfexec.PlanOption
@@ -109,7 +240,11 @@ func (c *TerraformClient) Plan(ctx context.Context, stage schema.StageConfig) (b
 		vars = append(vars, tfexec.VarFile(c.cfg.Config.TfVarFilePath()))
 	}
 	c.setStageEnv(tf, stage)
-	return tf.Plan(ctx, vars...)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return false, NewOperationError(stage.Id, OpPlan, err)
+	}
+	changed, err := tf.Plan(ctx, vars...)
+	return changed, NewOperationError(stage.Id, OpPlan, err)
 }
 
 // Apply applies the Terraform configuration for the specified stage.
@@ -117,13 +252,13 @@ func (c *TerraformClient) Plan(ctx context.Context, stage schema.StageConfig) (b
 func (c *TerraformClient) Apply(ctx context.Context, stage schema.StageConfig) error {
 	if stage.Debug.Break {
 		util.Msgf("Break point at stage %s", stage.Id)
-		return fmt.Errorf("break")
+		return NewOperationError(stage.Id, OpApply, fmt.Errorf("break"))
 	}
 
 	log.Debug("terraform apply", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpApply, err)
 	}
 
 	var vars []tfexec.ApplyOption
@@ -134,8 +269,11 @@ func (c *TerraformClient) Apply(ctx context.Context, stage schema.StageConfig) e
 		vars = append(vars, tfexec.VarFile(c.cfg.Config.TfVarFilePath()))
 	}
 	c.setStageEnv(tf, stage)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return NewOperationError(stage.Id, OpApply, err)
+	}
 
-	return tf.Apply(ctx, vars...)
+	return NewOperationError(stage.Id, OpApply, tf.Apply(ctx, vars...))
 }
 
 // Destroy destroys the Terraform-managed infrastructure for the specified stage.
@@ -143,7 +281,7 @@ func (c *TerraformClient) Apply(ctx context.Context, stage schema.StageConfig) e
 func (c *TerraformClient) Destroy(ctx context.Context, stage schema.StageConfig) error {
 	if stage.Debug.Break {
 		util.Msgf("Break point at stage %s", stage.Id)
-		return fmt.Errorf("break")
+		return NewOperationError(stage.Id, OpDestroy, fmt.Errorf("break"))
 	}
 
 	if stage.Destroy.Skip {
@@ -152,9 +290,9 @@ func (c *TerraformClient) Destroy(ctx context.Context, stage schema.StageConfig)
 	}
 
 	log.Debug("terraform destroy", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpDestroy, err)
 	}
 
 	var vars []tfexec.DestroyOption
@@ -168,9 +306,14 @@ func (c *TerraformClient) Destroy(ctx context.Context, stage schema.StageConfig)
 		vars = append(vars, tfexec.VarFile(c.cfg.Config.TfVarFilePath()))
 	}
 
+	c.setStageEnv(tf, stage)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return NewOperationError(stage.Id, OpDestroy, err)
+	}
+
 	targets, found, err := targetsToDestroy(ctx, tf, stage)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpDestroy, err)
 	}
 
 	if !found {
@@ -183,17 +326,143 @@ func (c *TerraformClient) Destroy(ctx context.Context, stage schema.StageConfig)
 		vars = append(vars, tfexec.Target(t))
 	}
 
+	return NewOperationError(stage.Id, OpDestroy, tf.Destroy(ctx, vars...))
+}
+
+// DestroyTargets resolves the resources a destroy of the specified stage removes, from the
+// stage's destroy include and exclude filters. A nil target list is a full destroy, found is
+// false when the filters matched nothing and the destroy would be skipped.
+func (c *TerraformClient) DestroyTargets(ctx context.Context, stage schema.StageConfig) ([]string, bool, error) {
+	log.Debug("terraform destroy targets", "stage", stage)
+	if len(stage.Destroy.Include) == 0 && len(stage.Destroy.Exclude) == 0 {
+		return nil, true, nil
+	}
+
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return nil, false, NewOperationError(stage.Id, OpShow, err)
+	}
+
+	c.setStageEnv(tf, stage)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return nil, false, NewOperationError(stage.Id, OpShow, err)
+	}
+	targets, found, err := targetsToDestroy(ctx, tf, stage)
+	if err != nil {
+		return nil, false, NewOperationError(stage.Id, OpShow, err)
+	}
+
+	return targets, found, nil
+}
+
+// WorkspaceList lists the Terraform workspaces for the specified stage.
+// It returns the workspace names and the currently selected workspace.
+func (c *TerraformClient) WorkspaceList(ctx context.Context, stage schema.StageConfig) ([]string, string, error) {
+	log.Debug("terraform workspace list", "stage", stage)
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return nil, "", NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
 	c.setStageEnv(tf, stage)
-	return tf.Destroy(ctx, vars...)
+	ws, current, err := tf.WorkspaceList(ctx)
+	if err != nil {
+		return nil, "", NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
+	return ws, current, nil
+}
+
+// WorkspaceSelect selects an existing Terraform workspace for the specified stage.
+func (c *TerraformClient) WorkspaceSelect(ctx context.Context, stage schema.StageConfig, name string) error {
+	log.Debug("terraform workspace select", "stage", stage, "workspace", name)
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
+	c.setStageEnv(tf, stage)
+	if err := tf.WorkspaceSelect(ctx, name); err != nil {
+		return NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
+	c.setWorkspace(tf, name)
+	return nil
+}
+
+// WorkspaceNew creates and selects a new Terraform workspace for the specified stage.
+func (c *TerraformClient) WorkspaceNew(ctx context.Context, stage schema.StageConfig, name string) error {
+	log.Debug("terraform workspace new", "stage", stage, "workspace", name)
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
+	c.setStageEnv(tf, stage)
+	if err := tf.WorkspaceNew(ctx, name); err != nil {
+		return NewOperationError(stage.Id, OpWorkspace, err)
+	}
+
+	c.setWorkspace(tf, name)
+	return nil
+}
+
+// selectWorkspace selects the stage's configured workspace before running, creating it when
+// missing. The selection is kept in the directory's .terraform, so it's only checked again when
+// another stage sharing the directory selected a different workspace this run.
+func (c *TerraformClient) selectWorkspace(ctx context.Context, tf *tfexec.Terraform, stage schema.StageConfig) error {
+	if stage.Workspace == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	current := c.workspaces[tf.WorkingDir()]
+	c.mu.Unlock()
+
+	if current == stage.Workspace {
+		return nil
+	}
+
+	ws, current, err := tf.WorkspaceList(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current == stage.Workspace:
+	case slices.Contains(ws, stage.Workspace):
+		log.Debug("Selecting terraform workspace", "stage", stage.Id, "workspace", stage.Workspace)
+		err = tf.WorkspaceSelect(ctx, stage.Workspace)
+	default:
+		log.Info("Creating terraform workspace", "stage", stage.Id, "workspace", stage.Workspace)
+		err = tf.WorkspaceNew(ctx, stage.Workspace)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.setWorkspace(tf, stage.Workspace)
+	return nil
+}
+
+// setWorkspace records the workspace selected in the terraform working directory this run.
+func (c *TerraformClient) setWorkspace(tf *tfexec.Terraform, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.workspaces == nil {
+		c.workspaces = make(map[string]string)
+	}
+	c.workspaces[tf.WorkingDir()] = name
 }
 
 // Refresh updates the Terraform state for the specified stage.
 // It runs `terraform refresh` with the configured input variables.
 func (c *TerraformClient) Refresh(ctx context.Context, stage schema.StageConfig) error {
 	log.Debug("terraform refresh", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return err
+		return NewOperationError(stage.Id, OpRefresh, err)
 	}
 
 	var vars []tfexec.RefreshCmdOption
@@ -204,21 +473,28 @@ func (c *TerraformClient) Refresh(ctx context.Context, stage schema.StageConfig)
 		vars = append(vars, tfexec.VarFile(c.cfg.Config.TfVarFilePath()))
 	}
 	c.setStageEnv(tf, stage)
-	return tf.Refresh(ctx, vars...)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return NewOperationError(stage.Id, OpRefresh, err)
+	}
+	return NewOperationError(stage.Id, OpRefresh, tf.Refresh(ctx, vars...))
 }
 
 // Output retrieves the Terraform output for the specified stage directory.
 // It returns a map of output variable names to their values in JSON format.
 func (c *TerraformClient) Output(ctx context.Context, stage schema.StageConfig) (map[string][]byte, error) {
 	log.Debug("terraform output", "stage", stage)
-	tf, err := c.getTf(stage.Path)
+	tf, err := c.stageTf(stage)
 	if err != nil {
-		return nil, err
+		return nil, NewOperationError(stage.Id, OpOutput, err)
 	}
 
+	c.setStageEnv(tf, stage)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return nil, NewOperationError(stage.Id, OpOutput, err)
+	}
 	output, err := tf.Output(ctx)
 	if err != nil {
-		return nil, err
+		return nil, NewOperationError(stage.Id, OpOutput, err)
 	}
 
 	res := make(map[string][]byte)
@@ -231,8 +507,99 @@ func (c *TerraformClient) Output(ctx context.Context, stage schema.StageConfig)
 	return res, nil
 }
 
+// Show retrieves the current Terraform state for the specified stage.
+func (c *TerraformClient) Show(ctx context.Context, stage schema.StageConfig) (*tfjson.State, error) {
+	log.Debug("terraform show", "stage", stage)
+	tf, err := c.stageTf(stage)
+	if err != nil {
+		return nil, NewOperationError(stage.Id, OpShow, err)
+	}
+
+	c.setStageEnv(tf, stage)
+	if err := c.selectWorkspace(ctx, tf, stage); err != nil {
+		return nil, NewOperationError(stage.Id, OpShow, err)
+	}
+	state, err := tf.Show(ctx)
+	if err != nil {
+		return nil, NewOperationError(stage.Id, OpShow, err)
+	}
+
+	return state, nil
+}
+
+// StateAddresses returns the addresses of all resources in the state, including those in child modules.
+func StateAddresses(state *tfjson.State) []string {
+	if state == nil || state.Values == nil || state.Values.RootModule == nil {
+		return nil
+	}
+
+	var res []string
+	var walk func(*tfjson.StateModule)
+	walk = func(mod *tfjson.StateModule) {
+		for _, r := range mod.Resources {
+			res = append(res, r.Address)
+		}
+
+		for _, m := range mod.ChildModules {
+			walk(m)
+		}
+	}
+	walk(state.Values.RootModule)
+
+	return res
+}
+
+// usePluginCache points env at a plugin cache directory no other init is using, terraform doesn't
+// support concurrent installs into a shared cache. The configured directory is used when it's
+// free, concurrent inits (e.g. init-all workers) get <dir>-<n> so they don't wait on each other.
+// Returns the func releasing the directory once init completes.
+func usePluginCache(env map[string]string) func() {
+	base := env["TF_PLUGIN_CACHE_DIR"]
+	if base == "" {
+		return func() {}
+	}
+
+	pluginCacheMu.Lock()
+	defer pluginCacheMu.Unlock()
+
+	dir := base
+	for i := 1; pluginCacheInUse[dir]; i++ {
+		dir = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Warn("Failed to create terraform plugin cache, continuing without it", "dir", dir, "err", err)
+		delete(env, "TF_PLUGIN_CACHE_DIR")
+		return func() {}
+	}
+
+	if pluginCacheInUse == nil {
+		pluginCacheInUse = make(map[string]bool)
+	}
+	pluginCacheInUse[dir] = true
+	env["TF_PLUGIN_CACHE_DIR"] = dir
+
+	return func() {
+		pluginCacheMu.Lock()
+		defer pluginCacheMu.Unlock()
+		delete(pluginCacheInUse, dir)
+	}
+}
+
 // setStageEnv sets the environment variables for the Terraform process based on the stage configuration.
 func (c *TerraformClient) setStageEnv(tf *tfexec.Terraform, stage schema.StageConfig) {
+	setTfEnv(tf, stage, c.stageEnv(stage))
+}
+
+// setTfEnv sets the environment of the Terraform process, logging any failure.
+func setTfEnv(tf *tfexec.Terraform, stage schema.StageConfig, env map[string]string) {
+	if err := tf.SetEnv(env); err != nil {
+		log.Warn("Failed to set terraform environment", "stage", stage, "err", err)
+	}
+}
+
+// stageEnv returns the environment variables for the Terraform process based on the stage configuration.
+func (c *TerraformClient) stageEnv(stage schema.StageConfig) map[string]string {
 	env := util.OsEnvMap()
 
 	if stage.Providers.Kubernetes {
@@ -241,9 +608,17 @@ func (c *TerraformClient) setStageEnv(tf *tfexec.Terraform, stage schema.StageCo
 		})
 	}
 
-	if err := tf.SetEnv(env); err != nil {
-		log.Warn("Failed to set terraform environment", "stage", stage, "err", err)
+	// share downloaded providers across stages, unless the environment already points at a cache
+	if _, found := env["TF_PLUGIN_CACHE_DIR"]; !found && c.cfg.Config.Terraform.PluginCache.Enabled {
+		dir := c.cfg.Config.TfPluginCachePath()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Warn("Failed to create terraform plugin cache, continuing without it", "dir", dir, "err", err)
+		} else {
+			env["TF_PLUGIN_CACHE_DIR"] = dir
+		}
 	}
+
+	return env
 }
 
 // stageVars generates the input variables for the specified stage based on its configuration.
@@ -11,3 +11,7 @@ output "var1" {
 output "var2" {
   value = var.settings.dns.domain
 }
+
+resource "terraform_data" "simple" {
+  input = var.value_input
+}
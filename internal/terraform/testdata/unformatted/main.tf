@@ -0,0 +1,8 @@
+variable "value_input" {
+    type=string
+    default = "literal"
+}
+
+output "var1" {
+  value=var.value_input
+}
@@ -45,6 +45,9 @@ const (
 var (
 	writer io.Writer = os.Stderr
 
+	// quiet suppresses decorative output, see SetQuiet
+	quiet = false
+
 	width = 100
 
 	hdrStyle = lipgloss.NewStyle().
@@ -92,39 +95,94 @@ func SetWriter(w io.Writer) {
 	writer = w
 }
 
-// Hdr prints a header-formatted string to the console.
+// SetQuiet sets whether headers, messages and text are suppressed. Errors, Info messages,
+// tables and data written directly to stdout are still printed.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// IsQuiet returns true if decorative output is suppressed, by SetQuiet or the QUARTZ_QUIET env.
+func IsQuiet() bool {
+	return quiet || os.Getenv("QUARTZ_QUIET") != ""
+}
+
+// consoleWriter forwards writes to the console writer current at the time of the write.
+type consoleWriter struct{}
+
+// Write writes p to the console writer.
+func (consoleWriter) Write(p []byte) (int, error) {
+	return writer.Write(p)
+}
+
+// ConsoleWriter returns a writer streaming to the console, following later calls to SetWriter.
+func ConsoleWriter() io.Writer {
+	return consoleWriter{}
+}
+
+// Hdr prints a header-formatted string to the console, unless quiet.
 func Hdr(a ...any) {
 	log.Debug("Formatted Header", "content", fmt.Sprint(a...))
+	if IsQuiet() {
+		return
+	}
 	println(writer, &hdrStyle, a...)
 }
 
-// Hdrf prints a formatted header string to the console.
+// Hdrf prints a formatted header string to the console, unless quiet.
 func Hdrf(format string, a ...any) {
 	log.Debug("Formatted Header", "content", fmt.Sprintf(format, a...))
+	if IsQuiet() {
+		return
+	}
 	printfln(writer, &hdrStyle, format, a...)
 }
 
-// Msg prints a message-formatted string to the console.
+// Msg prints a message-formatted string to the console, unless quiet.
 func Msg(a ...any) {
 	log.Debug("Formatted Message", "content", fmt.Sprint(a...))
+	if IsQuiet() {
+		return
+	}
 	println(writer, &msgStyle, a...)
 }
 
-// Msgf prints a formatted message string to the console.
+// Msgf prints a formatted message string to the console, unless quiet.
 func Msgf(format string, a ...any) {
+	log.Debug("Formatted Message", "content", fmt.Sprintf(format, a...))
+	if IsQuiet() {
+		return
+	}
+	printfln(writer, &msgStyle, format, a...)
+}
+
+// Info prints a message-formatted string to the console, even when quiet. Used for the
+// prompt context, data and table titles the user needs to see.
+func Info(a ...any) {
+	log.Debug("Formatted Message", "content", fmt.Sprint(a...))
+	println(writer, &msgStyle, a...)
+}
+
+// Infof prints a formatted message string to the console, even when quiet.
+func Infof(format string, a ...any) {
 	log.Debug("Formatted Message", "content", fmt.Sprintf(format, a...))
 	printfln(writer, &msgStyle, format, a...)
 }
 
-// Print prints a formatted string to the console.
+// Print prints a formatted string to the console, unless quiet.
 func Print(a ...any) {
 	log.Debug("Formatted Text", "content", fmt.Sprint(a...))
+	if IsQuiet() {
+		return
+	}
 	println(writer, &txtStyle, a...)
 }
 
-// Printf prints a formatted string to the console.
+// Printf prints a formatted string to the console, unless quiet.
 func Printf(format string, a ...any) {
 	log.Debug("Formatted Text", "content", fmt.Sprintf(format, a...))
+	if IsQuiet() {
+		return
+	}
 	printfln(writer, &txtStyle, format, a...)
 }
 
@@ -202,6 +260,71 @@ func PromptYesNo(msg string) bool {
 	return r
 }
 
+// PromptInput displays a text input prompt to the console and returns the entered value.
+// Unlike PromptYesNo, silent mode doesn't answer the prompt.
+func PromptInput(msg string) (string, error) {
+	log.Debug("Formatted Input Prompt", "message", msg)
+
+	var r string
+
+	accessible := os.Getenv("ACCESSIBLE") != ""
+
+	err := huh.NewInput().
+		Title(msg).
+		Value(&r).
+		WithAccessible(accessible).
+		Run() // blocking
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(r), nil
+}
+
+// PromptMultiSelect displays a multiselect prompt with every option selected by default
+// and returns the options left selected.
+func PromptMultiSelect(msg string, options []string) ([]string, error) {
+	log.Debug("Formatted MultiSelect Prompt", "message", msg, "options", options)
+
+	var r []string
+
+	accessible := os.Getenv("ACCESSIBLE") != ""
+
+	opts := make([]huh.Option[string], len(options))
+	for i, o := range options {
+		opts[i] = huh.NewOption(o, o).Selected(true)
+	}
+
+	err := huh.NewMultiSelect[string]().
+		Title(msg).
+		Options(opts...).
+		Value(&r).
+		WithAccessible(accessible).
+		Run() // blocking
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// IsInteractive reports whether prompts can be shown, i.e. silent mode is disabled and
+// both stdin and stdout are attached to a terminal.
+func IsInteractive() bool {
+	if os.Getenv("SILENT") != "" {
+		return false
+	}
+
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		fi, err := f.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // PrintBanner prints the Quartz ASCII art banner to the console.
 func PrintBanner() {
 	log.Debug("Printing ASCII banner")
@@ -16,6 +16,7 @@ package util
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +36,55 @@ func TestConsoleWrite(t *testing.T) {
 	Errorf(msgF, arg)
 }
 
+func TestConsoleQuiet(t *testing.T) {
+	var buf strings.Builder
+	SetWriter(&buf)
+	defer SetWriter(os.Stderr)
+
+	SetQuiet(true)
+	defer SetQuiet(false)
+
+	Hdr("header")
+	Hdrf("header %s", "f")
+	Msg("message")
+	Msgf("message %s", "f")
+	Print("text")
+	Printf("text %s", "f")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected decorative output to be suppressed when quiet, found %s", buf.String())
+	}
+
+	Error("error")
+	Errorf("error %s", "f")
+
+	if !strings.Contains(buf.String(), "error") || !strings.Contains(buf.String(), "error f") {
+		t.Errorf("expected errors to be printed when quiet, found %s", buf.String())
+	}
+
+	Info("info")
+	Infof("info %s", "f")
+
+	if !strings.Contains(buf.String(), "info") || !strings.Contains(buf.String(), "info f") {
+		t.Errorf("expected info messages to be printed when quiet, found %s", buf.String())
+	}
+
+	SetQuiet(false)
+	t.Setenv("QUARTZ_QUIET", "1")
+	buf.Reset()
+
+	Msg("message")
+	if buf.Len() != 0 {
+		t.Errorf("expected output to be suppressed with QUARTZ_QUIET, found %s", buf.String())
+	}
+
+	t.Setenv("QUARTZ_QUIET", "")
+	Msg("message")
+	if !strings.Contains(buf.String(), "message") {
+		t.Errorf("expected message to be printed when not quiet, found %s", buf.String())
+	}
+}
+
 func TestConsoleTable(t *testing.T) {
 	headers := []string{"col1", "col2"}
 	rows := [][]string{
@@ -99,3 +149,51 @@ func TestConsolePromptYesNoSilent(t *testing.T) {
 func TestConsolePrintBanner(t *testing.T) {
 	PrintBanner()
 }
+
+func TestConsolePromptInput(t *testing.T) {
+	r, w, _ := os.Pipe()
+	w.Write([]byte("  mycluster \n"))
+	w.Close()
+
+	// Temporarily replace os.Stdin with our buffer
+	defer func(v *os.File) { os.Stdin = v }(os.Stdin)
+	os.Stdin = r
+
+	t.Setenv("ACCESSIBLE", "1")
+	res, err := PromptInput("this is a test")
+	if err != nil {
+		t.Errorf("unexpected error from input prompt, %v", err)
+	}
+
+	if res != "mycluster" {
+		t.Errorf("unexpected response from input prompt, expected %v, found %v", "mycluster", res)
+	}
+}
+
+func TestConsolePromptMultiSelect(t *testing.T) {
+	r, w, _ := os.Pipe()
+	w.Write([]byte("2\n0\n"))
+	w.Close()
+
+	// Temporarily replace os.Stdin with our buffer
+	defer func(v *os.File) { os.Stdin = v }(os.Stdin)
+	os.Stdin = r
+
+	t.Setenv("ACCESSIBLE", "1")
+	res, err := PromptMultiSelect("this is a test", []string{"one", "two", "three"})
+	if err != nil {
+		t.Errorf("unexpected error from multiselect prompt, %v", err)
+	}
+
+	// every option starts selected, toggling the second deselects it
+	if strings.Join(res, ",") != "one,three" {
+		t.Errorf("unexpected response from multiselect prompt, expected %v, found %v", "one,three", res)
+	}
+}
+
+func TestConsoleIsInteractiveSilent(t *testing.T) {
+	t.Setenv("SILENT", "1")
+	if IsInteractive() {
+		t.Error("expected non-interactive when silent")
+	}
+}
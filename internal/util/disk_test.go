@@ -0,0 +1,32 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "testing"
+
+func TestFreeDiskSpace(t *testing.T) {
+	free, err := FreeDiskSpace(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error looking up free disk space, %v", err)
+	}
+
+	if free == 0 {
+		t.Errorf("expected free disk space in the temp dir")
+	}
+
+	if _, err := FreeDiskSpace("/nonexistent/quartz/path"); err == nil {
+		t.Errorf("expected error looking up free disk space for a missing path")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "fmt"
+
+// ConfigError indicates a failure caused by invalid or missing configuration.
+type ConfigError struct {
+	Err error // The underlying error.
+}
+
+// NewConfigError wraps the provided error as a ConfigError.
+func NewConfigError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return ConfigError{Err: err}
+}
+
+// NewConfigErrorf formats a new ConfigError.
+func NewConfigErrorf(format string, a ...any) error {
+	return ConfigError{Err: fmt.Errorf(format, a...)}
+}
+
+// Error returns the error message of the ConfigError.
+func (e ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error of the ConfigError.
+func (e ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// AccessError indicates a failure to access a required provider.
+type AccessError struct {
+	Provider string // The name of the provider that could not be accessed.
+	Err      error  // The underlying error.
+}
+
+// NewAccessError wraps the provided error as an AccessError for the named provider.
+func NewAccessError(provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return AccessError{Provider: provider, Err: err}
+}
+
+// Error returns the error message of the AccessError.
+func (e AccessError) Error() string {
+	return fmt.Sprintf("%s access failed, %v", e.Provider, e.Err)
+}
+
+// Unwrap returns the underlying error of the AccessError.
+func (e AccessError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestUtilConfigError(t *testing.T) {
+	if err := NewConfigError(nil); err != nil {
+		t.Errorf("expected nil for nil config error, found %v", err)
+	}
+
+	inner := fmt.Errorf("inner")
+	err := NewConfigError(inner)
+
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("expected ConfigError, found %T", err)
+	}
+
+	if !errors.Is(err, inner) {
+		t.Errorf("expected config error to wrap %v", inner)
+	}
+
+	if err.Error() != "inner" {
+		t.Errorf("unexpected config error message, %s", err.Error())
+	}
+
+	err = NewConfigErrorf("bad value %s", "x")
+	if !errors.As(err, &cfgErr) || err.Error() != "bad value x" {
+		t.Errorf("unexpected formatted config error, %v", err)
+	}
+}
+
+func TestUtilAccessError(t *testing.T) {
+	if err := NewAccessError("test", nil); err != nil {
+		t.Errorf("expected nil for nil access error, found %v", err)
+	}
+
+	inner := fmt.Errorf("denied")
+	err := NewAccessError("test", inner)
+
+	var accessErr AccessError
+	if !errors.As(err, &accessErr) {
+		t.Errorf("expected AccessError, found %T", err)
+	}
+
+	if accessErr.Provider != "test" {
+		t.Errorf("unexpected access error provider, %s", accessErr.Provider)
+	}
+
+	if !errors.Is(err, inner) {
+		t.Errorf("expected access error to wrap %v", inner)
+	}
+
+	if err.Error() != "test access failed, denied" {
+		t.Errorf("unexpected access error message, %s", err.Error())
+	}
+}
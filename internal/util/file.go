@@ -16,6 +16,8 @@ package util
 
 import (
 	"bufio"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -72,3 +74,63 @@ func WriteBytesToFile(b []byte, path string) error {
 
 	return nil
 }
+
+// CopyDir recursively copies the src directory into dst, overwriting existing files and
+// preserving file modes and symlinks. Entries for which skip returns true, given the path
+// relative to src, aren't copied.
+func CopyDir(src string, dst string, skip func(rel string, d fs.DirEntry) bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if rel != "." && skip != nil && skip(rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0740)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		default:
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return copyFile(path, target, info.Mode())
+		}
+	})
+}
+
+// copyFile copies the contents of the src file to dst, created with the given mode.
+func copyFile(src string, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
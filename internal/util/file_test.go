@@ -15,8 +15,10 @@
 package util
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -57,3 +59,40 @@ func TestUtilWriteBytesToFile(t *testing.T) {
 		t.Errorf("file contents don't match, expected %s, found %s", b, actual)
 	}
 }
+
+func TestUtilCopyDir(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "main.tf"), []byte("source"), 0600)
+	os.MkdirAll(filepath.Join(src, "nested"), 0740)
+	os.WriteFile(filepath.Join(src, "nested", "run.sh"), []byte("#!/bin/sh"), 0700)
+	os.MkdirAll(filepath.Join(src, ".terraform"), 0740)
+	os.WriteFile(filepath.Join(src, ".terraform", "skipped"), []byte("skipped"), 0600)
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	os.MkdirAll(dst, 0740)
+	os.WriteFile(filepath.Join(dst, "main.tf"), []byte("stale contents to overwrite"), 0600)
+
+	err := CopyDir(src, dst, func(rel string, d fs.DirEntry) bool {
+		return d.IsDir() && d.Name() == ".terraform"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error copying %s, %v", src, err)
+	}
+
+	actual, err := os.ReadFile(filepath.Join(dst, "main.tf"))
+	if err != nil || string(actual) != "source" {
+		t.Errorf("file contents don't match, expected source, found %s (%v)", actual, err)
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "nested", "run.sh"))
+	if err != nil {
+		t.Fatalf("expected nested file to be copied, %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		t.Errorf("file mode not preserved, expected 0700, found %v", info.Mode().Perm())
+	}
+
+	if _, err := os.Stat(filepath.Join(dst, ".terraform")); !os.IsNotExist(err) {
+		t.Errorf("expected skipped directory not to be copied, %v", err)
+	}
+}
@@ -22,18 +22,33 @@ var (
 	runOnceStore sync.Map
 )
 
+// runOnceEntry holds the result of a RunOnce key, concurrent callers wait on the first run.
+type runOnceEntry struct {
+	once sync.Once
+	err  error
+}
+
 // RunOnce ensures that a function identified by the
 // given key is only executed the first time. Subequent calls
 // will return a cached response.
+//
+// Keys are process-global and live until ResetRunOnce is called,
+// so repeated sub-invocations within one CLI run (e.g. each stage
+// running tf:prep) share the first result. Concurrent calls with the
+// same key block until the first call completes.
 func RunOnce(key string, f func() error) error {
-	if v, loaded := runOnceStore.Load(key); loaded {
-		if v != nil {
-			return v.(error)
-		}
-		return nil
-	}
+	v, _ := runOnceStore.LoadOrStore(key, &runOnceEntry{})
+	e := v.(*runOnceEntry)
+	e.once.Do(func() {
+		e.err = f()
+	})
+
+	return e.err
+}
 
-	err := f()
-	runOnceStore.Store(key, err)
-	return err
+// ResetRunOnce clears all cached RunOnce results so that every key
+// executes again on its next call. Intended for tests and long-lived
+// library usage that runs multiple independent commands.
+func ResetRunOnce() {
+	runOnceStore.Clear()
 }
@@ -14,7 +14,11 @@
 
 package util
 
-import "testing"
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
 
 func TestRunOnce(t *testing.T) {
 	count := 0
@@ -47,3 +51,48 @@ func TestRunOnce(t *testing.T) {
 		t.Errorf("unexpected run count in RunOnce third call, expected 2, found %d", count)
 	}
 }
+
+func TestResetRunOnce(t *testing.T) {
+	count := 0
+	countFunc := func() error {
+		count = count + 1
+		return nil
+	}
+
+	RunOnce("reset", countFunc)
+	RunOnce("reset", countFunc)
+
+	if count != 1 {
+		t.Errorf("unexpected run count in RunOnce before reset, expected 1, found %d", count)
+	}
+
+	// after reset the previously run key executes again
+	ResetRunOnce()
+	RunOnce("reset", countFunc)
+
+	if count != 2 {
+		t.Errorf("unexpected run count in RunOnce after reset, expected 2, found %d", count)
+	}
+}
+
+func TestRunOnceConcurrent(t *testing.T) {
+	var count atomic.Int32
+	countFunc := func() error {
+		count.Add(1)
+		return nil
+	}
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RunOnce("concurrent", countFunc)
+		}()
+	}
+	wg.Wait()
+
+	if c := count.Load(); c != 1 {
+		t.Errorf("unexpected run count in concurrent RunOnce, expected 1, found %d", c)
+	}
+}
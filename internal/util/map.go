@@ -34,6 +34,45 @@ func MergeMaps[K comparable, V interface{}](maps ...map[K]V) map[K]V {
 	return merged
 }
 
+// DeepMergeMaps recursively merges two or more nested maps into a new map.
+// Later maps take precedence, nested maps are merged key by key while slices and
+// all other values are replaced. Nested map[string]string values are merged as
+// map[string]interface{}, and the input maps are never modified.
+func DeepMergeMaps(maps ...map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+
+	for _, m := range maps {
+		for k, v := range m {
+			src, ok := nestedMap(v)
+			if !ok {
+				merged[k] = v
+				continue
+			}
+
+			dst, _ := nestedMap(merged[k])
+			merged[k] = DeepMergeMaps(dst, src)
+		}
+	}
+
+	return merged
+}
+
+// nestedMap returns v as a map[string]interface{} if it is a map DeepMergeMaps can merge.
+func nestedMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[string]string:
+		r := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			r[k] = v
+		}
+		return r, true
+	}
+
+	return nil, false
+}
+
 // MapContainsKey checks if a map contains a specific key.
 func MapContainsKey[K comparable, V interface{}](m map[K]V, key K) bool {
 	_, ok := m[key]
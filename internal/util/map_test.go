@@ -14,7 +14,12 @@
 
 package util
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MetroStar/quartzctl/internal/config/schema"
+)
 
 // https://dave.cheney.net/2013/06/30/how-to-write-benchmarks-in-go
 var benchmarkResult map[string]string
@@ -90,6 +95,121 @@ func BenchmarkMergeMaps(b *testing.B) {
 	benchmarkResult = r
 }
 
+func TestDeepMergeMapsNested(t *testing.T) {
+	base := map[string]interface{}{
+		"name": "base",
+		"tags": []string{"a", "b"},
+		"nested": map[string]interface{}{
+			"keep":     "base",
+			"override": "base",
+			"deeper": map[string]interface{}{
+				"keep":     1,
+				"override": 1,
+			},
+		},
+		"replaced": map[string]interface{}{
+			"gone": true,
+		},
+	}
+	overlay := map[string]interface{}{
+		"tags": []string{"c"},
+		"nested": map[string]interface{}{
+			"override": "overlay",
+			"deeper": map[string]interface{}{
+				"override": 2,
+				"added":    3,
+			},
+		},
+		"replaced": "scalar",
+	}
+
+	expected := map[string]interface{}{
+		"name": "base",
+		"tags": []string{"c"},
+		"nested": map[string]interface{}{
+			"keep":     "base",
+			"override": "overlay",
+			"deeper": map[string]interface{}{
+				"keep":     1,
+				"override": 2,
+				"added":    3,
+			},
+		},
+		"replaced": "scalar",
+	}
+
+	actual := DeepMergeMaps(base, overlay)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected deep merge result, expected %v, found %v", expected, actual)
+	}
+
+	// inputs are left untouched
+	if base["nested"].(map[string]interface{})["override"] != "base" {
+		t.Errorf("deep merge modified its input, %v", base)
+	}
+
+	actual["nested"].(map[string]interface{})["keep"] = "changed"
+	if base["nested"].(map[string]interface{})["keep"] != "base" {
+		t.Errorf("deep merge result shares nested maps with its input, %v", base)
+	}
+}
+
+func TestDeepMergeMapsKeycloakMappers(t *testing.T) {
+	env := schema.NewApplicationEnvironmentConfig("dev", "Development", "")
+
+	overlay := map[string]interface{}{
+		"mappers": map[string]interface{}{
+			"groups": map[string]interface{}{
+				"config": map[string]string{
+					"full.path": "true",
+				},
+			},
+			"email": map[string]interface{}{
+				"protocol":       "openid-connect",
+				"protocolMapper": "oidc-usermodel-property-mapper",
+			},
+		},
+	}
+
+	actual := DeepMergeMaps(env.Keycloak, overlay)
+
+	mappers := actual["mappers"].(map[string]interface{})
+	groups := mappers["groups"].(map[string]interface{})
+	if groups["protocolMapper"] != "oidc-group-membership-mapper" {
+		t.Errorf("expected groups mapper to keep its protocol mapper, found %v", groups)
+	}
+
+	config := groups["config"].(map[string]interface{})
+	if config["full.path"] != "true" {
+		t.Errorf("expected overlay to override full.path, found %v", config["full.path"])
+	}
+
+	if config["claim.name"] != "groups" || config["multivalued"] != "true" {
+		t.Errorf("expected remaining mapper config to be kept, found %v", config)
+	}
+
+	if _, ok := mappers["email"]; !ok {
+		t.Errorf("expected overlay mapper to be added, found %v", mappers)
+	}
+
+	// the default environment config is left untouched
+	if env.Keycloak["mappers"].(map[string]interface{})["groups"].(map[string]interface{})["config"].(map[string]string)["full.path"] != "false" {
+		t.Errorf("deep merge modified the default keycloak mappers")
+	}
+}
+
+func TestDeepMergeMapsEmpty(t *testing.T) {
+	actual := DeepMergeMaps()
+	if actual == nil || len(actual) != 0 {
+		t.Errorf("expected empty non-nil map, found %v", actual)
+	}
+
+	actual = DeepMergeMaps(nil, map[string]interface{}{"key": "value"}, nil)
+	if !reflect.DeepEqual(actual, map[string]interface{}{"key": "value"}) {
+		t.Errorf("unexpected deep merge result with nil maps, %v", actual)
+	}
+}
+
 func TestMapContainsKey(t *testing.T) {
 	m := map[string]string{
 		"key1": "value1",
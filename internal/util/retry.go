@@ -0,0 +1,103 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryOpts configures the attempts and backoff used by Retry.
+type RetryOpts struct {
+	MaxAttempts int           // Total attempts including the first, values <= 0 mean a single attempt.
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each retry after it.
+	MaxDelay    time.Duration // Upper bound for the delay between attempts, 0 for no bound.
+	Jitter      float64       // Fraction of the delay to randomize, e.g. 0.2 waits between 80% and 120% of it.
+
+	// Retryable reports whether an error is worth another attempt, nil retries every error.
+	Retryable func(err error) bool
+	// OnRetry is called after a failed attempt, before waiting delay for the next one.
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// Retry calls f until it succeeds, returns an error that isn't retryable, the attempts are
+// exhausted or the context is done. The last error from f is returned, joined with the
+// context error if the context ended the retries.
+func Retry(ctx context.Context, opts RetryOpts, f func(ctx context.Context) error) error {
+	attempts := max(opts.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(err, ctxErr)
+		}
+
+		err = f(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= attempts || (opts.Retryable != nil && !opts.Retryable(err)) {
+			return err
+		}
+
+		delay := opts.delay(attempt)
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, delay, err)
+		}
+
+		if delay <= 0 {
+			continue
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// delay returns the backoff before the retry following the given attempt,
+// BaseDelay * 2^(attempt-1) capped at MaxDelay with jitter applied.
+func (o RetryOpts) delay(attempt int) time.Duration {
+	if o.BaseDelay <= 0 {
+		return 0
+	}
+
+	d := o.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if (o.MaxDelay > 0 && d >= o.MaxDelay) || d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+
+	if o.MaxDelay > 0 && d > o.MaxDelay {
+		d = o.MaxDelay
+	}
+
+	if o.Jitter > 0 {
+		j := min(o.Jitter, 1)
+		d = time.Duration(float64(d) * (1 + j*(2*rand.Float64()-1)))
+	}
+
+	return d
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Metrostar Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetrySuccessAfterN(t *testing.T) {
+	count := 0
+	var delays []time.Duration
+
+	err := Retry(context.Background(), RetryOpts{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    3 * time.Millisecond,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			delays = append(delays, delay)
+		},
+	}, func(ctx context.Context) error {
+		count = count + 1
+		if count < 4 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error from Retry, %v", err)
+	}
+
+	if count != 4 {
+		t.Errorf("unexpected attempt count, expected 4, found %d", count)
+	}
+
+	// doubles from the base delay, capped at the max delay
+	expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}
+	if len(delays) != len(expected) {
+		t.Fatalf("unexpected retry delays, expected %v, found %v", expected, delays)
+	}
+
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("unexpected retry delay %d, expected %v, found %v", i, expected[i], delays[i])
+		}
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	count := 0
+	retries := 0
+
+	err := Retry(context.Background(), RetryOpts{
+		MaxAttempts: 3,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			retries = retries + 1
+		},
+	}, func(ctx context.Context) error {
+		count = count + 1
+		return errors.New("always")
+	})
+	if err == nil || err.Error() != "always" {
+		t.Errorf("expected last error from exhausted Retry, found %v", err)
+	}
+
+	if count != 3 || retries != 2 {
+		t.Errorf("unexpected attempts, expected 3 attempts and 2 retries, found %d and %d", count, retries)
+	}
+}
+
+func TestRetrySingleAttemptDefault(t *testing.T) {
+	count := 0
+
+	Retry(context.Background(), RetryOpts{}, func(ctx context.Context) error {
+		count = count + 1
+		return errors.New("fail")
+	})
+
+	if count != 1 {
+		t.Errorf("expected a single attempt without MaxAttempts, found %d", count)
+	}
+}
+
+func TestRetryNonRetryable(t *testing.T) {
+	fatal := errors.New("fatal")
+	count := 0
+
+	err := Retry(context.Background(), RetryOpts{
+		MaxAttempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, fatal)
+		},
+	}, func(ctx context.Context) error {
+		count = count + 1
+		if count == 2 {
+			return fatal
+		}
+		return errors.New("transient")
+	})
+	if !errors.Is(err, fatal) {
+		t.Errorf("expected non-retryable error from Retry, found %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("expected Retry to stop at the non-retryable error, found %d attempts", count)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+
+	start := time.Now()
+	err := Retry(ctx, RetryOpts{
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			cancel()
+		},
+	}, func(ctx context.Context) error {
+		count = count + 1
+		return errors.New("transient")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error from Retry, found %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected a single attempt before cancel, found %d, %v", count, err)
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("expected Retry to return on cancel without waiting for the delay")
+	}
+
+	// an already canceled context makes no attempts
+	count = 0
+	err = Retry(ctx, RetryOpts{MaxAttempts: 5}, func(ctx context.Context) error {
+		count = count + 1
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || count != 0 {
+		t.Errorf("expected no attempts with a canceled context, found %d, %v", count, err)
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	opts := RetryOpts{
+		BaseDelay: 100 * time.Millisecond,
+		Jitter:    0.5,
+	}
+
+	for range 20 {
+		d := opts.delay(1)
+		if d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Errorf("jittered delay out of range, expected 50ms-150ms, found %v", d)
+		}
+	}
+
+	// no base delay means no waiting, regardless of jitter
+	if d := (RetryOpts{Jitter: 0.5}).delay(3); d != 0 {
+		t.Errorf("expected no delay without a base delay, found %v", d)
+	}
+}
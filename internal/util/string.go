@@ -66,3 +66,48 @@ func EqualsOrRegexMatchString(pattern string, s string, insensitive bool) bool {
 
 	return false
 }
+
+// Levenshtein returns the edit distance between `a` and `b`, the minimum number of
+// single character insertions, deletions or substitutions to turn one into the other.
+func Levenshtein(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr := make([]int, len(t)+1)
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+
+	return prev[len(t)]
+}
+
+// ClosestMatch returns the candidate with the smallest edit distance to `s`, if it is close
+// enough to be a likely typo, i.e. within a third of the length of `s` (at least 2).
+// Ties are resolved in favor of the earlier candidate.
+func ClosestMatch(s string, candidates []string) (string, bool) {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		d := Levenshtein(s, c)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+
+	if bestDist < 0 || bestDist > max(2, len(s)/3) {
+		return "", false
+	}
+
+	return best, true
+}
@@ -106,3 +106,49 @@ func TestUtilEqualsOrRegexMatchString(t *testing.T) {
 		}
 	}
 }
+
+func TestUtilLevenshtein(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"argocd", "argocd", 0},
+		{"argo", "argocd", 2},
+		{"", "vpc", 3},
+		{"kitten", "sitting", 3},
+		{"eks", "ekss", 1},
+	}
+
+	for _, tc := range tests {
+		actual := Levenshtein(tc.a, tc.b)
+		if actual != tc.expected {
+			t.Errorf("Levenshtein(%s, %s) expected %d, got %d", tc.a, tc.b, tc.expected, actual)
+		}
+	}
+}
+
+func TestUtilClosestMatch(t *testing.T) {
+	candidates := []string{"vpc", "eks", "argocd", "keycloak"}
+
+	actual, ok := ClosestMatch("argo", candidates)
+	if !ok || actual != "argocd" {
+		t.Errorf("expected argocd, got %s (%v)", actual, ok)
+	}
+
+	actual, ok = ClosestMatch("keycloack", candidates)
+	if !ok || actual != "keycloak" {
+		t.Errorf("expected keycloak, got %s (%v)", actual, ok)
+	}
+
+	actual, ok = ClosestMatch("monitoring", candidates)
+	if ok {
+		t.Errorf("expected no match, got %s", actual)
+	}
+
+	_, ok = ClosestMatch("vpc", nil)
+	if ok {
+		t.Errorf("expected no match without candidates")
+	}
+}